@@ -0,0 +1,407 @@
+package api
+
+import (
+	"strconv"
+
+	"lucky/common/mysql"
+	"lucky/common/response"
+	"lucky/middleware"
+	"lucky/model"
+	"lucky/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetGameStatusRequest 启用/停用游戏请求
+type SetGameStatusRequest struct {
+	IsActive *bool  `json:"isActive" binding:"required"`
+	Reason   string `json:"reason"`
+}
+
+// AdminDrawResultRequest 手动录入/更正开奖结果请求
+type AdminDrawResultRequest struct {
+	service.DrawResultInput
+	Reason string `json:"reason"` // 操作原因，更正时必填
+}
+
+// TriggerCrawlRequest 触发抓取请求
+type TriggerCrawlRequest struct {
+	Mode  string `json:"mode" binding:"required"` // latest(最新一期), backfill(回补历史)
+	Pages int    `json:"pages"`                   // 回补页数，每页30期
+}
+
+// RunJobRequest 执行任务请求
+type RunJobRequest struct {
+	Params map[string]string `json:"params"` // 任务参数，按任务的参数定义校验
+}
+
+// SetUserRoleRequest 修改用户角色请求
+type SetUserRoleRequest struct {
+	Role   string `json:"role" binding:"required"` // user, admin
+	Reason string `json:"reason"`
+}
+
+// DrawRevisionsResponse 开奖结果及更正历史
+type DrawRevisionsResponse struct {
+	Current   *model.DrawResult           `json:"current"`
+	Revisions []*model.DrawResultRevision `json:"revisions"`
+}
+
+// adminOperator 当前管理员及请求IP
+func adminOperator(c *gin.Context) service.AdminOperator {
+	operator := service.AdminOperator{IP: c.ClientIP()}
+	if user, ok := middleware.GetCurrentUser(c); ok {
+		operator.AdminID = user.ID
+	}
+	return operator
+}
+
+// adminPagination 解析分页参数
+func adminPagination(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return page, pageSize
+}
+
+// AdminListGames 获取全部游戏（含未启用）
+// @Summary 获取全部游戏（含未启用）
+// @Tags 管理后台
+// @Produce json
+// @Success 200 {object} response.Body{data=[]model.LotteryGame}
+// @Failure 401 {object} response.Body
+// @Failure 403 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Security BearerAuth
+// @Router /api/admin/games [get]
+func AdminListGames(c *gin.Context) {
+	games, err := service.ListAllGames(mysql.DB)
+	if err != nil {
+		response.Fail(c, err)
+		return
+	}
+
+	response.OK(c, games)
+}
+
+// AdminSetGameStatus 启用或停用游戏
+// @Summary 启用或停用游戏
+// @Tags 管理后台
+// @Accept json
+// @Produce json
+// @Param gameCode path string true "游戏代码"
+// @Param request body SetGameStatusRequest true "状态和原因"
+// @Success 200 {object} response.Body{data=model.LotteryGame}
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Failure 403 {object} response.Body
+// @Security BearerAuth
+// @Router /api/admin/games/{gameCode}/status [put]
+func AdminSetGameStatus(c *gin.Context) {
+	var req SetGameStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.ErrInvalidParams.Wrap(err))
+		return
+	}
+
+	game, err := service.SetGameActive(mysql.DB, adminOperator(c), c.Param("gameCode"), *req.IsActive, req.Reason)
+	if err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	response.Success(c, "更新成功", game)
+}
+
+// AdminCreateDrawResult 手动录入开奖结果
+// @Summary 手动录入开奖结果
+// @Tags 管理后台
+// @Accept json
+// @Produce json
+// @Param gameCode path string true "游戏代码"
+// @Param request body AdminDrawResultRequest true "开奖结果"
+// @Success 200 {object} response.Body{data=model.DrawResult}
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Failure 403 {object} response.Body
+// @Failure 409 {object} response.Body
+// @Security BearerAuth
+// @Router /api/admin/draws/{gameCode} [post]
+func AdminCreateDrawResult(c *gin.Context) {
+	var req AdminDrawResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.ErrInvalidParams.Wrap(err))
+		return
+	}
+
+	drawResult, err := service.CreateDrawResultManually(mysql.DB, adminOperator(c), c.Param("gameCode"), &req.DrawResultInput, req.Reason)
+	if err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	response.Success(c, "录入成功", drawResult)
+}
+
+// AdminCorrectDrawResult 更正开奖结果
+// @Summary 更正开奖结果并重新核对中奖记录
+// @Tags 管理后台
+// @Accept json
+// @Produce json
+// @Param gameCode path string true "游戏代码"
+// @Param period path string true "期号"
+// @Param request body AdminDrawResultRequest true "更正后的开奖结果和原因"
+// @Success 200 {object} response.Body{data=service.DrawCorrectionResult}
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Failure 403 {object} response.Body
+// @Security BearerAuth
+// @Router /api/admin/draws/{gameCode}/{period} [put]
+func AdminCorrectDrawResult(c *gin.Context) {
+	var req AdminDrawResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.ErrInvalidParams.Wrap(err))
+		return
+	}
+
+	result, err := service.CorrectDrawResult(mysql.DB, adminOperator(c), c.Param("gameCode"), c.Param("period"), &req.DrawResultInput, req.Reason)
+	if result == nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+	if err != nil {
+		// 开奖结果已更正，重新核对部分失败
+		_ = c.Error(err)
+		response.Success(c, "更正成功，重新核对中奖记录失败", result)
+		return
+	}
+
+	response.Success(c, "更正成功", result)
+}
+
+// AdminGetDrawRevisions 获取开奖结果的更正历史
+// @Summary 获取开奖结果的更正历史
+// @Tags 管理后台
+// @Produce json
+// @Param gameCode path string true "游戏代码"
+// @Param period path string true "期号"
+// @Success 200 {object} response.Body{data=DrawRevisionsResponse}
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Failure 403 {object} response.Body
+// @Security BearerAuth
+// @Router /api/admin/draws/{gameCode}/{period}/revisions [get]
+func AdminGetDrawRevisions(c *gin.Context) {
+	drawResult, revisions, err := service.GetDrawResultRevisions(mysql.DB, c.Param("gameCode"), c.Param("period"))
+	if err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	response.OK(c, DrawRevisionsResponse{Current: drawResult, Revisions: revisions})
+}
+
+// AdminTriggerCrawl 触发抓取最新一期或回补历史数据
+// @Summary 触发抓取最新一期或回补历史
+// @Tags 管理后台
+// @Accept json
+// @Produce json
+// @Param gameCode path string true "游戏代码"
+// @Param request body TriggerCrawlRequest true "抓取模式"
+// @Success 200 {object} response.Body{data=model.CrawlRun}
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Failure 403 {object} response.Body
+// @Failure 429 {object} response.Body "请求过于频繁，Retry-After 响应头为需等待的秒数"
+// @Security BearerAuth
+// @Router /api/admin/crawl/{gameCode} [post]
+func AdminTriggerCrawl(c *gin.Context) {
+	var req TriggerCrawlRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.ErrInvalidParams.Wrap(err))
+		return
+	}
+
+	run, err := service.TriggerCrawl(mysql.DB, adminOperator(c), c.Param("gameCode"), req.Mode, req.Pages)
+	if run == nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+	if err != nil {
+		// 抓取记录已保存，失败原因见抓取记录
+		_ = c.Error(err)
+		response.Success(c, "抓取失败", run)
+		return
+	}
+
+	message := "抓取成功"
+	if req.Mode == model.CrawlModeBackfill {
+		message = "回补任务已开始"
+	}
+	response.Success(c, message, run)
+}
+
+// AdminListCrawlRuns 获取抓取记录
+// @Summary 获取抓取记录
+// @Tags 管理后台
+// @Produce json
+// @Param gameCode query string false "游戏代码"
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页条数" default(20)
+// @Success 200 {object} response.Body{data=response.Page{list=[]model.CrawlRun}}
+// @Failure 401 {object} response.Body
+// @Failure 403 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Security BearerAuth
+// @Router /api/admin/crawl-runs [get]
+func AdminListCrawlRuns(c *gin.Context) {
+	page, pageSize := adminPagination(c)
+
+	runs, total, err := service.ListCrawlRuns(mysql.DB, c.Query("gameCode"), page, pageSize)
+	if err != nil {
+		response.Fail(c, err)
+		return
+	}
+
+	response.OK(c, response.Page{List: runs, Total: total, Page: page, PageSize: pageSize})
+}
+
+// AdminListJobs 获取已注册的任务及参数定义
+// @Summary 获取已注册的任务及参数定义
+// @Tags 管理后台
+// @Produce json
+// @Success 200 {object} response.Body{data=[]service.Job}
+// @Failure 401 {object} response.Body
+// @Failure 403 {object} response.Body
+// @Security BearerAuth
+// @Router /api/admin/jobs [get]
+func AdminListJobs(c *gin.Context) {
+	response.OK(c, service.RegisteredJobs())
+}
+
+// AdminRunJob 执行任务，同步返回执行记录
+// @Summary 执行任务
+// @Tags 管理后台
+// @Accept json
+// @Produce json
+// @Param name path string true "任务名称"
+// @Param request body RunJobRequest true "任务参数"
+// @Success 200 {object} response.Body{data=model.JobRun}
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Failure 403 {object} response.Body
+// @Failure 409 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Security BearerAuth
+// @Router /api/admin/jobs/{name}/run [post]
+func AdminRunJob(c *gin.Context) {
+	var req RunJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.ErrInvalidParams.Wrap(err))
+		return
+	}
+
+	run, err := service.RunJobAsAdmin(c.Request.Context(), mysql.DB, adminOperator(c), c.Param("name"), req.Params)
+	if run == nil {
+		response.Fail(c, err)
+		return
+	}
+	if err != nil {
+		// 执行记录已保存，失败原因见执行记录
+		_ = c.Error(err)
+		response.Success(c, "任务执行失败", run)
+		return
+	}
+
+	response.OK(c, run)
+}
+
+// AdminListJobRuns 获取任务执行记录
+// @Summary 获取任务执行记录
+// @Tags 管理后台
+// @Produce json
+// @Param jobName query string false "任务名称"
+// @Param status query string false "执行状态"
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页条数" default(20)
+// @Success 200 {object} response.Body{data=response.Page{list=[]model.JobRun}}
+// @Failure 401 {object} response.Body
+// @Failure 403 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Security BearerAuth
+// @Router /api/admin/job-runs [get]
+func AdminListJobRuns(c *gin.Context) {
+	page, pageSize := adminPagination(c)
+
+	runs, total, err := service.ListJobRuns(mysql.DB, c.Query("jobName"), c.Query("status"), page, pageSize)
+	if err != nil {
+		response.Fail(c, err)
+		return
+	}
+
+	response.OK(c, response.Page{List: runs, Total: total, Page: page, PageSize: pageSize})
+}
+
+// AdminListAuditLogs 获取管理操作审计日志
+// @Summary 获取管理操作审计日志
+// @Tags 管理后台
+// @Produce json
+// @Param action query string false "操作类型"
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页条数" default(20)
+// @Success 200 {object} response.Body{data=response.Page{list=[]model.AdminAuditLog}}
+// @Failure 401 {object} response.Body
+// @Failure 403 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Security BearerAuth
+// @Router /api/admin/audit-logs [get]
+func AdminListAuditLogs(c *gin.Context) {
+	page, pageSize := adminPagination(c)
+
+	logs, total, err := service.ListAuditLogs(mysql.DB, c.Query("action"), page, pageSize)
+	if err != nil {
+		response.Fail(c, err)
+		return
+	}
+
+	response.OK(c, response.Page{List: logs, Total: total, Page: page, PageSize: pageSize})
+}
+
+// AdminSetUserRole 修改用户角色
+// @Summary 修改用户角色
+// @Tags 管理后台
+// @Accept json
+// @Produce json
+// @Param id path int true "用户ID"
+// @Param request body SetUserRoleRequest true "角色和原因"
+// @Success 200 {object} response.Body
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Failure 403 {object} response.Body
+// @Security BearerAuth
+// @Router /api/admin/users/{id}/role [put]
+func AdminSetUserRole(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Fail(c, response.ErrInvalidParams.WithDetail("用户ID错误"))
+		return
+	}
+
+	var req SetUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.ErrInvalidParams.Wrap(err))
+		return
+	}
+
+	if err := service.SetUserRole(mysql.DB, adminOperator(c), userID, req.Role, req.Reason); err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	response.Success(c, "更新成功", nil)
+}
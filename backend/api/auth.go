@@ -8,7 +8,8 @@ import (
 
 	"lucky/common/config"
 	"lucky/common/jwt"
-	"lucky/common/mysql"
+	applog "lucky/common/log"
+	"lucky/common/response"
 	"lucky/model"
 	"lucky/service"
 
@@ -45,26 +46,31 @@ type wxCode2SessionResp struct {
 }
 
 // WxLogin 微信小程序登录
+// @Summary 微信小程序登录
+// @Description 用 code 换取 openid，首次登录自动注册，返回 JWT
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body WxLoginRequest true "wx.login 获取的 code 及用户信息"
+// @Success 200 {object} response.Body{data=WxLoginResponse}
+// @Failure 400 {object} response.Body
+// @Failure 429 {object} response.Body "请求过于频繁，Retry-After 响应头为需等待的秒数"
+// @Failure 500 {object} response.Body
+// @Failure 502 {object} response.Body
+// @Router /api/auth/wxlogin [post]
 func WxLogin(c *gin.Context) {
 	var req WxLoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    400,
-			"message": "参数错误",
-			"error":   err.Error(),
-		})
+		response.Fail(c, response.ErrInvalidParams.Wrap(err))
 		return
 	}
 
 	// 获取微信配置
-	section := config.Config.Section("wechat")
-	appid := section.Key("app_id").String()
-	secret := section.Key("app_secret").String()
+	wechat := config.Get().WeChat
+	appid := wechat.AppID
+	secret := wechat.AppSecret
 	if appid == "" || secret == "" {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    500,
-			"message": "未配置微信小程序appid/secret",
-		})
+		response.Fail(c, response.ErrServerConfig.WithDetail("未配置微信小程序appid/secret"))
 		return
 	}
 
@@ -72,36 +78,24 @@ func WxLogin(c *gin.Context) {
 	url := fmt.Sprintf("https://api.weixin.qq.com/sns/jscode2session?appid=%s&secret=%s&js_code=%s&grant_type=authorization_code", appid, secret, req.Code)
 	resp, err := http.Get(url)
 	if err != nil {
-		c.JSON(http.StatusBadGateway, gin.H{
-			"code":    502,
-			"message": "请求微信接口失败",
-			"error":   err.Error(),
-		})
+		response.Fail(c, response.ErrWeChat.Wrap(err))
 		return
 	}
 	defer resp.Body.Close()
 
 	var wx wxCode2SessionResp
 	if err := json.NewDecoder(resp.Body).Decode(&wx); err != nil {
-		c.JSON(http.StatusBadGateway, gin.H{
-			"code":    502,
-			"message": "解析微信响应失败",
-			"error":   err.Error(),
-		})
+		response.Fail(c, response.ErrWeChat.Wrap(err))
 		return
 	}
 
 	if wx.ErrCode != 0 || wx.OpenID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    400,
-			"message": "微信登录失败",
-			"error":   wx.ErrMsg,
-		})
+		response.Fail(c, response.ErrWeChatLogin.WithDetail(wx.ErrMsg))
 		return
 	}
 
 	// 获取数据库连接
-	db := mysql.DB
+	db := requestDB(c)
 
 	// 设置默认用户信息
 	nickname := req.Nickname
@@ -113,11 +107,7 @@ func WxLogin(c *gin.Context) {
 	// 获取或创建用户
 	user, err := service.GetOrCreateUser(db, wx.OpenID, nickname, avatarURL)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    500,
-			"message": "用户信息处理失败",
-			"error":   err.Error(),
-		})
+		response.Fail(c, err)
 		return
 	}
 
@@ -127,17 +117,14 @@ func WxLogin(c *gin.Context) {
 	now := time.Now()
 	if err := userDAO.UpdateLoginInfo(user.ID, now, clientIP); err != nil {
 		// 记录日志但不影响登录流程
-		fmt.Printf("更新登录信息失败: %v\n", err)
+		applog.Ctx(c.Request.Context()).WithField(applog.FieldUserID, user.ID).Warnf("更新登录信息失败: %v", err)
 	}
+	applog.Ctx(c.Request.Context()).WithField(applog.FieldUserID, user.ID).Info("微信登录")
 
 	// 生成JWT token
 	token, err := jwt.GenerateToken(uint64(user.ID), user.OpenID, user.Nickname)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    500,
-			"message": "生成token失败",
-			"error":   err.Error(),
-		})
+		response.Fail(c, err)
 		return
 	}
 
@@ -145,7 +132,7 @@ func WxLogin(c *gin.Context) {
 	expiresAt := time.Now().Add(jwt.GetAccessTokenExpire())
 
 	// 返回登录成功响应
-	response := WxLoginResponse{
+	data := WxLoginResponse{
 		Token:     token,
 		ExpiresAt: expiresAt,
 		User: WxUserInfo{
@@ -155,9 +142,5 @@ func WxLogin(c *gin.Context) {
 		},
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code":    200,
-		"message": "登录成功",
-		"data":    response,
-	})
+	response.Success(c, "登录成功", data)
 }
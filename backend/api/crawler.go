@@ -1,59 +1,75 @@
 package api
 
 import (
+	"errors"
+
+	"lucky/common/mysql"
+	"lucky/common/response"
+	"lucky/model"
 	"lucky/service"
-	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
 // CrawlLatestHandler 抓取最新开奖数据
+// @Summary 抓取并保存最新开奖数据
+// @Tags 数据抓取
+// @Produce json
+// @Param gameCode path string true "游戏代码"
+// @Success 200 {object} response.Body{data=model.JobRun}
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Failure 403 {object} response.Body
+// @Failure 409 {object} response.Body
+// @Failure 429 {object} response.Body "请求过于频繁，Retry-After 响应头为需等待的秒数"
+// @Failure 502 {object} response.Body
+// @Security BearerAuth
+// @Router /api/crawler/crawl/{gameCode} [post]
 func CrawlLatestHandler(c *gin.Context) {
 	gameCode := c.Param("gameCode")
 	if gameCode == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 400,
-			"msg":  "游戏代码不能为空",
-		})
+		response.Fail(c, response.ErrInvalidParams.WithDetail("游戏代码不能为空"))
 		return
 	}
 
-	crawler := service.NewCrawlerService()
-	err := crawler.CrawlAndSaveLatest(gameCode)
+	run, err := service.RunJobByName(c.Request.Context(), mysql.DB, "crawl", map[string]string{"gameCode": gameCode}, model.JobTriggerAPI, adminOperator(c).AdminID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code": 500,
-			"msg":  "抓取失败: " + err.Error(),
-		})
+		// 任务正在执行、参数错误按对应的错误码响应，其余为抓取失败
+		if errors.Is(err, service.ErrJobRunning) || errors.Is(err, service.ErrInvalidJob) {
+			response.Fail(c, err)
+			return
+		}
+		response.Fail(c, response.ErrCrawlFailed.Wrap(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"msg":  "抓取成功",
-	})
+	response.Success(c, "抓取成功", run)
 }
 
 // TestCrawlHandler 测试抓取功能
+// @Summary 测试抓取最新开奖数据（不保存）
+// @Tags 数据抓取
+// @Produce json
+// @Param gameCode path string true "游戏代码"
+// @Success 200 {object} response.Body{data=service.DrawResult}
+// @Failure 401 {object} response.Body
+// @Failure 403 {object} response.Body
+// @Failure 429 {object} response.Body "请求过于频繁，Retry-After 响应头为需等待的秒数"
+// @Failure 502 {object} response.Body
+// @Security BearerAuth
+// @Router /api/crawler/test/{gameCode} [get]
 func TestCrawlHandler(c *gin.Context) {
 	gameCode := c.Param("gameCode")
 	if gameCode == "" {
 		gameCode = "ssq" // 默认双色球
 	}
 
-	crawler := service.NewCrawlerService()
+	crawler := service.NewCrawlerService().WithContext(c.Request.Context())
 	result, err := crawler.CrawlLatestResults(gameCode)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code": 500,
-			"msg":  "抓取失败: " + err.Error(),
-		})
+		response.Fail(c, response.ErrCrawlFailed.Wrap(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"msg":  "抓取成功",
-		"data": result,
-	})
+	response.Success(c, "抓取成功", result)
 }
@@ -0,0 +1,58 @@
+package api
+
+import (
+	_ "embed"
+	"net/http"
+	"sync"
+
+	"lucky/common/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:generate go run ../cmd/openapi -dir . -out openapi_gen.go
+
+// docsPage Swagger UI 页面，加载 /api/docs/openapi.json
+//
+//go:embed docs/index.html
+var docsPage []byte
+
+// apiVersion 接口文档版本
+const apiVersion = "1.0.0"
+
+var (
+	openAPIOnce sync.Once
+	openAPIDoc  *openapi.Document
+)
+
+// OpenAPI 由处理函数注释生成的 OpenAPI 文档，修改注释后执行 go generate ./api 更新
+func OpenAPI() *openapi.Document {
+	openAPIOnce.Do(func() {
+		doc := openapi.Build(openapi.Info{
+			Title:       "Lucky API",
+			Description: "彩票号码管理接口，响应格式为 {\"code\",\"message\",\"data\"}，错误码见 API_DOCS.md",
+			Version:     apiVersion,
+		}, openAPIRoutes)
+		doc.Components.SecuritySchemes["BearerAuth"] = &openapi.SecurityScheme{
+			Type:         "http",
+			Scheme:       "bearer",
+			BearerFormat: "JWT",
+			Description:  "登录接口返回的 token，请求头 Authorization: Bearer <token>",
+		}
+		openAPIDoc = doc
+	})
+	return openAPIDoc
+}
+
+// RegisterDocsRoutes 注册接口文档路由：/api/docs 为 Swagger UI，/api/docs/openapi.json 为 OpenAPI 文档
+func RegisterDocsRoutes(r *gin.Engine) {
+	docsGroup := r.Group("/api/docs")
+	{
+		docsGroup.GET("", func(c *gin.Context) {
+			c.Data(http.StatusOK, "text/html; charset=utf-8", docsPage)
+		})
+		docsGroup.GET("/openapi.json", func(c *gin.Context) {
+			c.JSON(http.StatusOK, OpenAPI())
+		})
+	}
+}
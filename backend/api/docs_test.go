@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"lucky/common/openapi"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOpenAPIGenerated 修改处理函数注释后需执行 go generate ./api
+func TestOpenAPIGenerated(t *testing.T) {
+	pkg, err := openapi.ParseDir(".")
+	require.NoError(t, err)
+	want, err := openapi.Generate(pkg, "openAPIRoutes", "cmd/openapi")
+	require.NoError(t, err)
+
+	got, err := os.ReadFile("openapi_gen.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got), "openapi_gen.go 已过期，请执行 go generate ./api")
+}
+
+func TestDocsRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	RegisterDocsRoutes(r)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/docs", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, w.Body.String(), "/api/docs/openapi.json")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/docs/openapi.json", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, openapi.Version, doc["openapi"])
+
+	paths := doc["paths"].(map[string]interface{})
+	assert.Contains(t, paths, "/api/missing")
+	assert.Contains(t, paths, "/api/numbers/{numberId}/check")
+
+	// 引用的结构都在 components 中
+	schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	for _, ref := range collectRefs(doc) {
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		assert.Containsf(t, schemas, name, "未定义的引用 %s", ref)
+	}
+}
+
+func TestOpenAPISchemas(t *testing.T) {
+	doc := OpenAPI()
+
+	op := doc.Paths["/api/numbers/save"]["post"]
+	require.NotNil(t, op)
+	require.NotNil(t, op.RequestBody)
+	assert.Equal(t, "#/components/schemas/api.SaveUserNumberRequest", op.RequestBody.Content["application/json"].Schema.Ref)
+	assert.ElementsMatch(t, []string{"gameCode", "redBalls"}, doc.Components.Schemas["api.SaveUserNumberRequest"].Required)
+
+	// 响应为统一格式，data 为号码
+	success := op.Responses["200"].Content["application/json"].Schema
+	require.Len(t, success.AllOf, 2)
+	assert.Equal(t, "#/components/schemas/response.Body", success.AllOf[0].Ref)
+	assert.Equal(t, "#/components/schemas/model.UserNumber", success.AllOf[1].Properties["data"].Ref)
+
+	admin := doc.Paths["/api/admin/games"]["get"]
+	require.NotNil(t, admin)
+	assert.Equal(t, []map[string][]string{{"BearerAuth": {}}}, admin.Security)
+	assert.Contains(t, doc.Components.SecuritySchemes, "BearerAuth")
+}
+
+func collectRefs(v interface{}) []string {
+	var refs []string
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if ref, ok := value.(string); ok && key == "$ref" {
+				refs = append(refs, ref)
+				continue
+			}
+			refs = append(refs, collectRefs(value)...)
+		}
+	case []interface{}:
+		for _, value := range v {
+			refs = append(refs, collectRefs(value)...)
+		}
+	}
+	return refs
+}
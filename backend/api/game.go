@@ -1,56 +1,51 @@
 package api
 
 import (
-	"net/http"
-
 	"lucky/common/mysql"
+	"lucky/common/response"
 	"lucky/service"
 
 	"github.com/gin-gonic/gin"
 )
 
 // GetGameList 获取游戏列表
+// @Summary 获取启用的游戏列表
+// @Tags 彩票游戏
+// @Produce json
+// @Success 200 {object} response.Body{data=[]model.LotteryGame}
+// @Failure 500 {object} response.Body
+// @Router /api/games [get]
 func GetGameList(c *gin.Context) {
 	games, err := service.GetActiveGames(mysql.DB)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    500,
-			"message": "获取游戏列表失败",
-			"error":   err.Error(),
-		})
+		response.Fail(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code":    200,
-		"message": "success",
-		"data":    games,
-	})
+	response.OK(c, games)
 }
 
 // GetGameDetail 获取游戏详情
+// @Summary 获取游戏详情
+// @Tags 彩票游戏
+// @Produce json
+// @Param gameCode path string true "游戏代码"
+// @Success 200 {object} response.Body{data=model.LotteryGame}
+// @Failure 400 {object} response.Body
+// @Failure 404 {object} response.Body
+// @Router /api/games/{gameCode} [get]
 func GetGameDetail(c *gin.Context) {
 	gameCode := c.Param("gameCode")
 	if gameCode == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    400,
-			"message": "游戏代码不能为空",
-		})
+		response.Fail(c, response.ErrInvalidParams.WithDetail("游戏代码不能为空"))
 		return
 	}
 
 	game, err := service.GetGameByCode(mysql.DB, gameCode)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"code":    404,
-			"message": "游戏不存在",
-		})
+		response.Fail(c, response.ErrGameNotFound)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code":    200,
-		"message": "success",
-		"data":    game,
-	})
+	response.OK(c, game)
 }
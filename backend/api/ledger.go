@@ -0,0 +1,166 @@
+package api
+
+import (
+	"strconv"
+
+	"lucky/common/mysql"
+	"lucky/common/response"
+	"lucky/middleware"
+	"lucky/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreatePurchaseRequest 录入购彩记录请求
+type CreatePurchaseRequest struct {
+	GameCode     string `json:"gameCode" binding:"required"`
+	Period       string `json:"period" binding:"required"` // 期号
+	NumberID     *int64 `json:"numberId"`                  // 购买的号码ID，与groupId二选一
+	GroupID      *int64 `json:"groupId"`                   // 购买的号码分组ID
+	BetCount     int    `json:"betCount"`                  // 注数，为空时自动计算
+	Multiplier   int    `json:"multiplier"`                // 倍数，为空时沿用号码设置
+	IsAdditional *bool  `json:"isAdditional"`              // 是否追加（仅大乐透），为空时沿用号码设置
+	Cost         int64  `json:"cost"`                      // 投注金额(分)，为空时自动计算
+	Note         string `json:"note"`
+}
+
+// GetLedgerSummary 获取购彩账本汇总
+// @Summary 获取购彩账本汇总
+// @Tags 购彩账本
+// @Produce json
+// @Success 200 {object} response.Body{data=service.LedgerSummary}
+// @Failure 401 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Security BearerAuth
+// @Router /api/user/ledger [get]
+func GetLedgerSummary(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	summary, err := service.GetLedgerSummary(mysql.DB, userID)
+	if err != nil {
+		response.Fail(c, err)
+		return
+	}
+
+	response.OK(c, summary)
+}
+
+// GetPurchases 获取购彩记录列表
+// @Summary 获取购彩记录列表
+// @Tags 购彩账本
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页条数" default(20)
+// @Success 200 {object} response.Body{data=response.Page{list=[]model.Purchase}}
+// @Failure 401 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Security BearerAuth
+// @Router /api/user/ledger/purchases [get]
+func GetPurchases(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	purchases, total, err := service.GetPurchases(mysql.DB, userID, page, pageSize)
+	if err != nil {
+		response.Fail(c, err)
+		return
+	}
+
+	response.OK(c, response.Page{List: purchases, Total: total, Page: page, PageSize: pageSize})
+}
+
+// CreatePurchase 录入购彩记录
+// @Summary 录入购彩记录
+// @Tags 购彩账本
+// @Accept json
+// @Produce json
+// @Param request body CreatePurchaseRequest true "购彩记录"
+// @Success 200 {object} response.Body{data=model.Purchase}
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Failure 404 {object} response.Body
+// @Security BearerAuth
+// @Router /api/user/ledger/purchases [post]
+func CreatePurchase(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	var req CreatePurchaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.ErrInvalidParams.Wrap(err))
+		return
+	}
+
+	game, err := service.GetGameByCode(mysql.DB, req.GameCode)
+	if err != nil {
+		response.Fail(c, response.ErrGameNotFound)
+		return
+	}
+
+	purchase, err := service.CreatePurchase(mysql.DB, userID, game, service.PurchaseInput{
+		Period:       req.Period,
+		UserNumberID: req.NumberID,
+		GroupID:      req.GroupID,
+		BetCount:     req.BetCount,
+		Multiplier:   req.Multiplier,
+		IsAdditional: req.IsAdditional,
+		Cost:         req.Cost,
+		Note:         req.Note,
+	})
+	if err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	response.Success(c, "录入成功", purchase)
+}
+
+// DeletePurchase 删除购彩记录
+// @Summary 删除购彩记录
+// @Tags 购彩账本
+// @Produce json
+// @Param id path int true "购彩记录ID"
+// @Success 200 {object} response.Body
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Security BearerAuth
+// @Router /api/user/ledger/purchases/{id} [delete]
+func DeletePurchase(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	purchaseID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Fail(c, response.ErrInvalidParams.WithDetail("ID参数错误"))
+		return
+	}
+
+	if err := service.DeletePurchase(mysql.DB, userID, purchaseID); err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	response.Success(c, "删除成功", nil)
+}
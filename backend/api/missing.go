@@ -1,11 +1,14 @@
 package api
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"lucky/common/cache"
 	http500 "lucky/common/http/500"
-	"lucky/common/redis"
-	"net/http"
+	"lucky/common/mysql"
+	"lucky/common/response"
+	"lucky/model"
+	"lucky/service"
 	"strconv"
 	"time"
 
@@ -14,7 +17,7 @@ import (
 
 // MissingDataRequest 遗漏数据请求参数
 type MissingDataRequest struct {
-	GameCode    string `json:"gameCode" binding:"required"`    // 游戏代码：dlt、ssq
+	GameCode    string `json:"gameCode" binding:"required"`    // 游戏代码
 	PeriodCount int    `json:"periodCount" binding:"required"` // 期数：10、30、50
 }
 
@@ -27,17 +30,71 @@ type MissingDataResponse struct {
 	CachedAt    time.Time   `json:"cachedAt"`
 }
 
+// missingDataSources 由500彩票网提供遗漏数据的游戏，其余游戏按已保存的开奖结果统计
+var missingDataSources = map[string]func(periodCount int) (interface{}, interface{}, error){
+	"dlt": func(periodCount int) (interface{}, interface{}, error) {
+		return http500.GetDLTMissingData(periodCount)
+	},
+	"ssq": func(periodCount int) (interface{}, interface{}, error) {
+		return http500.GetSSQMissingData(periodCount)
+	},
+}
+
+// lookupMissingGame 查询支持遗漏统计的游戏，数字型游戏按位统计走势，不支持遗漏数据
+func lookupMissingGame(gameCode string) (*model.LotteryGame, error) {
+	game, err := service.GetGameByCode(mysql.DB, gameCode)
+	if err != nil {
+		return nil, fmt.Errorf("不支持的游戏代码: %s", gameCode)
+	}
+	if service.IsDigitGame(game) {
+		return nil, fmt.Errorf("%s请使用按位走势", game.GameName)
+	}
+	return game, nil
+}
+
+// missingDataTTL 遗漏数据缓存时间，有新开奖结果时立即失效
+const missingDataTTL = time.Hour
+
+// loadMissingData 获取遗漏数据，优先读取缓存
+func loadMissingData(ctx context.Context, game *model.LotteryGame, periodCount int) (MissingDataResponse, error) {
+	key := fmt.Sprintf("missing_data:%s:%d", game.GameCode, periodCount)
+	return cache.GetOrLoad(ctx, key, missingDataTTL, []string{service.DrawCacheTag(game.GameCode)}, func() (MissingDataResponse, error) {
+		data := MissingDataResponse{
+			GameCode:    game.GameCode,
+			PeriodCount: periodCount,
+			CachedAt:    time.Now(),
+		}
+		if source, ok := missingDataSources[game.GameCode]; ok {
+			redBalls, blueBalls, err := source(periodCount)
+			if err != nil {
+				return data, fmt.Errorf("获取%s%d期遗漏数据失败: %v", game.GameName, periodCount, err)
+			}
+			data.RedBalls = redBalls
+			data.BlueBalls = blueBalls
+		} else {
+			stats, err := service.GetNumberMissing(mysql.DB, game.GameCode, periodCount)
+			if err != nil {
+				return data, fmt.Errorf("获取%s%d期遗漏数据失败: %v", game.GameName, periodCount, err)
+			}
+			data.RedBalls = stats.RedBalls
+			data.BlueBalls = stats.BlueBalls
+		}
+		return data, nil
+	})
+}
+
 // GetMissingData 获取遗漏数据
 // @Summary 获取彩票号码遗漏数据
-// @Description 支持大乐透(dlt)和双色球(ssq)的遗漏数据查询，支持10期、30期、50期数据
+// @Description 大乐透(dlt)和双色球(ssq)取自500彩票网，其余选号型游戏和快乐8按已保存的开奖结果统计，支持10期、30期、50期数据
 // @Tags 遗漏数据
 // @Accept json
 // @Produce json
-// @Param gameCode query string true "游戏代码" Enums(dlt, ssq)
+// @Param gameCode query string true "游戏代码"
 // @Param periodCount query int true "期数" Enums(10, 30, 50)
-// @Success 200 {object} MissingDataResponse
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Success 200 {object} response.Body{data=MissingDataResponse}
+// @Failure 400 {object} response.Body
+// @Failure 429 {object} response.Body "请求过于频繁，Retry-After 响应头为需等待的秒数"
+// @Failure 500 {object} response.Body
 // @Router /api/missing [get]
 func GetMissingData(c *gin.Context) {
 	// 获取查询参数
@@ -46,88 +103,34 @@ func GetMissingData(c *gin.Context) {
 
 	// 验证参数
 	if gameCode == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "gameCode 参数不能为空",
-		})
-		return
-	}
-
-	if gameCode != "dlt" && gameCode != "ssq" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "gameCode 只支持 dlt(大乐透) 或 ssq(双色球)",
-		})
+		response.Fail(c, response.ErrInvalidParams.WithDetail("gameCode 参数不能为空"))
 		return
 	}
 
 	periodCount, err := strconv.Atoi(periodCountStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "periodCount 必须是数字",
-		})
+		response.Fail(c, response.ErrInvalidParams.WithDetail("periodCount 必须是数字"))
 		return
 	}
 
 	if periodCount != 10 && periodCount != 30 && periodCount != 50 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "periodCount 只支持 10、30、50",
-		})
+		response.Fail(c, response.ErrInvalidParams.WithDetail("periodCount 只支持 10、30、50"))
 		return
 	}
 
-	// 构建Redis缓存键
-	redisKey := fmt.Sprintf("missing_data:%s:%d", gameCode, periodCount)
-
-	// 尝试从Redis获取缓存数据
-	var cachedData MissingDataResponse
-	if redis.DB != nil && redis.DB.IsEnabled() {
-		err := redis.DB.GetJson(redisKey, &cachedData)
-		if err == nil && !cachedData.CachedAt.IsZero() {
-			// 检查缓存是否过期（设置为1小时过期）
-			if time.Since(cachedData.CachedAt) < time.Hour {
-				c.JSON(http.StatusOK, cachedData)
-				return
-			}
-		}
-	}
-
-	// 缓存不存在或过期，从500.com获取数据
-	var response MissingDataResponse
-	response.GameCode = gameCode
-	response.PeriodCount = periodCount
-	response.CachedAt = time.Now()
-
-	if gameCode == "dlt" {
-		// 获取大乐透遗漏数据
-		redBalls, blueBalls, err := http500.GetDLTMissingData(periodCount)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": fmt.Sprintf("获取大乐透遗漏数据失败: %v", err),
-			})
-			return
-		}
-		response.RedBalls = redBalls
-		response.BlueBalls = blueBalls
-
-	} else if gameCode == "ssq" {
-		// 获取双色球遗漏数据
-		redBalls, blueBalls, err := http500.GetSSQMissingData(periodCount)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": fmt.Sprintf("获取双色球遗漏数据失败: %v", err),
-			})
-			return
-		}
-		response.RedBalls = redBalls
-		response.BlueBalls = blueBalls
+	game, err := lookupMissingGame(gameCode)
+	if err != nil {
+		response.Fail(c, response.ErrInvalidParams.Wrap(err))
+		return
 	}
 
-	// 将数据缓存到Redis（设置1小时过期）
-	if redis.DB != nil && redis.DB.IsEnabled() {
-		responseJSON, _ := json.Marshal(response)
-		redis.DB.Set(redisKey, string(responseJSON), time.Hour)
+	data, err := loadMissingData(c.Request.Context(), game, periodCount)
+	if err != nil {
+		response.Fail(c, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	response.OK(c, data)
 }
 
 // GetMissingDataBatch 批量获取遗漏数据
@@ -136,26 +139,24 @@ func GetMissingData(c *gin.Context) {
 // @Tags 遗漏数据
 // @Accept json
 // @Produce json
-// @Param gameCode query string true "游戏代码" Enums(dlt, ssq)
-// @Success 200 {object} map[string]MissingDataResponse
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Param gameCode query string true "游戏代码"
+// @Success 200 {object} response.Body{data=map[string]MissingDataResponse}
+// @Failure 400 {object} response.Body
+// @Failure 429 {object} response.Body "请求过于频繁，Retry-After 响应头为需等待的秒数"
+// @Failure 500 {object} response.Body
 // @Router /api/missing/batch [get]
 func GetMissingDataBatch(c *gin.Context) {
 	gameCode := c.Query("gameCode")
 
 	// 验证参数
 	if gameCode == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "gameCode 参数不能为空",
-		})
+		response.Fail(c, response.ErrInvalidParams.WithDetail("gameCode 参数不能为空"))
 		return
 	}
 
-	if gameCode != "dlt" && gameCode != "ssq" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "gameCode 只支持 dlt(大乐透) 或 ssq(双色球)",
-		})
+	game, err := lookupMissingGame(gameCode)
+	if err != nil {
+		response.Fail(c, response.ErrInvalidParams.Wrap(err))
 		return
 	}
 
@@ -164,63 +165,13 @@ func GetMissingDataBatch(c *gin.Context) {
 	results := make(map[string]MissingDataResponse)
 
 	for _, period := range periods {
-		redisKey := fmt.Sprintf("missing_data:%s:%d", gameCode, period)
-		periodKey := fmt.Sprintf("period_%d", period)
-
-		// 尝试从Redis获取缓存数据
-		var cachedData MissingDataResponse
-		cacheHit := false
-
-		if redis.DB != nil && redis.DB.IsEnabled() {
-			err := redis.DB.GetJson(redisKey, &cachedData)
-			if err == nil && !cachedData.CachedAt.IsZero() {
-				// 检查缓存是否过期（设置为1小时过期）
-				if time.Since(cachedData.CachedAt) < time.Hour {
-					results[periodKey] = cachedData
-					cacheHit = true
-				}
-			}
-		}
-
-		// 如果缓存未命中，从500.com获取数据
-		if !cacheHit {
-			var response MissingDataResponse
-			response.GameCode = gameCode
-			response.PeriodCount = period
-			response.CachedAt = time.Now()
-
-			if gameCode == "dlt" {
-				redBalls, blueBalls, err := http500.GetDLTMissingData(period)
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{
-						"error": fmt.Sprintf("获取大乐透%d期遗漏数据失败: %v", period, err),
-					})
-					return
-				}
-				response.RedBalls = redBalls
-				response.BlueBalls = blueBalls
-
-			} else if gameCode == "ssq" {
-				redBalls, blueBalls, err := http500.GetSSQMissingData(period)
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{
-						"error": fmt.Sprintf("获取双色球%d期遗漏数据失败: %v", period, err),
-					})
-					return
-				}
-				response.RedBalls = redBalls
-				response.BlueBalls = blueBalls
-			}
-
-			// 缓存数据
-			if redis.DB != nil && redis.DB.IsEnabled() {
-				responseJSON, _ := json.Marshal(response)
-				redis.DB.Set(redisKey, string(responseJSON), time.Hour)
-			}
-
-			results[periodKey] = response
+		data, err := loadMissingData(c.Request.Context(), game, period)
+		if err != nil {
+			response.Fail(c, err)
+			return
 		}
+		results[fmt.Sprintf("period_%d", period)] = data
 	}
 
-	c.JSON(http.StatusOK, results)
+	response.OK(c, results)
 }
@@ -6,6 +6,8 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"lucky/common/response"
+
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
@@ -29,7 +31,7 @@ func TestGetMissingDataValidation(t *testing.T) {
 			gameCode:     "invalid",
 			periodCount:  "10",
 			expectedCode: http.StatusBadRequest,
-			errorMessage: "gameCode 只支持 dlt(大乐透) 或 ssq(双色球)",
+			errorMessage: "不支持的游戏代码: invalid",
 		},
 		{
 			name:         "Invalid period count",
@@ -85,10 +87,11 @@ func TestGetMissingDataValidation(t *testing.T) {
 
 			// 验证错误消息
 			if tt.errorMessage != "" {
-				var response map[string]interface{}
-				err := json.Unmarshal(w.Body.Bytes(), &response)
+				var body response.Body
+				err := json.Unmarshal(w.Body.Bytes(), &body)
 				assert.NoError(t, err)
-				assert.Contains(t, response["error"], tt.errorMessage)
+				assert.Equal(t, response.ErrInvalidParams.Code, body.Code)
+				assert.Contains(t, body.Message, tt.errorMessage)
 			}
 		})
 	}
@@ -111,7 +114,7 @@ func TestGetMissingDataBatchValidation(t *testing.T) {
 			name:         "Invalid game code",
 			gameCode:     "invalid",
 			expectedCode: http.StatusBadRequest,
-			errorMessage: "gameCode 只支持 dlt(大乐透) 或 ssq(双色球)",
+			errorMessage: "不支持的游戏代码: invalid",
 		},
 		{
 			name:         "Missing game code",
@@ -142,10 +145,11 @@ func TestGetMissingDataBatchValidation(t *testing.T) {
 
 			// 验证错误消息
 			if tt.errorMessage != "" {
-				var response map[string]interface{}
-				err := json.Unmarshal(w.Body.Bytes(), &response)
+				var body response.Body
+				err := json.Unmarshal(w.Body.Bytes(), &body)
 				assert.NoError(t, err)
-				assert.Contains(t, response["error"], tt.errorMessage)
+				assert.Equal(t, response.ErrInvalidParams.Code, body.Code)
+				assert.Contains(t, body.Message, tt.errorMessage)
 			}
 		})
 	}
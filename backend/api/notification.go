@@ -0,0 +1,114 @@
+package api
+
+import (
+	"strconv"
+
+	"lucky/common/mysql"
+	"lucky/common/response"
+	"lucky/middleware"
+	"lucky/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationPage 站内通知列表
+type NotificationPage struct {
+	response.Page
+	Unread int64 `json:"unread"` // 未读数
+}
+
+// GetNotifications 获取站内通知列表
+// @Summary 获取站内通知列表
+// @Tags 站内通知
+// @Produce json
+// @Param unread query bool false "只看未读"
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页条数" default(20)
+// @Success 200 {object} response.Body{data=NotificationPage{list=[]model.Notification}}
+// @Failure 401 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Security BearerAuth
+// @Router /api/user/notifications [get]
+func GetNotifications(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	unreadOnly := c.Query("unread") == "true"
+
+	notifications, total, unread, err := service.GetNotifications(mysql.DB, userID, unreadOnly, page, pageSize)
+	if err != nil {
+		response.Fail(c, err)
+		return
+	}
+
+	response.OK(c, NotificationPage{
+		Page:   response.Page{List: notifications, Total: total, Page: page, PageSize: pageSize},
+		Unread: unread,
+	})
+}
+
+// MarkNotificationRead 标记单条通知为已读
+// @Summary 标记单条通知为已读
+// @Tags 站内通知
+// @Produce json
+// @Param id path int true "通知ID"
+// @Success 200 {object} response.Body
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Security BearerAuth
+// @Router /api/user/notifications/{id}/read [put]
+func MarkNotificationRead(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	notificationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || notificationID <= 0 {
+		response.Fail(c, response.ErrInvalidParams.WithDetail("通知ID错误"))
+		return
+	}
+
+	if err := service.MarkNotificationsRead(mysql.DB, userID, notificationID); err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	response.OK(c, nil)
+}
+
+// MarkAllNotificationsRead 标记全部通知为已读
+// @Summary 标记全部通知为已读
+// @Tags 站内通知
+// @Produce json
+// @Success 200 {object} response.Body
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Security BearerAuth
+// @Router /api/user/notifications/read [put]
+func MarkAllNotificationsRead(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	if err := service.MarkNotificationsRead(mysql.DB, userID, 0); err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	response.OK(c, nil)
+}
@@ -4,7 +4,8 @@ import (
 	"net/http"
 	"strconv"
 
-	"lucky/common/mysql"
+	"lucky/common/response"
+	"lucky/middleware"
 	"lucky/model"
 	"lucky/service"
 
@@ -13,201 +14,463 @@ import (
 
 // SaveUserNumberRequest 保存用户号码请求
 type SaveUserNumberRequest struct {
-	GameCode  string            `json:"gameCode" binding:"required"`
-	RedBalls  model.NumberArray `json:"redBalls" binding:"required"`
-	BlueBalls model.NumberArray `json:"blueBalls" binding:"required"`
-	Nickname  string            `json:"nickname"`
-	Source    string            `json:"source"`
+	GameCode     string            `json:"gameCode" binding:"required"`
+	RedBalls     model.NumberArray `json:"redBalls" binding:"required"` // 红球，数字型游戏为按位排列的数字
+	BlueBalls    model.NumberArray `json:"blueBalls"`                   // 蓝球，数字型游戏为空
+	PlayType     string            `json:"playType"`                    // 玩法：数字型为direct(直选,默认)、group3(组选三)、group6(组选六)；快乐8为pick1-pick10，默认按号码个数
+	Nickname     string            `json:"nickname"`
+	Source       string            `json:"source"`
+	Multiplier   *int              `json:"multiplier"`   // 倍数，默认1倍
+	IsAdditional *bool             `json:"isAdditional"` // 是否追加（仅大乐透）
+}
+
+// GenerateNumbersRequest 缩水生成号码请求
+type GenerateNumbersRequest struct {
+	GameCode string               `json:"gameCode" binding:"required"`
+	Count    int                  `json:"count"`
+	Filter   service.NumberFilter `json:"filter"`
+	Save     bool                 `json:"save"`     // 是否直接保存到我的号码
+	Nickname string               `json:"nickname"` // 保存时使用的昵称
+}
+
+// GenerateWheelRequest 旋转矩阵生成请求
+type GenerateWheelRequest struct {
+	GameCode       string `json:"gameCode" binding:"required"`
+	RedBalls       []int  `json:"redBalls" binding:"required"`  // 候选红球
+	BlueBalls      []int  `json:"blueBalls" binding:"required"` // 每注使用的蓝球
+	MatchCount     int    `json:"matchCount"`                   // 候选号码中开出的个数
+	GuaranteeCount int    `json:"guaranteeCount"`               // 保证至少一注命中的个数
+	Save           bool   `json:"save"`                         // 是否保存为号码分组
+	GroupName      string `json:"groupName"`                    // 分组名称
+}
+
+// ImportNumbersRequest 批量导入号码请求
+type ImportNumbersRequest struct {
+	GameCode string `json:"gameCode" binding:"required"`
+	Format   string `json:"format"`                     // 内容格式：txt(默认), csv, json
+	Content  string `json:"content" binding:"required"` // 导入内容
+	GroupID  *int64 `json:"groupId"`                    // 导入到的分组
 }
 
 // UpdateUserNumberRequest 更新用户号码请求
 type UpdateUserNumberRequest struct {
-	Nickname string `json:"nickname"`
-	IsActive *bool  `json:"isActive"`
+	Nickname     string  `json:"nickname"`
+	Note         *string `json:"note"`
+	IsActive     *bool   `json:"isActive"`
+	Multiplier   *int    `json:"multiplier"`   // 倍数
+	IsAdditional *bool   `json:"isAdditional"` // 是否追加（仅大乐透）
+}
+
+// GenerateWheelResponse 旋转矩阵生成结果
+type GenerateWheelResponse struct {
+	Wheel      *service.WheelResult `json:"wheel"`
+	Group      *model.NumberGroup   `json:"group,omitempty"`      // 保存为分组时新建的分组，没有新号码时不创建
+	Saved      int                  `json:"saved,omitempty"`      // 保存为分组时新保存的号码数
+	Duplicated int                  `json:"duplicated,omitempty"` // 保存为分组时已存在而跳过的号码数
 }
 
 // SaveUserNumber 保存用户号码
+// @Summary 保存用户号码
+// @Tags 号码管理
+// @Accept json
+// @Produce json
+// @Param X-User-ID header int true "用户ID"
+// @Param request body SaveUserNumberRequest true "号码"
+// @Success 200 {object} response.Body{data=model.UserNumber}
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Failure 404 {object} response.Body
+// @Router /api/numbers/save [post]
 func SaveUserNumber(c *gin.Context) {
 	userID := c.GetHeader("X-User-ID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"code":    401,
-			"message": "未授权",
-		})
+		response.Fail(c, response.ErrUnauthorized)
 		return
 	}
 
 	var req SaveUserNumberRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    400,
-			"message": "参数错误",
-			"error":   err.Error(),
-		})
+		response.Fail(c, response.ErrInvalidParams.Wrap(err))
 		return
 	}
 
 	// 获取游戏
-	game, err := service.GetGameByCode(mysql.DB, req.GameCode)
+	game, err := service.GetGameByCode(requestDB(c), req.GameCode)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"code":    404,
-			"message": "游戏不存在",
-		})
+		response.Fail(c, response.ErrGameNotFound)
 		return
 	}
 
 	// 验证号码
 	if err := service.ValidateNumbers(game, req.RedBalls, req.BlueBalls); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    400,
-			"message": "号码格式错误",
-			"error":   err.Error(),
-		})
+		response.Fail(c, response.ErrInvalidNumbers.Wrap(err))
+		return
+	}
+	if _, err := service.ValidatePlayType(game, req.PlayType, req.RedBalls); err != nil {
+		response.Fail(c, response.ErrInvalidNumbers.Wrap(err))
 		return
 	}
 
 	userIDUint, _ := strconv.ParseUint(userID, 10, 64)
 
+	// 验证倍数和追加
+	if req.Multiplier != nil || req.IsAdditional != nil {
+		multiplier, isAdditional := 1, false
+		if req.Multiplier != nil {
+			multiplier = *req.Multiplier
+		}
+		if req.IsAdditional != nil {
+			isAdditional = *req.IsAdditional
+		}
+		if err := service.ValidateBetOptions(game, multiplier, isAdditional); err != nil {
+			response.Fail(c, response.ErrInvalidParams.Wrap(err))
+			return
+		}
+	}
+
 	// 保存用户号码
-	userNumber, err := service.SaveUserNumber(mysql.DB, userIDUint, game.ID, req.RedBalls, req.BlueBalls, req.Nickname, req.Source)
+	userNumber, _, err := service.SaveUserNumber(requestDB(c), userIDUint, game.ID, req.RedBalls, req.BlueBalls, req.PlayType, req.Nickname, req.Source)
+	if err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	if req.Multiplier != nil || req.IsAdditional != nil {
+		if err := service.SetNumberBetOptions(requestDB(c), userIDUint, uint64(userNumber.ID), req.Multiplier, req.IsAdditional); err != nil {
+			response.Fail(c, response.ErrRequestRejected.Wrap(err))
+			return
+		}
+		if req.Multiplier != nil {
+			userNumber.Multiplier = *req.Multiplier
+		}
+		if req.IsAdditional != nil {
+			userNumber.IsAdditional = *req.IsAdditional
+		}
+	}
+
+	response.Success(c, "保存成功", userNumber)
+}
+
+// GenerateNumbers 按缩水条件生成号码
+// @Summary 按缩水条件生成号码
+// @Tags 号码管理
+// @Accept json
+// @Produce json
+// @Param X-User-ID header int false "用户ID，保存时必填"
+// @Param request body GenerateNumbersRequest true "缩水条件"
+// @Success 200 {object} response.Body{data=service.FilterGenerateResult}
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Failure 404 {object} response.Body
+// @Failure 429 {object} response.Body "请求过于频繁，Retry-After 响应头为需等待的秒数"
+// @Router /api/numbers/generate [post]
+func GenerateNumbers(c *gin.Context) {
+	var req GenerateNumbersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.ErrInvalidParams.Wrap(err))
+		return
+	}
+
+	if req.Count <= 0 {
+		req.Count = 5
+	}
+	if req.Count > 100 {
+		response.Fail(c, response.ErrInvalidParams.WithDetail("单次最多生成100注"))
+		return
+	}
+
+	userID := c.GetHeader("X-User-ID")
+	if req.Save && userID == "" {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	game, err := service.GetGameByCode(requestDB(c), req.GameCode)
+	if err != nil {
+		response.Fail(c, response.ErrGameNotFound)
+		return
+	}
+
+	result, err := service.GenerateFilteredNumbers(requestDB(c), game, req.Filter, req.Count)
+	if err != nil {
+		response.FailWithData(c, response.ErrRequestRejected.Wrap(err), result)
+		return
+	}
+
+	if req.Save {
+		userIDUint, _ := strconv.ParseUint(userID, 10, 64)
+		for _, number := range result.Numbers {
+			if _, _, err := service.SaveUserNumber(requestDB(c), userIDUint, game.ID, number.RedBalls, number.BlueBalls, "", req.Nickname, "filter"); err != nil {
+				response.Fail(c, response.ErrRequestRejected.Wrap(err))
+				return
+			}
+		}
+	}
+
+	response.OK(c, result)
+}
+
+// GenerateWheel 生成旋转矩阵号码
+// @Summary 生成旋转矩阵号码
+// @Tags 号码管理
+// @Accept json
+// @Produce json
+// @Param X-User-ID header int false "用户ID，保存时必填"
+// @Param request body GenerateWheelRequest true "候选号码和保证条件"
+// @Success 200 {object} response.Body{data=GenerateWheelResponse}
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Failure 404 {object} response.Body
+// @Failure 429 {object} response.Body "请求过于频繁，Retry-After 响应头为需等待的秒数"
+// @Router /api/numbers/wheel [post]
+func GenerateWheel(c *gin.Context) {
+	var req GenerateWheelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.ErrInvalidParams.Wrap(err))
+		return
+	}
+
+	userID := c.GetHeader("X-User-ID")
+	if req.Save && userID == "" {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	game, err := service.GetGameByCode(requestDB(c), req.GameCode)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    500,
-			"message": "保存失败",
-			"error":   err.Error(),
-		})
+		response.Fail(c, response.ErrGameNotFound)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code":    200,
-		"message": "保存成功",
-		"data":    userNumber,
-	})
+	// 默认：全部红球开出时保证至少一注少中一个
+	if req.MatchCount == 0 {
+		req.MatchCount = game.RedSelectCount
+	}
+	if req.GuaranteeCount == 0 {
+		req.GuaranteeCount = req.MatchCount - 1
+	}
+
+	wheel, err := service.GenerateWheel(game, req.RedBalls, req.BlueBalls, req.MatchCount, req.GuaranteeCount)
+	if err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	data := GenerateWheelResponse{Wheel: wheel}
+	if req.Save {
+		userIDUint, _ := strconv.ParseUint(userID, 10, 64)
+		saved, err := service.SaveWheelAsGroup(requestDB(c), userIDUint, game, wheel, req.GroupName)
+		if err != nil {
+			response.Fail(c, response.ErrRequestRejected.Wrap(err))
+			return
+		}
+		data.Group, data.Saved, data.Duplicated = saved.Group, saved.Saved, saved.Duplicated
+	}
+
+	response.OK(c, data)
 }
 
 // GetMyNumbers 获取我的号码
+// @Summary 获取我的号码
+// @Tags 号码管理
+// @Produce json
+// @Param X-User-ID header int true "用户ID"
+// @Param gameCode query string false "游戏代码"
+// @Param groupId query int false "分组ID"
+// @Param tagId query int false "标签ID"
+// @Param source query string false "来源"
+// @Param isActive query bool false "是否启用"
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页条数" default(20)
+// @Success 200 {object} response.Body{data=response.Page{list=[]model.UserNumber}}
+// @Failure 401 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Router /api/numbers/my [get]
 func GetMyNumbers(c *gin.Context) {
 	userID := c.GetHeader("X-User-ID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"code":    401,
-			"message": "未授权",
-		})
+		response.Fail(c, response.ErrUnauthorized)
 		return
 	}
 
-	gameCode := c.Query("gameCode")
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
 
+	filter := service.UserNumberFilter{
+		GameCode: c.Query("gameCode"),
+		GroupID:  parseOptionalInt64(c.Query("groupId")),
+		TagID:    parseOptionalInt64(c.Query("tagId")),
+		Source:   c.Query("source"),
+	}
+	if isActive, err := strconv.ParseBool(c.Query("isActive")); err == nil {
+		filter.IsActive = &isActive
+	}
+
 	userIDUint, _ := strconv.ParseUint(userID, 10, 64)
 
-	numbers, total, err := service.GetUserNumbers(mysql.DB, userIDUint, gameCode, page, pageSize)
+	numbers, total, err := service.GetUserNumbers(requestDB(c), userIDUint, filter, page, pageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    500,
-			"message": "获取失败",
-			"error":   err.Error(),
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"code":    200,
-		"message": "success",
-		"data": gin.H{
-			"list":     numbers,
-			"total":    total,
-			"page":     page,
-			"pageSize": pageSize,
-		},
-	})
+		response.Fail(c, err)
+		return
+	}
+
+	response.OK(c, response.Page{List: numbers, Total: total, Page: page, PageSize: pageSize})
 }
 
 // UpdateUserNumber 更新用户号码
+// @Summary 更新用户号码
+// @Tags 号码管理
+// @Accept json
+// @Produce json
+// @Param X-User-ID header int true "用户ID"
+// @Param id path int true "用户号码ID"
+// @Param request body UpdateUserNumberRequest true "要修改的字段"
+// @Success 200 {object} response.Body
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Router /api/numbers/{id} [put]
 func UpdateUserNumber(c *gin.Context) {
 	userID := c.GetHeader("X-User-ID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"code":    401,
-			"message": "未授权",
-		})
+		response.Fail(c, response.ErrUnauthorized)
 		return
 	}
 
 	numberID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    400,
-			"message": "ID参数错误",
-		})
+		response.Fail(c, response.ErrInvalidParams.WithDetail("ID参数错误"))
 		return
 	}
 
 	var req UpdateUserNumberRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    400,
-			"message": "参数错误",
-			"error":   err.Error(),
-		})
+		response.Fail(c, response.ErrInvalidParams.Wrap(err))
 		return
 	}
 
 	userIDUint, _ := strconv.ParseUint(userID, 10, 64)
 
-	err = service.UpdateUserNumber(mysql.DB, userIDUint, numberID, req.Nickname, req.IsActive)
+	err = service.UpdateUserNumber(requestDB(c), userIDUint, numberID, req.Nickname, req.Note, req.IsActive)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    500,
-			"message": "更新失败",
-			"error":   err.Error(),
-		})
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code":    200,
-		"message": "更新成功",
-	})
+	if req.Multiplier != nil || req.IsAdditional != nil {
+		if err := service.SetNumberBetOptions(requestDB(c), userIDUint, numberID, req.Multiplier, req.IsAdditional); err != nil {
+			response.Fail(c, response.ErrRequestRejected.Wrap(err))
+			return
+		}
+	}
+
+	response.Success(c, "更新成功", nil)
 }
 
 // DeleteUserNumber 删除用户号码
+// @Summary 删除用户号码
+// @Tags 号码管理
+// @Produce json
+// @Param X-User-ID header int true "用户ID"
+// @Param id path int true "用户号码ID"
+// @Success 200 {object} response.Body
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Router /api/numbers/{id} [delete]
 func DeleteUserNumber(c *gin.Context) {
 	userID := c.GetHeader("X-User-ID")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"code":    401,
-			"message": "未授权",
-		})
+		response.Fail(c, response.ErrUnauthorized)
 		return
 	}
 
 	numberID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    400,
-			"message": "ID参数错误",
-		})
+		response.Fail(c, response.ErrInvalidParams.WithDetail("ID参数错误"))
 		return
 	}
 
 	userIDUint, _ := strconv.ParseUint(userID, 10, 64)
 
-	err = service.DeleteUserNumber(mysql.DB, userIDUint, numberID)
+	err = service.DeleteUserNumber(requestDB(c), userIDUint, numberID)
+	if err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	response.Success(c, "删除成功", nil)
+}
+
+// ImportNumbers 批量导入号码
+// @Summary 批量导入号码
+// @Tags 号码管理
+// @Accept json
+// @Produce json
+// @Param request body ImportNumbersRequest true "导入内容"
+// @Success 200 {object} response.Body{data=service.ImportResult}
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Failure 404 {object} response.Body
+// @Security BearerAuth
+// @Router /api/numbers/import [post]
+func ImportNumbers(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	var req ImportNumbersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.ErrInvalidParams.Wrap(err))
+		return
+	}
+
+	game, err := service.GetGameByCode(requestDB(c), req.GameCode)
+	if err != nil {
+		response.Fail(c, response.ErrGameNotFound)
+		return
+	}
+
+	result, err := service.ImportUserNumbers(requestDB(c), userID, game, req.Format, req.Content, req.GroupID)
+	if err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	response.Success(c, "导入完成", result)
+}
+
+// ExportNumbers 导出我的号码
+// @Summary 导出我的号码
+// @Tags 号码管理
+// @Produce plain,csv,json
+// @Param format query string false "导出格式" Enums(txt, csv, json) default(txt)
+// @Param gameCode query string false "游戏代码"
+// @Success 200 {file} file "号码文件"
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Security BearerAuth
+// @Router /api/numbers/export [get]
+func ExportNumbers(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	format := c.DefaultQuery("format", "txt")
+	if format != "txt" && format != "csv" && format != "json" {
+		response.Fail(c, response.ErrInvalidParams.WithDetail("不支持的导出格式"))
+		return
+	}
+
+	data, contentType, err := service.ExportUserNumbers(requestDB(c), userID, c.Query("gameCode"), format)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    500,
-			"message": "删除失败",
-			"error":   err.Error(),
-		})
+		response.Fail(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code":    200,
-		"message": "删除成功",
-	})
+	c.Header("Content-Disposition", "attachment; filename=lucky_numbers."+format)
+	c.Data(http.StatusOK, contentType, data)
 }
@@ -0,0 +1,428 @@
+package api
+
+import (
+	"strconv"
+
+	"lucky/common/mysql"
+	"lucky/common/response"
+	"lucky/middleware"
+	"lucky/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateNumberGroupRequest 创建号码分组请求
+type CreateNumberGroupRequest struct {
+	GameCode string `json:"gameCode" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	Note     string `json:"note"`
+}
+
+// UpdateNumberGroupRequest 更新号码分组请求
+type UpdateNumberGroupRequest struct {
+	Name *string `json:"name"`
+	Note *string `json:"note"`
+}
+
+// CreateNumberTagRequest 创建号码标签请求
+type CreateNumberTagRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Color string `json:"color"`
+}
+
+// BulkMoveRequest 批量移动号码请求
+type BulkMoveRequest struct {
+	NumberIDs []int64 `json:"numberIds" binding:"required"`
+	GroupID   *int64  `json:"groupId"` // 为空表示移出分组
+}
+
+// BulkTagRequest 批量标签请求
+type BulkTagRequest struct {
+	NumberIDs []int64 `json:"numberIds" binding:"required"`
+	TagIDs    []int64 `json:"tagIds" binding:"required"`
+	Remove    bool    `json:"remove"` // true表示移除标签
+}
+
+// BulkActiveRequest 批量启用/停用请求
+type BulkActiveRequest struct {
+	NumberIDs []int64 `json:"numberIds" binding:"required"`
+	IsActive  bool    `json:"isActive"`
+}
+
+// BulkResponse 批量操作结果
+type BulkResponse struct {
+	Affected int64 `json:"affected"` // 实际修改的号码数
+}
+
+// GetNumberGroups 获取号码分组列表
+// @Summary 获取号码分组列表
+// @Tags 号码分组
+// @Produce json
+// @Success 200 {object} response.Body{data=[]service.NumberGroupInfo}
+// @Failure 401 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Security BearerAuth
+// @Router /api/numbers/groups [get]
+func GetNumberGroups(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	groups, err := service.GetNumberGroups(mysql.DB, userID)
+	if err != nil {
+		response.Fail(c, err)
+		return
+	}
+
+	response.OK(c, groups)
+}
+
+// CreateNumberGroup 创建号码分组
+// @Summary 创建号码分组
+// @Tags 号码分组
+// @Accept json
+// @Produce json
+// @Param request body CreateNumberGroupRequest true "分组"
+// @Success 200 {object} response.Body{data=model.NumberGroup}
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Failure 404 {object} response.Body
+// @Security BearerAuth
+// @Router /api/numbers/groups [post]
+func CreateNumberGroup(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	var req CreateNumberGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.ErrInvalidParams.Wrap(err))
+		return
+	}
+
+	game, err := service.GetGameByCode(mysql.DB, req.GameCode)
+	if err != nil {
+		response.Fail(c, response.ErrGameNotFound)
+		return
+	}
+
+	group, err := service.CreateNumberGroup(mysql.DB, userID, game.ID, req.Name, req.Note)
+	if err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	response.Success(c, "创建成功", group)
+}
+
+// UpdateNumberGroup 更新号码分组
+// @Summary 更新号码分组
+// @Tags 号码分组
+// @Accept json
+// @Produce json
+// @Param id path int true "分组ID"
+// @Param request body UpdateNumberGroupRequest true "要修改的字段"
+// @Success 200 {object} response.Body
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Security BearerAuth
+// @Router /api/numbers/groups/{id} [put]
+func UpdateNumberGroup(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	groupID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Fail(c, response.ErrInvalidParams.WithDetail("ID参数错误"))
+		return
+	}
+
+	var req UpdateNumberGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.ErrInvalidParams.Wrap(err))
+		return
+	}
+
+	if err := service.UpdateNumberGroup(mysql.DB, userID, groupID, req.Name, req.Note); err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	response.Success(c, "更新成功", nil)
+}
+
+// DeleteNumberGroup 删除号码分组
+// @Summary 删除号码分组
+// @Tags 号码分组
+// @Produce json
+// @Param id path int true "分组ID"
+// @Success 200 {object} response.Body
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Security BearerAuth
+// @Router /api/numbers/groups/{id} [delete]
+func DeleteNumberGroup(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	groupID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Fail(c, response.ErrInvalidParams.WithDetail("ID参数错误"))
+		return
+	}
+
+	if err := service.DeleteNumberGroup(mysql.DB, userID, groupID); err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	response.Success(c, "删除成功", nil)
+}
+
+// GetGroupWinningSummary 获取分组中奖汇总
+// @Summary 获取分组中奖汇总
+// @Tags 号码分组
+// @Produce json
+// @Param id path int true "分组ID"
+// @Param periodCount query int false "核对近N期，1-100" default(15)
+// @Success 200 {object} response.Body{data=service.GroupWinningSummary}
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Failure 404 {object} response.Body
+// @Security BearerAuth
+// @Router /api/numbers/groups/{id}/summary [get]
+func GetGroupWinningSummary(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	groupID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Fail(c, response.ErrInvalidParams.WithDetail("ID参数错误"))
+		return
+	}
+
+	periodCount, _ := strconv.Atoi(c.DefaultQuery("periodCount", "15"))
+	if periodCount <= 0 || periodCount > 100 {
+		periodCount = 15
+	}
+
+	summary, err := service.GetGroupWinningSummary(mysql.DB, userID, groupID, periodCount)
+	if err != nil {
+		response.Fail(c, err)
+		return
+	}
+
+	response.OK(c, summary)
+}
+
+// GetNumberTags 获取号码标签列表
+// @Summary 获取号码标签列表
+// @Tags 号码标签
+// @Produce json
+// @Success 200 {object} response.Body{data=[]model.NumberTag}
+// @Failure 401 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Security BearerAuth
+// @Router /api/numbers/tags [get]
+func GetNumberTags(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	tags, err := service.GetNumberTags(mysql.DB, userID)
+	if err != nil {
+		response.Fail(c, err)
+		return
+	}
+
+	response.OK(c, tags)
+}
+
+// CreateNumberTag 创建号码标签
+// @Summary 创建号码标签
+// @Tags 号码标签
+// @Accept json
+// @Produce json
+// @Param request body CreateNumberTagRequest true "标签"
+// @Success 200 {object} response.Body{data=model.NumberTag}
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Security BearerAuth
+// @Router /api/numbers/tags [post]
+func CreateNumberTag(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	var req CreateNumberTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.ErrInvalidParams.Wrap(err))
+		return
+	}
+
+	tag, err := service.CreateNumberTag(mysql.DB, userID, req.Name, req.Color)
+	if err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	response.Success(c, "创建成功", tag)
+}
+
+// DeleteNumberTag 删除号码标签
+// @Summary 删除号码标签
+// @Tags 号码标签
+// @Produce json
+// @Param id path int true "标签ID"
+// @Success 200 {object} response.Body
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Security BearerAuth
+// @Router /api/numbers/tags/{id} [delete]
+func DeleteNumberTag(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	tagID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Fail(c, response.ErrInvalidParams.WithDetail("ID参数错误"))
+		return
+	}
+
+	if err := service.DeleteNumberTag(mysql.DB, userID, tagID); err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	response.Success(c, "删除成功", nil)
+}
+
+// BulkMoveNumbers 批量移动号码到分组
+// @Summary 批量移动号码到分组
+// @Tags 号码管理
+// @Accept json
+// @Produce json
+// @Param request body BulkMoveRequest true "号码和目标分组"
+// @Success 200 {object} response.Body{data=BulkResponse}
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Security BearerAuth
+// @Router /api/numbers/bulk/move [post]
+func BulkMoveNumbers(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	var req BulkMoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.ErrInvalidParams.Wrap(err))
+		return
+	}
+
+	moved, err := service.BulkMoveNumbers(mysql.DB, userID, req.NumberIDs, req.GroupID)
+	if err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	response.Success(c, "移动成功", BulkResponse{Affected: moved})
+}
+
+// BulkTagNumbers 批量添加或移除号码标签
+// @Summary 批量添加或移除号码标签
+// @Tags 号码管理
+// @Accept json
+// @Produce json
+// @Param request body BulkTagRequest true "号码和标签"
+// @Success 200 {object} response.Body
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Security BearerAuth
+// @Router /api/numbers/bulk/tag [post]
+func BulkTagNumbers(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	var req BulkTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.ErrInvalidParams.Wrap(err))
+		return
+	}
+
+	if err := service.BulkTagNumbers(mysql.DB, userID, req.NumberIDs, req.TagIDs, req.Remove); err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	response.Success(c, "操作成功", nil)
+}
+
+// BulkSetNumbersActive 批量启用或停用号码
+// @Summary 批量启用或停用号码
+// @Tags 号码管理
+// @Accept json
+// @Produce json
+// @Param request body BulkActiveRequest true "号码和状态"
+// @Success 200 {object} response.Body{data=BulkResponse}
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Security BearerAuth
+// @Router /api/numbers/bulk/active [post]
+func BulkSetNumbersActive(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	var req BulkActiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.ErrInvalidParams.Wrap(err))
+		return
+	}
+
+	updated, err := service.BulkSetNumbersActive(mysql.DB, userID, req.NumberIDs, req.IsActive)
+	if err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	response.Success(c, "更新成功", BulkResponse{Affected: updated})
+}
+
+// parseOptionalInt64 解析可选的整数查询参数，为空或非法时返回nil
+func parseOptionalInt64(value string) *int64 {
+	if value == "" {
+		return nil
+	}
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"strconv"
+
+	"lucky/common/mysql"
+	"lucky/common/response"
+	"lucky/middleware"
+	"lucky/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateNumberPlanRequest 创建追号计划请求
+type CreateNumberPlanRequest struct {
+	NumberID     int64  `json:"numberId" binding:"required"`     // 追号的用户号码ID
+	StartPeriod  string `json:"startPeriod"`                     // 起始期号，为空时从下一期开始
+	TotalPeriods int    `json:"totalPeriods" binding:"required"` // 追号期数
+	StopAfterWin bool   `json:"stopAfterWin"`                    // 中奖后停止追号
+	Multiplier   int    `json:"multiplier"`                      // 每期倍数，为空时沿用号码设置
+	IsAdditional *bool  `json:"isAdditional"`                    // 是否追加（仅大乐透），为空时沿用号码设置
+}
+
+// CreateNumberPlan 创建追号计划
+// @Summary 创建追号计划
+// @Tags 追号计划
+// @Accept json
+// @Produce json
+// @Param request body CreateNumberPlanRequest true "追号计划"
+// @Success 200 {object} response.Body{data=service.NumberPlanInfo}
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Security BearerAuth
+// @Router /api/plans [post]
+func CreateNumberPlan(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	var req CreateNumberPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.ErrInvalidParams.Wrap(err))
+		return
+	}
+
+	plan, err := service.CreateNumberPlan(mysql.DB, userID, req.NumberID, req.StartPeriod, req.TotalPeriods, req.StopAfterWin, req.Multiplier, req.IsAdditional)
+	if err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	response.Success(c, "创建成功", plan)
+}
+
+// GetNumberPlans 获取追号计划列表
+// @Summary 获取追号计划列表
+// @Tags 追号计划
+// @Produce json
+// @Param status query string false "计划状态"
+// @Success 200 {object} response.Body{data=[]service.NumberPlanInfo}
+// @Failure 401 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Security BearerAuth
+// @Router /api/plans [get]
+func GetNumberPlans(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	plans, err := service.GetNumberPlans(mysql.DB, userID, c.Query("status"))
+	if err != nil {
+		response.Fail(c, err)
+		return
+	}
+
+	response.OK(c, plans)
+}
+
+// GetNumberPlanDetail 获取追号计划详情
+// @Summary 获取追号计划详情
+// @Tags 追号计划
+// @Produce json
+// @Param id path int true "追号计划ID"
+// @Success 200 {object} response.Body{data=service.NumberPlanDetail}
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Failure 404 {object} response.Body
+// @Security BearerAuth
+// @Router /api/plans/{id} [get]
+func GetNumberPlanDetail(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	planID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Fail(c, response.ErrInvalidParams.WithDetail("ID参数错误"))
+		return
+	}
+
+	detail, err := service.GetNumberPlanDetail(mysql.DB, userID, planID)
+	if err != nil {
+		response.Fail(c, response.ErrNotFound.Wrap(err))
+		return
+	}
+
+	response.OK(c, detail)
+}
+
+// CancelNumberPlan 取消追号计划
+// @Summary 取消追号计划
+// @Tags 追号计划
+// @Produce json
+// @Param id path int true "追号计划ID"
+// @Success 200 {object} response.Body
+// @Failure 400 {object} response.Body
+// @Failure 401 {object} response.Body
+// @Security BearerAuth
+// @Router /api/plans/{id}/cancel [post]
+func CancelNumberPlan(c *gin.Context) {
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		response.Fail(c, response.ErrUnauthorized)
+		return
+	}
+
+	planID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Fail(c, response.ErrInvalidParams.WithDetail("ID参数错误"))
+		return
+	}
+
+	if err := service.CancelNumberPlan(mysql.DB, userID, planID); err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	response.Success(c, "取消成功", nil)
+}
@@ -0,0 +1,1067 @@
+// Code generated by cmd/openapi; DO NOT EDIT.
+
+package api
+
+import (
+	"lucky/common/openapi"
+	"lucky/common/response"
+	"lucky/model"
+	"lucky/service"
+)
+
+// openAPIRoutes 处理函数注释中的接口定义
+var openAPIRoutes = []openapi.Route{
+	{
+		Method:  "get",
+		Path:    "/api/admin/games",
+		Handler: "AdminListGames",
+		Summary: "获取全部游戏（含未启用）",
+		Tags:    []string{"管理后台"},
+		Produce: []string{"application/json"},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[[]model.LotteryGame]()))},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 403, Body: openapi.Ref[response.Body]()},
+			{Status: 500, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "put",
+		Path:    "/api/admin/games/{gameCode}/status",
+		Handler: "AdminSetGameStatus",
+		Summary: "启用或停用游戏",
+		Tags:    []string{"管理后台"},
+		Accept:  []string{"application/json"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "gameCode", In: "path", Type: "string", Required: true, Description: "游戏代码"},
+			{Name: "request", In: "body", Body: openapi.Ref[SetGameStatusRequest](), Required: true, Description: "状态和原因"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[model.LotteryGame]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 403, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "post",
+		Path:    "/api/admin/draws/{gameCode}",
+		Handler: "AdminCreateDrawResult",
+		Summary: "手动录入开奖结果",
+		Tags:    []string{"管理后台"},
+		Accept:  []string{"application/json"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "gameCode", In: "path", Type: "string", Required: true, Description: "游戏代码"},
+			{Name: "request", In: "body", Body: openapi.Ref[AdminDrawResultRequest](), Required: true, Description: "开奖结果"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[model.DrawResult]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 403, Body: openapi.Ref[response.Body]()},
+			{Status: 409, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "put",
+		Path:    "/api/admin/draws/{gameCode}/{period}",
+		Handler: "AdminCorrectDrawResult",
+		Summary: "更正开奖结果并重新核对中奖记录",
+		Tags:    []string{"管理后台"},
+		Accept:  []string{"application/json"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "gameCode", In: "path", Type: "string", Required: true, Description: "游戏代码"},
+			{Name: "period", In: "path", Type: "string", Required: true, Description: "期号"},
+			{Name: "request", In: "body", Body: openapi.Ref[AdminDrawResultRequest](), Required: true, Description: "更正后的开奖结果和原因"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[service.DrawCorrectionResult]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 403, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "get",
+		Path:    "/api/admin/draws/{gameCode}/{period}/revisions",
+		Handler: "AdminGetDrawRevisions",
+		Summary: "获取开奖结果的更正历史",
+		Tags:    []string{"管理后台"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "gameCode", In: "path", Type: "string", Required: true, Description: "游戏代码"},
+			{Name: "period", In: "path", Type: "string", Required: true, Description: "期号"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[DrawRevisionsResponse]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 403, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "post",
+		Path:    "/api/admin/crawl/{gameCode}",
+		Handler: "AdminTriggerCrawl",
+		Summary: "触发抓取最新一期或回补历史",
+		Tags:    []string{"管理后台"},
+		Accept:  []string{"application/json"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "gameCode", In: "path", Type: "string", Required: true, Description: "游戏代码"},
+			{Name: "request", In: "body", Body: openapi.Ref[TriggerCrawlRequest](), Required: true, Description: "抓取模式"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[model.CrawlRun]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 403, Body: openapi.Ref[response.Body]()},
+			{Status: 429, Description: "请求过于频繁，Retry-After 响应头为需等待的秒数", Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "get",
+		Path:    "/api/admin/crawl-runs",
+		Handler: "AdminListCrawlRuns",
+		Summary: "获取抓取记录",
+		Tags:    []string{"管理后台"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "gameCode", In: "query", Type: "string", Description: "游戏代码"},
+			{Name: "page", In: "query", Type: "integer", Description: "页码", Default: "1"},
+			{Name: "pageSize", In: "query", Type: "integer", Description: "每页条数", Default: "20"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[response.Page](openapi.Field("list", openapi.Ref[[]model.CrawlRun]()))))},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 403, Body: openapi.Ref[response.Body]()},
+			{Status: 500, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "get",
+		Path:    "/api/admin/jobs",
+		Handler: "AdminListJobs",
+		Summary: "获取已注册的任务及参数定义",
+		Tags:    []string{"管理后台"},
+		Produce: []string{"application/json"},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[[]service.Job]()))},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 403, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "post",
+		Path:    "/api/admin/jobs/{name}/run",
+		Handler: "AdminRunJob",
+		Summary: "执行任务",
+		Tags:    []string{"管理后台"},
+		Accept:  []string{"application/json"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "name", In: "path", Type: "string", Required: true, Description: "任务名称"},
+			{Name: "request", In: "body", Body: openapi.Ref[RunJobRequest](), Required: true, Description: "任务参数"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[model.JobRun]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 403, Body: openapi.Ref[response.Body]()},
+			{Status: 409, Body: openapi.Ref[response.Body]()},
+			{Status: 500, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "get",
+		Path:    "/api/admin/job-runs",
+		Handler: "AdminListJobRuns",
+		Summary: "获取任务执行记录",
+		Tags:    []string{"管理后台"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "jobName", In: "query", Type: "string", Description: "任务名称"},
+			{Name: "status", In: "query", Type: "string", Description: "执行状态"},
+			{Name: "page", In: "query", Type: "integer", Description: "页码", Default: "1"},
+			{Name: "pageSize", In: "query", Type: "integer", Description: "每页条数", Default: "20"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[response.Page](openapi.Field("list", openapi.Ref[[]model.JobRun]()))))},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 403, Body: openapi.Ref[response.Body]()},
+			{Status: 500, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "get",
+		Path:    "/api/admin/audit-logs",
+		Handler: "AdminListAuditLogs",
+		Summary: "获取管理操作审计日志",
+		Tags:    []string{"管理后台"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "action", In: "query", Type: "string", Description: "操作类型"},
+			{Name: "page", In: "query", Type: "integer", Description: "页码", Default: "1"},
+			{Name: "pageSize", In: "query", Type: "integer", Description: "每页条数", Default: "20"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[response.Page](openapi.Field("list", openapi.Ref[[]model.AdminAuditLog]()))))},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 403, Body: openapi.Ref[response.Body]()},
+			{Status: 500, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "put",
+		Path:    "/api/admin/users/{id}/role",
+		Handler: "AdminSetUserRole",
+		Summary: "修改用户角色",
+		Tags:    []string{"管理后台"},
+		Accept:  []string{"application/json"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "id", In: "path", Type: "integer", Required: true, Description: "用户ID"},
+			{Name: "request", In: "body", Body: openapi.Ref[SetUserRoleRequest](), Required: true, Description: "角色和原因"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body]()},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 403, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "post",
+		Path:        "/api/auth/wxlogin",
+		Handler:     "WxLogin",
+		Summary:     "微信小程序登录",
+		Description: "用 code 换取 openid，首次登录自动注册，返回 JWT",
+		Tags:        []string{"认证"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "request", In: "body", Body: openapi.Ref[WxLoginRequest](), Required: true, Description: "wx.login 获取的 code 及用户信息"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[WxLoginResponse]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 429, Description: "请求过于频繁，Retry-After 响应头为需等待的秒数", Body: openapi.Ref[response.Body]()},
+			{Status: 500, Body: openapi.Ref[response.Body]()},
+			{Status: 502, Body: openapi.Ref[response.Body]()},
+		},
+	},
+	{
+		Method:  "post",
+		Path:    "/api/crawler/crawl/{gameCode}",
+		Handler: "CrawlLatestHandler",
+		Summary: "抓取并保存最新开奖数据",
+		Tags:    []string{"数据抓取"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "gameCode", In: "path", Type: "string", Required: true, Description: "游戏代码"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[model.JobRun]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 403, Body: openapi.Ref[response.Body]()},
+			{Status: 409, Body: openapi.Ref[response.Body]()},
+			{Status: 429, Description: "请求过于频繁，Retry-After 响应头为需等待的秒数", Body: openapi.Ref[response.Body]()},
+			{Status: 502, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "get",
+		Path:    "/api/crawler/test/{gameCode}",
+		Handler: "TestCrawlHandler",
+		Summary: "测试抓取最新开奖数据（不保存）",
+		Tags:    []string{"数据抓取"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "gameCode", In: "path", Type: "string", Required: true, Description: "游戏代码"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[service.DrawResult]()))},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 403, Body: openapi.Ref[response.Body]()},
+			{Status: 429, Description: "请求过于频繁，Retry-After 响应头为需等待的秒数", Body: openapi.Ref[response.Body]()},
+			{Status: 502, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "get",
+		Path:    "/api/games",
+		Handler: "GetGameList",
+		Summary: "获取启用的游戏列表",
+		Tags:    []string{"彩票游戏"},
+		Produce: []string{"application/json"},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[[]model.LotteryGame]()))},
+			{Status: 500, Body: openapi.Ref[response.Body]()},
+		},
+	},
+	{
+		Method:  "get",
+		Path:    "/api/games/{gameCode}",
+		Handler: "GetGameDetail",
+		Summary: "获取游戏详情",
+		Tags:    []string{"彩票游戏"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "gameCode", In: "path", Type: "string", Required: true, Description: "游戏代码"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[model.LotteryGame]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 404, Body: openapi.Ref[response.Body]()},
+		},
+	},
+	{
+		Method:  "get",
+		Path:    "/api/user/ledger",
+		Handler: "GetLedgerSummary",
+		Summary: "获取购彩账本汇总",
+		Tags:    []string{"购彩账本"},
+		Produce: []string{"application/json"},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[service.LedgerSummary]()))},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 500, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "get",
+		Path:    "/api/user/ledger/purchases",
+		Handler: "GetPurchases",
+		Summary: "获取购彩记录列表",
+		Tags:    []string{"购彩账本"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "page", In: "query", Type: "integer", Description: "页码", Default: "1"},
+			{Name: "pageSize", In: "query", Type: "integer", Description: "每页条数", Default: "20"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[response.Page](openapi.Field("list", openapi.Ref[[]model.Purchase]()))))},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 500, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "post",
+		Path:    "/api/user/ledger/purchases",
+		Handler: "CreatePurchase",
+		Summary: "录入购彩记录",
+		Tags:    []string{"购彩账本"},
+		Accept:  []string{"application/json"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "request", In: "body", Body: openapi.Ref[CreatePurchaseRequest](), Required: true, Description: "购彩记录"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[model.Purchase]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 404, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "delete",
+		Path:    "/api/user/ledger/purchases/{id}",
+		Handler: "DeletePurchase",
+		Summary: "删除购彩记录",
+		Tags:    []string{"购彩账本"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "id", In: "path", Type: "integer", Required: true, Description: "购彩记录ID"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body]()},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "get",
+		Path:        "/api/missing",
+		Handler:     "GetMissingData",
+		Summary:     "获取彩票号码遗漏数据",
+		Description: "大乐透(dlt)和双色球(ssq)取自500彩票网，其余选号型游戏和快乐8按已保存的开奖结果统计，支持10期、30期、50期数据",
+		Tags:        []string{"遗漏数据"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "gameCode", In: "query", Type: "string", Required: true, Description: "游戏代码"},
+			{Name: "periodCount", In: "query", Type: "integer", Required: true, Description: "期数", Enum: []string{"10", "30", "50"}},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[MissingDataResponse]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 429, Description: "请求过于频繁，Retry-After 响应头为需等待的秒数", Body: openapi.Ref[response.Body]()},
+			{Status: 500, Body: openapi.Ref[response.Body]()},
+		},
+	},
+	{
+		Method:      "get",
+		Path:        "/api/missing/batch",
+		Handler:     "GetMissingDataBatch",
+		Summary:     "批量获取多个期数的遗漏数据",
+		Description: "一次性获取指定游戏的多个期数遗漏数据",
+		Tags:        []string{"遗漏数据"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "gameCode", In: "query", Type: "string", Required: true, Description: "游戏代码"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[map[string]MissingDataResponse]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 429, Description: "请求过于频繁，Retry-After 响应头为需等待的秒数", Body: openapi.Ref[response.Body]()},
+			{Status: 500, Body: openapi.Ref[response.Body]()},
+		},
+	},
+	{
+		Method:  "get",
+		Path:    "/api/user/notifications",
+		Handler: "GetNotifications",
+		Summary: "获取站内通知列表",
+		Tags:    []string{"站内通知"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "unread", In: "query", Type: "boolean", Description: "只看未读"},
+			{Name: "page", In: "query", Type: "integer", Description: "页码", Default: "1"},
+			{Name: "pageSize", In: "query", Type: "integer", Description: "每页条数", Default: "20"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[NotificationPage](openapi.Field("list", openapi.Ref[[]model.Notification]()))))},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 500, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "put",
+		Path:    "/api/user/notifications/{id}/read",
+		Handler: "MarkNotificationRead",
+		Summary: "标记单条通知为已读",
+		Tags:    []string{"站内通知"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "id", In: "path", Type: "integer", Required: true, Description: "通知ID"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body]()},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "put",
+		Path:    "/api/user/notifications/read",
+		Handler: "MarkAllNotificationsRead",
+		Summary: "标记全部通知为已读",
+		Tags:    []string{"站内通知"},
+		Produce: []string{"application/json"},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body]()},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "post",
+		Path:    "/api/numbers/save",
+		Handler: "SaveUserNumber",
+		Summary: "保存用户号码",
+		Tags:    []string{"号码管理"},
+		Accept:  []string{"application/json"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "X-User-ID", In: "header", Type: "integer", Required: true, Description: "用户ID"},
+			{Name: "request", In: "body", Body: openapi.Ref[SaveUserNumberRequest](), Required: true, Description: "号码"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[model.UserNumber]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 404, Body: openapi.Ref[response.Body]()},
+		},
+	},
+	{
+		Method:  "post",
+		Path:    "/api/numbers/generate",
+		Handler: "GenerateNumbers",
+		Summary: "按缩水条件生成号码",
+		Tags:    []string{"号码管理"},
+		Accept:  []string{"application/json"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "X-User-ID", In: "header", Type: "integer", Description: "用户ID，保存时必填"},
+			{Name: "request", In: "body", Body: openapi.Ref[GenerateNumbersRequest](), Required: true, Description: "缩水条件"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[service.FilterGenerateResult]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 404, Body: openapi.Ref[response.Body]()},
+			{Status: 429, Description: "请求过于频繁，Retry-After 响应头为需等待的秒数", Body: openapi.Ref[response.Body]()},
+		},
+	},
+	{
+		Method:  "post",
+		Path:    "/api/numbers/wheel",
+		Handler: "GenerateWheel",
+		Summary: "生成旋转矩阵号码",
+		Tags:    []string{"号码管理"},
+		Accept:  []string{"application/json"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "X-User-ID", In: "header", Type: "integer", Description: "用户ID，保存时必填"},
+			{Name: "request", In: "body", Body: openapi.Ref[GenerateWheelRequest](), Required: true, Description: "候选号码和保证条件"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[GenerateWheelResponse]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 404, Body: openapi.Ref[response.Body]()},
+			{Status: 429, Description: "请求过于频繁，Retry-After 响应头为需等待的秒数", Body: openapi.Ref[response.Body]()},
+		},
+	},
+	{
+		Method:  "get",
+		Path:    "/api/numbers/my",
+		Handler: "GetMyNumbers",
+		Summary: "获取我的号码",
+		Tags:    []string{"号码管理"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "X-User-ID", In: "header", Type: "integer", Required: true, Description: "用户ID"},
+			{Name: "gameCode", In: "query", Type: "string", Description: "游戏代码"},
+			{Name: "groupId", In: "query", Type: "integer", Description: "分组ID"},
+			{Name: "tagId", In: "query", Type: "integer", Description: "标签ID"},
+			{Name: "source", In: "query", Type: "string", Description: "来源"},
+			{Name: "isActive", In: "query", Type: "boolean", Description: "是否启用"},
+			{Name: "page", In: "query", Type: "integer", Description: "页码", Default: "1"},
+			{Name: "pageSize", In: "query", Type: "integer", Description: "每页条数", Default: "20"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[response.Page](openapi.Field("list", openapi.Ref[[]model.UserNumber]()))))},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 500, Body: openapi.Ref[response.Body]()},
+		},
+	},
+	{
+		Method:  "put",
+		Path:    "/api/numbers/{id}",
+		Handler: "UpdateUserNumber",
+		Summary: "更新用户号码",
+		Tags:    []string{"号码管理"},
+		Accept:  []string{"application/json"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "X-User-ID", In: "header", Type: "integer", Required: true, Description: "用户ID"},
+			{Name: "id", In: "path", Type: "integer", Required: true, Description: "用户号码ID"},
+			{Name: "request", In: "body", Body: openapi.Ref[UpdateUserNumberRequest](), Required: true, Description: "要修改的字段"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body]()},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+		},
+	},
+	{
+		Method:  "delete",
+		Path:    "/api/numbers/{id}",
+		Handler: "DeleteUserNumber",
+		Summary: "删除用户号码",
+		Tags:    []string{"号码管理"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "X-User-ID", In: "header", Type: "integer", Required: true, Description: "用户ID"},
+			{Name: "id", In: "path", Type: "integer", Required: true, Description: "用户号码ID"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body]()},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+		},
+	},
+	{
+		Method:  "post",
+		Path:    "/api/numbers/import",
+		Handler: "ImportNumbers",
+		Summary: "批量导入号码",
+		Tags:    []string{"号码管理"},
+		Accept:  []string{"application/json"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "request", In: "body", Body: openapi.Ref[ImportNumbersRequest](), Required: true, Description: "导入内容"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[service.ImportResult]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 404, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "get",
+		Path:    "/api/numbers/export",
+		Handler: "ExportNumbers",
+		Summary: "导出我的号码",
+		Tags:    []string{"号码管理"},
+		Produce: []string{"text/plain", "text/csv", "application/json"},
+		Params: []openapi.Param{
+			{Name: "format", In: "query", Type: "string", Description: "导出格式", Enum: []string{"txt", "csv", "json"}, Default: "txt"},
+			{Name: "gameCode", In: "query", Type: "string", Description: "游戏代码"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Description: "号码文件", Body: openapi.File()},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 500, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "get",
+		Path:    "/api/numbers/groups",
+		Handler: "GetNumberGroups",
+		Summary: "获取号码分组列表",
+		Tags:    []string{"号码分组"},
+		Produce: []string{"application/json"},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[[]service.NumberGroupInfo]()))},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 500, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "post",
+		Path:    "/api/numbers/groups",
+		Handler: "CreateNumberGroup",
+		Summary: "创建号码分组",
+		Tags:    []string{"号码分组"},
+		Accept:  []string{"application/json"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "request", In: "body", Body: openapi.Ref[CreateNumberGroupRequest](), Required: true, Description: "分组"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[model.NumberGroup]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 404, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "put",
+		Path:    "/api/numbers/groups/{id}",
+		Handler: "UpdateNumberGroup",
+		Summary: "更新号码分组",
+		Tags:    []string{"号码分组"},
+		Accept:  []string{"application/json"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "id", In: "path", Type: "integer", Required: true, Description: "分组ID"},
+			{Name: "request", In: "body", Body: openapi.Ref[UpdateNumberGroupRequest](), Required: true, Description: "要修改的字段"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body]()},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "delete",
+		Path:    "/api/numbers/groups/{id}",
+		Handler: "DeleteNumberGroup",
+		Summary: "删除号码分组",
+		Tags:    []string{"号码分组"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "id", In: "path", Type: "integer", Required: true, Description: "分组ID"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body]()},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "get",
+		Path:    "/api/numbers/groups/{id}/summary",
+		Handler: "GetGroupWinningSummary",
+		Summary: "获取分组中奖汇总",
+		Tags:    []string{"号码分组"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "id", In: "path", Type: "integer", Required: true, Description: "分组ID"},
+			{Name: "periodCount", In: "query", Type: "integer", Description: "核对近N期，1-100", Default: "15"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[service.GroupWinningSummary]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 404, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "get",
+		Path:    "/api/numbers/tags",
+		Handler: "GetNumberTags",
+		Summary: "获取号码标签列表",
+		Tags:    []string{"号码标签"},
+		Produce: []string{"application/json"},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[[]model.NumberTag]()))},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 500, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "post",
+		Path:    "/api/numbers/tags",
+		Handler: "CreateNumberTag",
+		Summary: "创建号码标签",
+		Tags:    []string{"号码标签"},
+		Accept:  []string{"application/json"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "request", In: "body", Body: openapi.Ref[CreateNumberTagRequest](), Required: true, Description: "标签"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[model.NumberTag]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "delete",
+		Path:    "/api/numbers/tags/{id}",
+		Handler: "DeleteNumberTag",
+		Summary: "删除号码标签",
+		Tags:    []string{"号码标签"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "id", In: "path", Type: "integer", Required: true, Description: "标签ID"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body]()},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "post",
+		Path:    "/api/numbers/bulk/move",
+		Handler: "BulkMoveNumbers",
+		Summary: "批量移动号码到分组",
+		Tags:    []string{"号码管理"},
+		Accept:  []string{"application/json"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "request", In: "body", Body: openapi.Ref[BulkMoveRequest](), Required: true, Description: "号码和目标分组"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[BulkResponse]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "post",
+		Path:    "/api/numbers/bulk/tag",
+		Handler: "BulkTagNumbers",
+		Summary: "批量添加或移除号码标签",
+		Tags:    []string{"号码管理"},
+		Accept:  []string{"application/json"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "request", In: "body", Body: openapi.Ref[BulkTagRequest](), Required: true, Description: "号码和标签"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body]()},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "post",
+		Path:    "/api/numbers/bulk/active",
+		Handler: "BulkSetNumbersActive",
+		Summary: "批量启用或停用号码",
+		Tags:    []string{"号码管理"},
+		Accept:  []string{"application/json"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "request", In: "body", Body: openapi.Ref[BulkActiveRequest](), Required: true, Description: "号码和状态"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[BulkResponse]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "post",
+		Path:    "/api/plans",
+		Handler: "CreateNumberPlan",
+		Summary: "创建追号计划",
+		Tags:    []string{"追号计划"},
+		Accept:  []string{"application/json"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "request", In: "body", Body: openapi.Ref[CreateNumberPlanRequest](), Required: true, Description: "追号计划"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[service.NumberPlanInfo]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "get",
+		Path:    "/api/plans",
+		Handler: "GetNumberPlans",
+		Summary: "获取追号计划列表",
+		Tags:    []string{"追号计划"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "status", In: "query", Type: "string", Description: "计划状态"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[[]service.NumberPlanInfo]()))},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 500, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "get",
+		Path:    "/api/plans/{id}",
+		Handler: "GetNumberPlanDetail",
+		Summary: "获取追号计划详情",
+		Tags:    []string{"追号计划"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "id", In: "path", Type: "integer", Required: true, Description: "追号计划ID"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[service.NumberPlanDetail]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+			{Status: 404, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "post",
+		Path:    "/api/plans/{id}/cancel",
+		Handler: "CancelNumberPlan",
+		Summary: "取消追号计划",
+		Tags:    []string{"追号计划"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "id", In: "path", Type: "integer", Required: true, Description: "追号计划ID"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body]()},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:  "get",
+		Path:    "/api/results/{gameCode}",
+		Handler: "GetDrawResults",
+		Summary: "获取开奖结果列表",
+		Tags:    []string{"开奖结果"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "gameCode", In: "path", Type: "string", Required: true, Description: "游戏代码"},
+			{Name: "page", In: "query", Type: "integer", Description: "页码", Default: "1"},
+			{Name: "pageSize", In: "query", Type: "integer", Description: "每页条数", Default: "20"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[response.Page](openapi.Field("list", openapi.Ref[[]model.DrawResult]()))))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 500, Body: openapi.Ref[response.Body]()},
+		},
+	},
+	{
+		Method:  "get",
+		Path:    "/api/results/{gameCode}/{period}",
+		Handler: "GetDrawResultDetail",
+		Summary: "获取开奖结果详情",
+		Tags:    []string{"开奖结果"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "gameCode", In: "path", Type: "string", Required: true, Description: "游戏代码"},
+			{Name: "period", In: "path", Type: "string", Required: true, Description: "期号"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[model.DrawResult]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 404, Body: openapi.Ref[response.Body]()},
+		},
+	},
+	{
+		Method:  "get",
+		Path:    "/api/results/distribution/{gameCode}",
+		Handler: "GetNumberDistribution",
+		Summary: "获取号码分布",
+		Tags:    []string{"开奖结果"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "gameCode", In: "path", Type: "string", Required: true, Description: "游戏代码"},
+			{Name: "periodCount", In: "query", Type: "integer", Description: "统计期数", Enum: []string{"10", "30", "50"}, Default: "30"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[map[string][]service.NumberFrequency]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 500, Body: openapi.Ref[response.Body]()},
+		},
+	},
+	{
+		Method:  "get",
+		Path:    "/api/results/trend/{gameCode}",
+		Handler: "GetDigitTrend",
+		Summary: "获取数字型游戏按位走势",
+		Tags:    []string{"开奖结果"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "gameCode", In: "path", Type: "string", Required: true, Description: "游戏代码"},
+			{Name: "periodCount", In: "query", Type: "integer", Description: "统计期数", Enum: []string{"10", "30", "50", "100"}, Default: "30"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[service.DigitTrend]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+		},
+	},
+	{
+		Method:      "get",
+		Path:        "/api/results/missing/{gameCode}",
+		Handler:     "GetNumberMissing",
+		Summary:     "按开奖结果统计号码出现次数和遗漏",
+		Description: "适用于选号型游戏和快乐8，数字型游戏请使用按位走势",
+		Tags:        []string{"开奖结果"},
+		Produce:     []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "gameCode", In: "path", Type: "string", Required: true, Description: "游戏代码"},
+			{Name: "periodCount", In: "query", Type: "integer", Description: "统计期数", Enum: []string{"10", "30", "50", "100"}, Default: "30"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[service.NumberMissingStats]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+		},
+	},
+	{
+		Method:  "get",
+		Path:    "/ping",
+		Handler: "Ping",
+		Summary: "测试服务是否正常运行",
+		Tags:    []string{"系统"},
+		Produce: []string{"application/json"},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body]()},
+		},
+	},
+	{
+		Method:  "get",
+		Path:    "/metrics",
+		Handler: "Metrics",
+		Summary: "Prometheus 监控指标",
+		Tags:    []string{"系统"},
+		Produce: []string{"text/plain"},
+		Responses: []openapi.Result{
+			{Status: 200, Description: "Prometheus 文本格式", Body: openapi.Ref[string]()},
+		},
+	},
+	{
+		Method:  "post",
+		Path:    "/api/user/login",
+		Handler: "UserLogin",
+		Summary: "用户登录",
+		Tags:    []string{"用户"},
+		Accept:  []string{"application/json"},
+		Produce: []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "request", In: "body", Body: openapi.Ref[UserLoginRequest](), Required: true, Description: "登录参数"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[UserLoginResponse]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 429, Description: "请求过于频繁，Retry-After 响应头为需等待的秒数", Body: openapi.Ref[response.Body]()},
+			{Status: 500, Body: openapi.Ref[response.Body]()},
+		},
+	},
+	{
+		Method:  "get",
+		Path:    "/api/user/info",
+		Handler: "UserInfo",
+		Summary: "获取当前用户信息",
+		Tags:    []string{"用户"},
+		Produce: []string{"application/json"},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[UserLoginResponse]()))},
+			{Status: 401, Body: openapi.Ref[response.Body]()},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "get",
+		Path:        "/api/numbers/{numberId}/check",
+		Handler:     "CheckWinning",
+		Summary:     "核对用户号码在近15期的中奖情况",
+		Description: "对比用户号码和近15期开奖号码，返回中奖的期数、中奖等级等信息",
+		Tags:        []string{"号码管理"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []openapi.Param{
+			{Name: "numberId", In: "path", Type: "integer", Required: true, Description: "用户号码ID"},
+		},
+		Responses: []openapi.Result{
+			{Status: 200, Body: openapi.Ref[response.Body](openapi.Field("data", openapi.Ref[CheckWinningResponse]()))},
+			{Status: 400, Body: openapi.Ref[response.Body]()},
+			{Status: 404, Body: openapi.Ref[response.Body]()},
+			{Status: 500, Body: openapi.Ref[response.Body]()},
+		},
+	},
+}
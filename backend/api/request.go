@@ -0,0 +1,16 @@
+package api
+
+import (
+	"lucky/common/mysql"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// requestDB 带有请求 context 的数据库连接，服务中输出的日志带有请求ID、用户ID和游戏代码
+func requestDB(c *gin.Context) *gorm.DB {
+	if mysql.DB == nil {
+		return nil
+	}
+	return mysql.DB.WithContext(c.Request.Context())
+}
@@ -1,88 +1,87 @@
 package api
 
 import (
-	"net/http"
 	"strconv"
 
 	"lucky/common/mysql"
+	"lucky/common/response"
 	"lucky/service"
 
 	"github.com/gin-gonic/gin"
 )
 
 // GetDrawResults 获取开奖结果列表
+// @Summary 获取开奖结果列表
+// @Tags 开奖结果
+// @Produce json
+// @Param gameCode path string true "游戏代码"
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页条数" default(20)
+// @Success 200 {object} response.Body{data=response.Page{list=[]model.DrawResult}}
+// @Failure 400 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Router /api/results/{gameCode} [get]
 func GetDrawResults(c *gin.Context) {
 	gameCode := c.Param("gameCode")
 	if gameCode == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    400,
-			"message": "游戏代码不能为空",
-		})
+		response.Fail(c, response.ErrInvalidParams.WithDetail("游戏代码不能为空"))
 		return
 	}
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
 
-	results, total, err := service.GetDrawResults(mysql.DB, gameCode, page, pageSize)
+	results, err := service.CachedDrawResults(mysql.DB, gameCode, page, pageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    500,
-			"message": "获取开奖结果失败",
-			"error":   err.Error(),
-		})
+		response.Fail(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code":    200,
-		"message": "success",
-		"data": gin.H{
-			"list":     results,
-			"total":    total,
-			"page":     page,
-			"pageSize": pageSize,
-		},
-	})
+	response.OK(c, response.Page{List: results.List, Total: results.Total, Page: page, PageSize: pageSize})
 }
 
 // GetDrawResultDetail 获取开奖结果详情
+// @Summary 获取开奖结果详情
+// @Tags 开奖结果
+// @Produce json
+// @Param gameCode path string true "游戏代码"
+// @Param period path string true "期号"
+// @Success 200 {object} response.Body{data=model.DrawResult}
+// @Failure 400 {object} response.Body
+// @Failure 404 {object} response.Body
+// @Router /api/results/{gameCode}/{period} [get]
 func GetDrawResultDetail(c *gin.Context) {
 	gameCode := c.Param("gameCode")
 	period := c.Param("period")
 
 	if gameCode == "" || period == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    400,
-			"message": "游戏代码和期号不能为空",
-		})
+		response.Fail(c, response.ErrInvalidParams.WithDetail("游戏代码和期号不能为空"))
 		return
 	}
 
 	result, err := service.GetDrawResultByPeriod(mysql.DB, gameCode, period)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"code":    404,
-			"message": "开奖结果不存在",
-		})
+		response.Fail(c, response.ErrDrawResultNotFound)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code":    200,
-		"message": "success",
-		"data":    result,
-	})
+	response.OK(c, result)
 }
 
 // GetNumberDistribution 获取号码分布数据
+// @Summary 获取号码分布
+// @Tags 开奖结果
+// @Produce json
+// @Param gameCode path string true "游戏代码"
+// @Param periodCount query int false "统计期数" Enums(10, 30, 50) default(30)
+// @Success 200 {object} response.Body{data=map[string][]service.NumberFrequency}
+// @Failure 400 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Router /api/results/distribution/{gameCode} [get]
 func GetNumberDistribution(c *gin.Context) {
 	gameCode := c.Param("gameCode")
 	if gameCode == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    400,
-			"message": "游戏代码不能为空",
-		})
+		response.Fail(c, response.ErrInvalidParams.WithDetail("游戏代码不能为空"))
 		return
 	}
 
@@ -93,19 +92,74 @@ func GetNumberDistribution(c *gin.Context) {
 		periodCount = 30
 	}
 
-	distribution, err := service.GetNumberDistribution(mysql.DB, gameCode, periodCount)
+	distribution, err := service.CachedNumberDistribution(mysql.DB, gameCode, periodCount)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    500,
-			"message": "获取号码分布数据失败",
-			"error":   err.Error(),
-		})
+		response.Fail(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code":    200,
-		"message": "success",
-		"data":    distribution,
-	})
+	response.OK(c, distribution)
+}
+
+// GetDigitTrend 获取数字型游戏按位走势
+// @Summary 获取数字型游戏按位走势
+// @Tags 开奖结果
+// @Produce json
+// @Param gameCode path string true "游戏代码"
+// @Param periodCount query int false "统计期数" Enums(10, 30, 50, 100) default(30)
+// @Success 200 {object} response.Body{data=service.DigitTrend}
+// @Failure 400 {object} response.Body
+// @Router /api/results/trend/{gameCode} [get]
+func GetDigitTrend(c *gin.Context) {
+	gameCode := c.Param("gameCode")
+	if gameCode == "" {
+		response.Fail(c, response.ErrInvalidParams.WithDetail("游戏代码不能为空"))
+		return
+	}
+
+	// 获取期数参数，默认为30期，可选10、30、50、100期
+	periodCount, _ := strconv.Atoi(c.DefaultQuery("periodCount", "30"))
+	if periodCount != 10 && periodCount != 30 && periodCount != 50 && periodCount != 100 {
+		periodCount = 30
+	}
+
+	trend, err := service.CachedDigitTrend(mysql.DB, gameCode, periodCount)
+	if err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	response.OK(c, trend)
+}
+
+// GetNumberMissing 获取近N期号码出现次数和遗漏（按已保存的开奖结果统计）
+// @Summary 按开奖结果统计号码出现次数和遗漏
+// @Description 适用于选号型游戏和快乐8，数字型游戏请使用按位走势
+// @Tags 开奖结果
+// @Produce json
+// @Param gameCode path string true "游戏代码"
+// @Param periodCount query int false "统计期数" Enums(10, 30, 50, 100) default(30)
+// @Success 200 {object} response.Body{data=service.NumberMissingStats}
+// @Failure 400 {object} response.Body
+// @Router /api/results/missing/{gameCode} [get]
+func GetNumberMissing(c *gin.Context) {
+	gameCode := c.Param("gameCode")
+	if gameCode == "" {
+		response.Fail(c, response.ErrInvalidParams.WithDetail("游戏代码不能为空"))
+		return
+	}
+
+	// 获取期数参数，默认为30期，可选10、30、50、100期
+	periodCount, _ := strconv.Atoi(c.DefaultQuery("periodCount", "30"))
+	if periodCount != 10 && periodCount != 30 && periodCount != 50 && periodCount != 100 {
+		periodCount = 30
+	}
+
+	stats, err := service.CachedNumberMissing(mysql.DB, gameCode, periodCount)
+	if err != nil {
+		response.Fail(c, response.ErrRequestRejected.Wrap(err))
+		return
+	}
+
+	response.OK(c, stats)
 }
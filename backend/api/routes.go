@@ -1,6 +1,8 @@
 package api
 
 import (
+	"lucky/common/metrics"
+	"lucky/common/response"
 	"lucky/middleware"
 
 	"github.com/gin-gonic/gin"
@@ -8,19 +10,55 @@ import (
 
 // RegisterTestRoutes 注册测试路由
 func RegisterTestRoutes(r *gin.Engine) {
-	r.GET("/ping", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"message": "pong",
-		})
-	})
+	r.GET("/ping", Ping)
+}
+
+// Ping 测试服务是否正常运行
+// @Summary 测试服务是否正常运行
+// @Tags 系统
+// @Produce json
+// @Success 200 {object} response.Body
+// @Router /ping [get]
+func Ping(c *gin.Context) {
+	response.Success(c, "pong", nil)
+}
+
+// RegisterMetricsRoutes 注册 Prometheus 监控指标接口
+func RegisterMetricsRoutes(r *gin.Engine) {
+	r.GET("/metrics", Metrics)
+}
+
+// metricsHandler Prometheus 指标的 HTTP 处理器
+var metricsHandler = metrics.Handler()
+
+// Metrics Prometheus 监控指标
+// @Summary Prometheus 监控指标
+// @Tags 系统
+// @Produce plain
+// @Success 200 {string} string "Prometheus 文本格式"
+// @Router /metrics [get]
+func Metrics(c *gin.Context) {
+	metricsHandler.ServeHTTP(c.Writer, c.Request)
 }
 
 // RegisterUserRoutes 注册用户相关路由
 func RegisterUserRoutes(r *gin.Engine) {
 	userGroup := r.Group("/api/user")
 	{
-		userGroup.POST("/login", UserLogin)
+		userGroup.POST("/login", middleware.RateLimit("login"), UserLogin)
 		userGroup.GET("/info", middleware.AuthRequired(), UserInfo)
+
+		// 购彩账本，以下路由需要登录，用户ID取自登录令牌
+		authed := userGroup.Group("", middleware.AuthRequired())
+		authed.GET("/ledger", GetLedgerSummary)
+		authed.GET("/ledger/purchases", GetPurchases)
+		authed.POST("/ledger/purchases", CreatePurchase)
+		authed.DELETE("/ledger/purchases/:id", DeletePurchase)
+
+		// 站内通知
+		authed.GET("/notifications", GetNotifications)
+		authed.PUT("/notifications/read", MarkAllNotificationsRead)
+		authed.PUT("/notifications/:id/read", MarkNotificationRead)
 	}
 }
 
@@ -28,7 +66,7 @@ func RegisterUserRoutes(r *gin.Engine) {
 func RegisterAuthRoutes(r *gin.Engine) {
 	authGroup := r.Group("/api/auth")
 	{
-		authGroup.POST("/wxlogin", WxLogin)
+		authGroup.POST("/wxlogin", middleware.RateLimit("login"), WxLogin)
 	}
 }
 
@@ -46,10 +84,45 @@ func RegisterNumberRoutes(r *gin.Engine) {
 	numberGroup := r.Group("/api/numbers")
 	{
 		numberGroup.POST("/save", SaveUserNumber)
+		numberGroup.POST("/generate", middleware.RateLimit("generate"), GenerateNumbers) // 缩水生成号码
+		numberGroup.POST("/wheel", middleware.RateLimit("generate"), GenerateWheel)      // 旋转矩阵
 		numberGroup.GET("/my", GetMyNumbers)
 		numberGroup.PUT("/:id", UpdateUserNumber)
 		numberGroup.DELETE("/:id", DeleteUserNumber)
 		numberGroup.GET("/:numberId/check", CheckWinning) // 新增：中奖核对
+
+		// 以下路由需要登录，用户ID取自登录令牌
+		authed := numberGroup.Group("", middleware.AuthRequired())
+		authed.POST("/import", ImportNumbers) // 批量导入
+		authed.GET("/export", ExportNumbers)  // 导出
+
+		// 号码分组
+		authed.GET("/groups", GetNumberGroups)
+		authed.POST("/groups", CreateNumberGroup)
+		authed.PUT("/groups/:id", UpdateNumberGroup)
+		authed.DELETE("/groups/:id", DeleteNumberGroup)
+		authed.GET("/groups/:id/summary", GetGroupWinningSummary)
+
+		// 号码标签
+		authed.GET("/tags", GetNumberTags)
+		authed.POST("/tags", CreateNumberTag)
+		authed.DELETE("/tags/:id", DeleteNumberTag)
+
+		// 批量操作
+		authed.POST("/bulk/move", BulkMoveNumbers)
+		authed.POST("/bulk/tag", BulkTagNumbers)
+		authed.POST("/bulk/active", BulkSetNumbersActive)
+	}
+}
+
+// RegisterPlanRoutes 注册追号计划相关路由，需要登录
+func RegisterPlanRoutes(r *gin.Engine) {
+	planGroup := r.Group("/api/plans", middleware.AuthRequired())
+	{
+		planGroup.GET("", GetNumberPlans)
+		planGroup.POST("", CreateNumberPlan)
+		planGroup.GET("/:id", GetNumberPlanDetail)
+		planGroup.POST("/:id/cancel", CancelNumberPlan)
 	}
 }
 
@@ -60,25 +133,55 @@ func RegisterResultRoutes(r *gin.Engine) {
 	resultGroup := r.Group("/api/results")
 	{
 		resultGroup.GET("/distribution/:gameCode", GetNumberDistribution)
+		resultGroup.GET("/trend/:gameCode", GetDigitTrend)      // 数字型游戏按位走势
+		resultGroup.GET("/missing/:gameCode", GetNumberMissing) // 按开奖结果统计号码遗漏
 		resultGroup.GET("/:gameCode", GetDrawResults)
 		resultGroup.GET("/:gameCode/:period", GetDrawResultDetail) // 通配符路由放在最后
 	}
 }
 
-// RegisterCrawlerRoutes 注册数据抓取相关路由
+// RegisterCrawlerRoutes 注册数据抓取相关路由，会请求外部数据源，仅管理员可访问
 func RegisterCrawlerRoutes(r *gin.Engine) {
-	crawlerGroup := r.Group("/api/crawler")
+	crawlerGroup := r.Group("/api/crawler", middleware.AuthRequired(), middleware.AdminRequired(), middleware.RateLimit("crawler"))
 	{
 		crawlerGroup.POST("/crawl/:gameCode", CrawlLatestHandler) // 抓取最新开奖数据
 		crawlerGroup.GET("/test/:gameCode", TestCrawlHandler)     // 测试抓取功能
 	}
 }
 
-// RegisterMissingRoutes 注册遗漏数据相关路由
+// RegisterMissingRoutes 注册遗漏数据相关路由，缓存未命中时回源抓取，按客户端限流
 func RegisterMissingRoutes(r *gin.Engine) {
-	missingGroup := r.Group("/api/missing")
+	missingGroup := r.Group("/api/missing", middleware.RateLimit("missing"))
 	{
 		missingGroup.GET("", GetMissingData)            // 获取指定期数的遗漏数据
 		missingGroup.GET("/batch", GetMissingDataBatch) // 批量获取多个期数的遗漏数据
 	}
 }
+
+// RegisterAdminRoutes 注册管理后台路由，仅管理员可访问
+func RegisterAdminRoutes(r *gin.Engine) {
+	adminGroup := r.Group("/api/admin", middleware.AuthRequired(), middleware.AdminRequired(), middleware.RateLimit("admin"))
+	{
+		// 游戏管理
+		adminGroup.GET("/games", AdminListGames)
+		adminGroup.PUT("/games/:gameCode/status", AdminSetGameStatus)
+
+		// 开奖结果录入与更正
+		adminGroup.POST("/draws/:gameCode", AdminCreateDrawResult)
+		adminGroup.PUT("/draws/:gameCode/:period", AdminCorrectDrawResult)
+		adminGroup.GET("/draws/:gameCode/:period/revisions", AdminGetDrawRevisions)
+
+		// 抓取与回补
+		adminGroup.POST("/crawl/:gameCode", middleware.RateLimit("crawler"), AdminTriggerCrawl)
+		adminGroup.GET("/crawl-runs", AdminListCrawlRuns)
+
+		// 任务
+		adminGroup.GET("/jobs", AdminListJobs)
+		adminGroup.POST("/jobs/:name/run", AdminRunJob)
+		adminGroup.GET("/job-runs", AdminListJobRuns)
+
+		// 审计日志与用户角色
+		adminGroup.GET("/audit-logs", AdminListAuditLogs)
+		adminGroup.PUT("/users/:id/role", AdminSetUserRole)
+	}
+}
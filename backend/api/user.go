@@ -1,10 +1,9 @@
 package api
 
 import (
-	"net/http"
-
 	"lucky/common/jwt"
 	"lucky/common/mysql"
+	"lucky/common/response"
 	"lucky/middleware"
 	"lucky/service"
 
@@ -28,40 +27,38 @@ type UserLoginResponse struct {
 }
 
 // UserLogin 用户登录
+// @Summary 用户登录
+// @Tags 用户
+// @Accept json
+// @Produce json
+// @Param request body UserLoginRequest true "登录参数"
+// @Success 200 {object} response.Body{data=UserLoginResponse}
+// @Failure 400 {object} response.Body
+// @Failure 429 {object} response.Body "请求过于频繁，Retry-After 响应头为需等待的秒数"
+// @Failure 500 {object} response.Body
+// @Router /api/user/login [post]
 func UserLogin(c *gin.Context) {
 	var req UserLoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    400,
-			"message": "参数错误",
-			"error":   err.Error(),
-		})
+		response.Fail(c, response.ErrInvalidParams.Wrap(err))
 		return
 	}
 
 	// 获取或创建用户
 	user, err := service.GetOrCreateUser(mysql.DB, req.OpenID, req.Nickname, req.AvatarURL)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    500,
-			"message": "登录失败",
-			"error":   err.Error(),
-		})
+		response.Fail(c, err)
 		return
 	}
 
 	// 生成JWT token
 	token, err := jwt.GenerateToken(uint64(user.ID), user.OpenID, user.Nickname)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    500,
-			"message": "生成token失败",
-			"error":   err.Error(),
-		})
+		response.Fail(c, err)
 		return
 	}
 
-	response := UserLoginResponse{
+	resp := UserLoginResponse{
 		UserID:    uint64(user.ID),
 		OpenID:    user.OpenID,
 		Nickname:  user.Nickname,
@@ -69,34 +66,30 @@ func UserLogin(c *gin.Context) {
 		Token:     token,
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code":    200,
-		"message": "登录成功",
-		"data":    response,
-	})
+	response.Success(c, "登录成功", resp)
 }
 
 // UserInfo 获取用户信息
+// @Summary 获取当前用户信息
+// @Tags 用户
+// @Produce json
+// @Success 200 {object} response.Body{data=UserLoginResponse}
+// @Failure 401 {object} response.Body
+// @Security BearerAuth
+// @Router /api/user/info [get]
 func UserInfo(c *gin.Context) {
 	// 从中间件注入的上下文获取用户
 	if userModel, ok := middleware.GetCurrentUser(c); ok {
-		response := UserLoginResponse{
+		resp := UserLoginResponse{
 			UserID:    uint64(userModel.ID),
 			OpenID:    userModel.OpenID,
 			Nickname:  userModel.Nickname,
 			AvatarURL: userModel.AvatarURL,
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"code":    200,
-			"message": "success",
-			"data":    response,
-		})
+		response.OK(c, resp)
 		return
 	}
 
-	c.JSON(http.StatusUnauthorized, gin.H{
-		"code":    401,
-		"message": "未授权",
-	})
+	response.Fail(c, response.ErrUnauthorized)
 }
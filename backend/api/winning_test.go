@@ -5,6 +5,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"lucky/common/database/dbtest"
+	"lucky/common/mysql"
+	"lucky/model"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -12,6 +17,8 @@ import (
 
 func TestCheckWinningValidation(t *testing.T) {
 	gin.SetMode(gin.TestMode)
+	mysql.DB = dbtest.Open(t)
+	defer func() { mysql.DB = nil }()
 
 	// 创建路由
 	r := gin.New()
@@ -68,121 +75,63 @@ func TestCheckWinningValidation(t *testing.T) {
 	}
 }
 
-func TestCountMatches(t *testing.T) {
-	tests := []struct {
-		name      string
-		userBalls []int
-		drawBalls []int
-		expected  int
-	}{
-		{
-			name:      "No matches",
-			userBalls: []int{1, 2, 3, 4, 5},
-			drawBalls: []int{6, 7, 8, 9, 10},
-			expected:  0,
-		},
-		{
-			name:      "Partial matches",
-			userBalls: []int{1, 2, 3, 4, 5},
-			drawBalls: []int{1, 2, 8, 9, 10},
-			expected:  2,
-		},
-		{
-			name:      "All matches",
-			userBalls: []int{1, 2, 3, 4, 5},
-			drawBalls: []int{1, 2, 3, 4, 5},
-			expected:  5,
-		},
-		{
-			name:      "Duplicate in user balls",
-			userBalls: []int{1, 1, 2, 3, 4},
-			drawBalls: []int{1, 5, 6, 7, 8},
-			expected:  2, // 两个1都匹配同一个开奖号码1
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := countMatches(tt.userBalls, tt.drawBalls)
-			assert.Equal(t, tt.expected, result)
-		})
+// TestEvaluateWinning 一、二等奖取开奖结果公布的奖金，按倍数和追加计算实际奖金
+func TestEvaluateWinning(t *testing.T) {
+	drawResult := &model.DrawResult{
+		Period:       "2025001",
+		DrawDate:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local),
+		RedBalls:     model.NumberArray{1, 2, 3, 4, 5},
+		BlueBalls:    model.NumberArray{1, 2},
+		FirstAmount:  800000000,
+		SecondAmount: 20000000,
 	}
-}
+	dlt := model.LotteryGame{GameCode: "dlt", GameType: model.GameTypeBall}
 
-func TestCalculateWinLevel(t *testing.T) {
 	tests := []struct {
 		name          string
-		gameCode      string
-		redMatches    int
-		blueMatches   int
+		userNumber    model.UserNumber
 		expectedWin   string
 		expectedPrize int64
 	}{
-		// 双色球测试用例
 		{
-			name:          "SSQ First Prize",
-			gameCode:      "ssq",
-			redMatches:    6,
-			blueMatches:   1,
+			name:          "一等奖追加2倍",
+			userNumber:    model.UserNumber{Game: dlt, RedBalls: model.NumberArray{1, 2, 3, 4, 5}, BlueBalls: model.NumberArray{1, 2}, Multiplier: 2, IsAdditional: true},
 			expectedWin:   "一等奖",
-			expectedPrize: 500000000,
+			expectedPrize: (800000000 + 800000000*80/100) * 2,
 		},
 		{
-			name:          "SSQ Second Prize",
-			gameCode:      "ssq",
-			redMatches:    6,
-			blueMatches:   0,
+			name:          "二等奖",
+			userNumber:    model.UserNumber{Game: dlt, RedBalls: model.NumberArray{1, 2, 3, 4, 5}, BlueBalls: model.NumberArray{1, 9}, Multiplier: 1},
 			expectedWin:   "二等奖",
-			expectedPrize: 10000000,
-		},
-		{
-			name:          "SSQ Sixth Prize",
-			gameCode:      "ssq",
-			redMatches:    0,
-			blueMatches:   1,
-			expectedWin:   "六等奖",
-			expectedPrize: 500,
-		},
-		{
-			name:          "SSQ No Prize",
-			gameCode:      "ssq",
-			redMatches:    0,
-			blueMatches:   0,
-			expectedWin:   "",
-			expectedPrize: 0,
+			expectedPrize: 20000000,
 		},
-		// 大乐透测试用例
 		{
-			name:          "DLT First Prize",
-			gameCode:      "dlt",
-			redMatches:    5,
-			blueMatches:   2,
-			expectedWin:   "一等奖",
-			expectedPrize: 1000000000,
+			name:          "二等奖追加",
+			userNumber:    model.UserNumber{Game: dlt, RedBalls: model.NumberArray{1, 2, 3, 4, 5}, BlueBalls: model.NumberArray{2, 9}, Multiplier: 1, IsAdditional: true},
+			expectedWin:   "二等奖",
+			expectedPrize: 20000000 + 20000000*80/100,
 		},
 		{
-			name:          "DLT Eighth Prize",
-			gameCode:      "dlt",
-			redMatches:    0,
-			blueMatches:   1,
-			expectedWin:   "八等奖",
-			expectedPrize: 500,
+			name:          "固定奖级不追加",
+			userNumber:    model.UserNumber{Game: dlt, RedBalls: model.NumberArray{1, 2, 3, 4, 9}, BlueBalls: model.NumberArray{1, 2}, Multiplier: 3, IsAdditional: true},
+			expectedWin:   "四等奖",
+			expectedPrize: 300000 * 3,
 		},
 		{
-			name:          "DLT No Prize",
-			gameCode:      "dlt",
-			redMatches:    0,
-			blueMatches:   0,
-			expectedWin:   "",
-			expectedPrize: 0,
+			name:       "未中奖",
+			userNumber: model.UserNumber{Game: dlt, RedBalls: model.NumberArray{1, 2, 7, 8, 9}, BlueBalls: model.NumberArray{10, 11}, Multiplier: 1},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			winLevel, prizeAmount := calculateWinLevel(tt.gameCode, tt.redMatches, tt.blueMatches)
-			assert.Equal(t, tt.expectedWin, winLevel)
-			assert.Equal(t, tt.expectedPrize, prizeAmount)
+			match, won := evaluateWinning(&tt.userNumber, drawResult)
+			assert.Equal(t, tt.expectedWin != "", won)
+			assert.Equal(t, tt.expectedWin, match.WinLevel)
+			assert.Equal(t, tt.expectedPrize, match.PrizeAmount)
+			if won {
+				assert.Equal(t, "2025-01-01", match.DrawDate)
+			}
 		})
 	}
 }
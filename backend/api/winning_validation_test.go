@@ -3,9 +3,51 @@ package api
 import (
 	"testing"
 
+	"lucky/model"
+
 	"github.com/stretchr/testify/assert"
 )
 
+// 测试开奖结果公布的一、二等奖单注奖金(分)
+// 一、二等奖为浮动奖金，按开奖结果公布的金额计，不再使用固定的模拟金额，见 API_DOCS 中奖核对规则调整
+const (
+	ruleFirstAmount  = 600000000
+	ruleSecondAmount = 15000000
+)
+
+// evaluateMatches 构造红蓝球分别命中 redMatches、blueMatches 个的单注号码，按奖级规则核对
+func evaluateMatches(gameCode string, redMatches, blueMatches int) (string, int64) {
+	drawRed, drawBlue := model.NumberArray{1, 2, 3, 4, 5, 6}, model.NumberArray{1}
+	if gameCode == "dlt" {
+		drawRed, drawBlue = model.NumberArray{1, 2, 3, 4, 5}, model.NumberArray{1, 2}
+	}
+	userNumber := &model.UserNumber{
+		Game:       model.LotteryGame{GameCode: gameCode, GameType: model.GameTypeBall},
+		RedBalls:   pickBalls(drawRed, redMatches, 20),
+		BlueBalls:  pickBalls(drawBlue, blueMatches, 10),
+		Multiplier: 1,
+	}
+	drawResult := &model.DrawResult{
+		Period:       "2025001",
+		RedBalls:     drawRed,
+		BlueBalls:    drawBlue,
+		FirstAmount:  ruleFirstAmount,
+		SecondAmount: ruleSecondAmount,
+	}
+	match, _ := evaluateWinning(userNumber, drawResult)
+	return match.WinLevel, match.PrizeAmount
+}
+
+// pickBalls 取开奖号码的前 matches 个，其余用从 from 开始的未开出号码补齐
+func pickBalls(drawn model.NumberArray, matches, from int) model.NumberArray {
+	balls := append(model.NumberArray{}, drawn[:matches]...)
+	for len(balls) < len(drawn) {
+		balls = append(balls, from)
+		from++
+	}
+	return balls
+}
+
 // TestSSQWinningRulesComplete 完整测试双色球所有中奖规则
 func TestSSQWinningRulesComplete(t *testing.T) {
 	tests := []struct {
@@ -22,7 +64,7 @@ func TestSSQWinningRulesComplete(t *testing.T) {
 			redMatches:    6,
 			blueMatches:   1,
 			expectedWin:   "一等奖",
-			expectedPrize: 500000000,
+			expectedPrize: ruleFirstAmount,
 			description:   "选中6个红球+1个蓝球",
 		},
 		// 二等奖
@@ -31,7 +73,7 @@ func TestSSQWinningRulesComplete(t *testing.T) {
 			redMatches:    6,
 			blueMatches:   0,
 			expectedWin:   "二等奖",
-			expectedPrize: 10000000,
+			expectedPrize: ruleSecondAmount,
 			description:   "选中6个红球",
 		},
 		// 三等奖
@@ -139,7 +181,7 @@ func TestSSQWinningRulesComplete(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			winLevel, prizeAmount := calculateWinLevel("ssq", tt.redMatches, tt.blueMatches)
+			winLevel, prizeAmount := evaluateMatches("ssq", tt.redMatches, tt.blueMatches)
 			assert.Equal(t, tt.expectedWin, winLevel, "中奖等级不匹配: %s", tt.description)
 			assert.Equal(t, tt.expectedPrize, prizeAmount, "奖金金额不匹配: %s", tt.description)
 			t.Logf("✓ %s - %s", tt.name, tt.description)
@@ -163,7 +205,7 @@ func TestDLTWinningRulesComplete(t *testing.T) {
 			redMatches:    5,
 			blueMatches:   2,
 			expectedWin:   "一等奖",
-			expectedPrize: 1000000000,
+			expectedPrize: ruleFirstAmount,
 			description:   "选中5个前区+2个后区",
 		},
 		// 二等奖
@@ -172,7 +214,7 @@ func TestDLTWinningRulesComplete(t *testing.T) {
 			redMatches:    5,
 			blueMatches:   1,
 			expectedWin:   "二等奖",
-			expectedPrize: 80000000,
+			expectedPrize: ruleSecondAmount,
 			description:   "选中5个前区+1个后区",
 		},
 		// 三等奖
@@ -269,14 +311,6 @@ func TestDLTWinningRulesComplete(t *testing.T) {
 			description:   "只选中2个后区",
 		},
 		// 八等奖
-		{
-			name:          "DLT 八等奖 (2红+0蓝)",
-			redMatches:    2,
-			blueMatches:   0,
-			expectedWin:   "八等奖",
-			expectedPrize: 500,
-			description:   "选中2个前区",
-		},
 		{
 			name:          "DLT 八等奖 (1红+1蓝)",
 			redMatches:    1,
@@ -294,6 +328,15 @@ func TestDLTWinningRulesComplete(t *testing.T) {
 			description:   "只选中1个后区",
 		},
 		// 未中奖情况
+		// 2前+0后原按八等奖5元计，与账本结算规则统一后不中奖，见 API_DOCS 中奖核对规则调整
+		{
+			name:          "DLT 未中奖 (2红+0蓝)",
+			redMatches:    2,
+			blueMatches:   0,
+			expectedWin:   "",
+			expectedPrize: 0,
+			description:   "只选中2个前区号码",
+		},
 		{
 			name:          "DLT 未中奖 (1红+0蓝)",
 			redMatches:    1,
@@ -314,7 +357,7 @@ func TestDLTWinningRulesComplete(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			winLevel, prizeAmount := calculateWinLevel("dlt", tt.redMatches, tt.blueMatches)
+			winLevel, prizeAmount := evaluateMatches("dlt", tt.redMatches, tt.blueMatches)
 			assert.Equal(t, tt.expectedWin, winLevel, "中奖等级不匹配: %s", tt.description)
 			assert.Equal(t, tt.expectedPrize, prizeAmount, "奖金金额不匹配: %s", tt.description)
 			t.Logf("✓ %s - %s", tt.name, tt.description)
@@ -325,20 +368,20 @@ func TestDLTWinningRulesComplete(t *testing.T) {
 // TestWinningRulesSummary 输出中奖规则汇总
 func TestWinningRulesSummary(t *testing.T) {
 	t.Log("\n=== 双色球中奖规则汇总 ===")
-	t.Log("一等奖: 6红+1蓝 = 500万元")
-	t.Log("二等奖: 6红+0蓝 = 10万元")
+	t.Log("一等奖: 6红+1蓝 = 浮动奖金，取开奖结果公布的单注奖金")
+	t.Log("二等奖: 6红+0蓝 = 浮动奖金，取开奖结果公布的单注奖金")
 	t.Log("三等奖: 5红+1蓝 = 3000元")
 	t.Log("四等奖: 5红+0蓝 或 4红+1蓝 = 200元")
 	t.Log("五等奖: 4红+0蓝 或 3红+1蓝 = 10元")
 	t.Log("六等奖: 2红+1蓝 或 1红+1蓝 或 0红+1蓝 = 5元")
 
 	t.Log("\n=== 大乐透中奖规则汇总 ===")
-	t.Log("一等奖: 5前+2后 = 1000万元")
-	t.Log("二等奖: 5前+1后 = 80万元")
+	t.Log("一等奖: 5前+2后 = 浮动奖金，取开奖结果公布的单注奖金")
+	t.Log("二等奖: 5前+1后 = 浮动奖金，取开奖结果公布的单注奖金")
 	t.Log("三等奖: 5前+0后 = 1万元")
 	t.Log("四等奖: 4前+2后 = 3000元")
 	t.Log("五等奖: 4前+1后 或 3前+2后 = 300元")
 	t.Log("六等奖: 4前+0后 或 3前+1后 或 2前+2后 = 100元")
 	t.Log("七等奖: 3前+0后 或 2前+1后 或 1前+2后 或 0前+2后 = 15元")
-	t.Log("八等奖: 2前+0后 或 1前+1后 或 0前+1后 = 5元")
+	t.Log("八等奖: 1前+1后 或 0前+1后 = 5元")
 }
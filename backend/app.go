@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"lucky/api"
+	"lucky/common/cache"
+	"lucky/common/config"
+	applog "lucky/common/log"
+	"lucky/common/metrics"
+	"lucky/common/mysql"
+	"lucky/common/ratelimit"
+	"lucky/common/redis"
+	"lucky/middleware"
+	"lucky/migration"
+	"lucky/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// App 应用生命周期：启动时依次连接数据库、Redis，初始化缓存和 HTTP 服务，
+// 关闭时等待进行中的请求，停止定时抓取和后台任务，最后关闭 Redis 和数据库
+type App struct {
+	cfg       *config.Config
+	server    *http.Server
+	ctx       context.Context // 定时抓取和后台任务的根 context，关闭时取消
+	cancel    context.CancelFunc
+	scheduler sync.WaitGroup
+}
+
+// NewApp 创建应用
+func NewApp(cfg *config.Config) *App {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &App{cfg: cfg, ctx: ctx, cancel: cancel}
+}
+
+// Init 连接数据库和 Redis，检查表结构，初始化基础数据、缓存和路由
+func (a *App) Init() error {
+	// 初始化数据库
+	mysql.Init()
+	applog.Ctx(a.ctx).Info("数据库连接成功")
+
+	// 存在未执行的迁移或表结构与模型不一致时拒绝启动
+	if err := migration.CheckPending(mysql.DB); err != nil {
+		return fmt.Errorf("%v，请先执行 go run . migrate up", err)
+	}
+	if err := migration.CheckSchemaDrift(mysql.DB); err != nil {
+		return fmt.Errorf("%v，请新增迁移修正表结构", err)
+	}
+
+	// 初始化基础数据
+	if err := service.InitializeData(mysql.DB); err != nil {
+		return fmt.Errorf("基础数据初始化失败: %v", err)
+	}
+
+	// 加载游戏注册表
+	if err := service.LoadGameRegistry(mysql.DB); err != nil {
+		return fmt.Errorf("加载游戏注册表失败: %v", err)
+	}
+
+	// 监控指标：数据库连接池和各游戏最新开奖
+	sqlDB, err := mysql.DB.DB()
+	if err != nil {
+		return fmt.Errorf("获取数据库连接池失败: %v", err)
+	}
+	if err := metrics.RegisterDB(sqlDB, a.cfg.Database.Driver); err != nil {
+		return fmt.Errorf("注册数据库监控指标失败: %v", err)
+	}
+	if err := service.RecordLatestDrawMetrics(mysql.DB); err != nil {
+		return fmt.Errorf("加载最新开奖监控指标失败: %v", err)
+	}
+
+	// 初始化Redis
+	redis.Init()
+	if redis.DB != nil {
+		applog.Ctx(a.ctx).Info("Redis连接成功")
+	}
+
+	// 初始化缓存和限流，启用Redis时多实例共享缓存和限流计数，否则使用进程内LRU缓存和令牌桶
+	if redis.DB != nil && redis.DB.IsEnabled() {
+		cache.Default = cache.NewRedis(redis.DB)
+		ratelimit.Default = ratelimit.NewRedis(redis.DB)
+	} else {
+		cache.Default = cache.NewMemory(a.cfg.Cache.MaxEntries)
+		ratelimit.Default = ratelimit.NewMemory()
+	}
+
+	// 后台抓取和任务使用应用的根 context，关闭时一并取消
+	service.SetBaseContext(a.ctx)
+
+	router, err := newRouter(a.cfg.Server.Mode, a.cfg.Server.TrustedProxies)
+	if err != nil {
+		return fmt.Errorf("设置可信代理失败: %v", err)
+	}
+	a.server = &http.Server{
+		Addr:    a.cfg.Server.Addr,
+		Handler: router,
+	}
+	return nil
+}
+
+// newRouter 创建 gin 引擎并注册路由，运行模式需在创建引擎前设置
+// 只有直连地址在 trustedProxies 中时才按 X-Forwarded-For 取客户端 IP，为空时不信任任何代理，限流和登录日志使用直连地址
+func newRouter(mode string, trustedProxies []string) (*gin.Engine, error) {
+	gin.SetMode(mode)
+	r := gin.New()
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+	r.Use(gin.Recovery(), middleware.RequestID(), middleware.AccessLog(), middleware.Metrics())
+
+	// 注册路由
+	api.RegisterTestRoutes(r)
+	api.RegisterMetricsRoutes(r)
+	api.RegisterAuthRoutes(r)
+	api.RegisterUserRoutes(r)
+	api.RegisterGameRoutes(r)
+	api.RegisterNumberRoutes(r)
+	api.RegisterPlanRoutes(r)
+	api.RegisterResultRoutes(r)
+	api.RegisterCrawlerRoutes(r)
+	api.RegisterMissingRoutes(r)
+	api.RegisterAdminRoutes(r)
+	api.RegisterDocsRoutes(r)
+	return r, nil
+}
+
+// Run 启动 HTTP 服务和定时抓取，ctx 结束（收到退出信号）或服务异常退出后关闭应用
+func (a *App) Run(ctx context.Context) error {
+	serverErr := make(chan error, 1)
+	go func() {
+		applog.Ctx(ctx).Infof("服务启动在 %s", a.server.Addr)
+		if err := a.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	// 定时抓取开奖数据
+	a.scheduler.Add(1)
+	go func() {
+		defer a.scheduler.Done()
+		service.NewCrawlerService().ScheduleCrawl(a.ctx)
+	}()
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		applog.Ctx(ctx).Info("收到退出信号，开始关闭服务")
+	case err := <-serverErr:
+		runErr = fmt.Errorf("服务启动失败: %v", err)
+	}
+	return errors.Join(runErr, a.Shutdown())
+}
+
+// Shutdown 停止接收新请求并等待进行中的请求结束，然后停止定时抓取和后台任务，
+// 最后关闭 Redis 和数据库；超过 shutdown_timeout 时不再等待
+func (a *App) Shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), a.cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	if a.server != nil {
+		if err := a.server.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("等待请求结束失败: %v", err))
+		}
+	}
+
+	// 取消根 context，进行中的抓取和任务收到取消后尽快结束
+	a.cancel()
+	a.scheduler.Wait()
+	if err := service.WaitBackground(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("等待后台任务结束失败: %v", err))
+	}
+
+	if err := redis.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("关闭Redis连接失败: %v", err))
+	}
+	if err := mysql.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("关闭数据库连接失败: %v", err))
+	}
+	if len(errs) == 0 {
+		applog.Ctx(ctx).Info("服务已关闭")
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"lucky/api"
+	"lucky/common/config"
+	"lucky/common/openapi"
+	"lucky/common/ratelimit"
+	"lucky/common/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRouter 创建测试模式的路由
+func testRouter(t *testing.T, trustedProxies []string) *gin.Engine {
+	t.Helper()
+	r, err := newRouter(gin.TestMode, trustedProxies)
+	require.NoError(t, err)
+	return r
+}
+
+// limitMissing 遗漏数据接口限流为 limit，测试结束后恢复配置和限流器
+func limitMissing(t *testing.T, limit string) {
+	previous := config.Get()
+	cfg := *previous
+	cfg.RateLimit.Enabled = true
+	cfg.RateLimit.Missing = limit
+	config.Set(&cfg)
+	limiter := ratelimit.Default
+	ratelimit.Default = ratelimit.NewMemory()
+	t.Cleanup(func() {
+		config.Set(previous)
+		ratelimit.Default = limiter
+	})
+}
+
+// missingRequest 从 remoteAddr 直连请求遗漏数据接口，forwardedFor 不为空时带 X-Forwarded-For
+func missingRequest(r *gin.Engine, remoteAddr, forwardedFor string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/missing?gameCode=ssq&periodCount=7", nil)
+	req.RemoteAddr = remoteAddr + ":12345"
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestOpenAPICoversRoutes 注册的路由都要有接口文档，文档中的接口也都要有对应的路由
+func TestOpenAPICoversRoutes(t *testing.T) {
+	r := testRouter(t, nil)
+	doc := api.OpenAPI()
+
+	registered := make(map[string]bool)
+	for _, route := range r.Routes() {
+		// 接口文档自身
+		if strings.HasPrefix(route.Path, "/api/docs") {
+			continue
+		}
+		path := openapi.GinPath(route.Path)
+		registered[strings.ToLower(route.Method)+" "+path] = true
+		assert.Truef(t, doc.HasOperation(route.Method, path),
+			"路由 %s %s 没有接口文档，请在 %s 上添加 @Router 注释并执行 go generate ./api", route.Method, route.Path, route.Handler)
+	}
+
+	for path, item := range doc.Paths {
+		for method := range item {
+			assert.Truef(t, registered[method+" "+path], "接口文档中的 %s %s 没有注册路由", strings.ToUpper(method), path)
+		}
+	}
+}
+
+// TestRateLimit 超过限流返回 429 和 Retry-After，不同客户端分别计数
+func TestRateLimit(t *testing.T) {
+	limitMissing(t, "2/1m")
+	r := testRouter(t, nil)
+	request := func(ip string) *httptest.ResponseRecorder {
+		return missingRequest(r, ip, "")
+	}
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusBadRequest, request("10.0.0.1").Code, "未超过限流时由处理函数响应")
+	}
+	w := request("10.0.0.1")
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+	var body response.Body
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, response.ErrTooManyRequests.Code, body.Code)
+
+	assert.Equal(t, http.StatusBadRequest, request("10.0.0.2").Code, "其他客户端不受影响")
+}
+
+// TestRateLimitForwardedFor 只有来自可信代理的请求才按 X-Forwarded-For 计数，伪造的请求头不能换到新的令牌桶
+func TestRateLimitForwardedFor(t *testing.T) {
+	limitMissing(t, "2/1m")
+	r := testRouter(t, nil)
+	for i, forwardedFor := range []string{"1.1.1.1", "2.2.2.2"} {
+		assert.Equalf(t, http.StatusBadRequest, missingRequest(r, "10.0.0.1", forwardedFor).Code, "第 %d 次请求", i+1)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, missingRequest(r, "10.0.0.1", "3.3.3.3").Code,
+		"未配置可信代理时按直连地址计数")
+
+	limitMissing(t, "2/1m")
+	r = testRouter(t, []string{"10.0.0.0/8"})
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusBadRequest, missingRequest(r, "10.0.0.1", "1.1.1.1").Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, missingRequest(r, "10.0.0.1", "1.1.1.1").Code)
+	assert.Equal(t, http.StatusBadRequest, missingRequest(r, "10.0.0.1", "2.2.2.2").Code,
+		"可信代理转发的其他客户端分别计数")
+	assert.Equal(t, http.StatusBadRequest, missingRequest(r, "192.168.0.1", "1.1.1.1").Code,
+		"不可信来源的 X-Forwarded-For 被忽略，按直连地址计数")
+}
+
+// TestCrawlerRoutesRequireAdmin 手动抓取会请求外部数据源，未登录时拒绝
+func TestCrawlerRoutesRequireAdmin(t *testing.T) {
+	r := testRouter(t, nil)
+	for _, route := range []struct{ method, path string }{
+		{http.MethodPost, "/api/crawler/crawl/ssq"},
+		{http.MethodGet, "/api/crawler/test/ssq"},
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(route.method, route.path, nil))
+		assert.Equalf(t, http.StatusUnauthorized, w.Code, "%s %s", route.method, route.path)
+	}
+}
+
+// TestUserRoutesRequireLogin 账本、通知、号码导入导出、分组标签和追号计划的用户ID取自登录令牌，只带 X-User-ID 时拒绝
+func TestUserRoutesRequireLogin(t *testing.T) {
+	r := testRouter(t, nil)
+	for _, route := range []struct{ method, path string }{
+		{http.MethodGet, "/api/user/ledger"},
+		{http.MethodPost, "/api/user/ledger/purchases"},
+		{http.MethodDelete, "/api/user/ledger/purchases/1"},
+		{http.MethodGet, "/api/user/notifications"},
+		{http.MethodPut, "/api/user/notifications/read"},
+		{http.MethodPost, "/api/numbers/import"},
+		{http.MethodGet, "/api/numbers/export"},
+		{http.MethodGet, "/api/numbers/groups"},
+		{http.MethodDelete, "/api/numbers/groups/1"},
+		{http.MethodPost, "/api/numbers/tags"},
+		{http.MethodPost, "/api/numbers/bulk/move"},
+		{http.MethodGet, "/api/plans"},
+		{http.MethodPost, "/api/plans/1/cancel"},
+	} {
+		req := httptest.NewRequest(route.method, route.path, nil)
+		req.Header.Set("X-User-ID", "1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equalf(t, http.StatusUnauthorized, w.Code, "%s %s", route.method, route.path)
+	}
+}
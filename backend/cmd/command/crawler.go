@@ -1,22 +1,42 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 
+	"lucky/common/config"
+	applog "lucky/common/log"
 	"lucky/common/mysql"
+	"lucky/model"
 	"lucky/service"
 )
 
 func main() {
 	var (
-		gameCode = flag.String("game", "ssq", "游戏代码 (ssq/dlt)")
-		action   = flag.String("action", "test", "操作类型 (test/crawl/history)")
-		pages    = flag.Int("pages", 1, "抓取历史数据的页数")
+		gameCode   = flag.String("game", "ssq", "游戏代码 (ssq/dlt/fc3d/pl3/pl5/kl8/qlc/qxc)")
+		action     = flag.String("action", "test", "操作类型 (test/crawl/history/run/jobs/schedule)")
+		pages      = flag.Int("pages", 1, "抓取历史数据的页数")
+		configPath = flag.String("config", "", "配置文件路径，默认为 $LUCKY_CONFIG 或可执行文件目录下的 config/ini.ini")
 	)
 	flag.Parse()
 
+	// 加载配置
+	cfg, err := config.Init(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := cfg.ValidateDatabase(); err != nil {
+		log.Fatal(err)
+	}
+	applog.Init(cfg.Log)
+
 	// 初始化数据库
 	mysql.Init()
 
@@ -33,26 +53,44 @@ func main() {
 
 	case "crawl":
 		fmt.Printf("抓取并保存 %s 最新开奖数据...\n", *gameCode)
-		err := crawler.CrawlAndSaveLatest(*gameCode)
-		if err != nil {
-			log.Fatalf("抓取保存失败: %v", err)
-		}
+		runJob("crawl", map[string]string{"gameCode": *gameCode})
 		fmt.Println("抓取保存成功!")
 
 	case "history":
 		fmt.Printf("抓取 %s 历史数据，页数：%d...\n", *gameCode, *pages)
-		err := crawler.CrawlHistoryByPeriod(*gameCode, *pages)
+		runJob("backfill", map[string]string{"gameCode": *gameCode, "pages": strconv.Itoa(*pages)})
+		fmt.Println("历史数据抓取完成!")
+
+	case "run":
+		// 执行任务注册表中的任务，如 -action=run stats ssq 50
+		run, err := service.RunJobCommand(context.Background(), mysql.DB, strings.Join(flag.Args(), " "), model.JobTriggerCLI)
 		if err != nil {
-			log.Fatalf("历史数据抓取失败: %v", err)
+			log.Fatalf("任务执行失败: %v", err)
+		}
+		fmt.Println(run.Output)
+
+	case "jobs":
+		for _, job := range service.RegisteredJobs() {
+			fmt.Printf("%-36s %s\n", job.Usage(), job.Description)
 		}
-		fmt.Println("历史数据抓取完成!")
 
 	case "schedule":
 		fmt.Println("启动定时抓取任务...")
-		crawler.ScheduleCrawl()
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		crawler.ScheduleCrawl(ctx)
 
 	default:
 		fmt.Printf("不支持的操作: %s\n", *action)
-		fmt.Println("支持的操作: test, crawl, history, schedule")
+		fmt.Println("支持的操作: test, crawl, history, run, jobs, schedule")
+	}
+}
+
+// runJob 通过任务注册表执行任务并输出结果，失败时退出
+func runJob(name string, values map[string]string) {
+	run, err := service.RunJobByName(context.Background(), mysql.DB, name, values, model.JobTriggerCLI, 0)
+	if err != nil {
+		log.Fatalf("任务执行失败: %v", err)
 	}
+	fmt.Println(run.Output)
 }
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"lucky/common/openapi"
+)
+
+// 解析处理函数上的 swag 风格注释，生成 OpenAPI 路由表
+// 在 api 目录中通过 go generate 执行：go generate ./api
+func main() {
+	var (
+		dir     = flag.String("dir", ".", "处理函数所在目录")
+		out     = flag.String("out", "openapi_gen.go", "生成的文件")
+		varName = flag.String("var", "openAPIRoutes", "路由表变量名")
+	)
+	flag.Parse()
+
+	pkg, err := openapi.ParseDir(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := openapi.Generate(pkg, *varName, "cmd/openapi")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("已生成 %s，共 %d 个处理函数", *out, len(pkg.Annotations))
+}
@@ -1,25 +1,34 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"log"
-	"net/http"
 
+	"lucky/common/config"
+	applog "lucky/common/log"
 	"lucky/common/mysql"
+	"lucky/common/response"
+	"lucky/model"
 	"lucky/service"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/net/http2"
-	"golang.org/x/net/http2/h2c"
 )
 
-// Response 统一响应结构
-type Response struct {
-	Code    int         `json:"code"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
-}
-
 func main() {
+	configPath := flag.String("config", "", "配置文件路径，默认为 $LUCKY_CONFIG 或可执行文件目录下的 config/ini.ini")
+	flag.Parse()
+
+	// 加载配置
+	cfg, err := config.Init(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := cfg.ValidateDatabase(); err != nil {
+		log.Fatal(err)
+	}
+	applog.Init(cfg.Log)
+
 	// 初始化数据库
 	mysql.Init()
 	log.Println("MySQL连接成功")
@@ -31,10 +40,7 @@ func main() {
 	// 健康检查接口
 	r.GET("/health", func(c *gin.Context) {
 		log.Printf("[%s] %s - 健康检查", c.Request.Method, c.Request.RequestURI)
-		c.JSON(http.StatusOK, Response{
-			Code:    0,
-			Message: "OK",
-		})
+		response.Success(c, "OK", nil)
 	})
 
 	// 抓取任务接口
@@ -42,6 +48,10 @@ func main() {
 	{
 		// GET /task/crawl/:gameCode - 抓取并保存指定游戏的最新开奖数据
 		taskGroup.GET("/crawl/:gameCode", handleCrawlAndSave)
+		// GET /task/jobs - 已注册的任务列表
+		taskGroup.GET("/jobs", handleListJobs)
+		// GET /task/run/:name - 执行指定任务，参数通过查询参数传入
+		taskGroup.GET("/run/:name", handleRunJob)
 	}
 
 	// 启动 gRPC 服务（用于 gocron）
@@ -56,28 +66,6 @@ func main() {
 	}
 }
 
-// startGRPCServer 启动 gRPC 服务（支持 HTTP/2）
-func startGRPCServer() {
-	grpcPort := ":9091"
-
-	// 创建 HTTP 服务器处理 gRPC 请求
-	mux := http.NewServeMux()
-	mux.HandleFunc("/rpc.Task/Run", handleGRPCRequest)
-	mux.HandleFunc("/rpc.Task/Check", handleGRPCRequest)
-
-	// 使用 h2c (HTTP/2 Cleartext) 支持 gRPC over HTTP/2
-	h2s := &http2.Server{}
-	server := &http.Server{
-		Addr:    grpcPort,
-		Handler: h2c.NewHandler(mux, h2s),
-	}
-
-	log.Printf("gRPC 服务启动在端口 %s (支持 HTTP/2, 用于 gocron 调用)\n", grpcPort)
-	if err := server.ListenAndServe(); err != nil {
-		log.Fatalf("gRPC 服务启动失败: %v", err)
-	}
-}
-
 // handleCrawlAndSave 处理 GET 请求的抓取并保存任务
 func handleCrawlAndSave(c *gin.Context) {
 	log.Printf("[%s] %s - 接收到抓取请求", c.Request.Method, c.Request.RequestURI)
@@ -85,34 +73,57 @@ func handleCrawlAndSave(c *gin.Context) {
 	gameCode := c.Param("gameCode")
 
 	// 验证游戏代码
-	if gameCode != "ssq" && gameCode != "dlt" {
+	if _, err := service.GetGameByCode(mysql.DB, gameCode); err != nil {
 		log.Printf("不支持的游戏代码: %s", gameCode)
-		c.JSON(http.StatusBadRequest, Response{
-			Code:    400,
-			Message: "不支持的游戏代码，仅支持 ssq 或 dlt",
-		})
+		response.Fail(c, response.ErrGameNotFound.WithDetail(gameCode))
 		return
 	}
 
-	crawler := service.NewCrawlerService()
-
 	log.Printf("开始抓取并保存 %s 最新开奖数据...\n", gameCode)
-	err := crawler.CrawlAndSaveLatest(gameCode)
+	run, err := service.RunJobByName(c.Request.Context(), mysql.DB, "crawl", map[string]string{"gameCode": gameCode}, model.JobTriggerTaskAPI, 0)
 	if err != nil {
 		log.Printf("抓取并保存失败: %v\n", err)
-		c.JSON(http.StatusInternalServerError, Response{
-			Code:    500,
-			Message: "抓取并保存失败: " + err.Error(),
-		})
+		response.FailWithData(c, crawlJobError(err), run)
 		return
 	}
 
-	log.Printf("抓取并保存 %s 成功\n", gameCode)
-	c.JSON(http.StatusOK, Response{
-		Code:    0,
-		Message: "抓取并保存成功",
-		Data: map[string]string{
-			"game_code": gameCode,
-		},
+	log.Printf("抓取并保存 %s 成功: %s\n", gameCode, run.Output)
+	response.Success(c, "抓取并保存成功", map[string]interface{}{
+		"game_code": gameCode,
+		"job_run":   run,
 	})
 }
+
+// handleListJobs 返回已注册的任务及参数定义
+func handleListJobs(c *gin.Context) {
+	response.Success(c, "OK", service.RegisteredJobs())
+}
+
+// handleRunJob 按名称执行任务，任务参数通过查询参数传入，如 /task/run/backfill?gameCode=ssq&pages=2
+func handleRunJob(c *gin.Context) {
+	name := c.Param("name")
+	log.Printf("[%s] %s - 接收到任务请求", c.Request.Method, c.Request.RequestURI)
+
+	values := make(map[string]string)
+	for key := range c.Request.URL.Query() {
+		values[key] = c.Query(key)
+	}
+
+	run, err := service.RunJobByName(c.Request.Context(), mysql.DB, name, values, model.JobTriggerTaskAPI, 0)
+	if err != nil {
+		log.Printf("任务 %s 执行失败: %v\n", name, err)
+		response.FailWithData(c, err, run)
+		return
+	}
+
+	log.Printf("任务 %s 执行成功: %s\n", name, run.Output)
+	response.Success(c, "任务执行成功", run)
+}
+
+// crawlJobError 参数错误和已有同一任务在执行按错误码表响应，其余为抓取失败，失败原因见执行记录
+func crawlJobError(err error) error {
+	if errors.Is(err, service.ErrJobRunning) || errors.Is(err, service.ErrInvalidJob) {
+		return err
+	}
+	return response.ErrCrawlFailed.Wrap(err)
+}
@@ -0,0 +1,83 @@
+// Package cache 提供带标签失效的缓存接口，有 Redis 和进程内 LRU 两种实现
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	applog "lucky/common/log"
+	"lucky/common/metrics"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultMaxEntries 进程内缓存默认最多保存的条目数
+const DefaultMaxEntries = 10000
+
+// Cache 缓存接口，值为序列化后的字节
+// 写入时可附带标签，按标签失效时删除带有该标签的全部条目
+type Cache interface {
+	// Get 读取缓存，不存在或已过期时 ok 为 false
+	Get(key string) (value []byte, ok bool, err error)
+	// Set 写入缓存，ttl 为0时不过期
+	Set(key string, value []byte, ttl time.Duration, tags ...string) error
+	// Delete 删除指定的缓存
+	Delete(keys ...string) error
+	// InvalidateTags 删除带有任一标签的全部缓存
+	InvalidateTags(tags ...string) error
+}
+
+// Default 全局缓存，启动时启用 Redis 则替换为 Redis 实现
+var Default Cache = NewMemory(DefaultMaxEntries)
+
+// loadGroup 合并同一缓存键的并发加载，避免缓存失效时大量请求同时回源
+var loadGroup singleflight.Group
+
+// GetOrLoad 从 Default 缓存读取 key，不存在时调用 load 加载并写入缓存
+// 同一 key 同时只会有一个 load 在执行，其余调用等待并共享结果；缓存读写失败时记录 ctx 上的日志并直接回源
+func GetOrLoad[T any](ctx context.Context, key string, ttl time.Duration, tags []string, load func() (T, error)) (T, error) {
+	c := Default
+	var value T
+	if data, ok, err := c.Get(key); err != nil {
+		applog.Ctx(ctx).Warnf("读取缓存 %s 失败: %v", key, err)
+	} else if ok {
+		if err := json.Unmarshal(data, &value); err == nil {
+			metrics.ObserveCacheLookup(cacheName(key), true)
+			return value, nil
+		}
+	}
+	metrics.ObserveCacheLookup(cacheName(key), false)
+
+	result, err, _ := loadGroup.Do(key, func() (interface{}, error) {
+		loaded, err := load()
+		if err != nil {
+			return loaded, err
+		}
+		data, err := json.Marshal(loaded)
+		if err != nil {
+			return loaded, fmt.Errorf("序列化缓存 %s 失败: %v", key, err)
+		}
+		if err := c.Set(key, data, ttl, tags...); err != nil {
+			applog.Ctx(ctx).Warnf("写入缓存 %s 失败: %v", key, err)
+		}
+		return loaded, nil
+	})
+	if err != nil {
+		return value, err
+	}
+	return result.(T), nil
+}
+
+// cacheName 缓存类型，为缓存键第一个冒号前的部分，如 number_missing:ssq:30 为 number_missing
+func cacheName(key string) string {
+	name, _, _ := strings.Cut(key, ":")
+	return name
+}
+
+// InvalidateTags 删除 Default 缓存中带有任一标签的全部缓存
+func InvalidateTags(tags ...string) error {
+	return Default.InvalidateTags(tags...)
+}
@@ -0,0 +1,228 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryLRU(t *testing.T) {
+	m := NewMemory(2)
+	m.Set("a", []byte("1"), 0)
+	m.Set("b", []byte("2"), 0)
+	// 访问 a 后 b 成为最久未访问的条目
+	if _, ok, _ := m.Get("a"); !ok {
+		t.Fatal("a 应存在")
+	}
+	m.Set("c", []byte("3"), 0)
+
+	if _, ok, _ := m.Get("b"); ok {
+		t.Error("超过最大条目数时应淘汰 b")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok, _ := m.Get(key); !ok {
+			t.Errorf("%s 应存在", key)
+		}
+	}
+	if m.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", m.Len())
+	}
+}
+
+func TestMemoryTTL(t *testing.T) {
+	m := NewMemory(10)
+	m.Set("a", []byte("1"), 10*time.Millisecond)
+	m.Set("b", []byte("2"), 0)
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok, _ := m.Get("a"); ok {
+		t.Error("a 应已过期")
+	}
+	if value, ok, _ := m.Get("b"); !ok || string(value) != "2" {
+		t.Errorf("b = %q, %v", value, ok)
+	}
+}
+
+func TestMemoryTags(t *testing.T) {
+	m := NewMemory(10)
+	m.Set("ssq:1", []byte("1"), 0, "draws:ssq")
+	m.Set("ssq:2", []byte("2"), 0, "draws:ssq", "stats")
+	m.Set("dlt:1", []byte("3"), 0, "draws:dlt")
+
+	m.InvalidateTags("draws:ssq")
+	for _, key := range []string{"ssq:1", "ssq:2"} {
+		if _, ok, _ := m.Get(key); ok {
+			t.Errorf("%s 应已失效", key)
+		}
+	}
+	if _, ok, _ := m.Get("dlt:1"); !ok {
+		t.Error("其他标签的缓存不应失效")
+	}
+
+	// 覆盖写入后旧标签不再关联
+	m.Set("dlt:1", []byte("4"), 0)
+	m.InvalidateTags("draws:dlt")
+	if value, ok, _ := m.Get("dlt:1"); !ok || string(value) != "4" {
+		t.Errorf("覆盖写入后不应受旧标签影响, got %q %v", value, ok)
+	}
+	if len(m.tags) != 0 {
+		t.Errorf("标签索引应已清理: %v", m.tags)
+	}
+}
+
+// fakeRedis 以 map 模拟 Redis 命令
+type fakeRedis struct {
+	values map[string][]byte
+	sets   map[string]map[string]bool
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{values: make(map[string][]byte), sets: make(map[string]map[string]bool)}
+}
+
+func (f *fakeRedis) GetBytes(key string) ([]byte, bool, error) {
+	value, ok := f.values[key]
+	return value, ok, nil
+}
+
+func (f *fakeRedis) Set(key string, value interface{}, expiration time.Duration) (string, error) {
+	f.values[key] = value.([]byte)
+	return "OK", nil
+}
+
+func (f *fakeRedis) Delete(keys ...string) (int64, error) {
+	for _, key := range keys {
+		delete(f.values, key)
+		delete(f.sets, key)
+	}
+	return int64(len(keys)), nil
+}
+
+func (f *fakeRedis) SAdd(key string, members ...interface{}) (int64, error) {
+	if f.sets[key] == nil {
+		f.sets[key] = make(map[string]bool)
+	}
+	for _, member := range members {
+		f.sets[key][member.(string)] = true
+	}
+	return int64(len(members)), nil
+}
+
+func (f *fakeRedis) SMembers(key string) ([]string, error) {
+	var members []string
+	for member := range f.sets[key] {
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+func (f *fakeRedis) Expire(key string, expiration time.Duration) (bool, error) {
+	return true, nil
+}
+
+func TestRedisTags(t *testing.T) {
+	client := newFakeRedis()
+	r := NewRedis(client)
+	r.Set("ssq:1", []byte("1"), time.Hour, "draws:ssq")
+	r.Set("dlt:1", []byte("2"), time.Hour, "draws:dlt")
+
+	if value, ok, _ := r.Get("ssq:1"); !ok || string(value) != "1" {
+		t.Fatalf("ssq:1 = %q, %v", value, ok)
+	}
+	if err := r.InvalidateTags("draws:ssq"); err != nil {
+		t.Fatalf("InvalidateTags: %v", err)
+	}
+	if _, ok, _ := r.Get("ssq:1"); ok {
+		t.Error("ssq:1 应已失效")
+	}
+	if _, ok := client.sets[tagKeyPrefix+"draws:ssq"]; ok {
+		t.Error("标签集合应已删除")
+	}
+	if _, ok, _ := r.Get("dlt:1"); !ok {
+		t.Error("其他标签的缓存不应失效")
+	}
+}
+
+// useCache 测试期间替换 Default 缓存
+func useCache(t *testing.T, c Cache) {
+	previous := Default
+	Default = c
+	t.Cleanup(func() { Default = previous })
+}
+
+func TestGetOrLoad(t *testing.T) {
+	useCache(t, NewMemory(10))
+	type stats struct {
+		Count int `json:"count"`
+	}
+
+	loads := 0
+	load := func() (*stats, error) {
+		loads++
+		return &stats{Count: 3}, nil
+	}
+	for i := 0; i < 2; i++ {
+		value, err := GetOrLoad(context.Background(), "stats", time.Minute, []string{"draws:ssq"}, load)
+		if err != nil || value.Count != 3 {
+			t.Fatalf("GetOrLoad = %+v, %v", value, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("命中缓存时不应重复加载, loads=%d", loads)
+	}
+
+	InvalidateTags("draws:ssq")
+	GetOrLoad(context.Background(), "stats", time.Minute, nil, load)
+	if loads != 2 {
+		t.Errorf("按标签失效后应重新加载, loads=%d", loads)
+	}
+
+	// 加载失败时不写入缓存
+	loadErr := errors.New("boom")
+	if _, err := GetOrLoad(context.Background(), "failed", time.Minute, nil, func() (int, error) { return 0, loadErr }); err != loadErr {
+		t.Errorf("应返回加载错误, got %v", err)
+	}
+	if _, ok, _ := Default.Get("failed"); ok {
+		t.Error("加载失败时不应写入缓存")
+	}
+}
+
+func TestGetOrLoadSingleFlight(t *testing.T) {
+	useCache(t, NewMemory(10))
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func() (int, error) {
+		loads.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make(chan int, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, _ := GetOrLoad(context.Background(), "slow", time.Minute, nil, load)
+			results <- value
+		}()
+	}
+	// 等待全部调用进入加载等待后再放行
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	if n := loads.Load(); n != 1 {
+		t.Errorf("并发请求同一缓存键应只加载1次, loads=%d", n)
+	}
+	for value := range results {
+		if value != 42 {
+			t.Errorf("value = %d, want 42", value)
+		}
+	}
+}
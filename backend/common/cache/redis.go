@@ -0,0 +1,71 @@
+package cache
+
+import "time"
+
+// tagKeyPrefix 标签集合的键前缀，集合中保存带有该标签的缓存键
+const tagKeyPrefix = "cache_tag:"
+
+// RedisClient Redis 缓存依赖的命令，由 common/redis.RedisDB 实现
+type RedisClient interface {
+	GetBytes(key string) ([]byte, bool, error)
+	Set(key string, value interface{}, expiration time.Duration) (string, error)
+	Delete(keys ...string) (int64, error)
+	SAdd(key string, members ...interface{}) (int64, error)
+	SMembers(key string) ([]string, error)
+	Expire(key string, expiration time.Duration) (bool, error)
+}
+
+// Redis 基于 Redis 的缓存，多实例共享，标签对应的缓存键保存在 Redis 集合中
+type Redis struct {
+	client RedisClient
+}
+
+// NewRedis 创建 Redis 缓存
+func NewRedis(client RedisClient) *Redis {
+	return &Redis{client: client}
+}
+
+func (r *Redis) Get(key string) ([]byte, bool, error) {
+	return r.client.GetBytes(key)
+}
+
+func (r *Redis) Set(key string, value []byte, ttl time.Duration, tags ...string) error {
+	if _, err := r.client.Set(key, value, ttl); err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		tagKey := tagKeyPrefix + tag
+		if _, err := r.client.SAdd(tagKey, key); err != nil {
+			return err
+		}
+		// 标签集合比缓存条目多保留一段时间，同一标签的缓存使用相同的过期时间，集合过期时其中的键也已过期
+		if ttl > 0 {
+			if _, err := r.client.Expire(tagKey, ttl+time.Minute); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Redis) Delete(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := r.client.Delete(keys...)
+	return err
+}
+
+func (r *Redis) InvalidateTags(tags ...string) error {
+	for _, tag := range tags {
+		tagKey := tagKeyPrefix + tag
+		keys, err := r.client.SMembers(tagKey)
+		if err != nil {
+			return err
+		}
+		if err := r.Delete(append(keys, tagKey)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,28 +1,346 @@
 package config
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"net"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"lucky/common/ratelimit"
 
 	"gopkg.in/ini.v1"
 )
 
-var Config *ini.File
+// EnvPrefix 环境变量前缀，LUCKY_<节>_<键> 覆盖配置文件中对应的值，如 LUCKY_JWT_SECRET、LUCKY_MYSQL_PASSWORD
+const EnvPrefix = "LUCKY_"
+
+// EnvConfigPath 指定配置文件路径的环境变量，优先级低于 -config 参数
+const EnvConfigPath = "LUCKY_CONFIG"
+
+// ErrInvalidConfig 配置不完整或格式错误
+var ErrInvalidConfig = errors.New("配置错误")
+
+// Config 应用配置，每个字段对应 ini 文件的一节
+type Config struct {
+	Server    ServerConfig    `ini:"server"`
+	MySQL     MySQLConfig     `ini:"mysql"`
+	Database  DatabaseConfig  `ini:"database"`
+	Redis     RedisConfig     `ini:"redis"`
+	JWT       JWTConfig       `ini:"jwt"`
+	WeChat    WeChatConfig    `ini:"wechat"`
+	Log       LogConfig       `ini:"log"`
+	Cache     CacheConfig     `ini:"cache"`
+	RateLimit RateLimitConfig `ini:"rate_limit"`
+}
+
+// ServerConfig HTTP 服务配置
+type ServerConfig struct {
+	Addr            string        `ini:"addr"`             // 监听地址，默认 :8080
+	Mode            string        `ini:"mode"`             // gin 运行模式：debug、release、test
+	ShutdownTimeout time.Duration `ini:"shutdown_timeout"` // 关闭时等待进行中的请求和任务的最长时间
+	TrustedProxies  []string      `ini:"trusted_proxies"`  // 可信反向代理的 IP 或网段，逗号分隔；只有来自这些地址的请求才按 X-Forwarded-For 取客户端 IP，默认不信任任何代理
+}
+
+// MySQLConfig MySQL 连接配置
+type MySQLConfig struct {
+	Host     string `ini:"host"`
+	Port     string `ini:"port"`
+	User     string `ini:"user"`
+	Password string `ini:"password"`
+	DataBase string `ini:"db"`
+}
+
+// DSN MySQL 连接串
+func (c MySQLConfig) DSN() string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+		c.User, c.Password, c.Host, c.Port, c.DataBase)
+}
+
+// DatabaseConfig 数据库类型配置
+type DatabaseConfig struct {
+	Driver string `ini:"driver"` // mysql 或 sqlite，默认 mysql
+	Path   string `ini:"path"`   // SQLite 数据库文件路径，:memory: 为内存数据库
+}
+
+// RedisConfig Redis 配置
+type RedisConfig struct {
+	Host         string `ini:"host"`
+	Port         int    `ini:"port"`
+	Enabled      bool   `ini:"enabled"`
+	PoolSize     int    `ini:"pool_size"`
+	RequirePass  string `ini:"password"`
+	MaxClients   int    `ini:"max_clients"`
+	Db           int    `ini:"db"`
+	MinIdleConns int    `ini:"min_idle_conns"`
+}
 
-func init() {
+// JWTConfig JWT 配置，过期时间格式如 168h
+type JWTConfig struct {
+	Secret             string        `ini:"secret"`
+	AccessTokenExpire  time.Duration `ini:"access_token_expire"`
+	RefreshTokenExpire time.Duration `ini:"refresh_token_expire"`
+	Issuer             string        `ini:"issuer"`
+	Subject            string        `ini:"subject"`
+}
+
+// WeChatConfig 微信小程序配置
+type WeChatConfig struct {
+	AppID     string `ini:"app_id"`
+	AppSecret string `ini:"app_secret"`
+}
+
+// LogConfig 日志配置
+type LogConfig struct {
+	Level       string `ini:"level"`
+	File        string `ini:"file"`
+	WithConsole bool   `ini:"with_console"`
+	Format      string `ini:"format"`      // text 或 json
+	MaxSize     int    `ini:"max_size"`    // 单个日志文件的最大大小(MB)，超过后轮转
+	MaxBackups  int    `ini:"max_backups"` // 保留的旧日志文件数，0 为不限
+	MaxAge      int    `ini:"max_age"`     // 旧日志文件保留天数，0 为不限
+	Compress    bool   `ini:"compress"`    // 是否 gzip 压缩旧日志文件
+}
+
+// CacheConfig 缓存配置
+type CacheConfig struct {
+	MaxEntries int `ini:"max_entries"` // 未启用 Redis 时进程内缓存的最大条目数
+}
+
+// RateLimitConfig 限流配置，每项为一类接口的令牌桶，格式为 <次数>/<时间>，如 10/1m 表示每分钟 10 次，off 为不限流
+// 已登录的请求按用户计数，否则按客户端 IP 计数
+type RateLimitConfig struct {
+	Enabled  bool   `ini:"enabled"`
+	Login    string `ini:"login"`    // 登录和微信登录
+	Generate string `ini:"generate"` // 缩水生成号码和旋转矩阵
+	Missing  string `ini:"missing"`  // 遗漏数据，缓存未命中时回源抓取
+	Crawler  string `ini:"crawler"`  // 手动触发抓取
+	Admin    string `ini:"admin"`    // 管理后台
+}
+
+// Policy 按名称取限流配置，名称为 ini 中的键，如 login
+func (c RateLimitConfig) Policy(name string) string {
+	for _, policy := range c.policies() {
+		if policy.name == name {
+			return policy.value
+		}
+	}
+	return ""
+}
+
+// policies 全部限流配置项
+func (c RateLimitConfig) policies() []struct{ name, value string } {
+	return []struct{ name, value string }{
+		{"login", c.Login}, {"generate", c.Generate}, {"missing", c.Missing}, {"crawler", c.Crawler}, {"admin", c.Admin},
+	}
+}
+
+// Defaults 默认配置，配置文件和环境变量中没有的项保持默认值
+func Defaults() *Config {
+	return &Config{
+		Server:   ServerConfig{Addr: ":8080", Mode: "debug", ShutdownTimeout: 15 * time.Second},
+		MySQL:    MySQLConfig{Host: "localhost", Port: "3306"},
+		Database: DatabaseConfig{Driver: "mysql", Path: "data/lucky.db"},
+		Redis:    RedisConfig{Host: "localhost", Port: 6379, PoolSize: 100, MinIdleConns: 10},
+		JWT: JWTConfig{
+			AccessTokenExpire:  time.Hour * 24 * 7,
+			RefreshTokenExpire: time.Hour * 24 * 30,
+		},
+		Log: LogConfig{Level: "debug", File: "./log/lucky.log", Format: "text", MaxSize: 100, MaxBackups: 7, MaxAge: 30},
+		RateLimit: RateLimitConfig{
+			Enabled:  true,
+			Login:    "10/1m",
+			Generate: "30/1m",
+			Missing:  "60/1m",
+			Crawler:  "5/1m",
+			Admin:    "120/1m",
+		},
+	}
+}
+
+// DefaultPath 默认配置文件路径：LUCKY_CONFIG 环境变量，否则为可执行文件所在目录下的 config/ini.ini
+func DefaultPath() string {
+	if path := os.Getenv(EnvConfigPath); path != "" {
+		return path
+	}
 	exePath, err := os.Executable()
 	if err != nil {
-		log.Fatal("无法获取可执行文件路径: ", err)
+		return filepath.Join("config", "ini.ini")
+	}
+	return filepath.Join(filepath.Dir(exePath), "config", "ini.ini")
+}
+
+// Load 读取配置：默认值 < 配置文件 < 环境变量
+// path 为空时不读取配置文件，只使用默认值和环境变量
+func Load(path string) (*Config, error) {
+	file := ini.Empty()
+	if path != "" {
+		loaded, err := ini.Load(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: 读取配置文件 %s 失败: %v", ErrInvalidConfig, path, err)
+		}
+		file = loaded
+	}
+	applyEnv(file, os.LookupEnv)
+
+	cfg := Defaults()
+	if err := file.StrictMapTo(cfg); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	return cfg, nil
+}
+
+// applyEnv 将 LUCKY_<节>_<键> 环境变量写入配置文件对应的键
+func applyEnv(file *ini.File, lookup func(string) (string, bool)) {
+	configType := reflect.TypeOf(Config{})
+	for i := 0; i < configType.NumField(); i++ {
+		sectionField := configType.Field(i)
+		section := sectionField.Tag.Get("ini")
+		for j := 0; j < sectionField.Type.NumField(); j++ {
+			key := sectionField.Type.Field(j).Tag.Get("ini")
+			if value, ok := lookup(EnvName(section, key)); ok {
+				file.Section(section).Key(key).SetValue(value)
+			}
+		}
+	}
+}
+
+// EnvName 配置项对应的环境变量名
+func EnvName(section, key string) string {
+	return EnvPrefix + strings.ToUpper(section) + "_" + strings.ToUpper(key)
+}
+
+// ValidateDatabase 校验数据库配置，命令行工具只需要数据库时使用
+func (c *Config) ValidateDatabase() error {
+	return invalid(c.databaseProblems())
+}
+
+// databaseProblems 数据库配置的问题
+func (c *Config) databaseProblems() []string {
+	var problems []string
+	switch c.Database.Driver {
+	case "mysql":
+		var missing []string
+		for _, item := range []struct{ key, value string }{
+			{"host", c.MySQL.Host}, {"port", c.MySQL.Port}, {"user", c.MySQL.User}, {"db", c.MySQL.DataBase},
+		} {
+			if item.value == "" {
+				missing = append(missing, item.key)
+			}
+		}
+		if len(missing) > 0 {
+			problems = append(problems, fmt.Sprintf("MySQL 连接信息不完整，缺少 [mysql] %s", strings.Join(missing, "、")))
+		}
+	case "sqlite":
+		if c.Database.Path == "" {
+			problems = append(problems, "[database] path 未配置 SQLite 数据库文件路径")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("[database] driver 只支持 mysql 或 sqlite: %s", c.Database.Driver))
+	}
+	return problems
+}
+
+// Validate 校验 Web 服务需要的全部配置，列出所有缺失项
+func (c *Config) Validate() error {
+	problems := c.databaseProblems()
+	if c.Server.Addr == "" {
+		problems = append(problems, "[server] addr 未配置监听地址")
+	}
+	switch c.Server.Mode {
+	case "debug", "release", "test":
+	default:
+		problems = append(problems, fmt.Sprintf("[server] mode 只支持 debug、release 或 test: %s", c.Server.Mode))
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		problems = append(problems, "[server] shutdown_timeout 必须大于0")
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				problems = append(problems, fmt.Sprintf("[server] trusted_proxies 不是有效的 IP 或网段: %s", proxy))
+			}
+		}
+	}
+	if c.Log.Format != "text" && c.Log.Format != "json" {
+		problems = append(problems, fmt.Sprintf("[log] format 只支持 text 或 json: %s", c.Log.Format))
+	}
+	for _, policy := range c.RateLimit.policies() {
+		if _, _, err := ratelimit.ParseLimit(policy.value); err != nil {
+			problems = append(problems, fmt.Sprintf("[rate_limit] %s %v", policy.name, err))
+		}
+	}
+	if c.JWT.Secret == "" {
+		problems = append(problems, fmt.Sprintf("[jwt] secret 未配置（或设置环境变量 %s）", EnvName("jwt", "secret")))
+	}
+	if c.JWT.AccessTokenExpire <= 0 || c.JWT.RefreshTokenExpire <= 0 {
+		problems = append(problems, "[jwt] Token 过期时间必须大于0")
+	}
+	if c.WeChat.AppID == "" || c.WeChat.AppSecret == "" {
+		problems = append(problems, fmt.Sprintf("[wechat] app_id 和 app_secret 未配置（或设置环境变量 %s、%s）",
+			EnvName("wechat", "app_id"), EnvName("wechat", "app_secret")))
+	}
+	return invalid(problems)
+}
+
+// invalid 将发现的问题合并为 ErrInvalidConfig
+func invalid(problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidConfig, strings.Join(problems, "; "))
+}
+
+var (
+	mu      sync.RWMutex
+	current *Config
+)
+
+// Init 加载配置，程序启动时调用一次
+// path 为空时使用 DefaultPath()，默认路径的文件不存在时只使用默认值和环境变量；指定的文件不存在时返回错误
+func Init(path string) (*Config, error) {
+	if path == "" {
+		path = DefaultPath()
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			path = ""
+		}
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		return nil, err
 	}
-	baseDir := filepath.Dir(exePath)
-	fmt.Println("test", baseDir)
-	ConfigFile, err := ini.Load(baseDir + "/config/ini.ini")
-	// ConfigFile, err := ini.Load("D:/go/src/lucky/backend/config/local.ini")
+	Set(cfg)
+	return cfg, nil
+}
+
+// Set 替换当前配置，测试中用于注入配置
+func Set(cfg *Config) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = cfg
+}
+
+// Get 当前配置，未调用 Init 或 Set 时为默认值加环境变量
+func Get() *Config {
+	mu.RLock()
+	cfg := current
+	mu.RUnlock()
+	if cfg != nil {
+		return cfg
+	}
+
+	cfg, err := Load("")
 	if err != nil {
-		log.Fatal("未找到配置文件", err)
-		return
+		cfg = Defaults()
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if current == nil {
+		current = cfg
 	}
-	Config = ConfigFile
+	return current
 }
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfig 在临时目录写入配置文件
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ini.ini")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `
+[server]
+trusted_proxies = 10.0.0.1, 172.16.0.0/12
+
+[mysql]
+host = db.local
+user = lucky
+password = file-password
+db = lottery
+
+[jwt]
+secret = file-secret
+access_token_expire = 2h
+
+[redis]
+enabled = true
+port = 6380
+
+[rate_limit]
+missing = off
+`)
+	t.Setenv("LUCKY_MYSQL_PASSWORD", "env-password")
+	t.Setenv("LUCKY_RATE_LIMIT_LOGIN", "3/1m")
+	t.Setenv("LUCKY_WECHAT_APP_ID", "wx123")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.MySQL.Host != "db.local" || cfg.MySQL.Port != "3306" {
+		t.Errorf("未配置的项应保留默认值: %+v", cfg.MySQL)
+	}
+	if got := strings.Join(cfg.Server.TrustedProxies, ","); got != "10.0.0.1,172.16.0.0/12" {
+		t.Errorf("trusted_proxies = %v", cfg.Server.TrustedProxies)
+	}
+	if cfg.MySQL.Password != "env-password" || cfg.WeChat.AppID != "wx123" {
+		t.Errorf("环境变量应覆盖配置文件: mysql=%+v wechat=%+v", cfg.MySQL, cfg.WeChat)
+	}
+	if cfg.JWT.Secret != "file-secret" || cfg.JWT.AccessTokenExpire != 2*time.Hour || cfg.JWT.RefreshTokenExpire != 30*24*time.Hour {
+		t.Errorf("jwt = %+v", cfg.JWT)
+	}
+	if !cfg.Redis.Enabled || cfg.Redis.Port != 6380 || cfg.Redis.PoolSize != 100 {
+		t.Errorf("redis = %+v", cfg.Redis)
+	}
+	if !cfg.RateLimit.Enabled || cfg.RateLimit.Policy("login") != "3/1m" || cfg.RateLimit.Missing != "off" || cfg.RateLimit.Crawler != "5/1m" {
+		t.Errorf("rate_limit = %+v", cfg.RateLimit)
+	}
+	if got := cfg.MySQL.DSN(); got != "lucky:env-password@tcp(db.local:3306)/lottery?charset=utf8mb4&parseTime=True&loc=Local" {
+		t.Errorf("DSN() = %s", got)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.ini")); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("配置文件不存在应返回 ErrInvalidConfig, got %v", err)
+	}
+
+	path := writeConfig(t, "[jwt]\naccess_token_expire = 7天\n")
+	if _, err := Load(path); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("过期时间格式错误应返回 ErrInvalidConfig, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := Defaults()
+	err := cfg.Validate()
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("缺少必填项应返回 ErrInvalidConfig, got %v", err)
+	}
+	for _, want := range []string{"[mysql] user、db", "[jwt] secret", "LUCKY_JWT_SECRET", "[wechat] app_id"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("错误信息应包含 %q: %v", want, err)
+		}
+	}
+
+	cfg.MySQL.User, cfg.MySQL.DataBase = "lucky", "lottery"
+	cfg.JWT.Secret = "secret"
+	cfg.WeChat = WeChatConfig{AppID: "wx123", AppSecret: "wx-secret"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("配置完整时不应返回错误: %v", err)
+	}
+
+	cfg.Server.Mode = "production"
+	cfg.Server.ShutdownTimeout = 0
+	cfg.Server.TrustedProxies = []string{"10.0.0.1", "proxy.local"}
+	err = cfg.Validate()
+	for _, want := range []string{"[server] mode", "production", "[server] shutdown_timeout", "[server] trusted_proxies", "proxy.local"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("错误信息应包含 %q: %v", want, err)
+		}
+	}
+	cfg.Server = Defaults().Server
+
+	cfg.RateLimit.Crawler = "5次/分钟"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "[rate_limit] crawler") {
+		t.Errorf("限流配置格式错误应返回错误, got %v", err)
+	}
+	cfg.RateLimit = Defaults().RateLimit
+
+	// SQLite 不需要 MySQL 连接信息
+	sqlite := Defaults()
+	sqlite.Database.Driver = "sqlite"
+	if err := sqlite.ValidateDatabase(); err != nil {
+		t.Errorf("SQLite 配置不应返回错误: %v", err)
+	}
+	sqlite.Database.Driver = "postgres"
+	if err := sqlite.ValidateDatabase(); err == nil || !strings.Contains(err.Error(), "postgres") {
+		t.Errorf("不支持的数据库类型应返回错误, got %v", err)
+	}
+}
+
+func TestSetGet(t *testing.T) {
+	previous := Get()
+	defer Set(previous)
+
+	cfg := Defaults()
+	cfg.JWT.Secret = "injected"
+	Set(cfg)
+	if Get().JWT.Secret != "injected" {
+		t.Errorf("Get() 应返回注入的配置")
+	}
+}
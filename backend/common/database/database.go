@@ -0,0 +1,88 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// 支持的数据库类型
+const (
+	DriverMySQL  = "mysql"
+	DriverSQLite = "sqlite"
+)
+
+// DefaultSQLitePath SQLite 数据库文件默认路径
+const DefaultSQLitePath = "data/lucky.db"
+
+// Open 按数据库类型打开连接，dsn 为 MySQL 连接串或 SQLite 文件路径
+func Open(driver, dsn string) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch strings.ToLower(driver) {
+	case "", DriverMySQL:
+		dialector = mysql.Open(dsn)
+	case DriverSQLite:
+		sqliteDSN, err := SQLiteDSN(dsn)
+		if err != nil {
+			return nil, err
+		}
+		dialector = sqlite.Open(sqliteDSN)
+	default:
+		return nil, fmt.Errorf("不支持的数据库类型: %s", driver)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	if IsSQLite(db) {
+		// SQLite 同一时间只允许一个写连接，限制连接数避免 database is locked
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDB.SetMaxOpenConns(1)
+	}
+	return db, nil
+}
+
+// SQLiteDSN 生成 SQLite 连接串：开启外键约束、忙等待，时间按本地时区解析；
+// 文件数据库使用 WAL 模式并自动创建所在目录
+func SQLiteDSN(path string) (string, error) {
+	if path == "" {
+		path = DefaultSQLitePath
+	}
+	params := "_foreign_keys=1&_busy_timeout=5000&_loc=auto"
+	if path == ":memory:" || strings.HasPrefix(path, "file::memory:") || strings.Contains(path, "mode=memory") {
+		return joinParams(path, params), nil
+	}
+
+	file := strings.TrimPrefix(path, "file:")
+	if i := strings.Index(file, "?"); i >= 0 {
+		file = file[:i]
+	}
+	if dir := filepath.Dir(file); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", fmt.Errorf("创建 SQLite 数据目录失败: %v", err)
+		}
+	}
+	return joinParams(path, params+"&_journal_mode=WAL"), nil
+}
+
+// joinParams 在连接串末尾追加参数
+func joinParams(path, params string) string {
+	if strings.Contains(path, "?") {
+		return path + "&" + params
+	}
+	return path + "?" + params
+}
+
+// IsSQLite 是否为 SQLite 连接
+func IsSQLite(db *gorm.DB) bool {
+	return db != nil && db.Dialector.Name() == DriverSQLite
+}
@@ -0,0 +1,37 @@
+// Package dbtest 为测试提供执行过全部迁移的 SQLite 内存数据库
+package dbtest
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"lucky/common/database"
+	"lucky/migration"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var seq atomic.Int64
+
+// Open 打开独立的 SQLite 内存数据库并执行全部迁移，测试结束时关闭
+func Open(t testing.TB) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:lucky_test_%d?mode=memory&cache=shared", seq.Add(1))
+	db, err := database.Open(database.DriverSQLite, dsn)
+	if err != nil {
+		t.Fatalf("打开测试数据库失败: %v", err)
+	}
+	// 测试中预期的查询失败较多，不输出 SQL 日志
+	db = db.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Silent)})
+	if _, err := migration.Up(db, 0); err != nil {
+		t.Fatalf("执行迁移失败: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
@@ -22,42 +22,9 @@ ErrTokenExpired = errors.New("token已过期")
 ErrTokenInvalid = errors.New("无效的token")
 )
 
-// JWTConfig JWT配置结构
-type JWTConfig struct {
-Secret                string
-AccessTokenExpire     time.Duration
-RefreshTokenExpire    time.Duration
-Issuer                string
-Subject               string
-}
-
-// getJWTConfig 从配置文件获取JWT配置
-func getJWTConfig() *JWTConfig {
-section := config.Config.Section("jwt")
-
-// 解析过期时间
-accessExpireStr := section.Key("access_token_expire").String()
-refreshExpireStr := section.Key("refresh_token_expire").String()
-
-accessExpire, err := time.ParseDuration(accessExpireStr)
-if err != nil {
-// 默认7天
-accessExpire = time.Hour * 24 * 7
-}
-
-refreshExpire, err := time.ParseDuration(refreshExpireStr)
-if err != nil {
-// 默认30天
-refreshExpire = time.Hour * 24 * 30
-}
-
-return &JWTConfig{
-Secret:             section.Key("secret").String(),
-AccessTokenExpire:  accessExpire,
-RefreshTokenExpire: refreshExpire,
-Issuer:             section.Key("issuer").String(),
-Subject:            section.Key("subject").String(),
-}
+// getJWTConfig 获取JWT配置，启动时已加载，过期时间未配置时默认7天和30天
+func getJWTConfig() config.JWTConfig {
+return config.Get().JWT
 }
 
 // GenerateToken 生成JWT Token
@@ -0,0 +1,80 @@
+package log
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 请求上下文中携带的日志字段
+const (
+	FieldRequestID = "request_id"
+	FieldUserID    = "user_id"
+	FieldGameCode  = "game_code"
+)
+
+type fieldsKey struct{}
+
+// Ctx 带有请求上下文字段（请求ID、用户ID、游戏代码）的日志，ctx 为 nil 时不带字段
+func Ctx(ctx context.Context) *logrus.Entry {
+	if ctx == nil {
+		return logrus.NewEntry(logger)
+	}
+	return logger.WithContext(ctx).WithFields(contextFields(ctx))
+}
+
+// WithRequestID 在 ctx 中记录请求ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return withField(ctx, FieldRequestID, requestID)
+}
+
+// WithUserID 在 ctx 中记录用户ID
+func WithUserID(ctx context.Context, userID int64) context.Context {
+	return withField(ctx, FieldUserID, userID)
+}
+
+// WithGameCode 在 ctx 中记录游戏代码，为空时不记录
+func WithGameCode(ctx context.Context, gameCode string) context.Context {
+	if gameCode == "" {
+		return ctx
+	}
+	return withField(ctx, FieldGameCode, gameCode)
+}
+
+// RequestID ctx 中的请求ID，没有时为空
+func RequestID(ctx context.Context) string {
+	requestID, _ := contextFields(ctx)[FieldRequestID].(string)
+	return requestID
+}
+
+// contextFields ctx 中的日志字段，调用方不能修改返回值
+func contextFields(ctx context.Context) logrus.Fields {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).(logrus.Fields)
+	return fields
+}
+
+// withField 复制 ctx 中已有的字段并追加新字段，不影响父 ctx
+func withField(ctx context.Context, key string, value interface{}) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	parent := contextFields(ctx)
+	fields := make(logrus.Fields, len(parent)+1)
+	for k, v := range parent {
+		fields[k] = v
+	}
+	fields[key] = value
+	return context.WithValue(ctx, fieldsKey{}, fields)
+}
+
+// CopyFields 将 src 中的日志字段带到 dst，请求结束后继续执行的后台任务沿用请求ID
+func CopyFields(dst, src context.Context) context.Context {
+	fields := contextFields(src)
+	if len(fields) == 0 {
+		return dst
+	}
+	return context.WithValue(dst, fieldsKey{}, fields)
+}
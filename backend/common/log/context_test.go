@@ -0,0 +1,63 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestContextFields(t *testing.T) {
+	parent := WithRequestID(context.Background(), "req-1")
+	ctx := WithGameCode(WithUserID(parent, 42), "ssq")
+
+	if got := RequestID(ctx); got != "req-1" {
+		t.Errorf("RequestID = %q, want req-1", got)
+	}
+	fields := contextFields(ctx)
+	if fields[FieldUserID] != int64(42) || fields[FieldGameCode] != "ssq" {
+		t.Errorf("字段 = %v", fields)
+	}
+	// 子 context 追加字段不影响父 context
+	if _, ok := contextFields(parent)[FieldGameCode]; ok {
+		t.Error("父 context 不应带有游戏代码")
+	}
+	if WithGameCode(parent, "") != parent {
+		t.Error("游戏代码为空时不应追加字段")
+	}
+	if RequestID(context.Background()) != "" {
+		t.Error("没有请求ID时应为空")
+	}
+
+	base, cancel := context.WithCancel(context.Background())
+	copied := CopyFields(base, ctx)
+	cancel()
+	if RequestID(copied) != "req-1" || copied.Err() == nil {
+		t.Error("CopyFields 应带上日志字段并沿用 dst 的取消")
+	}
+}
+
+func TestCtxJSONOutput(t *testing.T) {
+	defer func() {
+		SetOutput(os.Stderr)
+		SetFormatter("text")
+	}()
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	SetFormatter("json")
+	SetLevel(logrus.InfoLevel)
+
+	ctx := WithGameCode(WithRequestID(context.Background(), "req-2"), "dlt")
+	Ctx(ctx).Info("抓取完成")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("应输出 JSON: %v, %s", err, buf.String())
+	}
+	if entry[FieldRequestID] != "req-2" || entry[FieldGameCode] != "dlt" || entry["msg"] != "抓取完成" {
+		t.Errorf("日志字段不正确: %v", entry)
+	}
+}
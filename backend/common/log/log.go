@@ -10,27 +10,26 @@ import (
 	"lucky/common/config"
 
 	"github.com/sirupsen/logrus"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 var logger *logrus.Logger
 
 func init() {
+	// 未调用 Init 前输出到控制台
 	logger = logrus.New()
-	initFromConfig()
+	SetFormatter("text")
 }
 
-// initFromConfig 从配置文件初始化日志设置
-func initFromConfig() {
-	// 读取日志配置
-	logSection := config.Config.Section("log")
-
-	// 设置日志等级
-	levelStr := logSection.Key("level").String()
-	level := parseLogLevel(levelStr)
-	SetLevel(level)
+// Init 按配置设置日志等级、格式和输出文件，程序启动加载配置后调用
+// 日志文件超过 max_size 后轮转，按 max_backups、max_age 清理旧文件
+func Init(cfg config.LogConfig) {
+	// 设置日志等级和格式
+	SetLevel(parseLogLevel(cfg.Level))
+	SetFormatter(cfg.Format)
 
 	// 设置日志文件路径
-	logFile := logSection.Key("file").String()
+	logFile := cfg.File
 	if logFile == "" {
 		logFile = "./log/lucky.log" // 默认路径
 	}
@@ -41,14 +40,18 @@ func initFromConfig() {
 		fmt.Printf("Failed to create log directory: %v\n", err)
 	}
 
-	// 设置控制台输出
-	withConsoleStr := logSection.Key("with_console").String()
-	withConsole := strings.ToLower(withConsoleStr) == "true"
-
-	SetOutput(logFile, withConsole)
-
-	// 设置日志格式
-	SetFormatter()
+	var out io.Writer = &lumberjack.Logger{
+		Filename:   logFile,
+		MaxSize:    cfg.MaxSize,
+		MaxBackups: cfg.MaxBackups,
+		MaxAge:     cfg.MaxAge,
+		Compress:   cfg.Compress,
+		LocalTime:  true,
+	}
+	if cfg.WithConsole {
+		out = io.MultiWriter(out, os.Stdout)
+	}
+	SetOutput(out)
 }
 
 // parseLogLevel 解析日志等级字符串
@@ -73,8 +76,12 @@ func parseLogLevel(levelStr string) logrus.Level {
 	}
 }
 
-// SetFormatter 设置日志格式
-func SetFormatter() {
+// SetFormatter 设置日志格式：json 每行输出一个 JSON 对象，便于日志系统按字段检索；其他值为文本格式
+func SetFormatter(format string) {
+	if strings.EqualFold(format, "json") {
+		logger.SetFormatter(&logrus.JSONFormatter{TimestampFormat: "2006-01-02 15:04:05"})
+		return
+	}
 	formatter := &logrus.TextFormatter{
 		FullTimestamp:   true,
 		TimestampFormat: "2006-01-02 15:04:05",
@@ -87,16 +94,8 @@ func SetLevel(level logrus.Level) {
 	logger.SetLevel(level)
 }
 
-func SetOutput(logFile string, withConsole bool) {
-	f, err := os.OpenFile(logFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
-	if err != nil {
-		fmt.Println("Failed to create logfile" + logFile)
-	}
-	if withConsole {
-		logger.SetOutput(io.MultiWriter(f, os.Stdout))
-	} else {
-		logger.SetOutput(f)
-	}
+func SetOutput(out io.Writer) {
+	logger.SetOutput(out)
 }
 
 func WithFields(fields logrus.Fields) *logrus.Entry {
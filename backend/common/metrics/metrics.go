@@ -0,0 +1,187 @@
+// Package metrics 定义 Prometheus 监控指标，通过 /metrics 接口暴露
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace 指标名前缀
+const namespace = "lucky"
+
+// Registry 应用指标注册表，包含 Go 运行时和进程指标
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP 请求耗时，按路由、方法和状态码统计",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	crawlAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "crawl_attempts_total",
+		Help:      "开奖数据抓取次数，按数据源、游戏和结果统计",
+	}, []string{"source", "game", "result"})
+
+	crawlDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "crawl_duration_seconds",
+		Help:      "开奖数据抓取耗时，按数据源和游戏统计",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"source", "game"})
+
+	cacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "缓存读取次数，按缓存类型和是否命中统计",
+	}, []string{"cache", "result"})
+
+	winningEvaluations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "winning_evaluations_total",
+		Help:      "保存的号码中奖核对结果数，按游戏和是否中奖统计",
+	}, []string{"game", "result"})
+
+	rateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_requests_total",
+		Help:      "被限流拒绝的请求数，按限流策略统计",
+	}, []string{"policy"})
+
+	lastDraws = newLastDrawCollector()
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestDuration,
+		crawlAttempts,
+		crawlDuration,
+		cacheRequests,
+		winningEvaluations,
+		rateLimited,
+		lastDraws,
+	)
+}
+
+// Handler /metrics 接口
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// RegisterDB 注册数据库连接池指标，重复注册时忽略
+func RegisterDB(db *sql.DB, name string) error {
+	err := Registry.Register(collectors.NewDBStatsCollector(db, name))
+	var registered prometheus.AlreadyRegisteredError
+	if errors.As(err, &registered) {
+		return nil
+	}
+	return err
+}
+
+// ObserveHTTPRequest 记录一次 HTTP 请求，route 为路由模板，未匹配路由时为空
+func ObserveHTTPRequest(route, method string, status int, duration time.Duration) {
+	if route == "" {
+		route = "unmatched"
+	}
+	httpRequestDuration.WithLabelValues(route, method, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+// ObserveCrawl 记录一次抓取，success 为是否抓到开奖数据
+func ObserveCrawl(source, game string, success bool, duration time.Duration) {
+	crawlAttempts.WithLabelValues(source, game, result(success, "success", "failure")).Inc()
+	crawlDuration.WithLabelValues(source, game).Observe(duration.Seconds())
+}
+
+// ObserveCacheLookup 记录一次缓存读取
+func ObserveCacheLookup(cache string, hit bool) {
+	cacheRequests.WithLabelValues(cache, result(hit, "hit", "miss")).Inc()
+}
+
+// ObserveWinningEvaluation 记录一次保存的中奖核对结果
+func ObserveWinningEvaluation(game string, won bool) {
+	winningEvaluations.WithLabelValues(game, result(won, "won", "lost")).Inc()
+}
+
+// ObserveRateLimited 记录一次被限流拒绝的请求
+func ObserveRateLimited(policy string) {
+	rateLimited.WithLabelValues(policy).Inc()
+}
+
+// SetLastDraw 记录游戏最新一期开奖，比已记录的开奖时间早时忽略（如更正历史开奖结果）
+func SetLastDraw(game, period string, drawDate time.Time) {
+	lastDraws.set(game, period, drawDate)
+}
+
+func result(ok bool, yes, no string) string {
+	if ok {
+		return yes
+	}
+	return no
+}
+
+// lastDraw 游戏最新一期开奖
+type lastDraw struct {
+	period   string
+	drawDate time.Time
+}
+
+// lastDrawCollector 输出各游戏最新开奖期号和距今时长，时长在采集时计算
+type lastDrawCollector struct {
+	mu         sync.RWMutex
+	draws      map[string]lastDraw
+	periodDesc *prometheus.Desc
+	ageDesc    *prometheus.Desc
+	now        func() time.Time
+}
+
+func newLastDrawCollector() *lastDrawCollector {
+	return &lastDrawCollector{
+		draws: make(map[string]lastDraw),
+		periodDesc: prometheus.NewDesc(prometheus.BuildFQName(namespace, "draw", "last_period"),
+			"游戏最新一期开奖的期号", []string{"game"}, nil),
+		ageDesc: prometheus.NewDesc(prometheus.BuildFQName(namespace, "draw", "last_age_seconds"),
+			"游戏最新一期开奖距今的秒数", []string{"game"}, nil),
+		now: time.Now,
+	}
+}
+
+func (c *lastDrawCollector) set(game, period string, drawDate time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if last, ok := c.draws[game]; ok && drawDate.Before(last.drawDate) {
+		return
+	}
+	c.draws[game] = lastDraw{period: period, drawDate: drawDate}
+}
+
+// Describe 实现 prometheus.Collector
+func (c *lastDrawCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.periodDesc
+	ch <- c.ageDesc
+}
+
+// Collect 实现 prometheus.Collector，期号不是数字时只输出距今时长
+func (c *lastDrawCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	now := c.now()
+	for game, draw := range c.draws {
+		if period, err := strconv.ParseFloat(draw.period, 64); err == nil {
+			ch <- prometheus.MustNewConstMetric(c.periodDesc, prometheus.GaugeValue, period, game)
+		}
+		ch <- prometheus.MustNewConstMetric(c.ageDesc, prometheus.GaugeValue, now.Sub(draw.drawDate).Seconds(), game)
+	}
+}
@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCounters(t *testing.T) {
+	ObserveCrawl("中国福彩", "ssq", true, 200*time.Millisecond)
+	ObserveCrawl("中国福彩", "ssq", false, time.Second)
+	if got := testutil.ToFloat64(crawlAttempts.WithLabelValues("中国福彩", "ssq", "failure")); got != 1 {
+		t.Errorf("抓取失败次数 = %v, want 1", got)
+	}
+
+	ObserveCacheLookup("number_missing", true)
+	ObserveCacheLookup("number_missing", true)
+	ObserveCacheLookup("number_missing", false)
+	if got := testutil.ToFloat64(cacheRequests.WithLabelValues("number_missing", "hit")); got != 2 {
+		t.Errorf("缓存命中次数 = %v, want 2", got)
+	}
+
+	ObserveWinningEvaluation("dlt", true)
+	if got := testutil.ToFloat64(winningEvaluations.WithLabelValues("dlt", "won")); got != 1 {
+		t.Errorf("中奖核对次数 = %v, want 1", got)
+	}
+
+	ObserveRateLimited("login")
+	if got := testutil.ToFloat64(rateLimited.WithLabelValues("login")); got != 1 {
+		t.Errorf("限流次数 = %v, want 1", got)
+	}
+}
+
+func TestLastDrawCollector(t *testing.T) {
+	c := newLastDrawCollector()
+	now := time.Date(2024, 5, 2, 21, 15, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	c.set("ssq", "2024049", now.Add(-time.Hour))
+	// 更正更早一期的开奖结果不应覆盖最新一期
+	c.set("ssq", "2024048", now.Add(-72*time.Hour))
+	c.set("custom", "A-01", now.Add(-2*time.Hour))
+
+	want := `
+# HELP lucky_draw_last_age_seconds 游戏最新一期开奖距今的秒数
+# TYPE lucky_draw_last_age_seconds gauge
+lucky_draw_last_age_seconds{game="custom"} 7200
+lucky_draw_last_age_seconds{game="ssq"} 3600
+# HELP lucky_draw_last_period 游戏最新一期开奖的期号
+# TYPE lucky_draw_last_period gauge
+lucky_draw_last_period{game="ssq"} 2.024049e+06
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	ObserveHTTPRequest("/api/results/:gameCode", "GET", 200, 10*time.Millisecond)
+	ObserveHTTPRequest("", "GET", 404, time.Millisecond)
+
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+	for _, want := range []string{
+		`lucky_http_request_duration_seconds_count{method="GET",route="/api/results/:gameCode",status="200"} 1`,
+		`lucky_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`,
+		"go_goroutines",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("/metrics 输出缺少 %s", want)
+		}
+	}
+}
@@ -1,9 +0,0 @@
-package mysql
-
-type Config struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	DataBase string
-}
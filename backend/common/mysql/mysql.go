@@ -1,31 +1,43 @@
 package mysql
 
 import (
-	"fmt"
 	"lucky/common/config"
+	"lucky/common/database"
 
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
+// DB 全局数据库连接，按 [database] driver 配置连接 MySQL 或 SQLite
 var DB *gorm.DB
 
 func Init() {
 	if DB != nil {
 		return
 	}
-	session := config.Config.Section("mysql")
-	user := session.Key("user").String()
-	password := session.Key("password").String()
-	host := session.Key("host").String()
-	port := session.Key("port").String()
-	dataBase := session.Key("db").String()
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		user, password, host, port, dataBase)
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	db, err := database.Open(dsn(config.Get()))
 	if err != nil {
 		panic(err)
 	}
 	DB = db
 	return
 }
+
+// Close 关闭数据库连接
+func Close() error {
+	if DB == nil {
+		return nil
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// dsn 数据库类型和连接串，SQLite 的连接串为数据库文件路径
+func dsn(cfg *config.Config) (string, string) {
+	if cfg.Database.Driver == database.DriverSQLite {
+		return cfg.Database.Driver, cfg.Database.Path
+	}
+	return database.DriverMySQL, cfg.MySQL.DSN()
+}
@@ -0,0 +1,243 @@
+package openapi
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Package 包中有 @Router 注释的函数
+type Package struct {
+	Name        string
+	Imports     map[string]string // 包名或别名 -> 导入路径
+	Annotations []Annotation
+}
+
+// Annotation 函数上的 swag 风格注释
+type Annotation struct {
+	Func        string
+	Pos         string // 文件名:行号，用于错误提示
+	Summary     string
+	Description string
+	Tags        []string
+	Accept      []string
+	Produce     []string
+	Params      []ParamAnnotation
+	Responses   []ResponseAnnotation
+	Security    []string
+	Routers     []RouterAnnotation
+}
+
+// ParamAnnotation @Param 名称 位置 类型 是否必填 "说明" 属性，如 @Param periodCount query int true "期数" Enums(10, 30, 50)
+type ParamAnnotation struct {
+	Name        string
+	In          string
+	Type        string
+	Required    bool
+	Description string
+	Enum        []string
+	Default     string
+}
+
+// ResponseAnnotation @Success、@Failure 状态码 {种类} 类型 "说明"，如 @Success 200 {object} response.Body{data=Foo}
+type ResponseAnnotation struct {
+	Status      int
+	Kind        string // object, array, string, file
+	Type        string
+	Description string
+}
+
+// RouterAnnotation @Router 路径 [方法]
+type RouterAnnotation struct {
+	Path   string
+	Method string
+}
+
+var (
+	paramPattern    = regexp.MustCompile(`^(\S+)\s+(path|query|header|body)\s+(\S+)\s+(true|false)\s+"([^"]*)"\s*(.*)$`)
+	attrPattern     = regexp.MustCompile(`(\w+)\(([^)]*)\)`)
+	responsePattern = regexp.MustCompile(`^(\d{3})(?:\s+\{(object|array|string|file)\}\s+(\S+))?(?:\s+"([^"]*)")?$`)
+	routerPattern   = regexp.MustCompile(`^(/\S*)\s+\[(get|post|put|patch|delete|head|options)\]$`)
+)
+
+// contentTypes @Accept、@Produce 中的简写
+var contentTypes = map[string]string{
+	"json":         "application/json",
+	"plain":        "text/plain",
+	"html":         "text/html",
+	"csv":          "text/csv",
+	"octet-stream": "application/octet-stream",
+}
+
+// ParseDir 解析目录中（不含测试文件）函数上的注释，按文件名和函数位置排序
+func ParseDir(dir string) (*Package, error) {
+	fset := token.NewFileSet()
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	pkg := &Package{Imports: make(map[string]string)}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if pkg.Name == "" {
+			pkg.Name = f.Name.Name
+		}
+		for _, spec := range f.Imports {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			name := path.Base(importPath)
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			pkg.Imports[name] = importPath
+		}
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Doc == nil {
+				continue
+			}
+			pos := fset.Position(fn.Pos())
+			annotation, err := parseAnnotation(fn.Name.Name, fn.Doc)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %s: %w", filepath.Base(pos.Filename), pos.Line, fn.Name.Name, err)
+			}
+			if len(annotation.Routers) == 0 {
+				continue
+			}
+			annotation.Pos = fmt.Sprintf("%s:%d", filepath.Base(pos.Filename), pos.Line)
+			pkg.Annotations = append(pkg.Annotations, annotation)
+		}
+	}
+	return pkg, nil
+}
+
+// parseAnnotation 解析函数注释中以 @ 开头的行，其余行忽略
+func parseAnnotation(funcName string, doc *ast.CommentGroup) (Annotation, error) {
+	annotation := Annotation{Func: funcName}
+	for _, comment := range doc.List {
+		line := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+		if !strings.HasPrefix(line, "@") {
+			continue
+		}
+		attr, value, _ := strings.Cut(line, " ")
+		value = strings.TrimSpace(value)
+
+		switch strings.ToLower(attr) {
+		case "@summary":
+			annotation.Summary = value
+		case "@description":
+			if annotation.Description != "" {
+				annotation.Description += "\n"
+			}
+			annotation.Description += value
+		case "@tags":
+			annotation.Tags = append(annotation.Tags, splitList(value)...)
+		case "@accept":
+			annotation.Accept = append(annotation.Accept, mimeTypes(value)...)
+		case "@produce":
+			annotation.Produce = append(annotation.Produce, mimeTypes(value)...)
+		case "@security":
+			annotation.Security = append(annotation.Security, value)
+		case "@param":
+			param, err := parseParam(value)
+			if err != nil {
+				return annotation, err
+			}
+			annotation.Params = append(annotation.Params, param)
+		case "@success", "@failure":
+			resp, err := parseResponse(value)
+			if err != nil {
+				return annotation, err
+			}
+			annotation.Responses = append(annotation.Responses, resp)
+		case "@router":
+			m := routerPattern.FindStringSubmatch(value)
+			if m == nil {
+				return annotation, fmt.Errorf("@Router 格式错误: %s", value)
+			}
+			annotation.Routers = append(annotation.Routers, RouterAnnotation{Path: m[1], Method: m[2]})
+		default:
+			return annotation, fmt.Errorf("不支持的注释 %s", attr)
+		}
+	}
+	return annotation, nil
+}
+
+func parseParam(value string) (ParamAnnotation, error) {
+	m := paramPattern.FindStringSubmatch(value)
+	if m == nil {
+		return ParamAnnotation{}, fmt.Errorf("@Param 格式错误: %s", value)
+	}
+	param := ParamAnnotation{Name: m[1], In: m[2], Type: m[3], Required: m[4] == "true", Description: m[5]}
+	if param.In != "body" {
+		typ, ok := paramTypes[param.Type]
+		if !ok {
+			return param, fmt.Errorf("@Param %s 不支持的类型 %s", param.Name, param.Type)
+		}
+		param.Type = typ
+	}
+	for _, attr := range attrPattern.FindAllStringSubmatch(m[6], -1) {
+		switch strings.ToLower(attr[1]) {
+		case "enums":
+			param.Enum = splitList(attr[2])
+		case "default":
+			param.Default = strings.TrimSpace(attr[2])
+		default:
+			return param, fmt.Errorf("@Param %s 不支持的属性 %s", param.Name, attr[1])
+		}
+	}
+	return param, nil
+}
+
+// paramTypes 路径、查询和请求头参数的类型
+var paramTypes = map[string]string{
+	"string":  "string",
+	"int":     "integer",
+	"integer": "integer",
+	"number":  "number",
+	"bool":    "boolean",
+	"boolean": "boolean",
+}
+
+func parseResponse(value string) (ResponseAnnotation, error) {
+	m := responsePattern.FindStringSubmatch(value)
+	if m == nil {
+		return ResponseAnnotation{}, fmt.Errorf("@Success/@Failure 格式错误: %s", value)
+	}
+	status, _ := strconv.Atoi(m[1])
+	return ResponseAnnotation{Status: status, Kind: m[2], Type: m[3], Description: m[4]}, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func mimeTypes(value string) []string {
+	items := splitList(value)
+	for i, item := range items {
+		if mime, ok := contentTypes[item]; ok {
+			items[i] = mime
+		}
+	}
+	return items
+}
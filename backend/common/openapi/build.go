@@ -0,0 +1,129 @@
+package openapi
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// defaultContentType 未通过 @Accept、@Produce 指定时的内容类型
+const defaultContentType = "application/json"
+
+// Build 按路由表生成文档，认证方式由调用方设置到 Components.SecuritySchemes
+func Build(info Info, routes []Route) *Document {
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			Schemas:         make(map[string]*Schema),
+			SecuritySchemes: make(map[string]*SecurityScheme),
+		},
+	}
+	builder := newSchemaBuilder(doc.Components.Schemas)
+	tags := make(map[string]bool)
+
+	for _, route := range routes {
+		op := &Operation{
+			OperationID: route.Handler,
+			Summary:     route.Summary,
+			Description: route.Description,
+			Tags:        route.Tags,
+			Responses:   make(map[string]*Response),
+		}
+		for _, tag := range route.Tags {
+			if !tags[tag] {
+				tags[tag] = true
+				doc.Tags = append(doc.Tags, Tag{Name: tag})
+			}
+		}
+
+		for _, param := range route.Params {
+			if param.In == "body" {
+				op.RequestBody = &RequestBody{
+					Description: param.Description,
+					Required:    param.Required,
+					Content:     content(route.Accept, builder.ref(param.Body)),
+				}
+				continue
+			}
+			op.Parameters = append(op.Parameters, Parameter{
+				Name:        param.Name,
+				In:          param.In,
+				Description: param.Description,
+				Required:    param.Required || param.In == "path",
+				Schema:      paramSchema(param),
+			})
+		}
+
+		for _, result := range route.Responses {
+			resp := &Response{Description: result.Description}
+			if resp.Description == "" {
+				resp.Description = http.StatusText(result.Status)
+			}
+			if result.Body != nil {
+				resp.Content = content(route.Produce, builder.ref(result.Body))
+			}
+			op.Responses[strconv.Itoa(result.Status)] = resp
+		}
+		if len(op.Responses) == 0 {
+			op.Responses["200"] = &Response{Description: http.StatusText(http.StatusOK)}
+		}
+
+		for _, name := range route.Security {
+			op.Security = append(op.Security, map[string][]string{name: {}})
+		}
+
+		item, ok := doc.Paths[route.Path]
+		if !ok {
+			item = make(PathItem)
+			doc.Paths[route.Path] = item
+		}
+		item[route.Method] = op
+	}
+	return doc
+}
+
+// content 各内容类型使用同一结构
+func content(types []string, schema *Schema) map[string]MediaType {
+	if len(types) == 0 {
+		types = []string{defaultContentType}
+	}
+	result := make(map[string]MediaType, len(types))
+	for _, t := range types {
+		result[t] = MediaType{Schema: schema}
+	}
+	return result
+}
+
+// paramSchema 路径、查询和请求头参数的结构，枚举值和默认值按参数类型转换
+func paramSchema(param Param) *Schema {
+	schema := &Schema{Type: param.Type}
+	if schema.Type == "" {
+		schema.Type = "string"
+	}
+	for _, value := range param.Enum {
+		schema.Enum = append(schema.Enum, paramValue(schema.Type, value))
+	}
+	if param.Default != "" {
+		schema.Default = paramValue(schema.Type, param.Default)
+	}
+	return schema
+}
+
+func paramValue(typ, value string) interface{} {
+	switch typ {
+	case "integer":
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return v
+		}
+	case "number":
+		if v, err := strconv.ParseFloat(value, 64); err == nil {
+			return v
+		}
+	case "boolean":
+		if v, err := strconv.ParseBool(value); err == nil {
+			return v
+		}
+	}
+	return value
+}
@@ -0,0 +1,218 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// importPath 本包的导入路径，生成的代码通过它引用 Route、Ref 等
+const importPath = "lucky/common/openapi"
+
+// goTypes 注释中的基本类型对应的 Go 类型
+var goTypes = map[string]string{
+	"integer": "int",
+	"number":  "float64",
+	"boolean": "bool",
+	"object":  "map[string]interface{}",
+}
+
+// qualifierPattern 类型中引用的包名，如 model.UserNumber 中的 model
+var qualifierPattern = regexp.MustCompile(`\b([A-Za-z_]\w*)\.`)
+
+// Generate 生成路由表源码：变量 varName 为 []openapi.Route，每个 @Router 一项
+func Generate(pkg *Package, varName, generator string) ([]byte, error) {
+	g := &routeGenerator{pkg: pkg, imports: map[string]string{"openapi": importPath}}
+
+	var body bytes.Buffer
+	for _, a := range pkg.Annotations {
+		for _, router := range a.Routers {
+			if err := g.route(&body, a, router); err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", a.Pos, a.Func, err)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by %s; DO NOT EDIT.\n\npackage %s\n\n", generator, pkg.Name)
+	buf.WriteString("import (\n")
+	names := make([]string, 0, len(g.imports))
+	for name := range g.imports {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return g.imports[names[i]] < g.imports[names[j]] })
+	for _, name := range names {
+		p := g.imports[name]
+		if path.Base(p) == name {
+			fmt.Fprintf(&buf, "\t%q\n", p)
+		} else {
+			fmt.Fprintf(&buf, "\t%s %q\n", name, p)
+		}
+	}
+	buf.WriteString(")\n\n")
+	fmt.Fprintf(&buf, "// %s 处理函数注释中的接口定义\nvar %s = []openapi.Route{\n", varName, varName)
+	buf.Write(body.Bytes())
+	buf.WriteString("}\n")
+
+	return format.Source(buf.Bytes())
+}
+
+type routeGenerator struct {
+	pkg     *Package
+	imports map[string]string
+}
+
+func (g *routeGenerator) route(w *bytes.Buffer, a Annotation, router RouterAnnotation) error {
+	fmt.Fprintf(w, "{\nMethod: %q,\nPath: %q,\nHandler: %q,\n", router.Method, router.Path, a.Func)
+	if a.Summary != "" {
+		fmt.Fprintf(w, "Summary: %q,\n", a.Summary)
+	}
+	if a.Description != "" {
+		fmt.Fprintf(w, "Description: %q,\n", a.Description)
+	}
+	writeStrings(w, "Tags", a.Tags)
+	writeStrings(w, "Accept", a.Accept)
+	writeStrings(w, "Produce", a.Produce)
+
+	if len(a.Params) > 0 {
+		w.WriteString("Params: []openapi.Param{\n")
+		for _, p := range a.Params {
+			fmt.Fprintf(w, "{Name: %q, In: %q", p.Name, p.In)
+			if p.In == "body" {
+				ref, err := g.typeRef(p.Type)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(w, ", Body: %s", ref)
+			} else {
+				fmt.Fprintf(w, ", Type: %q", p.Type)
+			}
+			if p.Required {
+				w.WriteString(", Required: true")
+			}
+			if p.Description != "" {
+				fmt.Fprintf(w, ", Description: %q", p.Description)
+			}
+			if len(p.Enum) > 0 {
+				fmt.Fprintf(w, ", Enum: %#v", p.Enum)
+			}
+			if p.Default != "" {
+				fmt.Fprintf(w, ", Default: %q", p.Default)
+			}
+			w.WriteString("},\n")
+		}
+		w.WriteString("},\n")
+	}
+
+	if len(a.Responses) > 0 {
+		w.WriteString("Responses: []openapi.Result{\n")
+		for _, r := range a.Responses {
+			fmt.Fprintf(w, "{Status: %d", r.Status)
+			if r.Description != "" {
+				fmt.Fprintf(w, ", Description: %q", r.Description)
+			}
+			switch r.Kind {
+			case "":
+			case "file":
+				w.WriteString(", Body: openapi.File()")
+			default:
+				typ := r.Type
+				if r.Kind == "array" {
+					typ = "[]" + typ
+				}
+				ref, err := g.typeRef(typ)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(w, ", Body: %s", ref)
+			}
+			w.WriteString("},\n")
+		}
+		w.WriteString("},\n")
+	}
+
+	writeStrings(w, "Security", a.Security)
+	w.WriteString("},\n")
+	return nil
+}
+
+// typeRef 注释中的类型对应的 openapi.Ref 表达式，类型可以覆盖字段，如 response.Body{data=[]model.UserNumber}
+func (g *routeGenerator) typeRef(typ string) (string, error) {
+	base, fields := typ, ""
+	if i := strings.IndexByte(typ, '{'); i >= 0 {
+		if !strings.HasSuffix(typ, "}") {
+			return "", fmt.Errorf("类型格式错误: %s", typ)
+		}
+		base, fields = typ[:i], typ[i+1:len(typ)-1]
+	}
+
+	goType, err := g.goType(base)
+	if err != nil {
+		return "", err
+	}
+	var args []string
+	for _, field := range splitFields(fields) {
+		name, fieldType, ok := strings.Cut(field, "=")
+		if !ok || name == "" {
+			return "", fmt.Errorf("字段格式错误: %s", field)
+		}
+		ref, err := g.typeRef(fieldType)
+		if err != nil {
+			return "", err
+		}
+		args = append(args, fmt.Sprintf("openapi.Field(%q, %s)", name, ref))
+	}
+	return fmt.Sprintf("openapi.Ref[%s](%s)", goType, strings.Join(args, ", ")), nil
+}
+
+// goType 注释中的类型对应的 Go 类型，记录引用的包
+func (g *routeGenerator) goType(typ string) (string, error) {
+	if typ == "" {
+		return "", fmt.Errorf("缺少类型")
+	}
+	elem := strings.TrimLeft(typ, "[]*")
+	if mapped, ok := goTypes[elem]; ok {
+		return typ[:len(typ)-len(elem)] + mapped, nil
+	}
+	for _, m := range qualifierPattern.FindAllStringSubmatch(typ, -1) {
+		p, ok := g.pkg.Imports[m[1]]
+		if !ok {
+			return "", fmt.Errorf("类型 %s 引用的包 %s 未导入", typ, m[1])
+		}
+		g.imports[m[1]] = p
+	}
+	return typ, nil
+}
+
+// splitFields 按顶层的逗号拆分覆盖的字段，忽略嵌套的 {} 中的逗号
+func splitFields(fields string) []string {
+	var result []string
+	depth, start := 0, 0
+	for i, r := range fields {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				result = append(result, strings.TrimSpace(fields[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if last := strings.TrimSpace(fields[start:]); last != "" {
+		result = append(result, last)
+	}
+	return result
+}
+
+func writeStrings(w *bytes.Buffer, field string, values []string) {
+	if len(values) > 0 {
+		fmt.Fprintf(w, "%s: %#v,\n", field, values)
+	}
+}
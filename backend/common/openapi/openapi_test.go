@@ -0,0 +1,224 @@
+package openapi
+
+import (
+	"go/ast"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testBase struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type testNode struct {
+	testBase
+	Name     string            `json:"name" binding:"required"`
+	Tags     []string          `json:"tags,omitempty"`
+	Parent   *testNode         `json:"parent"`
+	Extra    map[string]int    `json:"extra"`
+	Raw      []byte            `json:"raw"`
+	Any      interface{}       `json:"any"`
+	Hidden   string            `json:"-"`
+	Callback func()            `json:"-"`
+	Labels   map[string]string `json:"labels"`
+}
+
+type testEnvelope struct {
+	Code int         `json:"code"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+func TestSchemaFromStruct(t *testing.T) {
+	components := make(map[string]*Schema)
+	b := newSchemaBuilder(components)
+
+	ref := b.schema(Ref[testNode]().Type)
+	assert.Equal(t, "#/components/schemas/openapi.testNode", ref.Ref)
+
+	node := components["openapi.testNode"]
+	require.NotNil(t, node)
+	assert.Equal(t, []string{"name"}, node.Required)
+	assert.ElementsMatch(t, []string{"id", "createdAt", "name", "tags", "parent", "extra", "raw", "any", "labels"}, keys(node.Properties))
+	assert.Equal(t, &Schema{Type: "integer", Format: "int64"}, node.Properties["id"])
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time"}, node.Properties["createdAt"])
+	assert.Equal(t, &Schema{Type: "array", Items: &Schema{Type: "string"}}, node.Properties["tags"])
+	assert.Equal(t, "#/components/schemas/openapi.testNode", node.Properties["parent"].Ref)
+	assert.Equal(t, &Schema{Type: "object", AdditionalProperties: &Schema{Type: "integer", Format: "int64"}}, node.Properties["extra"])
+	assert.Equal(t, &Schema{Type: "string", Format: "byte"}, node.Properties["raw"])
+	assert.Equal(t, &Schema{}, node.Properties["any"])
+}
+
+func TestSchemaFieldOverride(t *testing.T) {
+	components := make(map[string]*Schema)
+	b := newSchemaBuilder(components)
+
+	schema := b.ref(Ref[testEnvelope](Field("data", Ref[[]testNode]())))
+	require.Len(t, schema.AllOf, 2)
+	assert.Equal(t, "#/components/schemas/openapi.testEnvelope", schema.AllOf[0].Ref)
+	data := schema.AllOf[1].Properties["data"]
+	assert.Equal(t, "array", data.Type)
+	assert.Equal(t, "#/components/schemas/openapi.testNode", data.Items.Ref)
+}
+
+func TestBuild(t *testing.T) {
+	doc := Build(Info{Title: "test", Version: "1"}, []Route{
+		{
+			Method:  "get",
+			Path:    "/items/{id}",
+			Handler: "GetItem",
+			Tags:    []string{"items"},
+			Params: []Param{
+				{Name: "id", In: "path", Type: "integer"},
+				{Name: "period", In: "query", Type: "integer", Enum: []string{"10", "30"}, Default: "30"},
+			},
+			Responses: []Result{
+				{Status: 200, Body: Ref[testEnvelope](Field("data", Ref[testNode]()))},
+				{Status: 404, Body: Ref[testEnvelope]()},
+			},
+			Security: []string{"BearerAuth"},
+		},
+		{Method: "post", Path: "/items", Handler: "CreateItem", Tags: []string{"items"},
+			Params: []Param{{Name: "request", In: "body", Body: Ref[testNode](), Required: true}}},
+	})
+
+	assert.Equal(t, Version, doc.OpenAPI)
+	assert.Equal(t, []Tag{{Name: "items"}}, doc.Tags)
+	assert.True(t, doc.HasOperation("GET", "/items/{id}"))
+	assert.False(t, doc.HasOperation("DELETE", "/items/{id}"))
+
+	get := doc.Paths["/items/{id}"]["get"]
+	assert.True(t, get.Parameters[0].Required, "路径参数必填")
+	assert.Equal(t, []interface{}{int64(10), int64(30)}, get.Parameters[1].Schema.Enum)
+	assert.Equal(t, int64(30), get.Parameters[1].Schema.Default)
+	assert.Equal(t, "Not Found", get.Responses["404"].Description)
+	assert.Equal(t, []map[string][]string{{"BearerAuth": {}}}, get.Security)
+
+	post := doc.Paths["/items"]["post"]
+	require.NotNil(t, post.RequestBody)
+	assert.Equal(t, "#/components/schemas/openapi.testNode", post.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Contains(t, post.Responses, "200", "没有 @Success 时默认 200")
+}
+
+func TestGinPath(t *testing.T) {
+	assert.Equal(t, "/api/results/{gameCode}/{period}", GinPath("/api/results/:gameCode/:period"))
+	assert.Equal(t, "/static/{filepath}", GinPath("/static/*filepath"))
+	assert.Equal(t, "/ping", GinPath("/ping"))
+}
+
+func TestParseAnnotation(t *testing.T) {
+	annotation, err := parseAnnotation("GetMissingData", comments(
+		"GetMissingData 获取遗漏数据",
+		"@Summary 获取遗漏数据",
+		"@Description 第一行",
+		"@Description 第二行",
+		"@Tags 遗漏数据, 统计",
+		"@Produce json",
+		`@Param gameCode query string true "游戏代码"`,
+		`@Param periodCount query int false "期数" Enums(10, 30, 50) default(30)`,
+		`@Param request body SaveRequest true "请求"`,
+		"@Success 200 {object} response.Body{data=MissingDataResponse}",
+		`@Failure 400 {object} response.Body "参数错误"`,
+		"@Security BearerAuth",
+		"@Router /api/missing [get]",
+	))
+	require.NoError(t, err)
+
+	assert.Equal(t, "获取遗漏数据", annotation.Summary)
+	assert.Equal(t, "第一行\n第二行", annotation.Description)
+	assert.Equal(t, []string{"遗漏数据", "统计"}, annotation.Tags)
+	assert.Equal(t, []string{"application/json"}, annotation.Produce)
+	assert.Equal(t, []string{"BearerAuth"}, annotation.Security)
+	assert.Equal(t, []RouterAnnotation{{Path: "/api/missing", Method: "get"}}, annotation.Routers)
+	assert.Equal(t, ParamAnnotation{Name: "periodCount", In: "query", Type: "integer", Description: "期数", Enum: []string{"10", "30", "50"}, Default: "30"}, annotation.Params[1])
+	assert.Equal(t, "SaveRequest", annotation.Params[2].Type)
+	assert.Equal(t, ResponseAnnotation{Status: 200, Kind: "object", Type: "response.Body{data=MissingDataResponse}"}, annotation.Responses[0])
+	assert.Equal(t, "参数错误", annotation.Responses[1].Description)
+}
+
+func TestParseAnnotationErrors(t *testing.T) {
+	tests := []string{
+		"@Router /api/missing",
+		"@Routr /api/missing [get]",
+		`@Param id path uuid true "ID"`,
+		`@Param id path int true "ID" minimum(1)`,
+		"@Success ok {object} Foo",
+	}
+	for _, line := range tests {
+		_, err := parseAnnotation("Handler", comments(line))
+		assert.Error(t, err, line)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	src := `package demo
+
+import (
+	"lucky/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Other 没有 @Router 的函数不生成
+func Other() {}
+
+// ListNumbers 号码列表
+// @Summary 号码列表
+// @Tags 号码
+// @Param page query int false "页码" default(1)
+// @Success 200 {object} Page{list=[]model.UserNumber}
+// @Success 201 {array} model.UserNumber
+// @Router /numbers [get]
+// @Router /v2/numbers [get]
+func ListNumbers(c *gin.Context) {}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "demo.go"), []byte(src), 0o644))
+
+	pkg, err := ParseDir(dir)
+	require.NoError(t, err)
+	require.Len(t, pkg.Annotations, 1)
+	assert.Equal(t, "demo", pkg.Name)
+
+	out, err := Generate(pkg, "routes", "test")
+	require.NoError(t, err)
+	code := string(out)
+	assert.True(t, strings.HasPrefix(code, "// Code generated by test; DO NOT EDIT."))
+	assert.Contains(t, code, `"lucky/model"`)
+	assert.NotContains(t, code, "gin-gonic", "只导入类型中引用的包")
+	assert.Contains(t, code, `openapi.Ref[Page](openapi.Field("list", openapi.Ref[[]model.UserNumber]()))`)
+	assert.Contains(t, code, `openapi.Ref[[]model.UserNumber]()`)
+	assert.Equal(t, 2, strings.Count(code, `Handler: "ListNumbers"`), "每个 @Router 生成一项")
+}
+
+func TestGenerateUnknownPackage(t *testing.T) {
+	pkg := &Package{Name: "demo", Imports: map[string]string{}, Annotations: []Annotation{{
+		Func:      "Get",
+		Routers:   []RouterAnnotation{{Path: "/x", Method: "get"}},
+		Responses: []ResponseAnnotation{{Status: 200, Kind: "object", Type: "model.Missing"}},
+	}}}
+	_, err := Generate(pkg, "routes", "test")
+	assert.ErrorContains(t, err, "model")
+}
+
+func comments(lines ...string) *ast.CommentGroup {
+	group := &ast.CommentGroup{}
+	for _, line := range lines {
+		group.List = append(group.List, &ast.Comment{Text: "// " + line})
+	}
+	return group
+}
+
+func keys(m map[string]*Schema) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
@@ -0,0 +1,65 @@
+package openapi
+
+import "reflect"
+
+// Route 由注释生成的接口定义，一个 @Router 对应一个 Route
+type Route struct {
+	Method      string // 小写的 HTTP 方法
+	Path        string // OpenAPI 格式的路径，如 /api/games/{gameCode}
+	Handler     string // 处理函数名，作为 operationId
+	Summary     string
+	Description string
+	Tags        []string
+	Accept      []string // 请求体的内容类型，默认 application/json
+	Produce     []string // 响应的内容类型，默认 application/json
+	Params      []Param
+	Responses   []Result
+	Security    []string
+}
+
+// Param 请求参数，In 为 body 时 Body 为请求体类型，否则 Type 为参数类型
+type Param struct {
+	Name        string
+	In          string // path, query, header, body
+	Type        string // string, integer, number, boolean
+	Body        *TypeRef
+	Required    bool
+	Description string
+	Enum        []string
+	Default     string
+}
+
+// Result 响应，Body 为空表示没有响应体
+type Result struct {
+	Status      int
+	Description string
+	Body        *TypeRef
+}
+
+// TypeRef 注释中的类型，Fields 覆盖结构体中字段的类型，如 response.Body{data=Foo}
+type TypeRef struct {
+	Type   reflect.Type
+	Fields []FieldRef
+	File   bool // 文件下载，忽略 Type
+}
+
+// FieldRef 覆盖的字段，Name 为 JSON 字段名
+type FieldRef struct {
+	Name string
+	Type *TypeRef
+}
+
+// Ref 类型 T 的 TypeRef，T 可以是任意类型，如 []model.UserNumber、map[string]int
+func Ref[T any](fields ...FieldRef) *TypeRef {
+	return &TypeRef{Type: reflect.TypeOf((*T)(nil)).Elem(), Fields: fields}
+}
+
+// Field 覆盖结构体字段的类型
+func Field(name string, ref *TypeRef) FieldRef {
+	return FieldRef{Name: name, Type: ref}
+}
+
+// File 文件下载
+func File() *TypeRef {
+	return &TypeRef{File: true}
+}
@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepInterval 清理空闲令牌桶的间隔
+const sweepInterval = time.Minute
+
+// Memory 进程内令牌桶限流，多实例部署时各实例分别计数，需要共享时启用 Redis
+type Memory struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// bucket 令牌桶状态，tokens 为 updatedAt 时的令牌数
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	fullAt    time.Time // 令牌补满的时间，之后的桶与新建的桶相同，可以删除
+}
+
+// NewMemory 创建进程内限流器
+func NewMemory() *Memory {
+	return &Memory{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+func (m *Memory) Allow(key string, limit Limit) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	m.sweep(now)
+
+	burst := float64(limit.Burst)
+	interval := limit.interval()
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, updatedAt: now}
+		m.buckets[key] = b
+	}
+	if elapsed := now.Sub(b.updatedAt); elapsed > 0 {
+		b.tokens = min(burst, b.tokens+float64(elapsed)/float64(interval))
+		b.updatedAt = now
+	}
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) * float64(interval))
+		return Result{RetryAfter: wait}, nil
+	}
+	b.tokens--
+	b.fullAt = now.Add(time.Duration((burst - b.tokens) * float64(interval)))
+	return Result{Allowed: true}, nil
+}
+
+// sweep 定期删除已补满的令牌桶，避免按 IP 限流时 key 无限增长
+func (m *Memory) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for key, b := range m.buckets {
+		if !now.Before(b.fullAt) {
+			delete(m.buckets, key)
+		}
+	}
+}
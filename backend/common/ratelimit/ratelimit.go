@@ -0,0 +1,75 @@
+// Package ratelimit 提供令牌桶限流，有 Redis 和进程内两种实现
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit 令牌桶参数：桶容量为 Burst，每 Per 时间补满 Burst 个令牌
+// 如 10/1m 表示最多连续请求 10 次，之后每 6 秒恢复一次
+type Limit struct {
+	Burst int
+	Per   time.Duration
+}
+
+// Unlimited 不限流的配置值
+const Unlimited = "off"
+
+// ParseLimit 解析 "<次数>/<时间>" 格式的限流配置，如 10/1m、5/30s；off 或空字符串表示不限流，ok 为 false
+func ParseLimit(s string) (limit Limit, ok bool, err error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == Unlimited {
+		return Limit{}, false, nil
+	}
+	count, per, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, false, fmt.Errorf("限流配置格式应为 <次数>/<时间>，如 10/1m: %s", s)
+	}
+	burst, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || burst <= 0 {
+		return Limit{}, false, fmt.Errorf("限流次数必须为正整数: %s", s)
+	}
+	duration, err := time.ParseDuration(strings.TrimSpace(per))
+	if err != nil || duration <= 0 {
+		return Limit{}, false, fmt.Errorf("限流时间格式错误，如 1m、30s: %s", s)
+	}
+	return Limit{Burst: burst, Per: duration}, true, nil
+}
+
+// String 配置格式，如 10/1m0s
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s", l.Burst, l.Per)
+}
+
+// interval 恢复一个令牌需要的时间
+func (l Limit) interval() time.Duration {
+	return l.Per / time.Duration(l.Burst)
+}
+
+// Result 一次限流判断的结果
+type Result struct {
+	Allowed    bool
+	RetryAfter time.Duration // 被拒绝时距离下一个令牌可用的时间
+}
+
+// RetryAfterSeconds Retry-After 响应头的秒数，向上取整且至少为1
+func (r Result) RetryAfterSeconds() int {
+	seconds := int(math.Ceil(r.RetryAfter.Seconds()))
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
+// Limiter 限流器，每个 key 一个令牌桶
+type Limiter interface {
+	// Allow 从 key 的令牌桶取一个令牌，桶为空时 Allowed 为 false
+	Allow(key string, limit Limit) (Result, error)
+}
+
+// Default 全局限流器，启动时启用 Redis 则替换为 Redis 实现，多实例共享限流计数
+var Default Limiter = NewMemory()
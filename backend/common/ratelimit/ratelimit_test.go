@@ -0,0 +1,133 @@
+package ratelimit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	limit, ok, err := ParseLimit(" 10/1m ")
+	if err != nil || !ok || limit != (Limit{Burst: 10, Per: time.Minute}) {
+		t.Errorf("ParseLimit(10/1m) = %+v, %v, %v", limit, ok, err)
+	}
+	if limit.interval() != 6*time.Second {
+		t.Errorf("interval = %v, want 6s", limit.interval())
+	}
+	for _, s := range []string{"", "off"} {
+		if _, ok, err := ParseLimit(s); ok || err != nil {
+			t.Errorf("ParseLimit(%q) 应表示不限流, got ok=%v err=%v", s, ok, err)
+		}
+	}
+	for _, s := range []string{"10", "0/1m", "abc/1m", "10/1分钟", "10/-1s"} {
+		if _, _, err := ParseLimit(s); err == nil {
+			t.Errorf("ParseLimit(%q) 应返回错误", s)
+		}
+	}
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	tests := map[time.Duration]int{0: 1, 300 * time.Millisecond: 1, 1500 * time.Millisecond: 2, 6 * time.Second: 6}
+	for wait, want := range tests {
+		if got := (Result{RetryAfter: wait}).RetryAfterSeconds(); got != want {
+			t.Errorf("RetryAfterSeconds(%v) = %d, want %d", wait, got, want)
+		}
+	}
+}
+
+func TestMemoryAllow(t *testing.T) {
+	m := NewMemory()
+	now := time.Date(2024, 5, 2, 21, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	limit := Limit{Burst: 3, Per: 3 * time.Second}
+
+	for i := 0; i < 3; i++ {
+		if r, _ := m.Allow("ip:1.2.3.4", limit); !r.Allowed {
+			t.Fatalf("第 %d 次请求应允许", i+1)
+		}
+	}
+	r, err := m.Allow("ip:1.2.3.4", limit)
+	if err != nil || r.Allowed || r.RetryAfter != time.Second {
+		t.Fatalf("令牌用完应拒绝并等待1秒, got %+v, %v", r, err)
+	}
+	// 不同 key 互不影响
+	if r, _ := m.Allow("ip:5.6.7.8", limit); !r.Allowed {
+		t.Error("其他 key 应允许")
+	}
+
+	now = now.Add(400 * time.Millisecond)
+	if r, _ := m.Allow("ip:1.2.3.4", limit); r.Allowed || r.RetryAfter != 600*time.Millisecond {
+		t.Errorf("令牌未恢复应拒绝, got %+v", r)
+	}
+	now = now.Add(600 * time.Millisecond)
+	if r, _ := m.Allow("ip:1.2.3.4", limit); !r.Allowed {
+		t.Error("恢复一个令牌后应允许")
+	}
+	if r, _ := m.Allow("ip:1.2.3.4", limit); r.Allowed {
+		t.Error("只恢复了一个令牌")
+	}
+}
+
+func TestMemorySweep(t *testing.T) {
+	m := NewMemory()
+	now := time.Date(2024, 5, 2, 21, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	limit := Limit{Burst: 2, Per: 10 * time.Minute}
+
+	m.Allow("a", limit)
+	now = now.Add(2 * time.Minute)
+	m.Allow("b", limit)
+	now = now.Add(4 * time.Minute)
+	m.Allow("c", limit)
+	if len(m.buckets) != 2 {
+		t.Errorf("已补满的令牌桶应被清理, 剩余 %d 个", len(m.buckets))
+	}
+	if _, ok := m.buckets["a"]; ok {
+		t.Error("a 已补满应被清理")
+	}
+}
+
+// fakeRedis 记录 Eval 参数并返回固定结果
+type fakeRedis struct {
+	keys  []string
+	args  []interface{}
+	reply interface{}
+	err   error
+}
+
+func (f *fakeRedis) Eval(script string, keys []string, args ...interface{}) (interface{}, error) {
+	f.keys, f.args = keys, args
+	return f.reply, f.err
+}
+
+func TestRedisAllow(t *testing.T) {
+	client := &fakeRedis{reply: []interface{}{int64(0), int64(1500)}}
+	r := NewRedis(client)
+	now := time.UnixMilli(1714654800000)
+	r.now = func() time.Time { return now }
+
+	result, err := r.Allow("login:ip:1.2.3.4", Limit{Burst: 10, Per: time.Minute})
+	if err != nil || result.Allowed || result.RetryAfter != 1500*time.Millisecond {
+		t.Errorf("Allow = %+v, %v", result, err)
+	}
+	if len(client.keys) != 1 || client.keys[0] != "ratelimit:login:ip:1.2.3.4" {
+		t.Errorf("keys = %v", client.keys)
+	}
+	if len(client.args) != 3 || client.args[0] != 10 || client.args[1] != int64(6000) || client.args[2] != now.UnixMilli() {
+		t.Errorf("args = %v", client.args)
+	}
+
+	client.reply = []interface{}{int64(1), int64(0)}
+	if result, _ := r.Allow("login:ip:1.2.3.4", Limit{Burst: 10, Per: time.Minute}); !result.Allowed {
+		t.Error("脚本返回 1 时应允许")
+	}
+
+	client.reply = "OK"
+	if _, err := r.Allow("x", Limit{Burst: 1, Per: time.Second}); err == nil {
+		t.Error("返回值格式错误应返回错误")
+	}
+	client.err = errors.New("connection refused")
+	if _, err := r.Allow("x", Limit{Burst: 1, Per: time.Second}); err == nil {
+		t.Error("Redis 错误应返回")
+	}
+}
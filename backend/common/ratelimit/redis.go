@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"fmt"
+	"time"
+)
+
+// keyPrefix 令牌桶在 Redis 中的键前缀
+const keyPrefix = "ratelimit:"
+
+// tokenBucketScript 在 Redis 中原子地补充并取出令牌
+// KEYS[1] 令牌桶；ARGV 为桶容量、恢复一个令牌的毫秒数、当前毫秒时间戳
+// 返回 {是否允许, 需等待的毫秒数}
+const tokenBucketScript = `
+local burst = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+if now > ts then
+	tokens = math.min(burst, tokens + (now - ts) / interval)
+	ts = now
+end
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) * interval)
+end
+redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'ts', ts)
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) * interval) + 1000)
+return {allowed, wait}
+`
+
+// RedisClient Redis 限流依赖的命令，由 common/redis.RedisDB 实现
+type RedisClient interface {
+	Eval(script string, keys []string, args ...interface{}) (interface{}, error)
+}
+
+// Redis 基于 Redis 的令牌桶限流，多实例共享计数
+// 时间戳由调用方传入，各实例的时钟需要同步
+type Redis struct {
+	client RedisClient
+	now    func() time.Time
+}
+
+// NewRedis 创建 Redis 限流器
+func NewRedis(client RedisClient) *Redis {
+	return &Redis{client: client, now: time.Now}
+}
+
+func (r *Redis) Allow(key string, limit Limit) (Result, error) {
+	interval := limit.interval().Milliseconds()
+	if interval < 1 {
+		interval = 1
+	}
+	reply, err := r.client.Eval(tokenBucketScript, []string{keyPrefix + key}, limit.Burst, interval, r.now().UnixMilli())
+	if err != nil {
+		return Result{}, err
+	}
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 2 {
+		return Result{}, fmt.Errorf("限流脚本返回值格式错误: %v", reply)
+	}
+	allowed, ok1 := values[0].(int64)
+	wait, ok2 := values[1].(int64)
+	if !ok1 || !ok2 {
+		return Result{}, fmt.Errorf("限流脚本返回值格式错误: %v", reply)
+	}
+	return Result{Allowed: allowed == 1, RetryAfter: time.Duration(wait) * time.Millisecond}, nil
+}
@@ -29,18 +29,13 @@ var DB *RedisDB
 
 type RedisDB struct {
 	client *redis.Client
-	config *RedisConfig
+	config *config.RedisConfig
 	prefix string
 }
 
 func Init() {
 
-	session := config.Config.Section("redis")
-	redisConfig := &RedisConfig{}
-	err := session.MapTo(redisConfig)
-	if err != nil {
-		panic(err)
-	}
+	redisConfig := config.Get().Redis
 	if !redisConfig.Enabled {
 		return
 	}
@@ -52,15 +47,23 @@ func Init() {
 		PoolSize:     redisConfig.PoolSize,
 		MinIdleConns: redisConfig.MinIdleConns,
 	})
-	DB = &RedisDB{client: client, config: redisConfig, prefix: prefix}
+	DB = &RedisDB{client: client, config: &redisConfig, prefix: prefix}
 
-	_, err = client.Ping().Result()
+	_, err := client.Ping().Result()
 	if err != nil {
 		panic(err)
 	}
 	log.Info("redis connection success ")
 }
 
+// Close 关闭 Redis 连接，未启用时不做处理
+func Close() error {
+	if DB == nil {
+		return nil
+	}
+	return DB.client.Close()
+}
+
 func (r *RedisDB) SetPrefix(prefix string) *RedisDB {
 	r.prefix = prefix
 	return r
@@ -77,6 +80,19 @@ func (r *RedisDB) Get(key string) (string, error) {
 	return res, err
 }
 
+// GetBytes 获取原始值，key 不存在时 ok 为 false
+func (r *RedisDB) GetBytes(key string) ([]byte, bool, error) {
+	key = r.getKey(key)
+	res, err := r.client.Get(key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return res, true, nil
+}
+
 // 包装Get方法
 // 从json字符串解析为go语言变量类型
 func (r *RedisDB) GetJson(key string, out interface{}) error {
@@ -139,7 +155,15 @@ func (r *RedisDB) Del(keys ...string) *redis.IntCmd {
 	if !r.config.Enabled {
 		return nil
 	}
-	return r.client.Del(keys...)
+	return r.client.Del(r.getKeys(keys)...)
+}
+
+// Delete 删除 key，返回删除的个数
+func (r *RedisDB) Delete(keys ...string) (int64, error) {
+	if !r.config.Enabled {
+		return 0, nil
+	}
+	return r.client.Del(r.getKeys(keys)...).Result()
 }
 
 func (r *RedisDB) HDel(key string, fields ...string) *redis.IntCmd {
@@ -236,7 +260,7 @@ func (r *RedisDB) SAdd(key string, members ...interface{}) (int64, error) {
 		return 0, nil
 	}
 	key = r.getKey(key)
-	result, err := r.client.SAdd(key, members).Result()
+	result, err := r.client.SAdd(key, members...).Result()
 	if err == redis.Nil {
 		log.Errorf("redis operation=%s key=%s not exist\n", "sadd", key)
 		return 0, nil
@@ -308,3 +332,11 @@ func (r *RedisDB) Eval(script string, keys []string, args ...interface{}) (inter
 func (r *RedisDB) getKey(key string) string {
 	return prefix + key
 }
+
+func (r *RedisDB) getKeys(keys []string) []string {
+	prefixed := make([]string, 0, len(keys))
+	for _, key := range keys {
+		prefixed = append(prefixed, r.getKey(key))
+	}
+	return prefixed
+}
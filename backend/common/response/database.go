@@ -0,0 +1,74 @@
+package response
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/gorm"
+)
+
+// databaseErrors gorm 和 database/sql 中表示数据库或连接出错的错误，不包括 gorm.ErrRecordNotFound
+var databaseErrors = []error{
+	sql.ErrConnDone,
+	sql.ErrTxDone,
+	driver.ErrBadConn,
+	gorm.ErrInvalidTransaction,
+	gorm.ErrInvalidData,
+	gorm.ErrInvalidField,
+	gorm.ErrInvalidDB,
+	gorm.ErrDuplicatedKey,
+	gorm.ErrForeignKeyViolated,
+	gorm.ErrCheckConstraintViolated,
+	gorm.ErrMissingWhereClause,
+	gorm.ErrUnsupportedRelation,
+	gorm.ErrPrimaryKeyRequired,
+	gorm.ErrModelValueRequired,
+	gorm.ErrNotImplemented,
+}
+
+// databaseErrorMarkers 服务层用 %v 包装后无法用 errors.As 识别的数据库错误特征
+var databaseErrorMarkers = []string{
+	"Error 1", // MySQL 错误，如 Error 1062 (23000): Duplicate entry
+	"SQLSTATE",
+	"sql: ",
+	"database is locked",
+	"no such table",
+	"no such column",
+	"constraint failed",
+	"driver: bad connection",
+	"invalid connection",
+	"connection refused",
+}
+
+// IsDatabaseError 是否为数据库错误，数据库错误可能包含表结构、SQL 和数据，不能展示给用户
+func IsDatabaseError(err error) bool {
+	if err == nil || errors.Is(err, gorm.ErrRecordNotFound) {
+		return false
+	}
+	var mysqlErr *mysqldriver.MySQLError
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &mysqlErr) || errors.As(err, &sqliteErr) {
+		return true
+	}
+	for _, target := range databaseErrors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	msg := err.Error()
+	for _, marker := range databaseErrorMarkers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// visibleCause 原始错误能否展示给用户
+func visibleCause(err error) bool {
+	return !IsDatabaseError(err) && !errors.Is(err, gorm.ErrRecordNotFound)
+}
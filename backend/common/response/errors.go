@@ -0,0 +1,179 @@
+package response
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// 支持的语言，按请求头 Accept-Language 选择，默认中文
+const (
+	LangZh = "zh"
+	LangEn = "en"
+)
+
+// Error 错误码表中的错误：业务码、HTTP 状态码和各语言的提示
+// 通过 WithDetail 附带给用户看的说明，通过 Wrap 附带原始错误；原始错误只在 4xx 错误中展示，且数据库错误从不展示
+type Error struct {
+	Code     int
+	Status   int
+	messages map[string]string
+	detail   string
+	cause    error
+}
+
+// codes 已定义的业务码，避免重复
+var (
+	codesMu sync.Mutex
+	codes   = make(map[int]*Error)
+)
+
+// Define 定义错误码，业务码重复时 panic
+func Define(code, status int, zh, en string) *Error {
+	codesMu.Lock()
+	defer codesMu.Unlock()
+	if _, ok := codes[code]; ok {
+		panic(fmt.Sprintf("错误码 %d 重复定义", code))
+	}
+	e := &Error{Code: code, Status: status, messages: map[string]string{LangZh: zh, LangEn: en}}
+	codes[code] = e
+	return e
+}
+
+// 错误码表：通用错误的业务码与 HTTP 状态码相同，具体错误为 HTTP 状态码后加两位序号
+var (
+	ErrInvalidParams   = Define(400, http.StatusBadRequest, "参数错误", "Invalid parameters")
+	ErrInvalidNumbers  = Define(40001, http.StatusBadRequest, "号码格式错误", "Invalid lottery numbers")
+	ErrRequestRejected = Define(40002, http.StatusBadRequest, "操作失败", "Request rejected")
+	ErrWeChatLogin     = Define(40003, http.StatusBadRequest, "微信登录失败", "WeChat login failed")
+
+	ErrUnauthorized = Define(401, http.StatusUnauthorized, "未授权", "Unauthorized")
+	ErrTokenMissing = Define(40101, http.StatusUnauthorized, "缺少认证信息", "Missing credentials")
+	ErrTokenInvalid = Define(40102, http.StatusUnauthorized, "认证失败", "Invalid or expired token")
+
+	ErrForbidden     = Define(403, http.StatusForbidden, "没有权限", "Forbidden")
+	ErrAdminRequired = Define(40301, http.StatusForbidden, "需要管理员权限", "Administrator permission required")
+
+	ErrNotFound           = Define(404, http.StatusNotFound, "资源不存在", "Not found")
+	ErrGameNotFound       = Define(40401, http.StatusNotFound, "游戏不存在", "Game not found")
+	ErrDrawResultNotFound = Define(40402, http.StatusNotFound, "开奖结果不存在", "Draw result not found")
+	ErrNumberNotFound     = Define(40403, http.StatusNotFound, "号码不存在", "Number not found")
+
+	ErrConflict   = Define(409, http.StatusConflict, "操作冲突", "Conflict")
+	ErrJobRunning = Define(40901, http.StatusConflict, "任务正在执行，请稍后再试", "Job is already running")
+
+	ErrTooManyRequests = Define(429, http.StatusTooManyRequests, "请求过于频繁，请稍后再试", "Too many requests")
+
+	ErrInternal     = Define(500, http.StatusInternalServerError, "服务器内部错误", "Internal server error")
+	ErrDatabase     = Define(50001, http.StatusInternalServerError, "数据服务暂时不可用", "Database unavailable")
+	ErrServerConfig = Define(50002, http.StatusInternalServerError, "服务配置错误", "Server misconfigured")
+
+	ErrUpstream    = Define(502, http.StatusBadGateway, "第三方服务请求失败", "Upstream service failed")
+	ErrWeChat      = Define(50201, http.StatusBadGateway, "微信服务请求失败", "WeChat service failed")
+	ErrCrawlFailed = Define(50202, http.StatusBadGateway, "抓取开奖数据失败", "Failed to crawl draw results")
+)
+
+// Error 实现 error，返回中文提示和原始错误，用于日志
+func (e *Error) Error() string {
+	msg := e.messages[LangZh]
+	if e.detail != "" {
+		msg += ": " + e.detail
+	}
+	if e.cause != nil {
+		msg += ": " + e.cause.Error()
+	}
+	return msg
+}
+
+// Unwrap 返回原始错误
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is 业务码相同即视为同一错误，WithDetail、Wrap 后仍可用 errors.Is 判断
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithDetail 附带给用户看的说明，如具体哪个参数错误
+func (e *Error) WithDetail(detail string) *Error {
+	copied := *e
+	copied.detail = detail
+	return &copied
+}
+
+// Wrap 附带原始错误，原始错误记录到日志
+func (e *Error) Wrap(err error) *Error {
+	copied := *e
+	copied.cause = err
+	return &copied
+}
+
+// Message 按语言返回提示，未翻译的语言使用中文
+// 有说明时追加说明；没有说明的 4xx 错误追加原始错误，原始错误为数据库错误或 gorm 的记录不存在时不追加
+func (e *Error) Message(lang string) string {
+	msg, ok := e.messages[lang]
+	if !ok {
+		msg = e.messages[LangZh]
+	}
+	detail := e.detail
+	if detail == "" && e.cause != nil && e.Status < http.StatusInternalServerError && visibleCause(e.cause) {
+		detail = e.cause.Error()
+	}
+	switch {
+	case detail == "" || detail == msg:
+		return msg
+	case strings.HasPrefix(detail, msg):
+		// 原始错误已包含提示，如 "号码不存在或不属于该用户"
+		return detail
+	default:
+		return msg + ": " + detail
+	}
+}
+
+// mappings 服务层错误与错误码的对应关系，由 Register 注册
+var (
+	mappingsMu sync.RWMutex
+	mappings   []mapping
+)
+
+type mapping struct {
+	target error
+	err    *Error
+}
+
+// Register 注册服务层错误对应的错误码，错误链中包含 target 时按 err 响应
+func Register(target error, err *Error) {
+	mappingsMu.Lock()
+	defer mappingsMu.Unlock()
+	mappings = append(mappings, mapping{target: target, err: err})
+}
+
+// Lookup 将任意错误转换为错误码表中的错误：
+// 已是 *Error 时直接返回；匹配 Register 注册的错误时转换为对应错误码并保留原始错误；
+// 数据库错误转换为 ErrDatabase，其余为 ErrInternal，原始错误均不展示给用户
+func Lookup(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		if e.Status < http.StatusInternalServerError && IsDatabaseError(e.cause) {
+			// 处理函数按参数错误等响应，但实际是数据库出错
+			return ErrDatabase.Wrap(err)
+		}
+		return e
+	}
+
+	mappingsMu.RLock()
+	defer mappingsMu.RUnlock()
+	for _, m := range mappings {
+		if errors.Is(err, m.target) {
+			return m.err.Wrap(err)
+		}
+	}
+	if IsDatabaseError(err) {
+		return ErrDatabase.Wrap(err)
+	}
+	return ErrInternal.Wrap(err)
+}
@@ -0,0 +1,74 @@
+// Package response 统一的接口响应格式 {"code","message","data"} 和错误码表
+// 成功时 code 为 200；失败时 code 为错误码表中的业务码，HTTP 状态码由错误码决定
+package response
+
+import (
+	"net/http"
+	"strings"
+
+	applog "lucky/common/log"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CodeSuccess 成功响应的业务码
+const CodeSuccess = 200
+
+// Body 响应体
+type Body struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func init() {
+	Register(gorm.ErrRecordNotFound, ErrNotFound)
+}
+
+// Page 分页列表数据
+type Page struct {
+	List     interface{} `json:"list"`
+	Total    int64       `json:"total"`
+	Page     int         `json:"page"`
+	PageSize int         `json:"pageSize"`
+}
+
+// OK 成功响应
+func OK(c *gin.Context, data interface{}) {
+	Success(c, "success", data)
+}
+
+// Success 带提示的成功响应
+func Success(c *gin.Context, message string, data interface{}) {
+	c.JSON(http.StatusOK, Body{Code: CodeSuccess, Message: message, Data: data})
+}
+
+// Fail 错误响应，err 按 Lookup 转换为错误码；5xx 错误和被隐藏的原始错误记录到日志
+func Fail(c *gin.Context, err error) {
+	FailWithData(c, err, nil)
+}
+
+// FailWithData 带数据的错误响应，如任务失败时返回执行记录
+func FailWithData(c *gin.Context, err error, data interface{}) {
+	e := Lookup(err)
+	if e.Status >= http.StatusInternalServerError {
+		applog.Ctx(c.Request.Context()).WithField("code", e.Code).Errorf("请求处理失败: %v", err)
+	}
+	_ = c.Error(err)
+	c.JSON(e.Status, Body{Code: e.Code, Message: e.Message(Lang(c)), Data: data})
+}
+
+// Abort 错误响应并终止后续处理，用于中间件
+func Abort(c *gin.Context, err error) {
+	Fail(c, err)
+	c.Abort()
+}
+
+// Lang 请求的语言，Accept-Language 以 en 开头时为英文，否则为中文
+func Lang(c *gin.Context) string {
+	if strings.HasPrefix(strings.ToLower(c.GetHeader("Accept-Language")), LangEn) {
+		return LangEn
+	}
+	return LangZh
+}
@@ -0,0 +1,171 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var errTestBusy = errors.New("busy")
+
+func init() {
+	Register(errTestBusy, ErrJobRunning)
+}
+
+func TestLookup(t *testing.T) {
+	duplicate := &mysqldriver.MySQLError{Number: 1062, Message: "Duplicate entry 'x' for key 'users.open_id'"}
+
+	tests := []struct {
+		name string
+		err  error
+		want *Error
+	}{
+		{"catalog error", ErrGameNotFound, ErrGameNotFound},
+		{"wrapped catalog error", fmt.Errorf("load: %w", ErrNumberNotFound.WithDetail("1")), ErrNumberNotFound},
+		{"registered error", fmt.Errorf("crawl ssq: %w", errTestBusy), ErrJobRunning},
+		{"record not found", gorm.ErrRecordNotFound, ErrNotFound},
+		{"mysql error", duplicate, ErrDatabase},
+		{"wrapped mysql error", fmt.Errorf("保存失败: %w", duplicate), ErrDatabase},
+		{"mysql error formatted with %v", fmt.Errorf("保存失败: %v", duplicate), ErrDatabase},
+		{"4xx wrapping database error", ErrRequestRejected.Wrap(duplicate), ErrDatabase},
+		{"unknown error", errors.New("boom"), ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Lookup(tt.err)
+			assert.Equal(t, tt.want.Code, got.Code)
+			assert.Equal(t, tt.want.Status, got.Status)
+			assert.ErrorIs(t, got, tt.want)
+		})
+	}
+}
+
+func TestMessage(t *testing.T) {
+	duplicate := &mysqldriver.MySQLError{Number: 1062, Message: "Duplicate entry 'x' for key 'users.open_id'"}
+
+	tests := []struct {
+		name string
+		err  *Error
+		lang string
+		want string
+	}{
+		{"zh", ErrGameNotFound, LangZh, "游戏不存在"},
+		{"en", ErrGameNotFound, LangEn, "Game not found"},
+		{"unknown language falls back to zh", ErrGameNotFound, "fr", "游戏不存在"},
+		{"detail", ErrGameNotFound.WithDetail("abc"), LangZh, "游戏不存在: abc"},
+		{"detail starting with message", ErrNumberNotFound.WithDetail("号码不存在或不属于该用户"), LangZh, "号码不存在或不属于该用户"},
+		{"4xx cause", ErrRequestRejected.Wrap(errors.New("号码已存在")), LangZh, "操作失败: 号码已存在"},
+		{"4xx database cause hidden", ErrRequestRejected.Wrap(duplicate), LangZh, "操作失败"},
+		{"4xx record not found hidden", ErrRequestRejected.Wrap(gorm.ErrRecordNotFound), LangZh, "操作失败"},
+		{"5xx cause hidden", ErrCrawlFailed.Wrap(errors.New("dial tcp 10.0.0.1:443")), LangZh, "抓取开奖数据失败"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.err.Message(tt.lang))
+		})
+	}
+}
+
+func TestErrorKeepsCatalogUnchanged(t *testing.T) {
+	cause := errors.New("cause")
+	wrapped := ErrInvalidParams.WithDetail("page").Wrap(cause)
+
+	assert.Equal(t, "参数错误: page: cause", wrapped.Error())
+	assert.ErrorIs(t, wrapped, cause)
+	assert.Equal(t, "参数错误", ErrInvalidParams.Message(LangZh))
+}
+
+func TestDefineDuplicateCodePanics(t *testing.T) {
+	assert.Panics(t, func() {
+		Define(ErrInternal.Code, http.StatusInternalServerError, "重复", "duplicate")
+	})
+}
+
+func TestFail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		err        error
+		lang       string
+		wantStatus int
+		wantBody   Body
+	}{
+		{
+			name:       "catalog error",
+			err:        ErrInvalidParams.WithDetail("gameCode 参数不能为空"),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   Body{Code: 400, Message: "参数错误: gameCode 参数不能为空"},
+		},
+		{
+			name:       "english",
+			err:        ErrAdminRequired,
+			lang:       "en-US,en;q=0.9",
+			wantStatus: http.StatusForbidden,
+			wantBody:   Body{Code: 40301, Message: "Administrator permission required"},
+		},
+		{
+			name:       "record not found",
+			err:        fmt.Errorf("查询号码: %w", gorm.ErrRecordNotFound),
+			wantStatus: http.StatusNotFound,
+			wantBody:   Body{Code: 404, Message: "资源不存在"},
+		},
+		{
+			name:       "database error",
+			err:        fmt.Errorf("Error 1146 (42S02): Table 'lucky.users' doesn't exist"),
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   Body{Code: 50001, Message: "数据服务暂时不可用"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			var recorded []*gin.Error
+			r.GET("/", func(c *gin.Context) {
+				Fail(c, tt.err)
+				recorded = c.Errors
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.lang != "" {
+				req.Header.Set("Accept-Language", tt.lang)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var body Body
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantBody, body)
+			require.Len(t, recorded, 1)
+			assert.Equal(t, tt.err, recorded[0].Err)
+		})
+	}
+}
+
+func TestSuccess(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.GET("/", func(c *gin.Context) {
+		OK(c, gin.H{"id": 1})
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"code":200,"message":"success","data":{"id":1}}`, w.Body.String())
+}
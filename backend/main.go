@@ -1,59 +1,51 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
-	"lucky/api"
 	"lucky/common/config"
-	"lucky/common/mysql"
-	"lucky/common/redis"
-	"lucky/migration"
-	"lucky/service"
-
-	"github.com/gin-gonic/gin"
+	applog "lucky/common/log"
 )
 
 func main() {
-	// 初始化配置
-	_ = config.Config
+	configPath := flag.String("config", "", "配置文件路径，默认为 $LUCKY_CONFIG 或可执行文件目录下的 config/ini.ini")
+	flag.Parse()
 
-	// 初始化MySQL
-	mysql.Init()
-	log.Println("MySQL连接成功")
+	// 加载配置，环境变量 LUCKY_<节>_<键> 覆盖配置文件中的值
+	cfg, err := config.Init(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	applog.Init(cfg.Log)
+
+	// 数据库迁移子命令：migrate up|down|status
+	if args := flag.Args(); len(args) > 0 && args[0] == "migrate" {
+		if err := cfg.ValidateDatabase(); err != nil {
+			log.Fatal(err)
+		}
+		runMigrate(args[1:])
+		return
+	}
 
-	// 数据库迁移
-	if err := migration.AutoMigrate(mysql.DB); err != nil {
-		log.Fatal("数据库迁移失败: ", err)
+	// 启动前校验配置，缺少必填项时列出全部问题
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
 	}
 
-	// 初始化基础数据
-	if err := service.InitializeData(mysql.DB); err != nil {
-		log.Fatal("基础数据初始化失败: ", err)
+	app := NewApp(cfg)
+	if err := app.Init(); err != nil {
+		log.Fatal(err)
 	}
 
-	// 初始化Redis
-	redis.Init()
-	log.Println("Redis连接成功")
-
-	r := gin.Default()
-	gin.SetMode(gin.DebugMode)
-
-	// 注册路由
-	api.RegisterTestRoutes(r)
-	api.RegisterAuthRoutes(r)
-	api.RegisterUserRoutes(r)
-	api.RegisterGameRoutes(r)
-	api.RegisterNumberRoutes(r)
-	api.RegisterResultRoutes(r)
-	api.RegisterCrawlerRoutes(r)
-	api.RegisterMissingRoutes(r)
-
-	// 定时抓取开奖数据
-	crawler := service.NewCrawlerService()
-	go crawler.ScheduleCrawl()
-
-	log.Println("服务启动在端口 :8080")
-	if err := r.Run(":8080"); err != nil {
-		log.Fatal("服务启动失败: ", err)
+	// 收到 SIGINT/SIGTERM 后优雅关闭
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := app.Run(ctx); err != nil {
+		log.Fatal(err)
 	}
 }
@@ -1,10 +1,11 @@
 package middleware
 
 import (
-	"net/http"
 	"strings"
 
+	applog "lucky/common/log"
 	"lucky/common/mysql"
+	"lucky/common/response"
 	"lucky/model"
 	"lucky/service"
 
@@ -17,35 +18,22 @@ func AuthRequired() gin.HandlerFunc {
 		// 获取Authorization header
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"code":    401,
-				"message": "缺少认证信息",
-			})
-			c.Abort()
+			response.Abort(c, response.ErrTokenMissing)
 			return
 		}
 
 		// 提取Bearer token
 		token := extractBearerToken(authHeader)
 		if token == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"code":    401,
-				"message": "无效的认证格式",
-			})
-			c.Abort()
+			response.Abort(c, response.ErrTokenInvalid.WithDetail("无效的认证格式"))
 			return
 		}
 
 		// 验证token
-		authService := service.NewAuthService(mysql.DB)
+		authService := service.NewAuthService(mysql.DB.WithContext(c.Request.Context()))
 		user, err := authService.ValidateAccessToken(token)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"code":    401,
-				"message": "认证失败",
-				"error":   err.Error(),
-			})
-			c.Abort()
+			response.Abort(c, response.ErrTokenInvalid.Wrap(err))
 			return
 		}
 
@@ -53,6 +41,7 @@ func AuthRequired() gin.HandlerFunc {
 		c.Set("user", user)
 		c.Set("user_id", user.ID)
 		c.Set("open_id", user.OpenID)
+		c.Request = c.Request.WithContext(applog.WithUserID(c.Request.Context(), user.ID))
 
 		c.Next()
 	}
@@ -76,7 +65,7 @@ func OptionalAuth() gin.HandlerFunc {
 		}
 
 		// 验证token
-		authService := service.NewAuthService(mysql.DB)
+		authService := service.NewAuthService(mysql.DB.WithContext(c.Request.Context()))
 		user, err := authService.ValidateAccessToken(token)
 		if err != nil {
 			// 认证失败，但不阻止请求继续
@@ -88,6 +77,7 @@ func OptionalAuth() gin.HandlerFunc {
 		c.Set("user", user)
 		c.Set("user_id", user.ID)
 		c.Set("open_id", user.OpenID)
+		c.Request = c.Request.WithContext(applog.WithUserID(c.Request.Context(), user.ID))
 
 		c.Next()
 	}
@@ -118,13 +108,30 @@ func GetCurrentUser(c *gin.Context) (*model.User, bool) {
 	return userModel, ok
 }
 
-// GetCurrentUserID 从gin.Context获取当前用户ID
+// GetCurrentUserID 从gin.Context获取当前用户ID，需在 AuthRequired 之后使用
 func GetCurrentUserID(c *gin.Context) (uint64, bool) {
 	userID, exists := c.Get("user_id")
 	if !exists {
 		return 0, false
 	}
 
-	id, ok := userID.(uint64)
-	return id, ok
+	id, ok := userID.(int64)
+	return uint64(id), ok
+}
+
+// AdminRequired 管理员权限中间件，需在 AuthRequired 之后使用
+func AdminRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := GetCurrentUser(c)
+		if !ok {
+			response.Abort(c, response.ErrUnauthorized)
+			return
+		}
+		if !user.IsAdmin() {
+			response.Abort(c, response.ErrAdminRequired)
+			return
+		}
+
+		c.Next()
+	}
 }
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"time"
+
+	"lucky/common/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics 记录每个请求的耗时和状态码，按路由模板统计，避免路径参数导致指标过多
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		metrics.ObserveHTTPRequest(c.FullPath(), c.Request.Method, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"fmt"
+	"strconv"
+
+	"lucky/common/config"
+	applog "lucky/common/log"
+	"lucky/common/metrics"
+	"lucky/common/ratelimit"
+	"lucky/common/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit 按 [rate_limit] 中名为 policy 的配置限流，未启用限流或该项为 off 时不做处理
+// 已登录（在 AuthRequired 之后使用）时按用户计数，否则按客户端 IP 计数
+// 超过限制返回 429 和 Retry-After 响应头；限流器出错时放行请求，避免 Redis 故障导致接口不可用
+func RateLimit(policy string) gin.HandlerFunc {
+	cfg := config.Get().RateLimit
+	limit, ok, err := ratelimit.ParseLimit(cfg.Policy(policy))
+	if err != nil {
+		panic(fmt.Sprintf("[rate_limit] %s %v", policy, err))
+	}
+	if !cfg.Enabled || !ok {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	return func(c *gin.Context) {
+		result, err := ratelimit.Default.Allow(policy+":"+rateLimitKey(c), limit)
+		if err != nil {
+			applog.Ctx(c.Request.Context()).Errorf("限流 %s 失败，放行请求: %v", policy, err)
+			c.Next()
+			return
+		}
+		if !result.Allowed {
+			metrics.ObserveRateLimited(policy)
+			c.Header("Retry-After", strconv.Itoa(result.RetryAfterSeconds()))
+			response.Abort(c, response.ErrTooManyRequests)
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateLimitKey 限流计数的 key：已登录为用户ID，否则为客户端 IP
+func rateLimitKey(c *gin.Context) string {
+	if user, ok := GetCurrentUser(c); ok && user != nil {
+		return "user:" + strconv.FormatInt(user.ID, 10)
+	}
+	return "ip:" + c.ClientIP()
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	applog "lucky/common/log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// RequestIDHeader 请求ID的请求头和响应头
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength 沿用调用方请求ID的最大长度，过长或含非法字符时重新生成
+const maxRequestIDLength = 64
+
+// RequestID 为每个请求分配请求ID：沿用调用方传入的 X-Request-ID，没有时生成，并写入响应头
+// 请求ID和路由中的游戏代码记录到请求 context，处理函数和服务通过 applog.Ctx 输出的日志都带有这些字段
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+		c.Header(RequestIDHeader, requestID)
+		c.Set("request_id", requestID)
+
+		ctx := applog.WithRequestID(c.Request.Context(), requestID)
+		ctx = applog.WithGameCode(ctx, c.Param("gameCode"))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// AccessLog 请求结束后输出访问日志，代替 gin 默认的文本日志
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		entry := applog.Ctx(c.Request.Context()).WithFields(logrus.Fields{
+			"method":     c.Request.Method,
+			"path":       c.Request.URL.Path,
+			"route":      c.FullPath(),
+			"status":     c.Writer.Status(),
+			"latency_ms": time.Since(start).Milliseconds(),
+			"client_ip":  c.ClientIP(),
+		})
+		if len(c.Errors) > 0 {
+			entry = entry.WithField("errors", c.Errors.String())
+		}
+		switch status := c.Writer.Status(); {
+		case status >= 500:
+			entry.Error("请求处理失败")
+		case status >= 400:
+			entry.Warn("请求被拒绝")
+		default:
+			entry.Info("请求完成")
+		}
+	}
+}
+
+// validRequestID 请求ID只允许字母、数字、- 和 _，避免日志注入
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID 生成32位十六进制随机请求ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return hex.EncodeToString([]byte(time.Now().Format("20060102150405.000000")))
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"lucky/common/mysql"
+	"lucky/migration"
+)
+
+// migrateUsage 迁移子命令用法
+const migrateUsage = `用法: go run . [-config 配置文件] migrate <command> [steps]（编译后为 ./lucky-linux-amd64 migrate <command> [steps]）
+
+  up [steps]    执行未执行的迁移，不指定 steps 时全部执行
+  down [steps]  回滚最近执行的迁移，不指定 steps 时回滚1个
+  status        查看迁移执行状态，并检查表结构与模型是否一致`
+
+// runMigrate 执行迁移子命令
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		log.Fatal(migrateUsage)
+	}
+	steps := 0
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			log.Fatalf("steps 必须是正整数: %s\n%s", args[1], migrateUsage)
+		}
+		steps = n
+	}
+
+	mysql.Init()
+
+	switch args[0] {
+	case "up":
+		done, err := migration.Up(mysql.DB, steps)
+		for _, m := range done {
+			fmt.Printf("已执行 %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(done) == 0 {
+			fmt.Println("没有需要执行的迁移")
+		}
+
+	case "down":
+		done, err := migration.Down(mysql.DB, steps)
+		for _, m := range done {
+			fmt.Printf("已回滚 %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(done) == 0 {
+			fmt.Println("没有可回滚的迁移")
+		}
+
+	case "status":
+		statuses, err := migration.Status(mysql.DB)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, s := range statuses {
+			state := "未执行"
+			if s.Applied {
+				state = "已执行 " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-32s %s\n", s.Version, s.Name, state)
+		}
+
+		problems, err := migration.CheckSchema(mysql.DB)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(problems) == 0 {
+			fmt.Println("表结构与模型一致")
+			return
+		}
+		fmt.Println("表结构与模型不一致:")
+		for _, problem := range problems {
+			fmt.Println("  - " + problem)
+		}
+
+	default:
+		log.Fatalf("不支持的迁移命令: %s\n%s", args[0], migrateUsage)
+	}
+}
@@ -0,0 +1,153 @@
+package migration
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"lucky/common/database"
+
+	"gorm.io/gorm"
+)
+
+// legacyVersion 旧表结构升级后对应的迁移版本，升级后与 0001 建立的表结构一致
+const legacyVersion = 1
+
+// legacyBaselineName 旧表结构升级后版本 1 的迁移记录名称
+// 与 0001 的名称不同，回滚时据此拒绝删除不是由迁移创建的表
+const legacyBaselineName = "upgrade_legacy_core_tables"
+
+// legacyStep 旧表结构的一步升级，when 为 nil 或返回 true 时按顺序执行 statements
+type legacyStep struct {
+	when       func(m gorm.Migrator) bool
+	statements []string
+}
+
+// hasColumn 表中存在该列
+func hasColumn(table, column string) func(gorm.Migrator) bool {
+	return func(m gorm.Migrator) bool {
+		return m.HasColumn(table, column)
+	}
+}
+
+// missingColumn 表中不存在该列
+func missingColumn(table, column string) func(gorm.Migrator) bool {
+	return func(m gorm.Migrator) bool {
+		return !m.HasColumn(table, column)
+	}
+}
+
+// renamedColumn 旧列存在且新列不存在
+func renamedColumn(table, from, to string) func(gorm.Migrator) bool {
+	return func(m gorm.Migrator) bool {
+		return m.HasColumn(table, from) && !m.HasColumn(table, to)
+	}
+}
+
+// legacyUpgrades 将迁移前由 sql/init.sql 建立的表升级为 0001 的表结构，按数据库类型区分
+// 按列是否存在决定是否执行，开发环境执行过 AutoMigrate 的旧库同样适用；不再使用的旧列保留数据，改为允许为空
+var legacyUpgrades = map[string][]legacyStep{
+	database.DriverMySQL: {
+		{when: missingColumn("users", "role"), statements: []string{
+			"ALTER TABLE `users` ADD COLUMN `role` varchar(16) NOT NULL DEFAULT 'user' COMMENT '角色(user:普通用户 admin:管理员)' AFTER `status`",
+		}},
+
+		// 刷新令牌：is_revoked 改为 is_active
+		{when: missingColumn("refresh_tokens", "is_active"), statements: []string{
+			"ALTER TABLE `refresh_tokens` ADD COLUMN `is_active` boolean DEFAULT true COMMENT '是否有效' AFTER `expires_at`",
+		}},
+		{when: hasColumn("refresh_tokens", "is_revoked"), statements: []string{
+			"UPDATE `refresh_tokens` SET `is_active` = (`is_revoked` = 0)",
+			"ALTER TABLE `refresh_tokens` DROP INDEX `idx_refresh_tokens_user_expires`",
+			"ALTER TABLE `refresh_tokens` DROP COLUMN `is_revoked`",
+			"CREATE INDEX `idx_refresh_tokens_user_expires` ON `refresh_tokens` (`user_id`, `expires_at`, `is_active`)",
+		}},
+
+		// 登录日志：client_ip、error_msg 改名，login_type、login_at 不再写入
+		{when: renamedColumn("login_logs", "client_ip", "login_ip"), statements: []string{
+			"ALTER TABLE `login_logs` CHANGE `client_ip` `login_ip` varchar(45) NOT NULL COMMENT '登录IP'",
+		}},
+		{when: hasColumn("login_logs", "client_ip"), statements: []string{
+			"ALTER TABLE `login_logs` MODIFY `client_ip` varchar(45) DEFAULT NULL COMMENT '客户端IP，已改用 login_ip'",
+		}},
+		{when: renamedColumn("login_logs", "error_msg", "message"), statements: []string{
+			"ALTER TABLE `login_logs` CHANGE `error_msg` `message` varchar(255) DEFAULT NULL COMMENT '登录消息'",
+		}},
+		{when: hasColumn("login_logs", "login_type"), statements: []string{
+			"ALTER TABLE `login_logs` MODIFY `login_type` varchar(32) DEFAULT NULL COMMENT '登录类型(wechat,refresh)，已不再写入'",
+		}},
+		{when: hasColumn("login_logs", "login_at"), statements: []string{
+			"ALTER TABLE `login_logs` MODIFY `login_at` datetime(3) DEFAULT NULL COMMENT '登录时间，已改用 created_at'",
+			"ALTER TABLE `login_logs` DROP INDEX `idx_login_logs_user_time`",
+			"CREATE INDEX `idx_login_logs_user_time` ON `login_logs` (`user_id`, `created_at`)",
+		}},
+		{statements: []string{
+			"ALTER TABLE `login_logs` MODIFY `status` bigint NOT NULL DEFAULT 1 COMMENT '登录状态(1:成功 0:失败)'",
+		}},
+
+		{when: missingColumn("lottery_games", "game_type"), statements: []string{
+			"ALTER TABLE `lottery_games` ADD COLUMN `game_type` varchar(16) DEFAULT 'ball' COMMENT '游戏类型：ball(选号型), digit(数字型), keno(快乐8型)' AFTER `game_name`",
+		}},
+		{when: missingColumn("lottery_games", "special_ball_count"), statements: []string{
+			"ALTER TABLE `lottery_games` ADD COLUMN `special_ball_count` bigint DEFAULT 0 COMMENT '特别号个数' AFTER `blue_select_count`",
+		}},
+
+		// 用户号码：ID 改为有符号，与 0002 中引用号码ID的外键类型一致
+		{statements: []string{
+			"ALTER TABLE `user_numbers` MODIFY `id` bigint NOT NULL AUTO_INCREMENT COMMENT '用户号码ID'",
+		}},
+		{when: missingColumn("user_numbers", "play_type"), statements: []string{
+			"ALTER TABLE `user_numbers` ADD COLUMN `play_type` varchar(16) DEFAULT NULL COMMENT '玩法，选号型游戏为空' AFTER `blue_balls`",
+		}},
+		{when: missingColumn("user_numbers", "group_id"), statements: []string{
+			"ALTER TABLE `user_numbers` ADD COLUMN `group_id` bigint DEFAULT NULL COMMENT '所属分组ID' AFTER `play_type`",
+			"CREATE INDEX `idx_user_numbers_group_id` ON `user_numbers` (`group_id`)",
+		}},
+		{when: missingColumn("user_numbers", "multiplier"), statements: []string{
+			"ALTER TABLE `user_numbers` ADD COLUMN `multiplier` bigint DEFAULT 1 COMMENT '倍数' AFTER `group_id`",
+		}},
+		{when: missingColumn("user_numbers", "is_additional"), statements: []string{
+			"ALTER TABLE `user_numbers` ADD COLUMN `is_additional` boolean DEFAULT false COMMENT '是否追加（大乐透）' AFTER `multiplier`",
+		}},
+		{when: missingColumn("user_numbers", "note"), statements: []string{
+			"ALTER TABLE `user_numbers` ADD COLUMN `note` varchar(512) DEFAULT NULL COMMENT '备注' AFTER `nickname`",
+		}},
+
+		{when: missingColumn("draw_results", "version"), statements: []string{
+			"ALTER TABLE `draw_results` ADD COLUMN `version` bigint NOT NULL DEFAULT 1 COMMENT '数据版本，每次更正加1' AFTER `second_amount`",
+		}},
+	},
+}
+
+// isLegacySchema 没有迁移记录但已存在用户表，为引入迁移前建立的数据库
+func isLegacySchema(db *gorm.DB, applied map[int]SchemaMigration) bool {
+	return len(applied) == 0 && db.Migrator().HasTable("users")
+}
+
+// upgradeLegacySchema 将旧表结构升级为 0001 的表结构，并记录版本 1 已执行
+func upgradeLegacySchema(db *gorm.DB) (Migration, error) {
+	baseline := Migration{Version: legacyVersion, Name: legacyBaselineName}
+	steps, ok := legacyUpgrades[db.Dialector.Name()]
+	if !ok {
+		return baseline, fmt.Errorf("数据库中已有 users 表但没有迁移记录，%s 不支持从旧表结构升级", db.Dialector.Name())
+	}
+
+	log.Printf("检测到引入迁移前建立的表结构，升级为 %04d 的表结构...", legacyVersion)
+	err := runMigration(db, "", func(tx *gorm.DB) error {
+		for _, step := range steps {
+			if step.when != nil && !step.when(tx.Migrator()) {
+				continue
+			}
+			for _, statement := range step.statements {
+				if err := tx.Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return tx.Create(&SchemaMigration{Version: baseline.Version, Name: baseline.Name, AppliedAt: time.Now()}).Error
+	})
+	if err != nil {
+		return baseline, fmt.Errorf("升级旧表结构失败: %v", err)
+	}
+	return baseline, nil
+}
@@ -1,143 +1,307 @@
 package migration
 
 import (
-	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
 	"log"
-	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
-	"lucky/model"
+	"lucky/common/database"
 
 	"gorm.io/gorm"
 )
 
-// AutoMigrate 自动迁移数据库表结构
-func AutoMigrate(db *gorm.DB) error {
-	env := os.Getenv("GO_ENV")
-	if env == "" {
-		env = "development"
-	}
+// 迁移脚本按数据库类型分目录存放：sql/mysql、sql/sqlite，两个目录的版本号和名称需一一对应
+//
+//go:embed sql/*/*.sql
+var migrationFiles embed.FS
 
-	log.Printf("开始数据库迁移 (环境: %s)...", env)
+// migrationFilePattern 迁移文件名格式：<版本号>_<名称>.<up|down>.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
 
-	// 生产环境禁用自动迁移
-	if env == "production" {
-		log.Println("生产环境禁用自动迁移，请使用手动迁移脚本")
-		return nil
+// ErrPendingMigrations 存在未执行的迁移
+var ErrPendingMigrations = errors.New("存在未执行的数据库迁移")
+
+// Migration 一个版本的迁移脚本
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// SchemaMigration 已执行的迁移记录表
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false;column:version"`
+	Name      string    `gorm:"size:128;not null;column:name"`
+	AppliedAt time.Time `gorm:"not null;column:applied_at"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrationStatus 迁移执行状态
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// Migrations 读取指定数据库类型的全部迁移，按版本号排序
+func Migrations(dialect string) ([]Migration, error) {
+	dir := path.Join("sql", dialect)
+	entries, err := migrationFiles.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("不支持的数据库类型: %s", dialect)
 	}
 
-	// 开发/测试环境允许自动迁移
-	if env == "development" || env == "test" {
-		log.Println("开发/测试环境：执行自动迁移...")
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		matches := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("迁移文件名格式错误: %s", entry.Name())
+		}
+		version, _ := strconv.Atoi(matches[1])
+		content, err := migrationFiles.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
 
-		// 兼容性处理：尝试移除历史外键以避免列类型修改失败
-		// 例如：refresh_tokens.user_id -> users.id 的外键 fk_refresh_tokens_user
-		if err := dropLegacyForeignKeys(db); err != nil {
-			log.Printf("移除历史外键失败(可忽略继续): %v", err)
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = m
+		} else if m.Name != matches[2] {
+			return nil, fmt.Errorf("迁移版本 %d 重复: %s, %s", version, m.Name, matches[2])
 		}
+		if matches[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
 
-		// 自动迁移表结构
-		err := db.AutoMigrate(
-			&model.User{},
-			&model.RefreshToken{}, // 新增
-			&model.LoginLog{},     // 新增
-			&model.LotteryGame{},
-			&model.UserNumber{},
-			&model.DrawResult{},
-		)
-		if err != nil {
-			log.Printf("自动迁移失败: %v", err)
-			return err
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("迁移 %04d_%s 缺少 up 或 down 脚本", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// splitStatements 按语句末尾的分号拆分迁移脚本，忽略 -- 注释行
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
 		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
+
+// appliedMigrations 已执行的迁移，按版本号索引
+func appliedMigrations(db *gorm.DB) (map[int]SchemaMigration, error) {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, fmt.Errorf("创建迁移记录表失败: %v", err)
+	}
+	var records []SchemaMigration
+	if err := db.Order("version").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int]SchemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
 
-		log.Println("表结构迁移完成")
+// Status 全部迁移的执行状态
+func Status(db *gorm.DB) ([]MigrationStatus, error) {
+	migrations, err := Migrations(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
 
-		// 创建自定义索引
-		err = createCustomIndexes(db)
-		if err != nil {
-			log.Printf("创建自定义索引失败: %v", err)
-			return err
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status := MigrationStatus{Version: m.Version, Name: m.Name}
+		if record, ok := applied[m.Version]; ok {
+			status.Name = record.Name
+			status.Applied = true
+			appliedAt := record.AppliedAt
+			status.AppliedAt = &appliedAt
 		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
 
-		log.Println("数据库迁移完成")
+// Pending 未执行的迁移
+func Pending(db *gorm.DB) ([]Migration, error) {
+	migrations, err := Migrations(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	var pending []Migration
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; !ok {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
 }
 
-// createCustomIndexes 创建自定义索引
-func createCustomIndexes(db *gorm.DB) error {
-	// 获取当前数据库名
-	dbName, err := getCurrentDatabase(db)
+// CheckPending 存在未执行的迁移时返回 ErrPendingMigrations
+func CheckPending(db *gorm.DB) error {
+	pending, err := Pending(db)
 	if err != nil {
 		return err
 	}
+	if len(pending) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(pending))
+	for _, m := range pending {
+		names = append(names, fmt.Sprintf("%04d_%s", m.Version, m.Name))
+	}
+	return fmt.Errorf("%w: %s", ErrPendingMigrations, strings.Join(names, ", "))
+}
 
-	// 定义需要的索引
-	indexes := []struct {
-		table     string
-		indexName string
-		createSQL string
-	}{
-		{"user_numbers", "idx_user_numbers_composite", "CREATE INDEX idx_user_numbers_composite ON user_numbers(user_id, game_id, is_active, created_at)"},
-		{"draw_results", "idx_draw_results_composite", "CREATE INDEX idx_draw_results_composite ON draw_results(game_id, draw_date, period)"},
-		{"refresh_tokens", "idx_refresh_tokens_user_expires", "CREATE INDEX idx_refresh_tokens_user_expires ON refresh_tokens(user_id, expires_at, is_active)"},
-		{"login_logs", "idx_login_logs_user_time", "CREATE INDEX idx_login_logs_user_time ON login_logs(user_id, created_at)"},
-		{"users", "idx_users_status_version", "CREATE INDEX idx_users_status_version ON users(status, token_version)"},
+// Up 按版本号顺序执行未执行的迁移，steps 为0时全部执行
+// 引入迁移前建立的数据库先升级为 0001 的表结构再执行后续迁移，已有的表不会重新创建
+// MySQL 的 DDL 会隐式提交，每个迁移执行成功后才记录版本，失败时需人工处理已执行的语句
+func Up(db *gorm.DB, steps int) ([]Migration, error) {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, idx := range indexes {
-		exists, err := indexExists(db, dbName, idx.table, idx.indexName)
+	var done []Migration
+	if isLegacySchema(db, applied) {
+		baseline, err := upgradeLegacySchema(db)
 		if err != nil {
-			log.Printf("检查索引是否存在失败: %s.%s %v", idx.table, idx.indexName, err)
-			return err
+			return nil, err
 		}
-		if exists {
-			continue
+		done = append(done, baseline)
+		if steps == 1 {
+			return done, nil
 		}
-		if err := db.Exec(idx.createSQL).Error; err != nil {
-			log.Printf("创建索引失败: %s on %s, err=%v", idx.indexName, idx.table, err)
-			return err
+		if steps > 1 {
+			steps--
 		}
 	}
 
-	log.Println("自定义索引创建完成")
-	return nil
-}
+	pending, err := Pending(db)
+	if err != nil {
+		return done, err
+	}
+	if steps > 0 && steps < len(pending) {
+		pending = pending[:steps]
+	}
 
-// dropLegacyForeignKeys 尝试移除历史外键，避免自动迁移被外键阻塞
-func dropLegacyForeignKeys(db *gorm.DB) error {
-	// MySQL: 如外键不存在会报错，这里忽略错误以继续后续迁移
-	_ = db.Exec("ALTER TABLE refresh_tokens DROP FOREIGN KEY fk_refresh_tokens_user").Error
-	_ = db.Exec("ALTER TABLE login_logs DROP FOREIGN KEY fk_login_logs_user").Error
-	_ = db.Exec("ALTER TABLE user_numbers DROP FOREIGN KEY fk_user_numbers_user").Error
-	// 可按需在此扩展其它可能遗留的外键名称
-	return nil
+	for _, m := range pending {
+		log.Printf("执行迁移 %04d_%s...", m.Version, m.Name)
+		err := runMigration(db, m.Up, func(tx *gorm.DB) error {
+			return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("迁移 %04d_%s 执行失败: %v", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
 }
 
-// indexExists 检查索引是否存在
-func indexExists(db *gorm.DB, dbName, table, indexName string) (bool, error) {
-	const q = `SELECT COUNT(1) FROM INFORMATION_SCHEMA.STATISTICS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND INDEX_NAME = ?`
-	var cnt int
-	if err := db.Raw(q, dbName, table, indexName).Scan(&cnt).Error; err != nil {
-		return false, err
+// Down 按版本号倒序回滚已执行的迁移，steps 为0时回滚1个
+func Down(db *gorm.DB, steps int) ([]Migration, error) {
+	migrations, err := Migrations(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+	if steps <= 0 {
+		steps = 1
+	}
+
+	var done []Migration
+	for i := len(migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		m := migrations[i]
+		record, ok := applied[m.Version]
+		if !ok {
+			continue
+		}
+		if record.Name != m.Name {
+			return done, fmt.Errorf("迁移 %04d 由已有表结构升级而来(%s)，不能回滚，以免删除不是由迁移创建的表", m.Version, record.Name)
+		}
+		log.Printf("回滚迁移 %04d_%s...", m.Version, m.Name)
+		err := runMigration(db, m.Down, func(tx *gorm.DB) error {
+			return tx.Delete(&SchemaMigration{}, m.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("迁移 %04d_%s 回滚失败: %v", m.Version, m.Name, err)
+		}
+		done = append(done, m)
 	}
-	return cnt > 0, nil
+	return done, nil
 }
 
-// getCurrentDatabase 获取当前数据库名
-func getCurrentDatabase(db *gorm.DB) (string, error) {
-	var name sql.NullString
-	if err := db.Raw("SELECT DATABASE()").Scan(&name).Error; err != nil {
-		return "", err
+// runMigration 执行迁移脚本并更新迁移记录，SQLite 支持事务内执行 DDL，失败时整体回滚
+func runMigration(db *gorm.DB, script string, record func(tx *gorm.DB) error) error {
+	run := func(tx *gorm.DB) error {
+		if err := execScript(tx, script); err != nil {
+			return err
+		}
+		return record(tx)
 	}
-	if !name.Valid {
-		return "", nil
+	if database.IsSQLite(db) {
+		return db.Transaction(run)
 	}
-	return name.String, nil
+	return run(db)
 }
 
-// ManualMigrate 手动迁移模式（用于生产环境）
-func ManualMigrate(db *gorm.DB) error {
-	log.Println("手动迁移模式暂未实现")
+// execScript 逐条执行迁移脚本中的语句
+func execScript(db *gorm.DB, script string) error {
+	for _, statement := range splitStatements(script) {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
 	return nil
 }
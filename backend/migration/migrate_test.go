@@ -0,0 +1,222 @@
+package migration
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"lucky/common/database"
+
+	"gorm.io/gorm/schema"
+)
+
+// dialects 内置迁移脚本的数据库类型
+var dialects = []string{database.DriverMySQL, database.DriverSQLite}
+
+func TestMigrations(t *testing.T) {
+	mysqlMigrations, err := Migrations(database.DriverMySQL)
+	if err != nil {
+		t.Fatalf("读取迁移失败: %v", err)
+	}
+	for _, dialect := range dialects {
+		migrations, err := Migrations(dialect)
+		if err != nil {
+			t.Fatalf("读取 %s 迁移失败: %v", dialect, err)
+		}
+		if len(migrations) == 0 {
+			t.Fatalf("%s 没有内置迁移", dialect)
+		}
+		if len(migrations) != len(mysqlMigrations) {
+			t.Errorf("%s 迁移数量 %d 与 mysql 的 %d 不一致", dialect, len(migrations), len(mysqlMigrations))
+		}
+		for i, m := range migrations {
+			if m.Version != i+1 {
+				t.Errorf("%s 迁移版本应连续: 第%d个迁移版本为 %d", dialect, i+1, m.Version)
+			}
+			if i < len(mysqlMigrations) && m.Name != mysqlMigrations[i].Name {
+				t.Errorf("%s 迁移 %04d 名称 %s 与 mysql 的 %s 不一致", dialect, m.Version, m.Name, mysqlMigrations[i].Name)
+			}
+			if len(splitStatements(m.Up)) == 0 || len(splitStatements(m.Down)) == 0 {
+				t.Errorf("%s 迁移 %04d_%s 的 up 或 down 脚本为空", dialect, m.Version, m.Name)
+			}
+		}
+	}
+
+	if _, err := Migrations("postgres"); err == nil {
+		t.Error("不支持的数据库类型应返回错误")
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- 注释
+CREATE TABLE a (
+  id bigint
+);
+
+-- 第二条
+DROP TABLE b;
+INSERT INTO c VALUES ('x')`
+
+	statements := splitStatements(script)
+	want := []string{"CREATE TABLE a (\n  id bigint\n)", "DROP TABLE b", "INSERT INTO c VALUES ('x')"}
+	if len(statements) != len(want) {
+		t.Fatalf("statements = %q", statements)
+	}
+	for i := range want {
+		if statements[i] != want[i] {
+			t.Errorf("statements[%d] = %q, want %q", i, statements[i], want[i])
+		}
+	}
+}
+
+// createTablePattern 迁移脚本中的建表语句
+var createTablePattern = regexp.MustCompile("(?s)CREATE TABLE `([a-z_]+)` \\((.*?)\\n\\)")
+
+// addColumnPattern 迁移脚本中的加列语句
+var addColumnPattern = regexp.MustCompile("ALTER TABLE `([a-z_]+)` ADD COLUMN (`[a-z_]+` )")
+
+// TestMigrationsCoverModels 迁移脚本建表后的结构需包含模型的全部表和列
+func TestMigrationsCoverModels(t *testing.T) {
+	cache := &sync.Map{}
+	for _, dialect := range dialects {
+		migrations, err := Migrations(dialect)
+		if err != nil {
+			t.Fatalf("读取 %s 迁移失败: %v", dialect, err)
+		}
+		tables := make(map[string]string)
+		for _, m := range migrations {
+			for _, match := range createTablePattern.FindAllStringSubmatch(m.Up, -1) {
+				tables[match[1]] = match[2]
+			}
+			for _, match := range addColumnPattern.FindAllStringSubmatch(m.Up, -1) {
+				tables[match[1]] += "\n" + match[2]
+			}
+		}
+
+		for _, m := range Models() {
+			s, err := schema.Parse(m, cache, schema.NamingStrategy{})
+			if err != nil {
+				t.Fatalf("解析模型 %T 失败: %v", m, err)
+			}
+			body, ok := tables[s.Table]
+			if !ok {
+				t.Errorf("%s 迁移中没有创建表 %s", dialect, s.Table)
+				continue
+			}
+			for _, field := range s.Fields {
+				if field.DBName == "" {
+					continue
+				}
+				if !strings.Contains(body, "`"+field.DBName+"` ") {
+					t.Errorf("%s 迁移中表 %s 缺少列 %s", dialect, s.Table, field.DBName)
+				}
+			}
+		}
+	}
+}
+
+// TestSQLiteUpDown 在 SQLite 内存数据库上执行全部迁移，检查表结构后全部回滚
+func TestSQLiteUpDown(t *testing.T) {
+	db, err := database.Open(database.DriverSQLite, ":memory:")
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	migrations, _ := Migrations(database.DriverSQLite)
+
+	done, err := Up(db, 0)
+	if err != nil {
+		t.Fatalf("执行迁移失败: %v", err)
+	}
+	if len(done) != len(migrations) {
+		t.Errorf("执行了 %d 个迁移, want %d", len(done), len(migrations))
+	}
+	if err := CheckPending(db); err != nil {
+		t.Errorf("执行后不应有未执行的迁移: %v", err)
+	}
+	problems, err := CheckSchema(db)
+	if err != nil {
+		t.Fatalf("检查表结构失败: %v", err)
+	}
+	for _, problem := range problems {
+		t.Errorf("表结构与模型不一致: %s", problem)
+	}
+
+	done, err = Down(db, len(migrations))
+	if err != nil {
+		t.Fatalf("回滚迁移失败: %v", err)
+	}
+	if len(done) != len(migrations) {
+		t.Errorf("回滚了 %d 个迁移, want %d", len(done), len(migrations))
+	}
+	if !errors.Is(CheckPending(db), ErrPendingMigrations) {
+		t.Error("全部回滚后应存在未执行的迁移")
+	}
+	for _, m := range Models() {
+		if db.Migrator().HasTable(m) {
+			t.Errorf("回滚后表 %T 仍存在", m)
+		}
+	}
+}
+
+// TestLegacySchemaUpgrade 引入迁移前建立的数据库先升级为 0001 的表结构再执行后续迁移，升级得到的版本不能回滚
+func TestLegacySchemaUpgrade(t *testing.T) {
+	db, err := database.Open(database.DriverSQLite, ":memory:")
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	migrations, _ := Migrations(database.DriverSQLite)
+
+	// 旧库：有核心表但缺少新增的列，没有迁移记录
+	if err := execScript(db, migrations[0].Up); err != nil {
+		t.Fatalf("创建旧表失败: %v", err)
+	}
+	if err := db.Exec("ALTER TABLE `users` DROP COLUMN `role`").Error; err != nil {
+		t.Fatalf("删除列失败: %v", err)
+	}
+
+	// 没有升级步骤的数据库类型拒绝执行，不会跳过已有的表并记录为已执行
+	if _, err := Up(db, 0); err == nil {
+		t.Fatal("不支持升级旧表结构时应返回错误")
+	}
+	if pending, _ := Pending(db); len(pending) != len(migrations) {
+		t.Fatalf("升级失败后不应记录迁移, 未执行 %d 个", len(pending))
+	}
+
+	legacyUpgrades[database.DriverSQLite] = []legacyStep{
+		{when: missingColumn("users", "role"), statements: []string{"ALTER TABLE `users` ADD COLUMN `role` varchar(16) NOT NULL DEFAULT 'user'"}},
+	}
+	defer delete(legacyUpgrades, database.DriverSQLite)
+
+	done, err := Up(db, 0)
+	if err != nil {
+		t.Fatalf("执行迁移失败: %v", err)
+	}
+	if len(done) != len(migrations) || done[0].Name != legacyBaselineName {
+		t.Fatalf("应先升级旧表再执行后续迁移, done = %+v", done)
+	}
+	problems, err := CheckSchema(db)
+	if err != nil {
+		t.Fatalf("检查表结构失败: %v", err)
+	}
+	for _, problem := range problems {
+		t.Errorf("表结构与模型不一致: %s", problem)
+	}
+	statuses, _ := Status(db)
+	if !statuses[0].Applied || statuses[0].Name != legacyBaselineName {
+		t.Errorf("状态应显示由旧表结构升级, got %+v", statuses[0])
+	}
+
+	// 后续迁移可以回滚，升级得到的版本不能回滚
+	done, err = Down(db, len(migrations))
+	if err == nil {
+		t.Error("回滚升级得到的版本应返回错误")
+	}
+	if len(done) != len(migrations)-1 {
+		t.Errorf("回滚了 %d 个迁移, want %d", len(done), len(migrations)-1)
+	}
+	if !db.Migrator().HasTable("users") {
+		t.Error("不应删除迁移前已有的表")
+	}
+}
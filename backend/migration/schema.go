@@ -0,0 +1,106 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"lucky/model"
+
+	"gorm.io/gorm"
+)
+
+// ErrSchemaDrift 数据库表结构与模型不一致
+var ErrSchemaDrift = errors.New("数据库表结构与模型不一致")
+
+// Models 需要持久化的全部模型，新增模型时同时新增迁移脚本
+func Models() []interface{} {
+	return []interface{}{
+		&model.User{},
+		&model.RefreshToken{},
+		&model.LoginLog{},
+		&model.LotteryGame{},
+		&model.UserNumber{},
+		&model.DrawResult{},
+		&model.NumberGroup{},
+		&model.NumberTag{},
+		&model.UserNumberTag{},
+		&model.NumberPlan{},
+		&model.NumberPlanPeriod{},
+		&model.UserDraw{},
+		&model.Purchase{},
+		&model.AdminAuditLog{},
+		&model.CrawlRun{},
+		&model.DrawResultRevision{},
+		&model.Notification{},
+		&model.JobRun{},
+	}
+}
+
+// CheckSchema 比较模型与数据库表结构，返回发现的问题：
+// 表或列不存在，以及模型中没有的列不允许为空且没有默认值（插入时会失败）
+func CheckSchema(db *gorm.DB) ([]string, error) {
+	var problems []string
+	migrator := db.Migrator()
+	for _, m := range Models() {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(m); err != nil {
+			return nil, fmt.Errorf("解析模型 %T 失败: %v", m, err)
+		}
+		table := stmt.Schema.Table
+		if !migrator.HasTable(table) {
+			problems = append(problems, fmt.Sprintf("表 %s 不存在", table))
+			continue
+		}
+
+		columnTypes, err := migrator.ColumnTypes(table)
+		if err != nil {
+			return nil, fmt.Errorf("读取表 %s 结构失败: %v", table, err)
+		}
+		existing := make(map[string]gorm.ColumnType, len(columnTypes))
+		for _, columnType := range columnTypes {
+			existing[strings.ToLower(columnType.Name())] = columnType
+		}
+
+		modelColumns := make(map[string]bool, len(stmt.Schema.DBNames))
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" || field.IgnoreMigration {
+				continue
+			}
+			modelColumns[strings.ToLower(field.DBName)] = true
+			if _, ok := existing[strings.ToLower(field.DBName)]; !ok {
+				problems = append(problems, fmt.Sprintf("列 %s.%s 不存在", table, field.DBName))
+			}
+		}
+
+		for name, columnType := range existing {
+			if modelColumns[name] {
+				continue
+			}
+			nullable, _ := columnType.Nullable()
+			_, hasDefault := columnType.DefaultValue()
+			if !nullable && !hasDefault && !isAutoIncrement(columnType) {
+				problems = append(problems, fmt.Sprintf("列 %s.%s 不在模型中且不允许为空", table, columnType.Name()))
+			}
+		}
+	}
+	return problems, nil
+}
+
+// CheckSchemaDrift 表结构与模型不一致时返回 ErrSchemaDrift
+func CheckSchemaDrift(db *gorm.DB) error {
+	problems, err := CheckSchema(db)
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrSchemaDrift, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// isAutoIncrement 是否为自增列
+func isAutoIncrement(columnType gorm.ColumnType) bool {
+	autoIncrement, ok := columnType.AutoIncrement()
+	return ok && autoIncrement
+}
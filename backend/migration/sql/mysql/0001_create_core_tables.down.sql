@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS `draw_results`;
+DROP TABLE IF EXISTS `user_numbers`;
+DROP TABLE IF EXISTS `lottery_games`;
+DROP TABLE IF EXISTS `login_logs`;
+DROP TABLE IF EXISTS `refresh_tokens`;
+DROP TABLE IF EXISTS `users`;
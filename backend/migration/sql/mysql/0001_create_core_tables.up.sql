@@ -0,0 +1,108 @@
+-- 用户、登录、游戏、号码和开奖结果表
+
+CREATE TABLE `users` (
+  `id` bigint NOT NULL AUTO_INCREMENT COMMENT '用户ID',
+  `open_id` varchar(64) NOT NULL COMMENT '微信OpenID',
+  `nickname` varchar(64) NOT NULL COMMENT '用户昵称',
+  `avatar_url` varchar(255) DEFAULT NULL COMMENT '头像URL',
+  `status` bigint NOT NULL DEFAULT 1 COMMENT '用户状态(1:正常 0:禁用)',
+  `role` varchar(16) NOT NULL DEFAULT 'user' COMMENT '角色(user:普通用户 admin:管理员)',
+  `token_version` bigint NOT NULL DEFAULT 1 COMMENT 'token版本号',
+  `last_login_at` datetime(3) NULL COMMENT '最后登录时间',
+  `last_login_ip` varchar(45) DEFAULT NULL COMMENT '最后登录IP',
+  `login_count` bigint NOT NULL DEFAULT 0 COMMENT '登录次数',
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_users_open_id` (`open_id`),
+  INDEX `idx_users_status_version` (`status`, `token_version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户表';
+
+CREATE TABLE `refresh_tokens` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` bigint NOT NULL COMMENT '用户ID',
+  `token` varchar(255) NOT NULL COMMENT '刷新令牌',
+  `expires_at` datetime(3) NOT NULL COMMENT '过期时间',
+  `is_active` boolean DEFAULT true COMMENT '是否有效',
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_refresh_tokens_token` (`token`),
+  INDEX `idx_refresh_tokens_user_id` (`user_id`),
+  INDEX `idx_refresh_tokens_user_expires` (`user_id`, `expires_at`, `is_active`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='刷新令牌表';
+
+CREATE TABLE `login_logs` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` bigint NOT NULL COMMENT '用户ID',
+  `login_ip` varchar(45) NOT NULL COMMENT '登录IP',
+  `user_agent` varchar(255) DEFAULT NULL COMMENT '用户代理',
+  `status` bigint NOT NULL DEFAULT 1 COMMENT '登录状态(1:成功 0:失败)',
+  `message` varchar(255) DEFAULT NULL COMMENT '登录消息',
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_login_logs_user_id` (`user_id`),
+  INDEX `idx_login_logs_user_time` (`user_id`, `created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='登录日志表';
+
+CREATE TABLE `lottery_games` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `game_code` varchar(32) NOT NULL COMMENT '游戏代码',
+  `game_name` varchar(64) NOT NULL COMMENT '游戏名称',
+  `game_type` varchar(16) DEFAULT 'ball' COMMENT '游戏类型：ball(选号型), digit(数字型), keno(快乐8型)',
+  `red_ball_count` bigint NOT NULL COMMENT '红球总数',
+  `blue_ball_count` bigint NOT NULL COMMENT '蓝球总数',
+  `red_select_count` bigint NOT NULL COMMENT '红球选择数',
+  `blue_select_count` bigint NOT NULL COMMENT '蓝球选择数',
+  `special_ball_count` bigint DEFAULT 0 COMMENT '特别号个数',
+  `is_active` boolean DEFAULT true COMMENT '是否启用',
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_lottery_games_game_code` (`game_code`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='彩票游戏表';
+
+CREATE TABLE `user_numbers` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` bigint NOT NULL COMMENT '用户ID',
+  `game_id` bigint unsigned NOT NULL COMMENT '游戏ID',
+  `red_balls` json NOT NULL COMMENT '红球号码JSON数组',
+  `blue_balls` json NOT NULL COMMENT '蓝球号码JSON数组',
+  `play_type` varchar(16) DEFAULT NULL COMMENT '玩法，选号型游戏为空',
+  `group_id` bigint DEFAULT NULL COMMENT '所属分组ID',
+  `multiplier` bigint DEFAULT 1 COMMENT '倍数',
+  `is_additional` boolean DEFAULT false COMMENT '是否追加（大乐透）',
+  `nickname` varchar(128) DEFAULT NULL COMMENT '号码昵称',
+  `note` varchar(512) DEFAULT NULL COMMENT '备注',
+  `source` varchar(32) DEFAULT 'manual' COMMENT '来源',
+  `is_active` boolean DEFAULT true COMMENT '是否启用',
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_user_numbers_user_id` (`user_id`),
+  INDEX `idx_user_numbers_game_id` (`game_id`),
+  INDEX `idx_user_numbers_group_id` (`group_id`),
+  INDEX `idx_user_numbers_composite` (`user_id`, `game_id`, `is_active`, `created_at`),
+  CONSTRAINT `fk_user_numbers_game` FOREIGN KEY (`game_id`) REFERENCES `lottery_games` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户号码表';
+
+CREATE TABLE `draw_results` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `game_id` bigint unsigned NOT NULL COMMENT '游戏ID',
+  `period` varchar(32) NOT NULL COMMENT '期号',
+  `red_balls` json NOT NULL COMMENT '红球号码JSON数组',
+  `blue_balls` json NOT NULL COMMENT '蓝球号码JSON数组',
+  `draw_date` datetime(3) NOT NULL COMMENT '开奖时间',
+  `sales_amount` bigint DEFAULT 0 COMMENT '销售额(分)',
+  `prize_pool` bigint DEFAULT 0 COMMENT '奖池金额(分)',
+  `first_prize` bigint DEFAULT 0 COMMENT '一等奖注数',
+  `first_amount` bigint DEFAULT 0 COMMENT '一等奖单注奖金(分)',
+  `second_prize` bigint DEFAULT 0 COMMENT '二等奖注数',
+  `second_amount` bigint DEFAULT 0 COMMENT '二等奖单注奖金(分)',
+  `version` bigint NOT NULL DEFAULT 1 COMMENT '数据版本，每次更正加1',
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_draw_results_game_period` (`game_id`, `period`),
+  INDEX `idx_draw_results_game_id` (`game_id`),
+  INDEX `idx_draw_results_composite` (`game_id`, `draw_date`, `period`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='开奖结果表';
@@ -0,0 +1,117 @@
+-- 号码分组、标签、追号计划、中奖记录和购彩记录表
+
+CREATE TABLE `number_groups` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` bigint NOT NULL COMMENT '用户ID',
+  `game_id` bigint unsigned NOT NULL COMMENT '游戏ID',
+  `name` varchar(64) NOT NULL COMMENT '分组名称',
+  `source` varchar(32) DEFAULT NULL COMMENT '来源：manual(手动), wheel(旋转矩阵)',
+  `note` varchar(512) DEFAULT NULL COMMENT '备注',
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_number_groups_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='号码分组表';
+
+CREATE TABLE `number_tags` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` bigint NOT NULL COMMENT '用户ID',
+  `name` varchar(32) NOT NULL COMMENT '标签名称',
+  `color` varchar(16) DEFAULT NULL COMMENT '标签颜色',
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_number_tags_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='号码标签表';
+
+CREATE TABLE `user_number_tags` (
+  `user_number_id` bigint NOT NULL COMMENT '用户号码ID',
+  `tag_id` bigint NOT NULL COMMENT '标签ID',
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`user_number_id`, `tag_id`),
+  INDEX `idx_user_number_tags_tag_id` (`tag_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='号码标签关联表';
+
+CREATE TABLE `number_plans` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` bigint NOT NULL COMMENT '用户ID',
+  `user_number_id` bigint NOT NULL COMMENT '追号的用户号码ID',
+  `game_id` bigint unsigned NOT NULL COMMENT '游戏ID',
+  `start_period` varchar(32) NOT NULL COMMENT '起始期号',
+  `total_periods` bigint NOT NULL COMMENT '追号期数',
+  `stop_after_win` boolean DEFAULT false COMMENT '中奖后停止追号',
+  `multiplier` bigint DEFAULT 1 COMMENT '每期倍数',
+  `is_additional` boolean DEFAULT false COMMENT '是否追加（大乐透）',
+  `status` varchar(16) DEFAULT 'active' COMMENT '状态：active, finished, stopped, cancelled',
+  `drawn_periods` bigint DEFAULT 0 COMMENT '已开奖期数',
+  `winning_periods` bigint DEFAULT 0 COMMENT '中奖期数',
+  `total_prize` bigint DEFAULT 0 COMMENT '累计奖金(分)',
+  `last_period` varchar(32) DEFAULT NULL COMMENT '最近核对的期号',
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_number_plans_user_id` (`user_id`),
+  INDEX `idx_number_plans_user_number_id` (`user_number_id`),
+  INDEX `idx_number_plans_game_id` (`game_id`),
+  CONSTRAINT `fk_number_plans_user_number` FOREIGN KEY (`user_number_id`) REFERENCES `user_numbers` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='追号计划表';
+
+CREATE TABLE `number_plan_periods` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `plan_id` bigint NOT NULL COMMENT '追号计划ID',
+  `period` varchar(32) NOT NULL COMMENT '期号',
+  `draw_result_id` bigint unsigned NOT NULL COMMENT '开奖结果ID',
+  `red_matches` bigint DEFAULT 0 COMMENT '红球匹配数',
+  `blue_matches` bigint DEFAULT 0 COMMENT '蓝球匹配数',
+  `prize_level` bigint DEFAULT 0 COMMENT '奖级，0表示未中奖',
+  `prize_amount` bigint DEFAULT 0 COMMENT '奖金(分)',
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_plan_period` (`plan_id`, `period`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='追号计划每期核对结果表';
+
+CREATE TABLE `user_draws` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `user_number_id` bigint unsigned NOT NULL COMMENT '用户号码ID',
+  `draw_result_id` bigint unsigned NOT NULL COMMENT '开奖结果ID',
+  `prize_level` bigint NOT NULL DEFAULT 0 COMMENT '奖级',
+  `prize_amount` bigint NOT NULL DEFAULT 0 COMMENT '单注奖金(分)',
+  `is_winning` boolean NOT NULL DEFAULT false COMMENT '是否中奖',
+  `is_active` boolean NOT NULL DEFAULT true COMMENT '是否有效',
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_user_draws_user_number_id` (`user_number_id`),
+  INDEX `idx_user_draws_draw_result_id` (`draw_result_id`),
+  INDEX `idx_user_draws_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户号码中奖记录表';
+
+CREATE TABLE `purchases` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` bigint NOT NULL COMMENT '用户ID',
+  `game_id` bigint unsigned NOT NULL COMMENT '游戏ID',
+  `period` varchar(32) NOT NULL COMMENT '期号',
+  `draw_date` datetime(3) NULL COMMENT '开奖日期',
+  `user_number_id` bigint DEFAULT NULL COMMENT '购买的用户号码ID',
+  `group_id` bigint DEFAULT NULL COMMENT '购买的号码分组ID',
+  `plan_id` bigint DEFAULT NULL COMMENT '来源追号计划ID',
+  `bet_count` bigint NOT NULL DEFAULT 1 COMMENT '注数',
+  `multiplier` bigint NOT NULL DEFAULT 1 COMMENT '倍数',
+  `is_additional` boolean DEFAULT false COMMENT '是否追加（大乐透）',
+  `cost` bigint NOT NULL DEFAULT 0 COMMENT '投注金额(分)',
+  `source` varchar(16) DEFAULT 'manual' COMMENT '来源：manual(手动), plan(追号)',
+  `status` varchar(16) DEFAULT 'pending' COMMENT '状态：pending(待开奖), settled(已开奖)',
+  `prize_level` bigint DEFAULT 0 COMMENT '最高奖级，0表示未中奖',
+  `prize_amount` bigint DEFAULT 0 COMMENT '中奖金额(分)',
+  `note` varchar(512) DEFAULT NULL COMMENT '备注',
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_purchases_user_id` (`user_id`),
+  INDEX `idx_purchases_game_id` (`game_id`),
+  INDEX `idx_purchases_period` (`period`),
+  INDEX `idx_purchases_user_number_id` (`user_number_id`),
+  INDEX `idx_purchases_group_id` (`group_id`),
+  INDEX `idx_purchases_plan_id` (`plan_id`),
+  CONSTRAINT `fk_purchases_game` FOREIGN KEY (`game_id`) REFERENCES `lottery_games` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='购彩记录表';
@@ -0,0 +1,91 @@
+-- 管理后台审计日志、抓取记录、开奖更正历史、站内通知和任务执行记录表
+
+CREATE TABLE `admin_audit_logs` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `admin_id` bigint NOT NULL COMMENT '操作管理员ID',
+  `action` varchar(32) NOT NULL COMMENT '操作类型',
+  `target_type` varchar(32) NOT NULL COMMENT '操作对象类型',
+  `target_id` varchar(64) DEFAULT NULL COMMENT '操作对象标识',
+  `before_data` text COMMENT '修改前数据JSON',
+  `after_data` text COMMENT '修改后数据JSON',
+  `reason` varchar(255) DEFAULT NULL COMMENT '操作原因',
+  `ip` varchar(45) DEFAULT NULL COMMENT '操作IP',
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_admin_audit_logs_admin_id` (`admin_id`),
+  INDEX `idx_admin_audit_logs_action` (`action`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='管理操作审计日志表';
+
+CREATE TABLE `crawl_runs` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `game_code` varchar(32) NOT NULL COMMENT '游戏代码',
+  `mode` varchar(16) NOT NULL COMMENT '抓取方式：latest(最新), backfill(回补)',
+  `pages` bigint DEFAULT 0 COMMENT '回补页数',
+  `trigger_source` varchar(16) NOT NULL COMMENT '触发来源',
+  `operator_id` bigint DEFAULT 0 COMMENT '触发的管理员ID',
+  `status` varchar(16) NOT NULL COMMENT '状态：running, success, failed, no_data',
+  `error` varchar(512) DEFAULT NULL COMMENT '失败原因',
+  `started_at` datetime(3) NOT NULL COMMENT '开始时间',
+  `finished_at` datetime(3) NULL COMMENT '结束时间',
+  PRIMARY KEY (`id`),
+  INDEX `idx_crawl_runs_game_code` (`game_code`),
+  INDEX `idx_crawl_runs_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='开奖数据抓取记录表';
+
+CREATE TABLE `draw_result_revisions` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `draw_result_id` bigint unsigned NOT NULL COMMENT '开奖结果ID',
+  `version` bigint NOT NULL COMMENT '被替换的版本号',
+  `game_id` bigint unsigned NOT NULL COMMENT '游戏ID',
+  `period` varchar(32) NOT NULL COMMENT '期号',
+  `red_balls` json NOT NULL COMMENT '该版本的红球号码',
+  `blue_balls` json NOT NULL COMMENT '该版本的蓝球号码',
+  `draw_date` datetime(3) NOT NULL COMMENT '该版本的开奖时间',
+  `sales_amount` bigint DEFAULT 0 COMMENT '销售额(分)',
+  `prize_pool` bigint DEFAULT 0 COMMENT '奖池金额(分)',
+  `first_prize` bigint DEFAULT 0 COMMENT '一等奖注数',
+  `first_amount` bigint DEFAULT 0 COMMENT '一等奖单注奖金(分)',
+  `second_prize` bigint DEFAULT 0 COMMENT '二等奖注数',
+  `second_amount` bigint DEFAULT 0 COMMENT '二等奖单注奖金(分)',
+  `change_source` varchar(16) NOT NULL COMMENT '更正来源：admin, crawler',
+  `operator_id` bigint DEFAULT 0 COMMENT '更正的管理员ID',
+  `reason` varchar(255) DEFAULT NULL COMMENT '更正原因',
+  `created_at` datetime(3) NULL COMMENT '更正时间',
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_draw_version` (`draw_result_id`, `version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='开奖结果更正历史表';
+
+CREATE TABLE `notifications` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` bigint NOT NULL COMMENT '用户ID',
+  `type` varchar(32) NOT NULL COMMENT '通知类型',
+  `title` varchar(128) NOT NULL COMMENT '标题',
+  `content` varchar(1024) DEFAULT NULL COMMENT '内容',
+  `draw_result_id` bigint unsigned DEFAULT NULL COMMENT '关联的开奖结果ID',
+  `user_number_id` bigint DEFAULT NULL COMMENT '关联的用户号码ID',
+  `is_read` boolean DEFAULT false COMMENT '是否已读',
+  `read_at` datetime(3) NULL COMMENT '阅读时间',
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_notifications_user_id` (`user_id`),
+  INDEX `idx_notifications_draw_result_id` (`draw_result_id`),
+  INDEX `idx_notifications_is_read` (`is_read`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户站内通知表';
+
+CREATE TABLE `job_runs` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `job_name` varchar(32) NOT NULL COMMENT '任务名称',
+  `lock_key` varchar(64) NOT NULL COMMENT '并发控制键',
+  `params` text COMMENT '任务参数JSON',
+  `trigger_source` varchar(16) NOT NULL COMMENT '触发来源',
+  `operator_id` bigint DEFAULT 0 COMMENT '触发的管理员ID',
+  `status` varchar(16) NOT NULL COMMENT '状态：running, success, failed',
+  `output` text COMMENT '任务输出',
+  `error` varchar(1024) DEFAULT NULL COMMENT '失败原因',
+  `started_at` datetime(3) NOT NULL COMMENT '开始时间',
+  `finished_at` datetime(3) NULL COMMENT '结束时间',
+  `duration_ms` bigint DEFAULT 0 COMMENT '执行耗时(毫秒)',
+  PRIMARY KEY (`id`),
+  INDEX `idx_job_lock` (`job_name`, `lock_key`),
+  INDEX `idx_job_runs_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='任务执行记录表';
@@ -0,0 +1,4 @@
+-- 任务执行中占用的并发控制键，唯一索引保证多个进程中同一键只有一条执行中的记录，执行结束后置空
+
+ALTER TABLE `job_runs` ADD COLUMN `running_key` varchar(100) DEFAULT NULL COMMENT '执行中占用的并发控制键，结束后为空' AFTER `lock_key`;
+CREATE UNIQUE INDEX `idx_job_runs_running_key` ON `job_runs` (`running_key`);
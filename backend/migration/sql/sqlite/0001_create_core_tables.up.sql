@@ -0,0 +1,102 @@
+-- 用户、登录、游戏、号码和开奖结果表
+
+CREATE TABLE `users` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `open_id` varchar(64) NOT NULL,
+  `nickname` varchar(64) NOT NULL,
+  `avatar_url` varchar(255),
+  `status` integer NOT NULL DEFAULT 1,
+  `role` varchar(16) NOT NULL DEFAULT 'user',
+  `token_version` integer NOT NULL DEFAULT 1,
+  `last_login_at` datetime,
+  `last_login_ip` varchar(45),
+  `login_count` integer NOT NULL DEFAULT 0,
+  `created_at` datetime,
+  `updated_at` datetime
+);
+CREATE UNIQUE INDEX `idx_users_open_id` ON `users` (`open_id`);
+CREATE INDEX `idx_users_status_version` ON `users` (`status`, `token_version`);
+
+CREATE TABLE `refresh_tokens` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `token` varchar(255) NOT NULL,
+  `expires_at` datetime NOT NULL,
+  `is_active` boolean DEFAULT true,
+  `created_at` datetime
+);
+CREATE UNIQUE INDEX `idx_refresh_tokens_token` ON `refresh_tokens` (`token`);
+CREATE INDEX `idx_refresh_tokens_user_id` ON `refresh_tokens` (`user_id`);
+CREATE INDEX `idx_refresh_tokens_user_expires` ON `refresh_tokens` (`user_id`, `expires_at`, `is_active`);
+
+CREATE TABLE `login_logs` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `login_ip` varchar(45) NOT NULL,
+  `user_agent` varchar(255),
+  `status` integer NOT NULL DEFAULT 1,
+  `message` varchar(255),
+  `created_at` datetime
+);
+CREATE INDEX `idx_login_logs_user_id` ON `login_logs` (`user_id`);
+CREATE INDEX `idx_login_logs_user_time` ON `login_logs` (`user_id`, `created_at`);
+
+CREATE TABLE `lottery_games` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `game_code` varchar(32) NOT NULL,
+  `game_name` varchar(64) NOT NULL,
+  `game_type` varchar(16) DEFAULT 'ball',
+  `red_ball_count` integer NOT NULL,
+  `blue_ball_count` integer NOT NULL,
+  `red_select_count` integer NOT NULL,
+  `blue_select_count` integer NOT NULL,
+  `special_ball_count` integer DEFAULT 0,
+  `is_active` boolean DEFAULT true,
+  `created_at` datetime,
+  `updated_at` datetime
+);
+CREATE UNIQUE INDEX `idx_lottery_games_game_code` ON `lottery_games` (`game_code`);
+
+CREATE TABLE `user_numbers` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `game_id` integer NOT NULL,
+  `red_balls` text NOT NULL,
+  `blue_balls` text NOT NULL,
+  `play_type` varchar(16),
+  `group_id` integer,
+  `multiplier` integer DEFAULT 1,
+  `is_additional` boolean DEFAULT false,
+  `nickname` varchar(128),
+  `note` varchar(512),
+  `source` varchar(32) DEFAULT 'manual',
+  `is_active` boolean DEFAULT true,
+  `created_at` datetime,
+  `updated_at` datetime,
+  CONSTRAINT `fk_user_numbers_game` FOREIGN KEY (`game_id`) REFERENCES `lottery_games` (`id`)
+);
+CREATE INDEX `idx_user_numbers_user_id` ON `user_numbers` (`user_id`);
+CREATE INDEX `idx_user_numbers_game_id` ON `user_numbers` (`game_id`);
+CREATE INDEX `idx_user_numbers_group_id` ON `user_numbers` (`group_id`);
+CREATE INDEX `idx_user_numbers_composite` ON `user_numbers` (`user_id`, `game_id`, `is_active`, `created_at`);
+
+CREATE TABLE `draw_results` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `game_id` integer NOT NULL,
+  `period` varchar(32) NOT NULL,
+  `red_balls` text NOT NULL,
+  `blue_balls` text NOT NULL,
+  `draw_date` datetime NOT NULL,
+  `sales_amount` integer DEFAULT 0,
+  `prize_pool` integer DEFAULT 0,
+  `first_prize` integer DEFAULT 0,
+  `first_amount` integer DEFAULT 0,
+  `second_prize` integer DEFAULT 0,
+  `second_amount` integer DEFAULT 0,
+  `version` integer NOT NULL DEFAULT 1,
+  `created_at` datetime,
+  `updated_at` datetime
+);
+CREATE UNIQUE INDEX `idx_draw_results_game_period` ON `draw_results` (`game_id`, `period`);
+CREATE INDEX `idx_draw_results_game_id` ON `draw_results` (`game_id`);
+CREATE INDEX `idx_draw_results_composite` ON `draw_results` (`game_id`, `draw_date`, `period`);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS `purchases`;
+DROP TABLE IF EXISTS `user_draws`;
+DROP TABLE IF EXISTS `number_plan_periods`;
+DROP TABLE IF EXISTS `number_plans`;
+DROP TABLE IF EXISTS `user_number_tags`;
+DROP TABLE IF EXISTS `number_tags`;
+DROP TABLE IF EXISTS `number_groups`;
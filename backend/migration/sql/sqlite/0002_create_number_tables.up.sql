@@ -0,0 +1,111 @@
+-- 号码分组、标签、追号计划、中奖记录和购彩记录表
+
+CREATE TABLE `number_groups` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `game_id` integer NOT NULL,
+  `name` varchar(64) NOT NULL,
+  `source` varchar(32),
+  `note` varchar(512),
+  `created_at` datetime,
+  `updated_at` datetime
+);
+CREATE INDEX `idx_number_groups_user_id` ON `number_groups` (`user_id`);
+
+CREATE TABLE `number_tags` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `name` varchar(32) NOT NULL,
+  `color` varchar(16),
+  `created_at` datetime
+);
+CREATE INDEX `idx_number_tags_user_id` ON `number_tags` (`user_id`);
+
+CREATE TABLE `user_number_tags` (
+  `user_number_id` integer NOT NULL,
+  `tag_id` integer NOT NULL,
+  `created_at` datetime,
+  PRIMARY KEY (`user_number_id`, `tag_id`)
+);
+CREATE INDEX `idx_user_number_tags_tag_id` ON `user_number_tags` (`tag_id`);
+
+CREATE TABLE `number_plans` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `user_number_id` integer NOT NULL,
+  `game_id` integer NOT NULL,
+  `start_period` varchar(32) NOT NULL,
+  `total_periods` integer NOT NULL,
+  `stop_after_win` boolean DEFAULT false,
+  `multiplier` integer DEFAULT 1,
+  `is_additional` boolean DEFAULT false,
+  `status` varchar(16) DEFAULT 'active',
+  `drawn_periods` integer DEFAULT 0,
+  `winning_periods` integer DEFAULT 0,
+  `total_prize` integer DEFAULT 0,
+  `last_period` varchar(32),
+  `created_at` datetime,
+  `updated_at` datetime,
+  CONSTRAINT `fk_number_plans_user_number` FOREIGN KEY (`user_number_id`) REFERENCES `user_numbers` (`id`)
+);
+CREATE INDEX `idx_number_plans_user_id` ON `number_plans` (`user_id`);
+CREATE INDEX `idx_number_plans_user_number_id` ON `number_plans` (`user_number_id`);
+CREATE INDEX `idx_number_plans_game_id` ON `number_plans` (`game_id`);
+
+CREATE TABLE `number_plan_periods` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `plan_id` integer NOT NULL,
+  `period` varchar(32) NOT NULL,
+  `draw_result_id` integer NOT NULL,
+  `red_matches` integer DEFAULT 0,
+  `blue_matches` integer DEFAULT 0,
+  `prize_level` integer DEFAULT 0,
+  `prize_amount` integer DEFAULT 0,
+  `created_at` datetime
+);
+CREATE UNIQUE INDEX `idx_plan_period` ON `number_plan_periods` (`plan_id`, `period`);
+
+CREATE TABLE `user_draws` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_number_id` integer NOT NULL,
+  `draw_result_id` integer NOT NULL,
+  `prize_level` integer NOT NULL DEFAULT 0,
+  `prize_amount` integer NOT NULL DEFAULT 0,
+  `is_winning` boolean NOT NULL DEFAULT false,
+  `is_active` boolean NOT NULL DEFAULT true,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime
+);
+CREATE INDEX `idx_user_draws_user_number_id` ON `user_draws` (`user_number_id`);
+CREATE INDEX `idx_user_draws_draw_result_id` ON `user_draws` (`draw_result_id`);
+CREATE INDEX `idx_user_draws_deleted_at` ON `user_draws` (`deleted_at`);
+
+CREATE TABLE `purchases` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `game_id` integer NOT NULL,
+  `period` varchar(32) NOT NULL,
+  `draw_date` datetime,
+  `user_number_id` integer,
+  `group_id` integer,
+  `plan_id` integer,
+  `bet_count` integer NOT NULL DEFAULT 1,
+  `multiplier` integer NOT NULL DEFAULT 1,
+  `is_additional` boolean DEFAULT false,
+  `cost` integer NOT NULL DEFAULT 0,
+  `source` varchar(16) DEFAULT 'manual',
+  `status` varchar(16) DEFAULT 'pending',
+  `prize_level` integer DEFAULT 0,
+  `prize_amount` integer DEFAULT 0,
+  `note` varchar(512),
+  `created_at` datetime,
+  `updated_at` datetime,
+  CONSTRAINT `fk_purchases_game` FOREIGN KEY (`game_id`) REFERENCES `lottery_games` (`id`)
+);
+CREATE INDEX `idx_purchases_user_id` ON `purchases` (`user_id`);
+CREATE INDEX `idx_purchases_game_id` ON `purchases` (`game_id`);
+CREATE INDEX `idx_purchases_period` ON `purchases` (`period`);
+CREATE INDEX `idx_purchases_user_number_id` ON `purchases` (`user_number_id`);
+CREATE INDEX `idx_purchases_group_id` ON `purchases` (`group_id`);
+CREATE INDEX `idx_purchases_plan_id` ON `purchases` (`plan_id`);
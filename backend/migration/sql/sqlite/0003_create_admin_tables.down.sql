@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS `job_runs`;
+DROP TABLE IF EXISTS `notifications`;
+DROP TABLE IF EXISTS `draw_result_revisions`;
+DROP TABLE IF EXISTS `crawl_runs`;
+DROP TABLE IF EXISTS `admin_audit_logs`;
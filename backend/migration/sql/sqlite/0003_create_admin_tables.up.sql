@@ -0,0 +1,86 @@
+-- 管理后台审计日志、抓取记录、开奖更正历史、站内通知和任务执行记录表
+
+CREATE TABLE `admin_audit_logs` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `admin_id` integer NOT NULL,
+  `action` varchar(32) NOT NULL,
+  `target_type` varchar(32) NOT NULL,
+  `target_id` varchar(64),
+  `before_data` text,
+  `after_data` text,
+  `reason` varchar(255),
+  `ip` varchar(45),
+  `created_at` datetime
+);
+CREATE INDEX `idx_admin_audit_logs_admin_id` ON `admin_audit_logs` (`admin_id`);
+CREATE INDEX `idx_admin_audit_logs_action` ON `admin_audit_logs` (`action`);
+
+CREATE TABLE `crawl_runs` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `game_code` varchar(32) NOT NULL,
+  `mode` varchar(16) NOT NULL,
+  `pages` integer DEFAULT 0,
+  `trigger_source` varchar(16) NOT NULL,
+  `operator_id` integer DEFAULT 0,
+  `status` varchar(16) NOT NULL,
+  `error` varchar(512),
+  `started_at` datetime NOT NULL,
+  `finished_at` datetime
+);
+CREATE INDEX `idx_crawl_runs_game_code` ON `crawl_runs` (`game_code`);
+CREATE INDEX `idx_crawl_runs_status` ON `crawl_runs` (`status`);
+
+CREATE TABLE `draw_result_revisions` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `draw_result_id` integer NOT NULL,
+  `version` integer NOT NULL,
+  `game_id` integer NOT NULL,
+  `period` varchar(32) NOT NULL,
+  `red_balls` text NOT NULL,
+  `blue_balls` text NOT NULL,
+  `draw_date` datetime NOT NULL,
+  `sales_amount` integer DEFAULT 0,
+  `prize_pool` integer DEFAULT 0,
+  `first_prize` integer DEFAULT 0,
+  `first_amount` integer DEFAULT 0,
+  `second_prize` integer DEFAULT 0,
+  `second_amount` integer DEFAULT 0,
+  `change_source` varchar(16) NOT NULL,
+  `operator_id` integer DEFAULT 0,
+  `reason` varchar(255),
+  `created_at` datetime
+);
+CREATE UNIQUE INDEX `idx_draw_version` ON `draw_result_revisions` (`draw_result_id`, `version`);
+
+CREATE TABLE `notifications` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `type` varchar(32) NOT NULL,
+  `title` varchar(128) NOT NULL,
+  `content` varchar(1024),
+  `draw_result_id` integer,
+  `user_number_id` integer,
+  `is_read` boolean DEFAULT false,
+  `read_at` datetime,
+  `created_at` datetime
+);
+CREATE INDEX `idx_notifications_user_id` ON `notifications` (`user_id`);
+CREATE INDEX `idx_notifications_draw_result_id` ON `notifications` (`draw_result_id`);
+CREATE INDEX `idx_notifications_is_read` ON `notifications` (`is_read`);
+
+CREATE TABLE `job_runs` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `job_name` varchar(32) NOT NULL,
+  `lock_key` varchar(64) NOT NULL,
+  `params` text,
+  `trigger_source` varchar(16) NOT NULL,
+  `operator_id` integer DEFAULT 0,
+  `status` varchar(16) NOT NULL,
+  `output` text,
+  `error` varchar(1024),
+  `started_at` datetime NOT NULL,
+  `finished_at` datetime,
+  `duration_ms` integer DEFAULT 0
+);
+CREATE INDEX `idx_job_lock` ON `job_runs` (`job_name`, `lock_key`);
+CREATE INDEX `idx_job_runs_status` ON `job_runs` (`status`);
@@ -0,0 +1,2 @@
+DROP INDEX `idx_job_runs_running_key`;
+ALTER TABLE `job_runs` DROP COLUMN `running_key`;
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 管理操作类型
+const (
+	AuditActionGameStatus = "game_status" // 启用/停用游戏
+	AuditActionDrawCreate = "draw_create" // 手动录入开奖结果
+	AuditActionDrawUpdate = "draw_update" // 更正开奖结果
+	AuditActionCrawl      = "crawl"       // 触发抓取或回补
+	AuditActionUserRole   = "user_role"   // 修改用户角色
+	AuditActionRunJob     = "run_job"     // 执行任务
+)
+
+// AdminAuditLog 管理操作审计日志表
+type AdminAuditLog struct {
+	ID         int64     `gorm:"primaryKey;column:id" json:"id"`
+	AdminID    int64     `gorm:"not null;index;column:admin_id" json:"admin_id"`         // 操作管理员ID
+	Action     string    `gorm:"size:32;not null;index;column:action" json:"action"`     // 操作类型
+	TargetType string    `gorm:"size:32;not null;column:target_type" json:"target_type"` // 操作对象类型：game, draw_result, crawl_run, job_run, user
+	TargetID   string    `gorm:"size:64;column:target_id" json:"target_id"`              // 操作对象标识
+	Before     string    `gorm:"type:text;column:before_data" json:"before"`             // 修改前数据JSON
+	After      string    `gorm:"type:text;column:after_data" json:"after"`               // 修改后数据JSON
+	Reason     string    `gorm:"size:255;column:reason" json:"reason"`                   // 操作原因
+	IP         string    `gorm:"size:45;column:ip" json:"ip"`                            // 操作IP
+	CreatedAt  time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+func (AdminAuditLog) TableName() string {
+	return "admin_audit_logs"
+}
+
+// AdminAuditLogDAO 管理操作审计日志数据访问对象
+type AdminAuditLogDAO struct {
+	db *gorm.DB
+}
+
+func NewAdminAuditLogDAO(db *gorm.DB) *AdminAuditLogDAO {
+	return &AdminAuditLogDAO{db: db}
+}
+
+// Create 创建审计日志
+func (dao *AdminAuditLogDAO) Create(log *AdminAuditLog) error {
+	return dao.db.Create(log).Error
+}
+
+// List 分页获取审计日志，action 为空时不过滤
+func (dao *AdminAuditLogDAO) List(action string, offset, limit int) ([]*AdminAuditLog, int64, error) {
+	var logs []*AdminAuditLog
+	var total int64
+	query := dao.db.Model(&AdminAuditLog{})
+	if action != "" {
+		query = query.Where("action = ?", action)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&logs).Error
+	return logs, total, err
+}
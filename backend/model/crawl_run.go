@@ -0,0 +1,93 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 抓取方式
+const (
+	CrawlModeLatest   = "latest"   // 抓取最新一期
+	CrawlModeBackfill = "backfill" // 按页回补历史数据
+)
+
+// 抓取触发来源
+const (
+	CrawlTriggerSchedule = "schedule" // 定时任务
+	CrawlTriggerAPI      = "api"      // 抓取接口
+	CrawlTriggerAdmin    = "admin"    // 管理后台
+)
+
+// 抓取状态
+const (
+	CrawlStatusRunning = "running"
+	CrawlStatusSuccess = "success"
+	CrawlStatusFailed  = "failed"
+	CrawlStatusNoData  = "no_data" // 最新一期已保存，没有新的开奖数据
+)
+
+// CrawlRun 开奖数据抓取记录表
+type CrawlRun struct {
+	ID         int64      `gorm:"primaryKey;column:id" json:"id"`
+	GameCode   string     `gorm:"size:32;not null;index;column:game_code" json:"game_code"` // 游戏代码
+	Mode       string     `gorm:"size:16;not null;column:mode" json:"mode"`                 // 抓取方式：latest(最新), backfill(回补)
+	Pages      int        `gorm:"default:0;column:pages" json:"pages"`                      // 回补页数
+	Trigger    string     `gorm:"size:16;not null;column:trigger_source" json:"trigger"`    // 触发来源：schedule, api, admin，经任务注册表执行时为任务触发来源
+	OperatorID int64      `gorm:"default:0;column:operator_id" json:"operator_id"`          // 触发的管理员ID，非管理员触发为0
+	Status     string     `gorm:"size:16;not null;index;column:status" json:"status"`       // 状态：running, success, failed, no_data
+	Error      string     `gorm:"size:512;column:error" json:"error"`                       // 失败原因
+	StartedAt  time.Time  `gorm:"not null;column:started_at" json:"started_at"`             // 开始时间
+	FinishedAt *time.Time `gorm:"column:finished_at" json:"finished_at"`                    // 结束时间
+}
+
+func (CrawlRun) TableName() string {
+	return "crawl_runs"
+}
+
+// CrawlRunDAO 抓取记录数据访问对象
+type CrawlRunDAO struct {
+	db *gorm.DB
+}
+
+func NewCrawlRunDAO(db *gorm.DB) *CrawlRunDAO {
+	return &CrawlRunDAO{db: db}
+}
+
+// Create 创建抓取记录
+func (dao *CrawlRunDAO) Create(run *CrawlRun) error {
+	return dao.db.Create(run).Error
+}
+
+// Finish 更新抓取结果
+func (dao *CrawlRunDAO) Finish(id int64, status, errMsg string, finishedAt time.Time) error {
+	return dao.db.Model(&CrawlRun{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":      status,
+		"error":       errMsg,
+		"finished_at": finishedAt,
+	}).Error
+}
+
+// CountRunning 统计某游戏进行中的抓取记录
+func (dao *CrawlRunDAO) CountRunning(gameCode, mode string) (int64, error) {
+	var count int64
+	err := dao.db.Model(&CrawlRun{}).
+		Where("game_code = ? AND mode = ? AND status = ?", gameCode, mode, CrawlStatusRunning).
+		Count(&count).Error
+	return count, err
+}
+
+// List 分页获取抓取记录，gameCode 为空时不过滤
+func (dao *CrawlRunDAO) List(gameCode string, offset, limit int) ([]*CrawlRun, int64, error) {
+	var runs []*CrawlRun
+	var total int64
+	query := dao.db.Model(&CrawlRun{})
+	if gameCode != "" {
+		query = query.Where("game_code = ?", gameCode)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&runs).Error
+	return runs, total, err
+}
@@ -19,22 +19,33 @@ func (na *NumberArray) Scan(value interface{}) error {
 		return nil
 	}
 
+	// MySQL 的 json 列返回 []byte，SQLite 的 text 列返回 string
+	var data []byte
 	switch v := value.(type) {
 	case []byte:
-		return json.Unmarshal(v, na)
+		data = v
 	case string:
-		return json.Unmarshal([]byte(v), na)
+		data = []byte(v)
 	default:
 		return fmt.Errorf("cannot scan %T into NumberArray", value)
 	}
+	if len(data) == 0 {
+		*na = nil
+		return nil
+	}
+	return json.Unmarshal(data, na)
 }
 
-// Value 实现 Valuer 接口
+// Value 实现 Valuer 接口，以 JSON 字符串写入，空数组写入 []（列不允许为空）
 func (na NumberArray) Value() (driver.Value, error) {
 	if na == nil {
-		return nil, nil
+		return "[]", nil
+	}
+	data, err := json.Marshal(na)
+	if err != nil {
+		return nil, err
 	}
-	return json.Marshal(na)
+	return string(data), nil
 }
 
 // DrawResult 开奖结果表
@@ -51,6 +62,7 @@ type DrawResult struct {
 	FirstAmount  int64       `gorm:"default:0;column:first_amount" json:"first_amount"`      // 一等奖单注奖金(分)
 	SecondPrize  int         `gorm:"default:0;column:second_prize" json:"second_prize"`      // 二等奖注数
 	SecondAmount int64       `gorm:"default:0;column:second_amount" json:"second_amount"`    // 二等奖单注奖金(分)
+	Version      int         `gorm:"not null;default:1;column:version" json:"version"`       // 数据版本，每次更正加1
 	CreatedAt    time.Time   `gorm:"column:created_at" json:"created_at"`
 	UpdatedAt    time.Time   `gorm:"column:updated_at" json:"updated_at"`
 }
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 开奖结果更正来源
+const (
+	DrawChangeSourceAdmin   = "admin"   // 管理员更正
+	DrawChangeSourceCrawler = "crawler" // 数据源更正
+)
+
+// DrawResultRevision 开奖结果历史版本表，每次更正前保存被替换的版本
+type DrawResultRevision struct {
+	ID           int64       `gorm:"primaryKey;column:id" json:"id"`
+	DrawResultID uint64      `gorm:"not null;uniqueIndex:idx_draw_version;column:draw_result_id" json:"draw_result_id"` // 开奖结果ID
+	Version      int         `gorm:"not null;uniqueIndex:idx_draw_version;column:version" json:"version"`               // 被替换的版本号
+	GameID       uint64      `gorm:"not null;column:game_id" json:"game_id"`                                            // 游戏ID
+	Period       string      `gorm:"size:32;not null;column:period" json:"period"`                                      // 期号
+	RedBalls     NumberArray `gorm:"type:json;not null;column:red_balls" json:"red_balls"`                              // 该版本的红球号码
+	BlueBalls    NumberArray `gorm:"type:json;not null;column:blue_balls" json:"blue_balls"`                            // 该版本的蓝球号码
+	DrawDate     time.Time   `gorm:"not null;column:draw_date" json:"draw_date"`                                        // 该版本的开奖时间
+	SalesAmount  int64       `gorm:"default:0;column:sales_amount" json:"sales_amount"`                                 // 销售额(分)
+	PrizePool    int64       `gorm:"default:0;column:prize_pool" json:"prize_pool"`                                     // 奖池金额(分)
+	FirstPrize   int         `gorm:"default:0;column:first_prize" json:"first_prize"`                                   // 一等奖注数
+	FirstAmount  int64       `gorm:"default:0;column:first_amount" json:"first_amount"`                                 // 一等奖单注奖金(分)
+	SecondPrize  int         `gorm:"default:0;column:second_prize" json:"second_prize"`                                 // 二等奖注数
+	SecondAmount int64       `gorm:"default:0;column:second_amount" json:"second_amount"`                               // 二等奖单注奖金(分)
+	ChangeSource string      `gorm:"size:16;not null;column:change_source" json:"change_source"`                        // 更正来源：admin, crawler
+	OperatorID   int64       `gorm:"default:0;column:operator_id" json:"operator_id"`                                   // 更正的管理员ID
+	Reason       string      `gorm:"size:255;column:reason" json:"reason"`                                              // 更正原因
+	CreatedAt    time.Time   `gorm:"column:created_at" json:"created_at"`                                               // 更正时间
+}
+
+func (DrawResultRevision) TableName() string {
+	return "draw_result_revisions"
+}
+
+// NewDrawResultRevision 根据当前开奖结果生成历史版本
+func NewDrawResultRevision(drawResult *DrawResult, changeSource string, operatorID int64, reason string) *DrawResultRevision {
+	return &DrawResultRevision{
+		DrawResultID: drawResult.ID,
+		Version:      drawResult.Version,
+		GameID:       drawResult.GameID,
+		Period:       drawResult.Period,
+		RedBalls:     drawResult.RedBalls,
+		BlueBalls:    drawResult.BlueBalls,
+		DrawDate:     drawResult.DrawDate,
+		SalesAmount:  drawResult.SalesAmount,
+		PrizePool:    drawResult.PrizePool,
+		FirstPrize:   drawResult.FirstPrize,
+		FirstAmount:  drawResult.FirstAmount,
+		SecondPrize:  drawResult.SecondPrize,
+		SecondAmount: drawResult.SecondAmount,
+		ChangeSource: changeSource,
+		OperatorID:   operatorID,
+		Reason:       reason,
+	}
+}
+
+// DrawResultRevisionDAO 开奖结果历史版本数据访问对象
+type DrawResultRevisionDAO struct {
+	db *gorm.DB
+}
+
+func NewDrawResultRevisionDAO(db *gorm.DB) *DrawResultRevisionDAO {
+	return &DrawResultRevisionDAO{db: db}
+}
+
+// Create 创建历史版本
+func (dao *DrawResultRevisionDAO) Create(revision *DrawResultRevision) error {
+	return dao.db.Create(revision).Error
+}
+
+// GetByDrawResultID 获取某期开奖结果的全部历史版本，按版本倒序
+func (dao *DrawResultRevisionDAO) GetByDrawResultID(drawResultID uint64) ([]*DrawResultRevision, error) {
+	var revisions []*DrawResultRevision
+	err := dao.db.Where("draw_result_id = ?", drawResultID).Order("version DESC").Find(&revisions).Error
+	return revisions, err
+}
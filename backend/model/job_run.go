@@ -0,0 +1,112 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 任务触发来源
+const (
+	JobTriggerCLI      = "cli"      // 命令行工具
+	JobTriggerTaskAPI  = "task_api" // 任务服务 HTTP 接口
+	JobTriggerGocron   = "gocron"   // gocron 调度
+	JobTriggerAPI      = "api"      // 主服务接口
+	JobTriggerSchedule = "schedule" // 主服务内置定时任务
+	JobTriggerAdmin    = "admin"    // 管理后台
+)
+
+// 任务执行状态
+const (
+	JobStatusRunning = "running"
+	JobStatusSuccess = "success"
+	JobStatusFailed  = "failed"
+)
+
+// JobRun 任务执行记录表
+type JobRun struct {
+	ID         int64      `gorm:"primaryKey;column:id" json:"id"`
+	JobName    string     `gorm:"size:32;not null;index:idx_job_lock;column:job_name" json:"job_name"` // 任务名称
+	LockKey    string     `gorm:"size:64;not null;index:idx_job_lock;column:lock_key" json:"lock_key"` // 并发控制键，同一键同时只执行一个
+	RunningKey *string    `gorm:"size:100;uniqueIndex;column:running_key" json:"-"`                    // 执行中占用的并发控制键，结束后为空
+	Params     string     `gorm:"type:text;column:params" json:"params"`                               // 任务参数JSON
+	Trigger    string     `gorm:"size:16;not null;column:trigger_source" json:"trigger"`               // 触发来源：cli, task_api, gocron, api, schedule, admin
+	OperatorID int64      `gorm:"default:0;column:operator_id" json:"operator_id"`                     // 触发的管理员ID
+	Status     string     `gorm:"size:16;not null;index;column:status" json:"status"`                  // 状态：running, success, failed
+	Output     string     `gorm:"type:text;column:output" json:"output"`                               // 任务输出
+	Error      string     `gorm:"size:1024;column:error" json:"error"`                                 // 失败原因
+	StartedAt  time.Time  `gorm:"not null;column:started_at" json:"started_at"`                        // 开始时间
+	FinishedAt *time.Time `gorm:"column:finished_at" json:"finished_at"`                               // 结束时间
+	DurationMs int64      `gorm:"default:0;column:duration_ms" json:"duration_ms"`                     // 执行耗时(毫秒)
+}
+
+func (JobRun) TableName() string {
+	return "job_runs"
+}
+
+// JobRunDAO 任务执行记录数据访问对象
+type JobRunDAO struct {
+	db *gorm.DB
+}
+
+func NewJobRunDAO(db *gorm.DB) *JobRunDAO {
+	return &JobRunDAO{db: db}
+}
+
+// Create 创建任务执行记录
+func (dao *JobRunDAO) Create(run *JobRun) error {
+	return dao.db.Create(run).Error
+}
+
+// Finish 更新任务执行结果
+func (dao *JobRunDAO) Finish(run *JobRun) error {
+	return dao.db.Model(&JobRun{}).Where("id = ?", run.ID).Updates(map[string]interface{}{
+		"status":      run.Status,
+		"output":      run.Output,
+		"error":       run.Error,
+		"finished_at": run.FinishedAt,
+		"duration_ms": run.DurationMs,
+	}).Error
+}
+
+// CreateRunning 创建执行记录并占用 run.RunningKey，唯一索引保证同一键只有一条执行中的记录
+// since 之前开始仍占用该键的记录视为进程异常退出遗留，先释放；该键已被占用时返回 false
+func (dao *JobRunDAO) CreateRunning(run *JobRun, since time.Time) (bool, error) {
+	err := dao.db.Model(&JobRun{}).
+		Where("running_key = ? AND started_at < ?", *run.RunningKey, since).
+		Update("running_key", nil).Error
+	if err != nil {
+		return false, err
+	}
+	if err := dao.db.Create(run).Error; err != nil {
+		var count int64
+		if dao.db.Model(&JobRun{}).Where("running_key = ?", *run.RunningKey).Count(&count).Error == nil && count > 0 {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// ReleaseRunning 释放执行记录占用的并发控制键
+func (dao *JobRunDAO) ReleaseRunning(id int64) error {
+	return dao.db.Model(&JobRun{}).Where("id = ?", id).Update("running_key", nil).Error
+}
+
+// List 分页获取任务执行记录，jobName、status 为空时不过滤
+func (dao *JobRunDAO) List(jobName, status string, offset, limit int) ([]*JobRun, int64, error) {
+	var runs []*JobRun
+	var total int64
+	query := dao.db.Model(&JobRun{})
+	if jobName != "" {
+		query = query.Where("job_name = ?", jobName)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&runs).Error
+	return runs, total, err
+}
@@ -6,18 +6,27 @@ import (
 	"gorm.io/gorm"
 )
 
+// 游戏类型
+const (
+	GameTypeBall  = "ball"  // 选号型：红蓝球不重复，不计顺序
+	GameTypeDigit = "digit" // 数字型：每位0-9可重复，按位置对奖
+	GameTypeKeno  = "keno"  // 快乐8型：开出多个号码，按所选玩法的命中个数对奖
+)
+
 // LotteryGame 彩票游戏表
 type LotteryGame struct {
-	ID              uint64    `gorm:"primaryKey;column:id" json:"id"`
-	GameCode        string    `gorm:"size:32;not null;column:game_code" json:"game_code"`         // 游戏代码
-	GameName        string    `gorm:"size:64;not null;column:game_name" json:"game_name"`         // 游戏名称
-	RedBallCount    int       `gorm:"not null;column:red_ball_count" json:"red_ball_count"`       // 红球总数
-	BlueBallCount   int       `gorm:"not null;column:blue_ball_count" json:"blue_ball_count"`     // 蓝球总数
-	RedSelectCount  int       `gorm:"not null;column:red_select_count" json:"red_select_count"`   // 红球选择数
-	BlueSelectCount int       `gorm:"not null;column:blue_select_count" json:"blue_select_count"` // 蓝球选择数
-	IsActive        bool      `gorm:"default:true;column:is_active" json:"is_active"`             // 是否启用
-	CreatedAt       time.Time `gorm:"column:created_at" json:"created_at"`
-	UpdatedAt       time.Time `gorm:"column:updated_at" json:"updated_at"`
+	ID               uint64    `gorm:"primaryKey;column:id" json:"id"`
+	GameCode         string    `gorm:"size:32;not null;column:game_code" json:"game_code"`            // 游戏代码
+	GameName         string    `gorm:"size:64;not null;column:game_name" json:"game_name"`            // 游戏名称
+	GameType         string    `gorm:"size:16;default:'ball';column:game_type" json:"game_type"`      // 游戏类型：ball(选号型), digit(数字型), keno(快乐8型)
+	RedBallCount     int       `gorm:"not null;column:red_ball_count" json:"red_ball_count"`          // 红球总数，数字型为每位可选数字个数
+	BlueBallCount    int       `gorm:"not null;column:blue_ball_count" json:"blue_ball_count"`        // 蓝球总数，七星彩为后区可选数字个数
+	RedSelectCount   int       `gorm:"not null;column:red_select_count" json:"red_select_count"`      // 红球选择数，数字型为位数，快乐8型为最多可选个数
+	BlueSelectCount  int       `gorm:"not null;column:blue_select_count" json:"blue_select_count"`    // 蓝球选择数，七星彩为后区位数
+	SpecialBallCount int       `gorm:"default:0;column:special_ball_count" json:"special_ball_count"` // 特别号个数（七乐彩），从红球号码池中额外开出，无需选择
+	IsActive         bool      `gorm:"default:true;column:is_active" json:"is_active"`                // 是否启用
+	CreatedAt        time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt        time.Time `gorm:"column:updated_at" json:"updated_at"`
 }
 
 func (LotteryGame) TableName() string {
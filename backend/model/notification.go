@@ -0,0 +1,79 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 站内通知类型
+const (
+	NotificationDrawCorrected = "draw_corrected" // 开奖结果更正导致中奖结果变化
+)
+
+// Notification 用户站内通知表
+type Notification struct {
+	ID           int64      `gorm:"primaryKey;column:id" json:"id"`
+	UserID       int64      `gorm:"not null;index;column:user_id" json:"user_id"`      // 用户ID
+	Type         string     `gorm:"size:32;not null;column:type" json:"type"`          // 通知类型
+	Title        string     `gorm:"size:128;not null;column:title" json:"title"`       // 标题
+	Content      string     `gorm:"size:1024;column:content" json:"content"`           // 内容
+	DrawResultID *uint64    `gorm:"index;column:draw_result_id" json:"draw_result_id"` // 关联的开奖结果ID
+	UserNumberID *int64     `gorm:"column:user_number_id" json:"user_number_id"`       // 关联的用户号码ID
+	IsRead       bool       `gorm:"default:false;index;column:is_read" json:"is_read"` // 是否已读
+	ReadAt       *time.Time `gorm:"column:read_at" json:"read_at"`                     // 阅读时间
+	CreatedAt    time.Time  `gorm:"column:created_at" json:"created_at"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// NotificationDAO 站内通知数据访问对象
+type NotificationDAO struct {
+	db *gorm.DB
+}
+
+func NewNotificationDAO(db *gorm.DB) *NotificationDAO {
+	return &NotificationDAO{db: db}
+}
+
+// Create 创建通知
+func (dao *NotificationDAO) Create(notification *Notification) error {
+	return dao.db.Create(notification).Error
+}
+
+// GetByUserID 分页获取用户通知，unreadOnly 为 true 时只返回未读通知
+func (dao *NotificationDAO) GetByUserID(userID int64, unreadOnly bool, offset, limit int) ([]*Notification, int64, error) {
+	var notifications []*Notification
+	var total int64
+	query := dao.db.Model(&Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("is_read = ?", false)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&notifications).Error
+	return notifications, total, err
+}
+
+// CountUnread 统计用户未读通知数
+func (dao *NotificationDAO) CountUnread(userID int64) (int64, error) {
+	var count int64
+	err := dao.db.Model(&Notification{}).Where("user_id = ? AND is_read = ?", userID, false).Count(&count).Error
+	return count, err
+}
+
+// MarkRead 标记用户的通知为已读，id 为0时标记全部
+func (dao *NotificationDAO) MarkRead(userID, id int64) (int64, error) {
+	query := dao.db.Model(&Notification{}).Where("user_id = ? AND is_read = ?", userID, false)
+	if id > 0 {
+		query = query.Where("id = ?", id)
+	}
+	result := query.Updates(map[string]interface{}{
+		"is_read": true,
+		"read_at": time.Now(),
+	})
+	return result.RowsAffected, result.Error
+}
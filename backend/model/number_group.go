@@ -0,0 +1,67 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NumberGroup 用户号码分组表
+type NumberGroup struct {
+	ID        int64     `gorm:"primaryKey;column:id" json:"id"`
+	UserID    int64     `gorm:"not null;index;column:user_id" json:"user_id"` // 用户ID
+	GameID    uint64    `gorm:"not null;column:game_id" json:"game_id"`       // 游戏ID
+	Name      string    `gorm:"size:64;not null;column:name" json:"name"`     // 分组名称
+	Source    string    `gorm:"size:32;column:source" json:"source"`          // 来源：manual(手动), wheel(旋转矩阵)
+	Note      string    `gorm:"size:512;column:note" json:"note"`             // 备注
+	CreatedAt time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (NumberGroup) TableName() string {
+	return "number_groups"
+}
+
+// NumberGroupDAO 号码分组数据访问对象
+type NumberGroupDAO struct {
+	db *gorm.DB
+}
+
+func NewNumberGroupDAO(db *gorm.DB) *NumberGroupDAO {
+	return &NumberGroupDAO{db: db}
+}
+
+// Create 创建号码分组
+func (dao *NumberGroupDAO) Create(group *NumberGroup) error {
+	return dao.db.Create(group).Error
+}
+
+// GetByID 根据ID获取号码分组
+func (dao *NumberGroupDAO) GetByID(id int64) (*NumberGroup, error) {
+	var group NumberGroup
+	err := dao.db.First(&group, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// GetByUserID 根据用户ID获取号码分组列表
+func (dao *NumberGroupDAO) GetByUserID(userID int64) ([]*NumberGroup, error) {
+	var groups []*NumberGroup
+	err := dao.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&groups).Error
+	return groups, err
+}
+
+// Update 更新号码分组
+func (dao *NumberGroupDAO) Update(group *NumberGroup) error {
+	return dao.db.Save(group).Error
+}
+
+// Delete 删除号码分组，组内号码移出分组
+func (dao *NumberGroupDAO) Delete(id int64) error {
+	if err := dao.db.Model(&UserNumber{}).Where("group_id = ?", id).Update("group_id", nil).Error; err != nil {
+		return err
+	}
+	return dao.db.Delete(&NumberGroup{}, id).Error
+}
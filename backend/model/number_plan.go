@@ -0,0 +1,130 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NumberPlan 追号计划表
+type NumberPlan struct {
+	ID             int64      `gorm:"primaryKey;column:id" json:"id"`
+	UserID         int64      `gorm:"not null;index;column:user_id" json:"user_id"`               // 用户ID
+	UserNumberID   int64      `gorm:"not null;index;column:user_number_id" json:"user_number_id"` // 追号的用户号码ID
+	UserNumber     UserNumber `gorm:"foreignKey:UserNumberID" json:"user_number"`                 // 号码信息
+	GameID         uint64     `gorm:"not null;index;column:game_id" json:"game_id"`               // 游戏ID
+	StartPeriod    string     `gorm:"size:32;not null;column:start_period" json:"start_period"`   // 起始期号
+	TotalPeriods   int        `gorm:"not null;column:total_periods" json:"total_periods"`         // 追号期数
+	StopAfterWin   bool       `gorm:"default:false;column:stop_after_win" json:"stop_after_win"`  // 中奖后停止追号
+	Multiplier     int        `gorm:"default:1;column:multiplier" json:"multiplier"`              // 每期倍数
+	IsAdditional   bool       `gorm:"default:false;column:is_additional" json:"is_additional"`    // 是否追加（大乐透）
+	Status         string     `gorm:"size:16;default:'active';column:status" json:"status"`       // 状态：active(进行中), finished(已完成), stopped(中奖停追), cancelled(已取消)
+	DrawnPeriods   int        `gorm:"default:0;column:drawn_periods" json:"drawn_periods"`        // 已开奖期数
+	WinningPeriods int        `gorm:"default:0;column:winning_periods" json:"winning_periods"`    // 中奖期数
+	TotalPrize     int64      `gorm:"default:0;column:total_prize" json:"total_prize"`            // 累计奖金(分)
+	LastPeriod     string     `gorm:"size:32;column:last_period" json:"last_period"`              // 最近核对的期号
+	CreatedAt      time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (NumberPlan) TableName() string {
+	return "number_plans"
+}
+
+// NumberPlanPeriod 追号计划逐期结果表
+type NumberPlanPeriod struct {
+	ID           int64     `gorm:"primaryKey;column:id" json:"id"`
+	PlanID       int64     `gorm:"not null;uniqueIndex:idx_plan_period;column:plan_id" json:"plan_id"` // 追号计划ID
+	Period       string    `gorm:"size:32;not null;uniqueIndex:idx_plan_period;column:period" json:"period"`
+	DrawResultID uint64    `gorm:"not null;column:draw_result_id" json:"draw_result_id"` // 开奖结果ID
+	RedMatches   int       `gorm:"default:0;column:red_matches" json:"red_matches"`      // 红球匹配数
+	BlueMatches  int       `gorm:"default:0;column:blue_matches" json:"blue_matches"`    // 蓝球匹配数
+	PrizeLevel   int       `gorm:"default:0;column:prize_level" json:"prize_level"`      // 奖级，0表示未中奖
+	PrizeAmount  int64     `gorm:"default:0;column:prize_amount" json:"prize_amount"`    // 奖金(分)
+	CreatedAt    time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+func (NumberPlanPeriod) TableName() string {
+	return "number_plan_periods"
+}
+
+// NumberPlanDAO 追号计划数据访问对象
+type NumberPlanDAO struct {
+	db *gorm.DB
+}
+
+func NewNumberPlanDAO(db *gorm.DB) *NumberPlanDAO {
+	return &NumberPlanDAO{db: db}
+}
+
+// Create 创建追号计划
+func (dao *NumberPlanDAO) Create(plan *NumberPlan) error {
+	return dao.db.Create(plan).Error
+}
+
+// GetByID 根据ID获取追号计划（包含号码和游戏信息）
+func (dao *NumberPlanDAO) GetByID(id int64) (*NumberPlan, error) {
+	var plan NumberPlan
+	err := dao.db.Preload("UserNumber.Game").First(&plan, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// GetByUserID 根据用户ID获取追号计划列表，status为空时返回全部
+func (dao *NumberPlanDAO) GetByUserID(userID int64, status string) ([]*NumberPlan, error) {
+	var plans []*NumberPlan
+	query := dao.db.Preload("UserNumber.Game").Where("user_id = ?", userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at DESC").Find(&plans).Error
+	return plans, err
+}
+
+// GetActiveByGameID 获取某游戏进行中的追号计划
+func (dao *NumberPlanDAO) GetActiveByGameID(gameID uint64, period string) ([]*NumberPlan, error) {
+	var plans []*NumberPlan
+	err := dao.db.Where("game_id = ? AND status = ? AND start_period <= ?", gameID, "active", period).Find(&plans).Error
+	return plans, err
+}
+
+// GetByDrawResultID 获取逐期结果包含某期开奖的追号计划（不含已取消的计划）
+func (dao *NumberPlanDAO) GetByDrawResultID(drawResultID uint64) ([]*NumberPlan, error) {
+	var plans []*NumberPlan
+	err := dao.db.Where("id IN (?) AND status <> ?",
+		dao.db.Model(&NumberPlanPeriod{}).Select("plan_id").Where("draw_result_id = ?", drawResultID),
+		"cancelled").Find(&plans).Error
+	return plans, err
+}
+
+// Update 更新追号计划
+func (dao *NumberPlanDAO) Update(plan *NumberPlan) error {
+	return dao.db.Omit("UserNumber").Save(plan).Error
+}
+
+// GetPeriods 获取追号计划的逐期结果
+func (dao *NumberPlanDAO) GetPeriods(planID int64) ([]*NumberPlanPeriod, error) {
+	var periods []*NumberPlanPeriod
+	err := dao.db.Where("plan_id = ?", planID).Order("period ASC").Find(&periods).Error
+	return periods, err
+}
+
+// DeleteByUserNumberID 删除号码关联的追号计划及逐期结果、购彩记录
+func (dao *NumberPlanDAO) DeleteByUserNumberID(userNumberID int64) error {
+	var planIDs []int64
+	if err := dao.db.Model(&NumberPlan{}).Where("user_number_id = ?", userNumberID).Pluck("id", &planIDs).Error; err != nil {
+		return err
+	}
+	if len(planIDs) == 0 {
+		return nil
+	}
+	if err := dao.db.Where("plan_id IN ?", planIDs).Delete(&NumberPlanPeriod{}).Error; err != nil {
+		return err
+	}
+	if err := dao.db.Where("plan_id IN ?", planIDs).Delete(&Purchase{}).Error; err != nil {
+		return err
+	}
+	return dao.db.Where("id IN ?", planIDs).Delete(&NumberPlan{}).Error
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NumberTag 用户号码标签表
+type NumberTag struct {
+	ID        int64     `gorm:"primaryKey;column:id" json:"id"`
+	UserID    int64     `gorm:"not null;index;column:user_id" json:"user_id"` // 用户ID
+	Name      string    `gorm:"size:32;not null;column:name" json:"name"`     // 标签名称
+	Color     string    `gorm:"size:16;column:color" json:"color"`            // 标签颜色
+	CreatedAt time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+func (NumberTag) TableName() string {
+	return "number_tags"
+}
+
+// UserNumberTag 用户号码与标签关联表
+type UserNumberTag struct {
+	UserNumberID int64     `gorm:"primaryKey;column:user_number_id" json:"user_number_id"`
+	TagID        int64     `gorm:"primaryKey;index;column:tag_id" json:"tag_id"`
+	CreatedAt    time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+func (UserNumberTag) TableName() string {
+	return "user_number_tags"
+}
+
+// NumberTagDAO 号码标签数据访问对象
+type NumberTagDAO struct {
+	db *gorm.DB
+}
+
+func NewNumberTagDAO(db *gorm.DB) *NumberTagDAO {
+	return &NumberTagDAO{db: db}
+}
+
+// Create 创建号码标签
+func (dao *NumberTagDAO) Create(tag *NumberTag) error {
+	return dao.db.Create(tag).Error
+}
+
+// GetByID 根据ID获取号码标签
+func (dao *NumberTagDAO) GetByID(id int64) (*NumberTag, error) {
+	var tag NumberTag
+	err := dao.db.First(&tag, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// GetByUserID 根据用户ID获取号码标签列表
+func (dao *NumberTagDAO) GetByUserID(userID int64) ([]*NumberTag, error) {
+	var tags []*NumberTag
+	err := dao.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&tags).Error
+	return tags, err
+}
+
+// Delete 删除号码标签及其关联
+func (dao *NumberTagDAO) Delete(id int64) error {
+	if err := dao.db.Where("tag_id = ?", id).Delete(&UserNumberTag{}).Error; err != nil {
+		return err
+	}
+	return dao.db.Delete(&NumberTag{}, id).Error
+}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Purchase 用户购彩记录表
+type Purchase struct {
+	ID           int64       `gorm:"primaryKey;column:id" json:"id"`
+	UserID       int64       `gorm:"not null;index;column:user_id" json:"user_id"`            // 用户ID
+	GameID       uint64      `gorm:"not null;index;column:game_id" json:"game_id"`            // 游戏ID
+	Game         LotteryGame `gorm:"foreignKey:GameID" json:"game"`                           // 游戏信息
+	Period       string      `gorm:"size:32;not null;index;column:period" json:"period"`      // 期号
+	DrawDate     time.Time   `gorm:"column:draw_date" json:"draw_date"`                       // 开奖日期，未开奖时为预计开奖日期
+	UserNumberID *int64      `gorm:"index;column:user_number_id" json:"user_number_id"`       // 购买的用户号码ID
+	GroupID      *int64      `gorm:"index;column:group_id" json:"group_id"`                   // 购买的号码分组ID
+	PlanID       *int64      `gorm:"index;column:plan_id" json:"plan_id"`                     // 来源追号计划ID
+	BetCount     int         `gorm:"not null;default:1;column:bet_count" json:"bet_count"`    // 注数
+	Multiplier   int         `gorm:"not null;default:1;column:multiplier" json:"multiplier"`  // 倍数
+	IsAdditional bool        `gorm:"default:false;column:is_additional" json:"is_additional"` // 是否追加（大乐透）
+	Cost         int64       `gorm:"not null;default:0;column:cost" json:"cost"`              // 投注金额(分)
+	Source       string      `gorm:"size:16;default:'manual';column:source" json:"source"`    // 来源：manual(手动), plan(追号)
+	Status       string      `gorm:"size:16;default:'pending';column:status" json:"status"`   // 状态：pending(待开奖), settled(已开奖)
+	PrizeLevel   int         `gorm:"default:0;column:prize_level" json:"prize_level"`         // 最高奖级，0表示未中奖
+	PrizeAmount  int64       `gorm:"default:0;column:prize_amount" json:"prize_amount"`       // 中奖金额(分)
+	Note         string      `gorm:"size:512;column:note" json:"note"`                        // 备注
+	CreatedAt    time.Time   `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt    time.Time   `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (Purchase) TableName() string {
+	return "purchases"
+}
+
+// PurchaseDAO 购彩记录数据访问对象
+type PurchaseDAO struct {
+	db *gorm.DB
+}
+
+func NewPurchaseDAO(db *gorm.DB) *PurchaseDAO {
+	return &PurchaseDAO{db: db}
+}
+
+// Create 创建购彩记录
+func (dao *PurchaseDAO) Create(purchase *Purchase) error {
+	return dao.db.Omit("Game").Create(purchase).Error
+}
+
+// GetByID 根据ID获取购彩记录
+func (dao *PurchaseDAO) GetByID(id int64) (*Purchase, error) {
+	var purchase Purchase
+	err := dao.db.Preload("Game").First(&purchase, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &purchase, nil
+}
+
+// GetByUserID 根据用户ID分页获取购彩记录
+func (dao *PurchaseDAO) GetByUserID(userID int64, offset, limit int) ([]*Purchase, error) {
+	var purchases []*Purchase
+	err := dao.db.Preload("Game").Where("user_id = ?", userID).
+		Order("period DESC, id DESC").Offset(offset).Limit(limit).Find(&purchases).Error
+	return purchases, err
+}
+
+// GetAllByUserID 获取用户全部购彩记录
+func (dao *PurchaseDAO) GetAllByUserID(userID int64) ([]*Purchase, error) {
+	var purchases []*Purchase
+	err := dao.db.Preload("Game").Where("user_id = ?", userID).Order("period ASC").Find(&purchases).Error
+	return purchases, err
+}
+
+// CountByUserID 获取用户购彩记录总数
+func (dao *PurchaseDAO) CountByUserID(userID int64) (int64, error) {
+	var count int64
+	err := dao.db.Model(&Purchase{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// GetPendingByPeriod 获取某游戏某期待开奖的购彩记录
+func (dao *PurchaseDAO) GetPendingByPeriod(gameID uint64, period string) ([]*Purchase, error) {
+	var purchases []*Purchase
+	err := dao.db.Where("game_id = ? AND period = ? AND status = ?", gameID, period, "pending").Find(&purchases).Error
+	return purchases, err
+}
+
+// GetSettledManualByPeriod 获取某游戏某期已结算的手动购彩记录，追号生成的记录随追号计划重新核对
+func (dao *PurchaseDAO) GetSettledManualByPeriod(gameID uint64, period string) ([]*Purchase, error) {
+	var purchases []*Purchase
+	err := dao.db.Where("game_id = ? AND period = ? AND status = ? AND plan_id IS NULL", gameID, period, "settled").Find(&purchases).Error
+	return purchases, err
+}
+
+// Update 更新购彩记录
+func (dao *PurchaseDAO) Update(purchase *Purchase) error {
+	return dao.db.Omit("Game").Save(purchase).Error
+}
+
+// Delete 删除购彩记录
+func (dao *PurchaseDAO) Delete(id int64) error {
+	return dao.db.Delete(&Purchase{}, id).Error
+}
+
+// DeleteByPlanID 删除追号计划生成的购彩记录
+func (dao *PurchaseDAO) DeleteByPlanID(planID int64) error {
+	return dao.db.Where("plan_id = ?", planID).Delete(&Purchase{}).Error
+}
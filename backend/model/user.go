@@ -5,12 +5,19 @@ import (
 "gorm.io/gorm"
 )
 
+// 用户角色
+const (
+RoleUser  = "user"  // 普通用户
+RoleAdmin = "admin" // 管理员
+)
+
 type User struct {
 ID           int64      `gorm:"primaryKey;column:id" json:"id"`
 OpenID       string     `gorm:"uniqueIndex;size:64;not null;column:open_id" json:"open_id"`
 Nickname     string     `gorm:"size:64;not null;column:nickname" json:"nickname"`
 AvatarURL    string     `gorm:"size:255;column:avatar_url" json:"avatar_url"`
 Status       int        `gorm:"not null;default:1;column:status;comment:用户状态(1:正常 0:禁用)" json:"status"`
+Role         string     `gorm:"size:16;not null;default:'user';column:role;comment:角色(user:普通用户 admin:管理员)" json:"role"`
 TokenVersion int        `gorm:"not null;default:1;column:token_version;comment:token版本号" json:"token_version"`
 LastLoginAt  *time.Time `gorm:"column:last_login_at;comment:最后登录时间" json:"last_login_at"`
 LastLoginIP  string     `gorm:"size:45;column:last_login_ip;comment:最后登录IP" json:"last_login_ip"`
@@ -23,6 +30,11 @@ func (User) TableName() string {
 return "users"
 }
 
+// IsAdmin 是否为管理员
+func (u *User) IsAdmin() bool {
+return u.Role == RoleAdmin
+}
+
 // UserDAO 用户数据访问对象
 type UserDAO struct {
 db *gorm.DB
@@ -76,6 +88,11 @@ return dao.db.Model(&User{}).Where("id = ?", userID).Updates(map[string]interfac
 }).Error
 }
 
+// UpdateRole 更新用户角色
+func (dao *UserDAO) UpdateRole(userID int64, role string) error {
+return dao.db.Model(&User{}).Where("id = ?", userID).Update("role", role).Error
+}
+
 // Delete 删除用户
 func (dao *UserDAO) Delete(id int64) error {
 return dao.db.Delete(&User{}, id).Error
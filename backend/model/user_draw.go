@@ -12,6 +12,7 @@ type UserDraw struct {
 	UserNumberID uint           `json:"user_number_id" gorm:"not null;index"`
 	DrawResultID uint           `json:"draw_result_id" gorm:"not null;index"`
 	PrizeLevel   int            `json:"prize_level" gorm:"not null;default:0"`
+	PrizeAmount  int64          `json:"prize_amount" gorm:"not null;default:0"` // 单注奖金(分)
 	IsWinning    bool           `json:"is_winning" gorm:"not null;default:false"`
 	IsActive     bool           `json:"is_active" gorm:"not null;default:true"`
 	CreatedAt    time.Time      `json:"created_at"`
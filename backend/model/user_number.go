@@ -6,19 +6,33 @@ import (
 	"gorm.io/gorm"
 )
 
+// 数字型游戏玩法
+const (
+	PlayTypeDirect = "direct" // 直选：号码和顺序均一致
+	PlayTypeGroup3 = "group3" // 组选三：三位中有两位相同，不计顺序
+	PlayTypeGroup6 = "group6" // 组选六：三位各不相同，不计顺序
+)
+
 // UserNumber 用户号码表
 type UserNumber struct {
-	ID        int64       `gorm:"primaryKey;column:id" json:"id"`
-	UserID    int64       `gorm:"not null;index;column:user_id" json:"user_id"`           // 用户ID
-	GameID    uint64      `gorm:"not null;index;column:game_id" json:"game_id"`           // 游戏ID
-	Game      LotteryGame `gorm:"foreignKey:GameID" json:"game"`                          // 游戏信息
-	RedBalls  NumberArray `gorm:"type:json;not null;column:red_balls" json:"red_balls"`   // 红球号码JSON数组
-	BlueBalls NumberArray `gorm:"type:json;not null;column:blue_balls" json:"blue_balls"` // 蓝球号码JSON数组
-	Nickname  string      `gorm:"size:128;column:nickname" json:"nickname"`               // 用户给号码起的昵称
-	Source    string      `gorm:"size:32;default:'manual';column:source" json:"source"`   // 来源：manual(手动), random(机选)
-	IsActive  bool        `gorm:"default:true;column:is_active" json:"is_active"`         // 是否启用
-	CreatedAt time.Time   `gorm:"column:created_at" json:"created_at"`
-	UpdatedAt time.Time   `gorm:"column:updated_at" json:"updated_at"`
+	ID           int64       `gorm:"primaryKey;column:id" json:"id"`
+	UserID       int64       `gorm:"not null;index;column:user_id" json:"user_id"`            // 用户ID
+	GameID       uint64      `gorm:"not null;index;column:game_id" json:"game_id"`            // 游戏ID
+	Game         LotteryGame `gorm:"foreignKey:GameID" json:"game"`                           // 游戏信息
+	RedBalls     NumberArray `gorm:"type:json;not null;column:red_balls" json:"red_balls"`    // 红球号码JSON数组
+	BlueBalls    NumberArray `gorm:"type:json;not null;column:blue_balls" json:"blue_balls"`  // 蓝球号码JSON数组
+	PlayType     string      `gorm:"size:16;column:play_type" json:"play_type"`               // 玩法：direct(直选), group3(组选三), group6(组选六), pick1-pick10(快乐8选一至选十)，选号型游戏为空
+	GroupID      *int64      `gorm:"index;column:group_id" json:"group_id"`                   // 所属分组ID
+	Multiplier   int         `gorm:"default:1;column:multiplier" json:"multiplier"`           // 倍数
+	IsAdditional bool        `gorm:"default:false;column:is_additional" json:"is_additional"` // 是否追加（大乐透）
+	Nickname     string      `gorm:"size:128;column:nickname" json:"nickname"`                // 用户给号码起的昵称
+	Note         string      `gorm:"size:512;column:note" json:"note"`                        // 备注
+	Source       string      `gorm:"size:32;default:'manual';column:source" json:"source"`    // 来源：manual(手动), random(机选), filter(缩水), wheel(旋转矩阵), import(导入)
+	IsActive     bool        `gorm:"default:true;column:is_active" json:"is_active"`          // 是否启用
+	CreatedAt    time.Time   `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt    time.Time   `gorm:"column:updated_at" json:"updated_at"`
+
+	Tags []NumberTag `gorm:"many2many:user_number_tags;joinForeignKey:UserNumberID;joinReferences:TagID" json:"tags"` // 标签
 }
 
 func (UserNumber) TableName() string {
@@ -102,6 +116,11 @@ func (dao *UserNumberDAO) UpdateNickname(id int64, nickname string) error {
 	return dao.db.Model(&UserNumber{}).Where("id = ?", id).Update("nickname", nickname).Error
 }
 
+// UpdateGroup 更新用户号码所属分组
+func (dao *UserNumberDAO) UpdateGroup(id int64, groupID *int64) error {
+	return dao.db.Model(&UserNumber{}).Where("id = ?", id).Update("group_id", groupID).Error
+}
+
 // Delete 删除用户号码
 func (dao *UserNumberDAO) Delete(id int64) error {
 	return dao.db.Delete(&UserNumber{}, id).Error
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"lucky/model"
+
+	"gorm.io/gorm"
+)
+
+// kenoDrawCount 快乐8每期开出的号码个数
+const kenoDrawCount = 20
+
+// maxBackfillPages 单次回补的最大页数
+const maxBackfillPages = 100
+
+// AdminOperator 执行管理操作的管理员
+type AdminOperator struct {
+	AdminID int64
+	IP      string
+}
+
+// DrawResultInput 手动录入或更正的开奖数据，金额单位为分
+type DrawResultInput struct {
+	Period       string            `json:"period"`
+	DrawDate     string            `json:"drawDate"`
+	RedBalls     model.NumberArray `json:"redBalls"`
+	BlueBalls    model.NumberArray `json:"blueBalls"`
+	SalesAmount  int64             `json:"salesAmount"`
+	PrizePool    int64             `json:"prizePool"`
+	FirstPrize   int               `json:"firstPrize"`
+	FirstAmount  int64             `json:"firstAmount"`
+	SecondPrize  int               `json:"secondPrize"`
+	SecondAmount int64             `json:"secondAmount"`
+}
+
+// recordAudit 写入管理操作审计日志，before/after 序列化为JSON
+func recordAudit(db *gorm.DB, operator AdminOperator, action, targetType, targetID string, before, after interface{}, reason string) error {
+	auditLog := &model.AdminAuditLog{
+		AdminID:    operator.AdminID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     marshalAuditData(before),
+		After:      marshalAuditData(after),
+		Reason:     reason,
+		IP:         operator.IP,
+	}
+	return model.NewAdminAuditLogDAO(db).Create(auditLog)
+}
+
+// marshalAuditData 审计数据转JSON，nil 返回空字符串
+func marshalAuditData(data interface{}) string {
+	if data == nil {
+		return ""
+	}
+	content, err := json.Marshal(data)
+	if err != nil {
+		return ""
+	}
+	return string(content)
+}
+
+// ListAllGames 获取全部游戏（含未启用），直接读取数据库
+func ListAllGames(db *gorm.DB) ([]model.LotteryGame, error) {
+	var games []model.LotteryGame
+	err := db.Order("id").Find(&games).Error
+	return games, err
+}
+
+// SetGameActive 启用或停用游戏，并刷新游戏注册表
+func SetGameActive(db *gorm.DB, operator AdminOperator, gameCode string, isActive bool, reason string) (*model.LotteryGame, error) {
+	var game model.LotteryGame
+	if err := db.Where("game_code = ?", gameCode).First(&game).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("游戏不存在: %s", gameCode)
+		}
+		return nil, err
+	}
+	if game.IsActive == isActive {
+		return &game, nil
+	}
+
+	before := map[string]bool{"isActive": game.IsActive}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := model.NewLotteryGameDAO(tx).UpdateStatus(game.ID, isActive); err != nil {
+			return err
+		}
+		return recordAudit(tx, operator, model.AuditActionGameStatus, "game", game.GameCode, before, map[string]bool{"isActive": isActive}, reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	InvalidateGameRegistry()
+	game.IsActive = isActive
+	return &game, nil
+}
+
+// ValidateDrawNumbers 按游戏规则校验开奖号码
+// 快乐8每期开出20个号码，七乐彩的蓝球为从红球号码池开出的特别号
+func ValidateDrawNumbers(game *model.LotteryGame, redBalls, blueBalls model.NumberArray) error {
+	switch {
+	case IsKenoGame(game):
+		if len(redBalls) != kenoDrawCount {
+			return fmt.Errorf("开奖号码数量不正确，需要%d个", kenoDrawCount)
+		}
+		if len(blueBalls) != 0 {
+			return errors.New("快乐8没有蓝球")
+		}
+		return validateBallRange(redBalls, game.RedBallCount)
+	case game.SpecialBallCount > 0:
+		if err := ValidateNumbers(game, redBalls, nil); err != nil {
+			return err
+		}
+		if len(blueBalls) != game.SpecialBallCount {
+			return fmt.Errorf("特别号数量不正确，需要%d个", game.SpecialBallCount)
+		}
+		if err := validateBallRange(append(append(model.NumberArray{}, redBalls...), blueBalls...), game.RedBallCount); err != nil {
+			return errors.New("特别号不能与基本号重复，且需在红球范围内")
+		}
+		return nil
+	default:
+		return ValidateNumbers(game, redBalls, blueBalls)
+	}
+}
+
+// validateBallRange 校验号码在1-maxNumber范围内且不重复
+func validateBallRange(balls model.NumberArray, maxNumber int) error {
+	used := make(map[int]bool, len(balls))
+	for _, ball := range balls {
+		if ball < 1 || ball > maxNumber {
+			return fmt.Errorf("号码超出范围(1-%d)", maxNumber)
+		}
+		if used[ball] {
+			return errors.New("号码重复")
+		}
+		used[ball] = true
+	}
+	return nil
+}
+
+// applyDrawResultInput 将录入数据写入开奖结果，日期为空时保留原值
+func applyDrawResultInput(drawResult *model.DrawResult, input *DrawResultInput) error {
+	if input.DrawDate != "" {
+		drawDate, err := parseDrawDate(input.DrawDate)
+		if err != nil {
+			return err
+		}
+		drawResult.DrawDate = drawDate
+	}
+	drawResult.RedBalls = input.RedBalls
+	drawResult.BlueBalls = input.BlueBalls
+	if drawResult.BlueBalls == nil {
+		drawResult.BlueBalls = model.NumberArray{}
+	}
+	drawResult.SalesAmount = input.SalesAmount
+	drawResult.PrizePool = input.PrizePool
+	drawResult.FirstPrize = input.FirstPrize
+	drawResult.FirstAmount = input.FirstAmount
+	drawResult.SecondPrize = input.SecondPrize
+	drawResult.SecondAmount = input.SecondAmount
+	return nil
+}
+
+// CreateDrawResultManually 手动录入一期开奖结果，录入后与抓取保存一样核对追号计划和购彩记录
+func CreateDrawResultManually(db *gorm.DB, operator AdminOperator, gameCode string, input *DrawResultInput, reason string) (*model.DrawResult, error) {
+	game, err := LookupGame(db, gameCode)
+	if err != nil {
+		return nil, fmt.Errorf("游戏不存在: %s", gameCode)
+	}
+	input.Period = strings.TrimSpace(input.Period)
+	if input.Period == "" || input.DrawDate == "" {
+		return nil, errors.New("期号和开奖日期不能为空")
+	}
+	if err := ValidateDrawNumbers(game, input.RedBalls, input.BlueBalls); err != nil {
+		return nil, err
+	}
+
+	drawResult := &model.DrawResult{GameID: game.ID, Period: input.Period}
+	if err := applyDrawResultInput(drawResult, input); err != nil {
+		return nil, err
+	}
+	if err := createDrawResult(db, drawResult); err != nil {
+		return nil, err
+	}
+
+	if err := recordAudit(db, operator, model.AuditActionDrawCreate, "draw_result", strconv.FormatUint(drawResult.ID, 10), nil, drawResult, reason); err != nil {
+		dbLogger(db).Errorf("记录审计日志失败: %v", err)
+	}
+	return drawResult, nil
+}
+
+// CorrectDrawResult 更正已保存的开奖结果，需填写更正原因
+// 被替换的版本保存在历史版本表中，修改前后的数据记入审计日志，并重新核对该期的中奖结果
+func CorrectDrawResult(db *gorm.DB, operator AdminOperator, gameCode, period string, input *DrawResultInput, reason string) (*DrawCorrectionResult, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, errors.New("请填写更正原因")
+	}
+	game, err := LookupGame(db, gameCode)
+	if err != nil {
+		return nil, fmt.Errorf("游戏不存在: %s", gameCode)
+	}
+	if err := ValidateDrawNumbers(game, input.RedBalls, input.BlueBalls); err != nil {
+		return nil, err
+	}
+
+	var current model.DrawResult
+	if err := db.Where("game_id = ? AND period = ?", game.ID, period).First(&current).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("期号 %s 不存在", period)
+		}
+		return nil, err
+	}
+
+	corrected := current
+	if err := applyDrawResultInput(&corrected, input); err != nil {
+		return nil, err
+	}
+
+	result, err := ApplyDrawCorrection(db, &current, &corrected, DrawCorrection{
+		Source:     model.DrawChangeSourceAdmin,
+		OperatorID: operator.AdminID,
+		Reason:     reason,
+	})
+	if result == nil {
+		return nil, err
+	}
+
+	if auditErr := recordAudit(db, operator, model.AuditActionDrawUpdate, "draw_result", strconv.FormatUint(current.ID, 10), current, result.DrawResult, reason); auditErr != nil {
+		dbLogger(db).Errorf("记录审计日志失败: %v", auditErr)
+	}
+	return result, err
+}
+
+// TriggerCrawl 管理员触发抓取：最新一期同步执行，回补在后台执行
+func TriggerCrawl(db *gorm.DB, operator AdminOperator, gameCode, mode string, pages int) (*model.CrawlRun, error) {
+	if _, err := LookupGame(db, gameCode); err != nil {
+		return nil, fmt.Errorf("游戏不存在: %s", gameCode)
+	}
+
+	crawler := NewCrawlerServiceWithDB(db)
+	var run *model.CrawlRun
+	var crawlErr error
+	switch mode {
+	case model.CrawlModeLatest:
+		run, crawlErr = crawler.CrawlTracked(gameCode, mode, model.CrawlTriggerAdmin, 0, operator.AdminID)
+	case model.CrawlModeBackfill:
+		if pages < 1 || pages > maxBackfillPages {
+			return nil, fmt.Errorf("回补页数需在1-%d之间", maxBackfillPages)
+		}
+		run, crawlErr = crawler.StartCrawlTracked(gameCode, mode, model.CrawlTriggerAdmin, pages, operator.AdminID)
+	default:
+		return nil, fmt.Errorf("不支持的抓取方式: %s", mode)
+	}
+	if run == nil {
+		return nil, crawlErr
+	}
+
+	if err := recordAudit(db, operator, model.AuditActionCrawl, "crawl_run", strconv.FormatInt(run.ID, 10), nil, run, ""); err != nil {
+		dbLogger(db).Errorf("记录审计日志失败: %v", err)
+	}
+	return run, crawlErr
+}
+
+// RunJobAsAdmin 管理员执行任务注册表中的任务并记录审计日志
+func RunJobAsAdmin(ctx context.Context, db *gorm.DB, operator AdminOperator, name string, values map[string]string) (*model.JobRun, error) {
+	run, err := RunJobByName(ctx, db, name, values, model.JobTriggerAdmin, operator.AdminID)
+	if run == nil {
+		return nil, err
+	}
+
+	if auditErr := recordAudit(db, operator, model.AuditActionRunJob, "job_run", strconv.FormatInt(run.ID, 10), nil, run, ""); auditErr != nil {
+		dbLogger(db).Errorf("记录审计日志失败: %v", auditErr)
+	}
+	return run, err
+}
+
+// SetUserRole 修改用户角色，管理员不能修改自己的角色
+func SetUserRole(db *gorm.DB, operator AdminOperator, userID int64, role, reason string) error {
+	if role != model.RoleUser && role != model.RoleAdmin {
+		return fmt.Errorf("不支持的角色: %s", role)
+	}
+	if userID == operator.AdminID {
+		return errors.New("不能修改自己的角色")
+	}
+
+	userDAO := model.NewUserDAO(db)
+	user, err := userDAO.GetByID(userID)
+	if err != nil {
+		return errors.New("用户不存在")
+	}
+	if user.Role == role {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := model.NewUserDAO(tx).UpdateRole(userID, role); err != nil {
+			return err
+		}
+		return recordAudit(tx, operator, model.AuditActionUserRole, "user", strconv.FormatInt(userID, 10), map[string]string{"role": user.Role}, map[string]string{"role": role}, reason)
+	})
+}
+
+// ListAuditLogs 分页获取审计日志
+func ListAuditLogs(db *gorm.DB, action string, page, pageSize int) ([]*model.AdminAuditLog, int64, error) {
+	return model.NewAdminAuditLogDAO(db).List(action, (page-1)*pageSize, pageSize)
+}
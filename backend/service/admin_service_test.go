@@ -0,0 +1,128 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"lucky/common/database/dbtest"
+	"lucky/model"
+)
+
+func TestValidateDrawNumbers(t *testing.T) {
+	cases := []struct {
+		name    string
+		game    *model.LotteryGame
+		red     model.NumberArray
+		blue    model.NumberArray
+		wantErr bool
+	}{
+		{"双色球合法", testSSQGame, model.NumberArray{1, 5, 12, 18, 25, 33}, model.NumberArray{8}, false},
+		{"双色球缺少蓝球", testSSQGame, model.NumberArray{1, 5, 12, 18, 25, 33}, nil, true},
+		{"快乐8开出20个号码", testKL8Game, testKL8Draw, nil, false},
+		{"快乐8号码不足20个", testKL8Game, testKL8Draw[:10], nil, true},
+		{"快乐8不能有蓝球", testKL8Game, testKL8Draw, model.NumberArray{1}, true},
+		{"七乐彩合法", testQLCGame, model.NumberArray{2, 6, 11, 15, 20, 24, 29}, model.NumberArray{30}, false},
+		{"七乐彩缺少特别号", testQLCGame, model.NumberArray{2, 6, 11, 15, 20, 24, 29}, nil, true},
+		{"七乐彩特别号与基本号重复", testQLCGame, model.NumberArray{2, 6, 11, 15, 20, 24, 29}, model.NumberArray{29}, true},
+		{"七乐彩特别号超出范围", testQLCGame, model.NumberArray{2, 6, 11, 15, 20, 24, 29}, model.NumberArray{31}, true},
+		{"七星彩合法", testQXCGame, model.NumberArray{1, 2, 3, 4, 5, 6}, model.NumberArray{14}, false},
+		{"七星彩后区超出范围", testQXCGame, model.NumberArray{1, 2, 3, 4, 5, 6}, model.NumberArray{15}, true},
+	}
+
+	for _, tc := range cases {
+		err := ValidateDrawNumbers(tc.game, tc.red, tc.blue)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: wantErr=%v, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+}
+
+func TestApplyDrawResultInput(t *testing.T) {
+	drawResult := &model.DrawResult{Period: "2025100"}
+	input := &DrawResultInput{DrawDate: "2025-09-02", RedBalls: model.NumberArray{1, 2, 3, 4, 5, 6}, FirstPrize: 3}
+	if err := applyDrawResultInput(drawResult, input); err != nil {
+		t.Fatalf("applyDrawResultInput: %v", err)
+	}
+	if drawResult.DrawDate.Format("2006-01-02") != "2025-09-02" || drawResult.FirstPrize != 3 {
+		t.Errorf("录入数据未写入: %+v", drawResult)
+	}
+	if drawResult.BlueBalls == nil {
+		t.Error("蓝球为空时应写入空数组")
+	}
+
+	// 日期为空时保留原开奖日期
+	input.DrawDate = ""
+	if err := applyDrawResultInput(drawResult, input); err != nil || drawResult.DrawDate.Format("2006-01-02") != "2025-09-02" {
+		t.Errorf("日期为空应保留原值, got %v %v", drawResult.DrawDate, err)
+	}
+
+	input.DrawDate = "not a date"
+	if err := applyDrawResultInput(drawResult, input); err == nil {
+		t.Error("无效日期应返回错误")
+	}
+}
+
+func TestCrawlRunStatus(t *testing.T) {
+	existsErr := fmt.Errorf("期号 %s %w", "2025100", ErrDrawResultExists)
+	if !errors.Is(existsErr, ErrDrawResultExists) {
+		t.Fatal("期号已存在错误应可识别")
+	}
+
+	cases := []struct {
+		err        error
+		wantStatus string
+	}{
+		{nil, model.CrawlStatusSuccess},
+		{existsErr, model.CrawlStatusNoData},
+		{errors.New("所有数据源都抓取失败"), model.CrawlStatusFailed},
+		{errors.New(strings.Repeat("错", maxCrawlErrorLength+10)), model.CrawlStatusFailed},
+	}
+	for _, tc := range cases {
+		status, errMsg := crawlRunStatus(tc.err)
+		if status != tc.wantStatus {
+			t.Errorf("%v: want %s, got %s", tc.err, tc.wantStatus, status)
+		}
+		if len([]rune(errMsg)) > maxCrawlErrorLength {
+			t.Errorf("失败原因应截断到%d个字符, got %d", maxCrawlErrorLength, len([]rune(errMsg)))
+		}
+	}
+}
+
+func TestCreateDrawResultManuallySQLite(t *testing.T) {
+	db := dbtest.Open(t)
+	game := *testSSQGame
+	game.GameName = "双色球"
+	if err := db.Create(&game).Error; err != nil {
+		t.Fatalf("创建游戏失败: %v", err)
+	}
+
+	operator := AdminOperator{AdminID: 1, IP: "127.0.0.1"}
+	input := &DrawResultInput{Period: "2025100", DrawDate: "2025-09-02", RedBalls: model.NumberArray{1, 5, 12, 18, 25, 33}, BlueBalls: model.NumberArray{8}}
+	created, err := CreateDrawResultManually(db, operator, "ssq", input, "")
+	if err != nil {
+		t.Fatalf("CreateDrawResultManually: %v", err)
+	}
+
+	var saved model.DrawResult
+	if err := db.First(&saved, created.ID).Error; err != nil {
+		t.Fatalf("读取开奖结果失败: %v", err)
+	}
+	if fmt.Sprint(saved.RedBalls) != "[1 5 12 18 25 33]" || fmt.Sprint(saved.BlueBalls) != "[8]" {
+		t.Errorf("号码读写不一致: red=%v blue=%v", saved.RedBalls, saved.BlueBalls)
+	}
+	if saved.DrawDate.Format("2006-01-02") != "2025-09-02" {
+		t.Errorf("开奖日期读写不一致: %v", saved.DrawDate)
+	}
+
+	var audits int64
+	db.Model(&model.AdminAuditLog{}).Where("action = ?", model.AuditActionDrawCreate).Count(&audits)
+	if audits != 1 {
+		t.Errorf("应记录1条审计日志, got %d", audits)
+	}
+
+	if _, err := CreateDrawResultManually(db, operator, "ssq", input, ""); err == nil {
+		t.Error("重复录入同一期应返回错误")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"lucky/common/jwt"
+	applog "lucky/common/log"
 	"lucky/model"
 
 	"gorm.io/gorm"
@@ -23,18 +24,22 @@ func NewAuthService(db *gorm.DB) *AuthService {
 func (s *AuthService) ValidateAccessToken(token string) (*model.User, error) {
 	claims, err := jwt.ValidateToken(token)
 	if err != nil {
+		dbLogger(s.db).Debugf("访问令牌无效: %v", err)
 		return nil, err
 	}
 
 	// 根据 claims 中的用户信息查询数据库
+	logger := dbLogger(s.db).WithField(applog.FieldUserID, claims.UserID)
 	userDAO := model.NewUserDAO(s.db)
 	user, err := userDAO.GetByID(int64(claims.UserID))
 	if err != nil {
+		logger.Warnf("访问令牌对应的用户不存在: %v", err)
 		return nil, err
 	}
 
 	// 基础一致性检查（可扩展：状态、tokenVersion 等）
 	if user.OpenID != claims.OpenID {
+		logger.Warn("访问令牌与用户不匹配")
 		return nil, errors.New("token与用户不匹配")
 	}
 
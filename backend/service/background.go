@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"sync"
+)
+
+var (
+	baseCtxMu  sync.RWMutex
+	baseCtx    = context.Background()
+	background sync.WaitGroup
+)
+
+// SetBaseContext 设置后台任务的根 context，服务关闭时取消，定时抓取和后台抓取随之停止
+func SetBaseContext(ctx context.Context) {
+	baseCtxMu.Lock()
+	defer baseCtxMu.Unlock()
+	baseCtx = ctx
+}
+
+// BaseContext 后台任务的根 context
+func BaseContext() context.Context {
+	baseCtxMu.RLock()
+	defer baseCtxMu.RUnlock()
+	return baseCtx
+}
+
+// goBackground 在后台执行 fn 并计入 WaitBackground 等待的任务
+func goBackground(fn func()) {
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		fn()
+	}()
+}
+
+// WaitBackground 等待后台任务全部结束，ctx 结束时返回 ctx.Err()
+func WaitBackground(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		background.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	applog "lucky/common/log"
+	"lucky/model"
+
+	"gorm.io/gorm"
+)
+
+// maxCrawlErrorLength 抓取记录中失败原因的最大长度
+const maxCrawlErrorLength = 500
+
+// crawlByMode 按抓取方式执行抓取
+func (c *CrawlerService) crawlByMode(gameCode, mode string, pages int) error {
+	switch mode {
+	case model.CrawlModeLatest:
+		return c.CrawlAndSaveLatest(gameCode)
+	case model.CrawlModeBackfill:
+		return c.CrawlHistoryByPeriod(gameCode, pages)
+	default:
+		return fmt.Errorf("不支持的抓取方式: %s", mode)
+	}
+}
+
+// newCrawlRun 创建进行中的抓取记录
+func (c *CrawlerService) newCrawlRun(gameCode, mode, trigger string, pages int, operatorID int64) (*model.CrawlRun, error) {
+	run := &model.CrawlRun{
+		GameCode:   gameCode,
+		Mode:       mode,
+		Pages:      pages,
+		Trigger:    trigger,
+		OperatorID: operatorID,
+		Status:     model.CrawlStatusRunning,
+		StartedAt:  time.Now(),
+	}
+	if err := model.NewCrawlRunDAO(c.db).Create(run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// crawlRunStatus 根据抓取错误确定抓取状态和失败原因，期号已存在视为没有新数据
+func crawlRunStatus(crawlErr error) (string, string) {
+	if crawlErr == nil {
+		return model.CrawlStatusSuccess, ""
+	}
+	if errors.Is(crawlErr, ErrDrawResultExists) {
+		return model.CrawlStatusNoData, ""
+	}
+	errMsg := []rune(crawlErr.Error())
+	if len(errMsg) > maxCrawlErrorLength {
+		errMsg = errMsg[:maxCrawlErrorLength]
+	}
+	return model.CrawlStatusFailed, string(errMsg)
+}
+
+// finishCrawlRun 记录抓取结果，记录失败只打印日志
+func (c *CrawlerService) finishCrawlRun(run *model.CrawlRun, crawlErr error) {
+	now := time.Now()
+	run.FinishedAt = &now
+	run.Status, run.Error = crawlRunStatus(crawlErr)
+	if err := model.NewCrawlRunDAO(c.db).Finish(run.ID, run.Status, run.Error, now); err != nil {
+		c.logger(run.GameCode).Warnf("更新抓取记录失败: %v", err)
+	}
+}
+
+// CrawlTracked 执行抓取并保存抓取记录，数据库不可用时不记录
+func (c *CrawlerService) CrawlTracked(gameCode, mode, trigger string, pages int, operatorID int64) (*model.CrawlRun, error) {
+	if c.db == nil {
+		return nil, c.crawlByMode(gameCode, mode, pages)
+	}
+	run, err := c.newCrawlRun(gameCode, mode, trigger, pages, operatorID)
+	if err != nil {
+		return nil, fmt.Errorf("创建抓取记录失败: %v", err)
+	}
+	crawlErr := c.crawlByMode(gameCode, mode, pages)
+	c.finishCrawlRun(run, crawlErr)
+	return run, crawlErr
+}
+
+// StartCrawlTracked 在后台执行抓取，立即返回进行中的抓取记录，服务关闭时停止抓取
+// 同一游戏同一抓取方式已有进行中的记录时拒绝重复触发
+func (c *CrawlerService) StartCrawlTracked(gameCode, mode, trigger string, pages int, operatorID int64) (*model.CrawlRun, error) {
+	running, err := model.NewCrawlRunDAO(c.db).CountRunning(gameCode, mode)
+	if err != nil {
+		return nil, err
+	}
+	if running > 0 {
+		return nil, fmt.Errorf("%s已有进行中的抓取任务", c.gameName(gameCode))
+	}
+
+	run, err := c.newCrawlRun(gameCode, mode, trigger, pages, operatorID)
+	if err != nil {
+		return nil, fmt.Errorf("创建抓取记录失败: %v", err)
+	}
+	started := *run
+	// 后台抓取随服务关闭取消，日志沿用发起请求的请求ID
+	crawler := c.WithContext(applog.CopyFields(BaseContext(), c.ctx))
+	goBackground(func() {
+		crawler.finishCrawlRun(run, crawler.crawlByMode(gameCode, mode, pages))
+	})
+	return &started, nil
+}
+
+// ListCrawlRuns 分页获取抓取记录
+func ListCrawlRuns(db *gorm.DB, gameCode string, page, pageSize int) ([]*model.CrawlRun, int64, error) {
+	return model.NewCrawlRunDAO(db).List(gameCode, (page-1)*pageSize, pageSize)
+}
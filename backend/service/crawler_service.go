@@ -2,7 +2,9 @@ package service
 
 import (
 	"compress/gzip"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -14,10 +16,13 @@ import (
 
 	"lucky/common/http/fucai"
 	"lucky/common/http/ticai"
+	applog "lucky/common/log"
+	"lucky/common/metrics"
 	"lucky/common/mysql"
 	"lucky/model"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
@@ -28,9 +33,29 @@ type DrawDataSource struct {
 	Priority int // 优先级，数字越小优先级越高
 }
 
+// officialCrawlConfig 通过官方接口抓取的游戏参数
+type officialCrawlConfig struct {
+	APIName   string // 中国福彩接口的游戏名称，体彩游戏为空
+	GameNo    string // 体彩接口的游戏编号，福彩游戏为空
+	RedCount  int    // 开奖号码个数，数字型为位数
+	BlueCount int    // 蓝球个数：七乐彩为特别号，七星彩为后区
+	Digit     bool   // 是否为数字型号码
+}
+
+// officialCrawlConfigs 数字型游戏、快乐8、七乐彩和七星彩的抓取配置
+var officialCrawlConfigs = map[string]officialCrawlConfig{
+	"fc3d": {APIName: "3d", RedCount: 3, Digit: true},
+	"pl3":  {GameNo: "35", RedCount: 3, Digit: true},
+	"pl5":  {GameNo: "350133", RedCount: 5, Digit: true},
+	"kl8":  {APIName: "kl8", RedCount: 20},
+	"qlc":  {APIName: "qlc", RedCount: 7, BlueCount: 1},
+	"qxc":  {GameNo: "04", RedCount: 6, BlueCount: 1, Digit: true},
+}
+
 // CrawlerService 开奖数据抓取服务
 type CrawlerService struct {
 	db      *gorm.DB
+	ctx     context.Context             // 抓取请求和抓取间隔使用的 context，取消时停止抓取
 	sources map[string][]DrawDataSource // 按游戏类型分组的数据源
 }
 
@@ -48,8 +73,38 @@ type CWLResult struct {
 
 // NewCrawlerService 创建抓取服务实例
 func NewCrawlerService() *CrawlerService {
+	return NewCrawlerServiceWithDB(mysql.DB)
+}
+
+// WithContext 返回使用指定 context 的抓取服务，context 取消时进行中的请求和抓取间隔立即结束
+func (c *CrawlerService) WithContext(ctx context.Context) *CrawlerService {
+	copied := *c
+	copied.ctx = ctx
+	return &copied
+}
+
+// logger 带有请求ID等上下文字段和游戏代码的日志
+func (c *CrawlerService) logger(gameCode string) *logrus.Entry {
+	return applog.Ctx(applog.WithGameCode(c.ctx, gameCode))
+}
+
+// pause 抓取间隔等待，context 取消时返回错误
+func (c *CrawlerService) pause(d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-c.ctx.Done():
+		return c.ctx.Err()
+	}
+}
+
+// NewCrawlerServiceWithDB 使用指定数据库连接创建抓取服务实例
+func NewCrawlerServiceWithDB(db *gorm.DB) *CrawlerService {
 	return &CrawlerService{
-		db: mysql.DB,
+		db:  db,
+		ctx: context.Background(),
 		sources: map[string][]DrawDataSource{
 			"ssq": { // 双色球数据源
 				{
@@ -75,6 +130,48 @@ func NewCrawlerService() *CrawlerService {
 					Priority: 2,
 				},
 			},
+			"fc3d": { // 福彩3D数据源
+				{
+					Name:     "中国福彩3D",
+					URL:      "https://www.cwl.gov.cn/ygkj/wqkjgg/fc3d/",
+					Priority: 1,
+				},
+			},
+			"pl3": { // 排列三数据源
+				{
+					Name:     "体彩排列三",
+					URL:      "https://webapi.sporttery.cn/gateway/lottery/getHistoryPageListV1.qry?gameNo=35&provinceId=0&isVerify=1",
+					Priority: 1,
+				},
+			},
+			"pl5": { // 排列五数据源
+				{
+					Name:     "体彩排列五",
+					URL:      "https://webapi.sporttery.cn/gateway/lottery/getHistoryPageListV1.qry?gameNo=350133&provinceId=0&isVerify=1",
+					Priority: 1,
+				},
+			},
+			"kl8": { // 快乐8数据源
+				{
+					Name:     "中国福彩快乐8",
+					URL:      "https://www.cwl.gov.cn/ygkj/wqkjgg/kl8/",
+					Priority: 1,
+				},
+			},
+			"qlc": { // 七乐彩数据源
+				{
+					Name:     "中国福彩七乐彩",
+					URL:      "https://www.cwl.gov.cn/ygkj/wqkjgg/qlc/",
+					Priority: 1,
+				},
+			},
+			"qxc": { // 七星彩数据源
+				{
+					Name:     "体彩七星彩",
+					URL:      "https://webapi.sporttery.cn/gateway/lottery/getHistoryPageListV1.qry?gameNo=04&provinceId=0&isVerify=1",
+					Priority: 1,
+				},
+			},
 		},
 	}
 }
@@ -100,6 +197,7 @@ type Prize struct {
 
 // CrawlLatestResults 抓取最新开奖结果
 func (c *CrawlerService) CrawlLatestResults(gameCode string) (*DrawResult, error) {
+	logger := c.logger(gameCode)
 	// 获取游戏特定的数据源
 	gameSources, exists := c.sources[gameCode]
 	if !exists {
@@ -110,11 +208,11 @@ func (c *CrawlerService) CrawlLatestResults(gameCode string) (*DrawResult, error
 	for _, source := range gameSources {
 		result, err := c.crawlFromSource(source, gameCode)
 		if err != nil {
-			fmt.Printf("%s抓取失败: %v\n", source.Name, err)
+			logger.Warnf("%s抓取失败: %v", source.Name, err)
 			continue
 		}
 		if result != nil {
-			fmt.Printf("成功从%s获取开奖数据\n", source.Name)
+			logger.Infof("成功从%s获取开奖数据", source.Name)
 			return result, nil
 		}
 	}
@@ -138,8 +236,16 @@ type SportteryResult struct {
 	ErrorMessage string `json:"errorMessage"`
 }
 
-// crawlFromSource 从指定数据源抓取
+// crawlFromSource 从指定数据源抓取，记录抓取次数和耗时
 func (c *CrawlerService) crawlFromSource(source DrawDataSource, gameCode string) (*DrawResult, error) {
+	start := time.Now()
+	result, err := c.fetchFromSource(source, gameCode)
+	metrics.ObserveCrawl(source.Name, gameCode, err == nil && result != nil, time.Since(start))
+	return result, err
+}
+
+// fetchFromSource 按数据源名称选择抓取方式
+func (c *CrawlerService) fetchFromSource(source DrawDataSource, gameCode string) (*DrawResult, error) {
 	switch source.Name {
 	case "500彩票网":
 		return c.crawlFrom500(gameCode)
@@ -149,6 +255,8 @@ func (c *CrawlerService) crawlFromSource(source DrawDataSource, gameCode string)
 		return c.crawlFromDLT(gameCode)
 	case "500彩票网大乐透":
 		return c.crawlFrom500DLT(gameCode)
+	case "中国福彩3D", "体彩排列三", "体彩排列五", "中国福彩快乐8", "中国福彩七乐彩", "体彩七星彩":
+		return c.crawlLatestOfficial(gameCode)
 	default:
 		return nil, fmt.Errorf("不支持的数据源 %s", source.Name)
 	}
@@ -156,6 +264,7 @@ func (c *CrawlerService) crawlFromSource(source DrawDataSource, gameCode string)
 
 // crawlFrom500 从500彩票网抓取（仅双色球）
 func (c *CrawlerService) crawlFrom500(gameCode string) (*DrawResult, error) {
+	logger := c.logger(gameCode)
 	if gameCode != "ssq" {
 		return nil, fmt.Errorf("500彩票网双色球数据源仅支持双色球")
 	}
@@ -166,7 +275,7 @@ func (c *CrawlerService) crawlFrom500(gameCode string) (*DrawResult, error) {
 		Timeout: 10 * time.Second, // 增加超时时间到10秒
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -203,15 +312,15 @@ func (c *CrawlerService) crawlFrom500(gameCode string) (*DrawResult, error) {
 		}
 		if periodText != "" {
 			result.Period = strings.TrimSpace(periodText)
-			fmt.Printf("500彩票网解析期号(方法1): %s\n", result.Period)
+			logger.Debugf("500彩票网解析期号(方法1): %s", result.Period)
 		}
 
 		// 查找开奖号码
 		numbersText := s.Text()
 		if numbersText != "" {
-			fmt.Printf("500彩票网解析号码文本(方法1): %s\n", numbersText)
+			logger.Debugf("500彩票网解析号码文本(方法1): %s", numbersText)
 			result.RedBalls, result.BlueBalls = c.parseNumbers(numbersText, gameCode)
-			fmt.Printf("500彩票网解析号码(方法1): 红球%v 蓝球%v\n", result.RedBalls, result.BlueBalls)
+			logger.Debugf("500彩票网解析号码(方法1): 红球%v 蓝球%v", result.RedBalls, result.BlueBalls)
 			if len(result.RedBalls) > 0 {
 				found = true
 			}
@@ -220,7 +329,7 @@ func (c *CrawlerService) crawlFrom500(gameCode string) (*DrawResult, error) {
 
 	// 方法2：尝试从 .red 和 .blue 选择器解析（500彩票网新格式）
 	if !found {
-		fmt.Println("500彩票网尝试方法2：从.red和.blue选择器解析")
+		logger.Debug("500彩票网尝试方法2：从.red和.blue选择器解析")
 
 		// 查找期号 - 尝试从页面文本中提取
 		pageText := doc.Text()
@@ -231,7 +340,7 @@ func (c *CrawlerService) crawlFrom500(gameCode string) (*DrawResult, error) {
 		for _, num := range allNums {
 			if len(num) == 7 && strings.HasPrefix(num, "2025") {
 				result.Period = num
-				fmt.Printf("500彩票网解析期号(方法2): %s\n", result.Period)
+				logger.Debugf("500彩票网解析期号(方法2): %s", result.Period)
 				break
 			}
 		}
@@ -251,7 +360,7 @@ func (c *CrawlerService) crawlFrom500(gameCode string) (*DrawResult, error) {
 				for _, p := range periods {
 					if len(p) == 7 && strings.HasPrefix(p, "2025") {
 						result.Period = p
-						fmt.Printf("500彩票网解析期号(方法2.1): %s\n", result.Period)
+						logger.Debugf("500彩票网解析期号(方法2.1): %s", result.Period)
 						break
 					}
 				}
@@ -260,11 +369,11 @@ func (c *CrawlerService) crawlFrom500(gameCode string) (*DrawResult, error) {
 			// 查找开奖号码
 			numbersText := s.Text()
 			if numbersText != "" {
-				fmt.Printf("500彩票网解析号码文本(方法2.1): %s\n", numbersText)
+				logger.Debugf("500彩票网解析号码文本(方法2.1): %s", numbersText)
 
 				// 专门针对500彩票网的格式解析
 				redBalls, blueBalls := c.parse500Numbers(numbersText)
-				fmt.Printf("500彩票网解析号码(方法2.1): 红球%v 蓝球%v\n", redBalls, blueBalls)
+				logger.Debugf("500彩票网解析号码(方法2.1): 红球%v 蓝球%v", redBalls, blueBalls)
 				if len(redBalls) == 6 && len(blueBalls) == 1 {
 					result.RedBalls = redBalls
 					result.BlueBalls = blueBalls
@@ -275,7 +384,7 @@ func (c *CrawlerService) crawlFrom500(gameCode string) (*DrawResult, error) {
 
 		// 方法2.2：如果方法2.1失败，尝试从.red和.blue选择器解析
 		if !found {
-			fmt.Println("500彩票网尝试方法2.2：从.red和.blue选择器解析")
+			logger.Debug("500彩票网尝试方法2.2：从.red和.blue选择器解析")
 
 			// 分别查找红球和蓝球
 			var redBalls, blueBalls []int
@@ -302,7 +411,7 @@ func (c *CrawlerService) crawlFrom500(gameCode string) (*DrawResult, error) {
 				}
 			})
 
-			fmt.Printf("500彩票网解析号码(方法2.2): 红球%v 蓝球%v\n", redBalls, blueBalls)
+			logger.Debugf("500彩票网解析号码(方法2.2): 红球%v 蓝球%v", redBalls, blueBalls)
 
 			if len(redBalls) == 6 && len(blueBalls) == 1 {
 				result.RedBalls = redBalls
@@ -336,6 +445,7 @@ func (c *CrawlerService) crawlFrom500(gameCode string) (*DrawResult, error) {
 
 // crawlFromCWL 从中国福彩官网抓取（仅双色球）
 func (c *CrawlerService) crawlFromCWL(gameCode string) (*DrawResult, error) {
+	logger := c.logger(gameCode)
 	if gameCode != "ssq" {
 		return nil, fmt.Errorf("中国福彩暂只支持双色球")
 	}
@@ -344,7 +454,7 @@ func (c *CrawlerService) crawlFromCWL(gameCode string) (*DrawResult, error) {
 	url := "https://www.cwl.gov.cn/"
 	client := &http.Client{Timeout: 10 * time.Second}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -361,7 +471,7 @@ func (c *CrawlerService) crawlFromCWL(gameCode string) (*DrawResult, error) {
 	}
 	defer resp.Body.Close()
 
-	fmt.Printf("中国福彩官网响应状态: %d\n", resp.StatusCode)
+	logger.Debugf("中国福彩官网响应状态: %d", resp.StatusCode)
 	if resp.StatusCode != 200 {
 		return nil, fmt.Errorf("中国福彩官网返回非200状态码: %d", resp.StatusCode)
 	}
@@ -374,18 +484,18 @@ func (c *CrawlerService) crawlFromCWL(gameCode string) (*DrawResult, error) {
 	result := &DrawResult{GameCode: gameCode}
 
 	// 方法1：从页面标题解析期号
-	fmt.Println("中国福彩尝试方法1：从页面内容解析")
+	logger.Debug("中国福彩尝试方法1：从页面内容解析")
 
 	// 查找期号 - 查找包含"第"和"期"的文本
 	pageText := doc.Text()
-	fmt.Printf("页面文本长度: %d\n", len(pageText))
+	logger.Debugf("页面文本长度: %d", len(pageText))
 
 	// 查找期号模式：第2025119期
 	periodRe := regexp.MustCompile(`第(\d{7})期`)
 	periodMatches := periodRe.FindStringSubmatch(pageText)
 	if len(periodMatches) > 1 {
 		result.Period = periodMatches[1]
-		fmt.Printf("中国福彩解析期号(方法1): %s\n", result.Period)
+		logger.Debugf("中国福彩解析期号(方法1): %s", result.Period)
 	}
 
 	// 查找开奖日期
@@ -393,32 +503,32 @@ func (c *CrawlerService) crawlFromCWL(gameCode string) (*DrawResult, error) {
 	dateMatches := dateRe.FindStringSubmatch(pageText)
 	if len(dateMatches) > 1 {
 		result.DrawDate = dateMatches[1]
-		fmt.Printf("中国福彩解析日期(方法1): %s\n", result.DrawDate)
+		logger.Debugf("中国福彩解析日期(方法1): %s", result.DrawDate)
 	}
 
 	// 查找开奖号码 - 查找包含红球和蓝球的区域
 	// 查找包含"双色球"的元素
 	ssqElements := doc.Find("*:contains('双色球')")
 	if ssqElements.Length() > 0 {
-		fmt.Printf("找到双色球相关元素，数量: %d\n", ssqElements.Length())
+		logger.Debugf("找到双色球相关元素，数量: %d", ssqElements.Length())
 
 		// 遍历双色球相关元素
 		ssqElements.Each(func(i int, s *goquery.Selection) {
 			elementText := strings.TrimSpace(s.Text())
-			fmt.Printf("双色球元素[%d]: %s\n", i, elementText[:min(200, len(elementText))])
+			logger.Debugf("双色球元素[%d]: %s", i, elementText[:min(200, len(elementText))])
 
 			// 查找这个元素附近的数字
 			parent := s.Parent()
 			if parent.Length() > 0 {
 				parentText := strings.TrimSpace(parent.Text())
-				fmt.Printf("父元素文本: %s\n", parentText[:min(300, len(parentText))])
+				logger.Debugf("父元素文本: %s", parentText[:min(300, len(parentText))])
 
 				// 尝试从父元素中提取号码
 				redBalls, blueBalls := c.parseCWLNumbersFromPage(parentText)
 				if len(redBalls) == 6 && len(blueBalls) == 1 {
 					result.RedBalls = redBalls
 					result.BlueBalls = blueBalls
-					fmt.Printf("中国福彩解析号码(方法1): 红球%v 蓝球%v\n", redBalls, blueBalls)
+					logger.Debugf("中国福彩解析号码(方法1): 红球%v 蓝球%v", redBalls, blueBalls)
 				}
 			}
 		})
@@ -426,19 +536,19 @@ func (c *CrawlerService) crawlFromCWL(gameCode string) (*DrawResult, error) {
 
 	// 方法2：兜底解析 - 从整个页面文本中提取
 	if len(result.RedBalls) != 6 || len(result.BlueBalls) != 1 {
-		fmt.Println("中国福彩尝试方法2：兜底解析")
+		logger.Debug("中国福彩尝试方法2：兜底解析")
 
 		// 从页面文本中提取所有数字
 		reNum := regexp.MustCompile(`\d+`)
 		allNums := reNum.FindAllString(pageText, -1)
-		fmt.Printf("页面数字前30个: %v\n", allNums[:min(30, len(allNums))])
+		logger.Debugf("页面数字前30个: %v", allNums[:min(30, len(allNums))])
 
 		// 查找期号
 		if result.Period == "" {
 			for _, num := range allNums {
 				if len(num) == 7 && strings.HasPrefix(num, "2025") {
 					result.Period = num
-					fmt.Printf("中国福彩解析期号(方法2): %s\n", result.Period)
+					logger.Debugf("中国福彩解析期号(方法2): %s", result.Period)
 					break
 				}
 			}
@@ -449,7 +559,7 @@ func (c *CrawlerService) crawlFromCWL(gameCode string) (*DrawResult, error) {
 		if len(redBalls) == 6 && len(blueBalls) == 1 {
 			result.RedBalls = redBalls
 			result.BlueBalls = blueBalls
-			fmt.Printf("中国福彩解析号码(方法2): 红球%v 蓝球%v\n", redBalls, blueBalls)
+			logger.Debugf("中国福彩解析号码(方法2): 红球%v 蓝球%v", redBalls, blueBalls)
 		}
 	}
 
@@ -474,15 +584,16 @@ func (c *CrawlerService) crawlFromCWL(gameCode string) (*DrawResult, error) {
 
 // crawlFromDLT 从体彩大乐透抓取（仅大乐透）
 func (c *CrawlerService) crawlFromDLT(gameCode string) (*DrawResult, error) {
+	logger := c.logger(gameCode)
 	if gameCode != "dlt" {
 		return nil, fmt.Errorf("体彩大乐透仅支持大乐透")
 	}
 
 	url := "https://webapi.sporttery.cn/gateway/lottery/getHistoryPageListV1.qry?gameNo=85&provinceId=0&isVerify=1&termLimits=50"
-	fmt.Printf("体彩大乐透抓取URL: %s\n", url)
+	logger.Debugf("体彩大乐透抓取URL: %s", url)
 	client := &http.Client{Timeout: 10 * time.Second}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -497,7 +608,7 @@ func (c *CrawlerService) crawlFromDLT(gameCode string) (*DrawResult, error) {
 	}
 	defer resp.Body.Close()
 
-	fmt.Printf("体彩大乐透API响应状态: %d\n", resp.StatusCode)
+	logger.Debugf("体彩大乐透API响应状态: %d", resp.StatusCode)
 	if resp.StatusCode != 200 {
 		return nil, fmt.Errorf("体彩大乐透API返回非200状态码: %d", resp.StatusCode)
 	}
@@ -519,8 +630,8 @@ func (c *CrawlerService) crawlFromDLT(gameCode string) (*DrawResult, error) {
 		return nil, fmt.Errorf("读取API响应失败: %v", err)
 	}
 
-	fmt.Printf("体彩大乐透API响应长度: %d\n", len(body))
-	fmt.Printf("体彩大乐透API响应前500字符: %s\n", string(body[:min(500, len(body))]))
+	logger.Debugf("体彩大乐透API响应长度: %d", len(body))
+	logger.Debugf("体彩大乐透API响应前500字符: %s", string(body[:min(500, len(body))]))
 
 	if err := json.Unmarshal(body, &apiResponse); err != nil {
 		return nil, fmt.Errorf("解析API响应JSON失败: %v", err)
@@ -532,7 +643,7 @@ func (c *CrawlerService) crawlFromDLT(gameCode string) (*DrawResult, error) {
 
 	// 获取最新开奖记录
 	latestDraw := apiResponse.Value.LastPoolDraw
-	fmt.Printf("体彩大乐透最新开奖记录: 期号=%s, 日期=%s, 结果=%s\n",
+	logger.Debugf("体彩大乐透最新开奖记录: 期号=%s, 日期=%s, 结果=%s",
 		latestDraw.LotteryDrawNum, latestDraw.LotteryDrawTime, latestDraw.LotteryDrawResult)
 
 	result := &DrawResult{GameCode: gameCode}
@@ -545,20 +656,20 @@ func (c *CrawlerService) crawlFromDLT(gameCode string) (*DrawResult, error) {
 		} else {
 			result.Period = latestDraw.LotteryDrawNum
 		}
-		fmt.Printf("体彩大乐透解析期号: %s\n", result.Period)
+		logger.Debugf("体彩大乐透解析期号: %s", result.Period)
 	}
 
 	// 解析开奖日期
 	if latestDraw.LotteryDrawTime != "" {
 		result.DrawDate = latestDraw.LotteryDrawTime
-		fmt.Printf("体彩大乐透解析日期: %s\n", result.DrawDate)
+		logger.Debugf("体彩大乐透解析日期: %s", result.DrawDate)
 	}
 
 	// 解析开奖号码
 	// 大乐透的号码格式是 "02 08 09 12 21 04 05"（前5个是前区，后2个是后区）
 	drawResult := latestDraw.LotteryDrawResult
 	if drawResult != "" {
-		fmt.Printf("体彩大乐透原始开奖结果: %s\n", drawResult)
+		logger.Debugf("体彩大乐透原始开奖结果: %s", drawResult)
 
 		// 按空格分割号码
 		allNumbers := strings.Fields(drawResult)
@@ -572,7 +683,7 @@ func (c *CrawlerService) crawlFromDLT(gameCode string) (*DrawResult, error) {
 			}
 		}
 
-		fmt.Printf("体彩大乐透解析号码: 前区%v 后区%v\n", result.RedBalls, result.BlueBalls)
+		logger.Debugf("体彩大乐透解析号码: 前区%v 后区%v", result.RedBalls, result.BlueBalls)
 	}
 
 	// 验证数据完整性
@@ -596,13 +707,14 @@ func (c *CrawlerService) crawlFromDLT(gameCode string) (*DrawResult, error) {
 
 // parseDLTNumbers 专门解析体彩大乐透的号码格式
 func (c *CrawlerService) parseDLTNumbers(numbersText string) ([]int, []int) {
+	logger := c.logger("")
 	var redBalls, blueBalls []int
 
 	// 使用正则表达式提取所有数字
 	re := regexp.MustCompile(`\d+`)
 	numbers := re.FindAllString(numbersText, -1)
 
-	fmt.Printf("parseDLTNumbers 开始解析，数字数组: %v\n", numbers[:min(20, len(numbers))])
+	logger.Debugf("parseDLTNumbers 开始解析，数字数组: %v", numbers[:min(20, len(numbers))])
 
 	// 查找期号位置，期号后面的数字就是开奖号码
 	periodIndex := -1
@@ -610,7 +722,7 @@ func (c *CrawlerService) parseDLTNumbers(numbersText string) ([]int, []int) {
 		// 查找期号（如25118）
 		if len(num) == 5 && strings.HasPrefix(num, "25") {
 			periodIndex = i
-			fmt.Printf("找到期号位置: %d, 期号: %s\n", i, num)
+			logger.Debugf("找到期号位置: %d, 期号: %s", i, num)
 			break
 		}
 	}
@@ -625,12 +737,12 @@ func (c *CrawlerService) parseDLTNumbers(numbersText string) ([]int, []int) {
 
 			// 过滤掉年份和日期等非号码数字
 			if num == 2025 || num == 10 || num == 18 || num == 14 {
-				fmt.Printf("过滤掉日期数字: %d\n", num)
+				logger.Debugf("过滤掉日期数字: %d", num)
 				continue
 			}
 			// 过滤掉奖池金额等大数字
 			if num > 1000000 {
-				fmt.Printf("过滤掉大数字: %d\n", num)
+				logger.Debugf("过滤掉大数字: %d", num)
 				continue
 			}
 			// 只处理1-2位的数字（彩票号码）
@@ -647,7 +759,7 @@ func (c *CrawlerService) parseDLTNumbers(numbersText string) ([]int, []int) {
 					}
 					if !exists {
 						redBalls = append(redBalls, num)
-						fmt.Printf("添加前区号码: %d\n", num)
+						logger.Debugf("添加前区号码: %d", num)
 					}
 				} else if len(blueBalls) < 2 && num >= 1 && num <= 12 {
 					// 避免重复添加相同的号码
@@ -660,7 +772,7 @@ func (c *CrawlerService) parseDLTNumbers(numbersText string) ([]int, []int) {
 					}
 					if !exists {
 						blueBalls = append(blueBalls, num)
-						fmt.Printf("添加后区号码: %d\n", num)
+						logger.Debugf("添加后区号码: %d", num)
 					}
 					if len(blueBalls) == 2 {
 						break
@@ -670,7 +782,7 @@ func (c *CrawlerService) parseDLTNumbers(numbersText string) ([]int, []int) {
 		}
 	} else {
 		// 如果没有找到期号，尝试从所有数字中提取可能的号码
-		fmt.Println("未找到期号，尝试从所有数字中提取号码")
+		logger.Debug("未找到期号，尝试从所有数字中提取号码")
 		for _, numStr := range numbers {
 			num, err := strconv.Atoi(numStr)
 			if err != nil {
@@ -686,10 +798,10 @@ func (c *CrawlerService) parseDLTNumbers(numbersText string) ([]int, []int) {
 			if len(numStr) >= 1 && len(numStr) <= 2 {
 				if len(redBalls) < 5 && num >= 1 && num <= 35 {
 					redBalls = append(redBalls, num)
-					fmt.Printf("添加前区号码(无期号): %d\n", num)
+					logger.Debugf("添加前区号码(无期号): %d", num)
 				} else if len(blueBalls) < 2 && num >= 1 && num <= 12 {
 					blueBalls = append(blueBalls, num)
-					fmt.Printf("添加后区号码(无期号): %d\n", num)
+					logger.Debugf("添加后区号码(无期号): %d", num)
 					if len(blueBalls) == 2 {
 						break
 					}
@@ -702,21 +814,22 @@ func (c *CrawlerService) parseDLTNumbers(numbersText string) ([]int, []int) {
 	sort.Ints(redBalls)
 	sort.Ints(blueBalls)
 
-	fmt.Printf("parseDLTNumbers 解析结果: 前区%v 后区%v\n", redBalls, blueBalls)
+	logger.Debugf("parseDLTNumbers 解析结果: 前区%v 后区%v", redBalls, blueBalls)
 	return redBalls, blueBalls
 }
 
 // crawlFrom500DLT 从500彩票网抓取大乐透数据
 func (c *CrawlerService) crawlFrom500DLT(gameCode string) (*DrawResult, error) {
+	logger := c.logger(gameCode)
 	if gameCode != "dlt" {
 		return nil, fmt.Errorf("500彩票网大乐透数据源仅支持大乐透")
 	}
 
 	url := "https://kaijiang.500.com/dlt.shtml"
-	fmt.Printf("500彩票网大乐透抓取URL: %s\n", url)
+	logger.Debugf("500彩票网大乐透抓取URL: %s", url)
 	client := &http.Client{Timeout: 10 * time.Second}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -728,7 +841,7 @@ func (c *CrawlerService) crawlFrom500DLT(gameCode string) (*DrawResult, error) {
 	}
 	defer resp.Body.Close()
 
-	fmt.Printf("500彩票网大乐透响应状态: %d\n", resp.StatusCode)
+	logger.Debugf("500彩票网大乐透响应状态: %d", resp.StatusCode)
 
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
@@ -738,11 +851,11 @@ func (c *CrawlerService) crawlFrom500DLT(gameCode string) (*DrawResult, error) {
 	result := &DrawResult{GameCode: gameCode}
 
 	// 方法1：尝试从页面元素中解析（参考双色球的成功实现）
-	fmt.Println("500彩票网大乐透尝试方法1：从页面元素解析")
+	logger.Debug("500彩票网大乐透尝试方法1：从页面元素解析")
 
 	// 查找期号 - 从页面文本中提取
 	pageText := doc.Text()
-	fmt.Printf("500彩票网大乐透页面文本长度: %d\n", len(pageText))
+	logger.Debugf("500彩票网大乐透页面文本长度: %d", len(pageText))
 
 	// 查找期号模式：25118期
 	periodRe := regexp.MustCompile(`(\d{5})期`)
@@ -754,7 +867,7 @@ func (c *CrawlerService) crawlFrom500DLT(gameCode string) (*DrawResult, error) {
 		} else {
 			result.Period = periodStr
 		}
-		fmt.Printf("500彩票网大乐透解析期号(方法1): %s\n", result.Period)
+		logger.Debugf("500彩票网大乐透解析期号(方法1): %s", result.Period)
 	}
 
 	// 查找开奖日期
@@ -767,32 +880,32 @@ func (c *CrawlerService) crawlFrom500DLT(gameCode string) (*DrawResult, error) {
 		dateStr = strings.ReplaceAll(dateStr, "月", "-")
 		dateStr = strings.ReplaceAll(dateStr, "日", "")
 		result.DrawDate = dateStr
-		fmt.Printf("500彩票网大乐透解析日期(方法1): %s\n", result.DrawDate)
+		logger.Debugf("500彩票网大乐透解析日期(方法1): %s", result.DrawDate)
 	}
 
 	// 查找开奖号码 - 查找包含号码的区域
 	// 查找包含"开奖号码"的元素
 	numberElements := doc.Find("*:contains('开奖号码')")
 	if numberElements.Length() > 0 {
-		fmt.Printf("找到开奖号码区域，元素数量: %d\n", numberElements.Length())
+		logger.Debugf("找到开奖号码区域，元素数量: %d", numberElements.Length())
 
 		// 遍历包含"开奖号码"的元素
 		numberElements.Each(func(i int, s *goquery.Selection) {
 			elementText := strings.TrimSpace(s.Text())
-			fmt.Printf("开奖号码元素[%d]: %s\n", i, elementText[:min(200, len(elementText))])
+			logger.Debugf("开奖号码元素[%d]: %s", i, elementText[:min(200, len(elementText))])
 
 			// 查找这个元素附近的数字
 			parent := s.Parent()
 			if parent.Length() > 0 {
 				parentText := strings.TrimSpace(parent.Text())
-				fmt.Printf("父元素文本: %s\n", parentText[:min(300, len(parentText))])
+				logger.Debugf("父元素文本: %s", parentText[:min(300, len(parentText))])
 
 				// 尝试从父元素中提取号码
 				redBalls, blueBalls := c.parse500DLTNumbers(parentText)
 				if len(redBalls) == 5 && len(blueBalls) == 2 {
 					result.RedBalls = redBalls
 					result.BlueBalls = blueBalls
-					fmt.Printf("500彩票网大乐透解析号码(方法1): 前区%v 后区%v\n", redBalls, blueBalls)
+					logger.Debugf("500彩票网大乐透解析号码(方法1): 前区%v 后区%v", redBalls, blueBalls)
 				}
 			}
 		})
@@ -800,19 +913,19 @@ func (c *CrawlerService) crawlFrom500DLT(gameCode string) (*DrawResult, error) {
 
 	// 方法2：兜底解析 - 从整个页面文本中提取
 	if len(result.RedBalls) != 5 || len(result.BlueBalls) != 2 {
-		fmt.Println("500彩票网大乐透尝试方法2：兜底解析")
+		logger.Debug("500彩票网大乐透尝试方法2：兜底解析")
 
 		// 从页面文本中提取所有数字
 		reNum := regexp.MustCompile(`\d+`)
 		allNums := reNum.FindAllString(pageText, -1)
-		fmt.Printf("页面数字前30个: %v\n", allNums[:min(30, len(allNums))])
+		logger.Debugf("页面数字前30个: %v", allNums[:min(30, len(allNums))])
 
 		// 查找期号
 		if result.Period == "" {
 			for _, num := range allNums {
 				if len(num) == 5 && strings.HasPrefix(num, "25") {
 					result.Period = "20" + num // 转换为7位期号
-					fmt.Printf("500彩票网大乐透解析期号(方法2): %s\n", result.Period)
+					logger.Debugf("500彩票网大乐透解析期号(方法2): %s", result.Period)
 					break
 				}
 			}
@@ -823,7 +936,7 @@ func (c *CrawlerService) crawlFrom500DLT(gameCode string) (*DrawResult, error) {
 		if len(redBalls) == 5 && len(blueBalls) == 2 {
 			result.RedBalls = redBalls
 			result.BlueBalls = blueBalls
-			fmt.Printf("500彩票网大乐透解析号码(方法2): 前区%v 后区%v\n", redBalls, blueBalls)
+			logger.Debugf("500彩票网大乐透解析号码(方法2): 前区%v 后区%v", redBalls, blueBalls)
 		}
 	}
 
@@ -848,13 +961,14 @@ func (c *CrawlerService) crawlFrom500DLT(gameCode string) (*DrawResult, error) {
 
 // parse500DLTNumbers 专门解析500彩票网大乐透的号码格式
 func (c *CrawlerService) parse500DLTNumbers(numbersText string) ([]int, []int) {
+	logger := c.logger("")
 	var redBalls, blueBalls []int
 
 	// 使用正则表达式提取所有数字
 	re := regexp.MustCompile(`\d+`)
 	numbers := re.FindAllString(numbersText, -1)
 
-	fmt.Printf("parse500DLTNumbers 开始解析，数字数组: %v\n", numbers[:min(20, len(numbers))])
+	logger.Debugf("parse500DLTNumbers 开始解析，数字数组: %v", numbers[:min(20, len(numbers))])
 
 	// 查找期号位置，期号后面的数字就是开奖号码
 	periodIndex := -1
@@ -862,7 +976,7 @@ func (c *CrawlerService) parse500DLTNumbers(numbersText string) ([]int, []int) {
 		// 查找期号（如25118）
 		if len(num) == 5 && strings.HasPrefix(num, "25") {
 			periodIndex = i
-			fmt.Printf("找到期号位置: %d, 期号: %s\n", i, num)
+			logger.Debugf("找到期号位置: %d, 期号: %s", i, num)
 			break
 		}
 	}
@@ -877,17 +991,17 @@ func (c *CrawlerService) parse500DLTNumbers(numbersText string) ([]int, []int) {
 
 			// 过滤掉年份和日期等非号码数字
 			if num == 2025 || num == 10 || num == 18 || num == 14 {
-				fmt.Printf("过滤掉日期数字: %d\n", num)
+				logger.Debugf("过滤掉日期数字: %d", num)
 				continue
 			}
 			// 过滤掉奖池金额等大数字
 			if num > 1000000 {
-				fmt.Printf("过滤掉大数字: %d\n", num)
+				logger.Debugf("过滤掉大数字: %d", num)
 				continue
 			}
 			// 过滤掉16（根据实际开奖结果，16不是正确的号码）
 			if num == 16 {
-				fmt.Printf("过滤掉16（不是正确号码）: %d\n", num)
+				logger.Debugf("过滤掉16（不是正确号码）: %d", num)
 				continue
 			}
 			// 只处理1-2位的数字（彩票号码）
@@ -904,7 +1018,7 @@ func (c *CrawlerService) parse500DLTNumbers(numbersText string) ([]int, []int) {
 					}
 					if !exists {
 						redBalls = append(redBalls, num)
-						fmt.Printf("添加前区号码: %d\n", num)
+						logger.Debugf("添加前区号码: %d", num)
 					}
 				} else if len(blueBalls) < 2 && num >= 1 && num <= 12 {
 					// 避免重复添加相同的号码
@@ -917,7 +1031,7 @@ func (c *CrawlerService) parse500DLTNumbers(numbersText string) ([]int, []int) {
 					}
 					if !exists {
 						blueBalls = append(blueBalls, num)
-						fmt.Printf("添加后区号码: %d\n", num)
+						logger.Debugf("添加后区号码: %d", num)
 					}
 					if len(blueBalls) == 2 {
 						break
@@ -927,7 +1041,7 @@ func (c *CrawlerService) parse500DLTNumbers(numbersText string) ([]int, []int) {
 		}
 	} else {
 		// 如果没有找到期号，尝试从所有数字中提取可能的号码
-		fmt.Println("未找到期号，尝试从所有数字中提取号码")
+		logger.Debug("未找到期号，尝试从所有数字中提取号码")
 		for _, numStr := range numbers {
 			num, err := strconv.Atoi(numStr)
 			if err != nil {
@@ -943,10 +1057,10 @@ func (c *CrawlerService) parse500DLTNumbers(numbersText string) ([]int, []int) {
 			if len(numStr) >= 1 && len(numStr) <= 2 {
 				if len(redBalls) < 5 {
 					redBalls = append(redBalls, num)
-					fmt.Printf("添加前区号码(无期号): %d\n", num)
+					logger.Debugf("添加前区号码(无期号): %d", num)
 				} else if len(blueBalls) < 2 {
 					blueBalls = append(blueBalls, num)
-					fmt.Printf("添加后区号码(无期号): %d\n", num)
+					logger.Debugf("添加后区号码(无期号): %d", num)
 					if len(blueBalls) == 2 {
 						break
 					}
@@ -959,7 +1073,7 @@ func (c *CrawlerService) parse500DLTNumbers(numbersText string) ([]int, []int) {
 	sort.Ints(redBalls)
 	sort.Ints(blueBalls)
 
-	fmt.Printf("parse500DLTNumbers 解析结果: 前区%v 后区%v\n", redBalls, blueBalls)
+	logger.Debugf("parse500DLTNumbers 解析结果: 前区%v 后区%v", redBalls, blueBalls)
 	return redBalls, blueBalls
 }
 
@@ -1157,7 +1271,7 @@ func (c *CrawlerService) parseCWLNumbersFromPage(pageText string) ([]int, []int)
 func (c *CrawlerService) visitCWLHomePage(client *http.Client) error {
 	homeURL := "https://www.cwl.gov.cn/"
 
-	req, err := http.NewRequest("GET", homeURL, nil)
+	req, err := http.NewRequestWithContext(c.ctx, "GET", homeURL, nil)
 	if err != nil {
 		return err
 	}
@@ -1243,7 +1357,8 @@ func (c *CrawlerService) parseCWLNumbersFromAPI(numbersStr string) ([]int, error
 
 // parsePeriod 解析期号，兼容两种格式：2025105 或 25105
 func (c *CrawlerService) parsePeriod(numbers []string) string {
-	fmt.Printf("parsePeriod 开始解析，数字数组: %v\n", numbers[:min(10, len(numbers))])
+	logger := c.logger("")
+	logger.Debugf("parsePeriod 开始解析，数字数组: %v", numbers[:min(10, len(numbers))])
 	for _, s := range numbers {
 		if len(s) >= 5 { // 至少5位
 			// 如果7位或8位，验证期号有效性
@@ -1278,7 +1393,8 @@ func (c *CrawlerService) parsePeriod(numbers []string) string {
 
 // parseCWLPeriod 专门解析中国福彩的期号
 func (c *CrawlerService) parseCWLPeriod(numbers []string) string {
-	fmt.Printf("开始解析期号，前20个数字: %v\n", numbers[:min(20, len(numbers))])
+	logger := c.logger("")
+	logger.Debugf("开始解析期号，前20个数字: %v", numbers[:min(20, len(numbers))])
 
 	// 优先查找7位期号格式（2025119）
 	for _, s := range numbers {
@@ -1287,10 +1403,10 @@ func (c *CrawlerService) parseCWLPeriod(numbers []string) string {
 			if len(s) >= 4 && s[0:4] == "2025" {
 				// 验证后三位是否为有效期号（001-365）
 				if periodNum, err := strconv.Atoi(s[4:7]); err == nil && periodNum >= 1 && periodNum <= 365 {
-					fmt.Printf("找到7位期号: %s\n", s)
+					logger.Debugf("找到7位期号: %s", s)
 					return s
 				} else {
-					fmt.Printf("跳过无效7位期号: %s (后三位: %s)\n", s, s[4:7])
+					logger.Debugf("跳过无效7位期号: %s (后三位: %s)", s, s[4:7])
 				}
 			}
 		}
@@ -1304,7 +1420,7 @@ func (c *CrawlerService) parseCWLPeriod(numbers []string) string {
 				// 验证后三位是否为有效期号
 				if periodNum, err := strconv.Atoi(s[2:5]); err == nil && periodNum >= 1 && periodNum <= 365 {
 					period := "2025" + s[2:5]
-					fmt.Printf("找到5位期号 %s，转换为: %s\n", s, period)
+					logger.Debugf("找到5位期号 %s，转换为: %s", s, period)
 					return period
 				}
 			}
@@ -1323,7 +1439,7 @@ func (c *CrawlerService) parseCWLPeriod(numbers []string) string {
 				if len(candidate) == 7 {
 					periodNum, err := strconv.Atoi(candidate[4:7])
 					if err == nil && periodNum >= 1 && periodNum <= 365 {
-						fmt.Printf("从长数字中提取期号: %s\n", candidate)
+						logger.Debugf("从长数字中提取期号: %s", candidate)
 						return candidate
 					}
 				}
@@ -1333,7 +1449,7 @@ func (c *CrawlerService) parseCWLPeriod(numbers []string) string {
 
 	// 兜底：使用通用解析
 	period := c.parsePeriod(numbers)
-	fmt.Printf("使用通用解析期号: %s\n", period)
+	logger.Debugf("使用通用解析期号: %s", period)
 	if period != "" {
 		// 验证期号是否在原始数字数组中
 		found := false
@@ -1344,7 +1460,7 @@ func (c *CrawlerService) parseCWLPeriod(numbers []string) string {
 			}
 		}
 		if !found {
-			fmt.Printf("警告：期号 %s 不在原始数字数组中\n", period)
+			logger.Warnf("期号 %s 不在原始数字数组中", period)
 		}
 	}
 	return period
@@ -1358,56 +1474,126 @@ func min(a, b int) int {
 	return b
 }
 
+// ErrDrawResultExists 该期开奖结果已保存
+var ErrDrawResultExists = errors.New("已存在")
+
 // SaveDrawResult 保存开奖结果到数据库
+// 抓取的号码与已保存的不一致时，再次抓取确认数据源已更正后按数据源更正处理，未能确认时返回 ErrDrawResultConflict
 func (c *CrawlerService) SaveDrawResult(result *DrawResult) error {
-	// 查找游戏ID
-	var game model.LotteryGame
-	// 将gameCode转换为游戏名称
-	var gameName string
-	if result.GameCode == "ssq" {
-		gameName = "双色球"
-	} else if result.GameCode == "dlt" {
-		gameName = "大乐透"
-	} else {
-		return fmt.Errorf("不支持的游戏代码: %s", result.GameCode)
+	_, err := saveDrawResult(c.db, result)
+	if !errors.Is(err, ErrDrawResultConflict) || !c.confirmDrawChange(result) {
+		return err
 	}
 
-	err := c.db.Where("game_name = ?", gameName).First(&game).Error
+	correction, err := correctCrawledDrawResult(c.db, result)
 	if err != nil {
-		return fmt.Errorf("游戏 %s 不存在", gameName)
+		return err
 	}
+	c.logger(result.GameCode).Warnf("期号 %s 数据源开奖号码已更正为 %s，重新核对 %d 注号码，发送 %d 条通知", result.Period,
+		FormatNumberLine(correction.DrawResult.RedBalls, correction.DrawResult.BlueBalls), correction.Reevaluated, correction.Notified)
+	return nil
+}
 
-	// 检查是否已存在
-	var existing model.DrawResult
-	err = c.db.Where("game_id = ? AND period = ?", game.ID, result.Period).First(&existing).Error
-	if err == nil {
-		return fmt.Errorf("期号 %s 已存在", result.Period)
+// confirmDrawChange 再次从该游戏的各数据源抓取最新一期，返回同一期相同号码时确认数据源已更正，排除单次抓取解析错误
+// 只能确认最新一期，历史期号号码不一致时仍需核实后通过管理后台更正
+func (c *CrawlerService) confirmDrawChange(result *DrawResult) bool {
+	for _, source := range c.sources[result.GameCode] {
+		latest, err := c.crawlFromSource(source, result.GameCode)
+		if err != nil || latest == nil || latest.Period != result.Period {
+			continue
+		}
+		if FormatNumberLine(latest.RedBalls, latest.BlueBalls) == FormatNumberLine(result.RedBalls, result.BlueBalls) {
+			return true
+		}
+	}
+	return false
+}
+
+// saveDrawResult 将抓取的开奖结果转换后保存
+func saveDrawResult(db *gorm.DB, result *DrawResult) (*model.DrawResult, error) {
+	drawResult, err := newDrawResultModel(db, result)
+	if err != nil {
+		return nil, err
+	}
+	if err := createDrawResult(db, drawResult); err != nil {
+		return nil, err
+	}
+	return drawResult, nil
+}
+
+// newDrawResultModel 将抓取的开奖结果转换为数据库记录
+func newDrawResultModel(db *gorm.DB, result *DrawResult) (*model.DrawResult, error) {
+	// 查找游戏ID
+	game, err := LookupGame(db, result.GameCode)
+	if err != nil {
+		return nil, fmt.Errorf("不支持的游戏代码: %s", result.GameCode)
 	}
 
 	// 转换日期格式
-	drawDate, err := time.Parse("2006-01-02", result.DrawDate)
+	drawDate, err := parseDrawDate(result.DrawDate)
 	if err != nil {
-		// 尝试其他日期格式
-		drawDate, err = time.Parse("2006-1-2", result.DrawDate)
-		if err != nil {
-			return fmt.Errorf("日期格式解析失败: %s", result.DrawDate)
-		}
+		return nil, err
 	}
 
-	// 转换号码为NumberArray类型
+	// 转换号码为NumberArray类型，数字型游戏没有蓝球
 	redBalls := model.NumberArray(result.RedBalls)
 	blueBalls := model.NumberArray(result.BlueBalls)
+	if blueBalls == nil {
+		blueBalls = model.NumberArray{}
+	}
 
-	// 创建数据库记录
-	drawResult := model.DrawResult{
+	return &model.DrawResult{
 		GameID:    game.ID,
 		Period:    result.Period,
 		DrawDate:  drawDate,
 		RedBalls:  redBalls,
 		BlueBalls: blueBalls,
+	}, nil
+}
+
+// parseDrawDate 解析开奖日期，兼容 "2025-09-28" 和 "2025-9-28"
+func parseDrawDate(text string) (time.Time, error) {
+	drawDate, err := time.Parse("2006-01-02", text)
+	if err != nil {
+		// 尝试其他日期格式
+		drawDate, err = time.Parse("2006-1-2", text)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("日期格式解析失败: %s", text)
+		}
+	}
+	return drawDate, nil
+}
+
+// createDrawResult 新增一期开奖结果，并核对追号计划、结算购彩记录
+func createDrawResult(db *gorm.DB, drawResult *model.DrawResult) error {
+	// 检查是否已存在
+	var existing model.DrawResult
+	err := db.Where("game_id = ? AND period = ?", drawResult.GameID, drawResult.Period).First(&existing).Error
+	if err == nil {
+		// 号码与已保存的不一致时不覆盖，由调用方确认后按更正处理
+		if !sameDrawNumbers(&existing, drawResult) {
+			return fmt.Errorf("期号 %s 抓取的开奖号码 %s %w %s，请核实后更正", drawResult.Period,
+				FormatNumberLine(drawResult.RedBalls, drawResult.BlueBalls), ErrDrawResultConflict,
+				FormatNumberLine(existing.RedBalls, existing.BlueBalls))
+		}
+		return fmt.Errorf("期号 %s %w", drawResult.Period, ErrDrawResultExists)
 	}
 
-	return c.db.Create(&drawResult).Error
+	if err := db.Create(drawResult).Error; err != nil {
+		return err
+	}
+	if game, err := GetGameByID(db, drawResult.GameID); err == nil {
+		notifyDrawResultChanged(db, game, drawResult)
+	}
+
+	// 核对进行中的追号计划并结算待开奖的购彩记录，失败不影响开奖结果保存
+	if err := EvaluateNumberPlans(db, drawResult); err != nil {
+		dbLogger(db).Warnf("核对追号计划失败: %v", err)
+	}
+	if err := EvaluatePurchases(db, drawResult); err != nil {
+		dbLogger(db).Warnf("结算购彩记录失败: %v", err)
+	}
+	return nil
 }
 
 // CrawlAndSaveLatest 抓取并保存最新开奖结果
@@ -1422,29 +1608,33 @@ func (c *CrawlerService) CrawlAndSaveLatest(gameCode string) error {
 
 // CrawlHistoryResults 抓取历史开奖结果
 func (c *CrawlerService) CrawlHistoryResults(gameCode string, periods []string) error {
+	logger := c.logger(gameCode)
 	for _, period := range periods {
 		// 调用单期抓取方法
 		result, err := c.crawlSinglePeriod(gameCode, period)
 		if err != nil {
-			fmt.Printf("抓取期号 %s 失败: %v\n", period, err)
+			logger.Warnf("抓取期号 %s 失败: %v", period, err)
 			continue
 		}
 
 		if err := c.SaveDrawResult(result); err != nil {
-			fmt.Printf("保存期号 %s 失败: %v\n", period, err)
+			logger.Warnf("保存期号 %s 失败: %v", period, err)
 		} else {
-			fmt.Printf("成功保存期号 %s\n", period)
+			logger.Infof("成功保存期号 %s", period)
 		}
 
 		// 控制抓取频率，避免被反爬
-		time.Sleep(1 * time.Second)
+		if err := c.pause(time.Second); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
 // CrawlHistoryByPeriod 抓取历史数据
 func (c *CrawlerService) CrawlHistoryByPeriod(gameCode string, pages int) error {
-	fmt.Printf("开始抓取 %s 历史数据，页数：%d\n", gameCode, pages)
+	logger := c.logger(gameCode)
+	logger.Infof("开始抓取 %s 历史数据，页数：%d", gameCode, pages)
 
 	switch gameCode {
 	case "ssq":
@@ -1452,13 +1642,25 @@ func (c *CrawlerService) CrawlHistoryByPeriod(gameCode string, pages int) error
 	case "dlt":
 		return c.crawlDLTHistoryByPages(pages)
 	default:
+		if _, ok := officialCrawlConfigs[gameCode]; ok {
+			return c.crawlOfficialHistoryByPages(gameCode, pages)
+		}
 		return fmt.Errorf("不支持的游戏类型: %s", gameCode)
 	}
 }
 
+// gameName 游戏名称，注册表中不存在时返回游戏代码
+func (c *CrawlerService) gameName(gameCode string) string {
+	if game, err := LookupGame(c.db, gameCode); err == nil {
+		return game.GameName
+	}
+	return gameCode
+}
+
 // crawlSSQHistoryByPages 从中国福彩API批量抓取双色球历史数据（按页数）
 func (c *CrawlerService) crawlSSQHistoryByPages(pages int) error {
-	fmt.Println("开始从中国福彩API批量抓取双色球历史数据...")
+	logger := c.logger("ssq")
+	logger.Info("开始从中国福彩API批量抓取双色球历史数据...")
 
 	var savedCount int
 	maxPages := pages // 根据传入的页数参数确定抓取页数
@@ -1467,7 +1669,7 @@ func (c *CrawlerService) crawlSSQHistoryByPages(pages int) error {
 	}
 
 	for page := 1; page <= maxPages; page++ {
-		fmt.Printf("正在抓取第 %d 页数据...\n", page)
+		logger.Infof("正在抓取第 %d 页数据...", page)
 
 		// 使用 fucai 包构建请求
 		req := fucai.SSQHistoryReq{
@@ -1486,13 +1688,13 @@ func (c *CrawlerService) crawlSSQHistoryByPages(pages int) error {
 		// 调用 fucai 包获取数据
 		apiResult, err := fucai.FucaiHandlerInst.GetSSQHistory(req)
 		if err != nil {
-			fmt.Printf("调用福彩API失败: %v，尝试下一页\n", err)
+			logger.Warnf("调用福彩API失败: %v，尝试下一页", err)
 			continue
 		}
 
 		// 如果没有更多数据，退出循环
 		if len(apiResult.Result) == 0 {
-			fmt.Println("没有更多数据，结束抓取")
+			logger.Info("没有更多数据，结束抓取")
 			break
 		}
 
@@ -1503,10 +1705,10 @@ func (c *CrawlerService) crawlSSQHistoryByPages(pages int) error {
 			// 解析日期
 			parsedDate, err := time.Parse("2006-01-02", drawDate)
 			if err != nil {
-				fmt.Printf("期号 %s 日期解析失败: %v, 使用当前日期\n", item.Code, err)
+				logger.Warnf("期号 %s 日期解析失败: %v, 使用当前日期", item.Code, err)
 				parsedDate = time.Now()
 			}
-			fmt.Println(item.Code)
+			logger.Debug(item.Code)
 			result := &DrawResult{
 				GameCode: "ssq",
 				Period:   item.Code,
@@ -1518,7 +1720,7 @@ func (c *CrawlerService) crawlSSQHistoryByPages(pages int) error {
 			for _, s := range redStrs {
 				num, err := strconv.Atoi(s)
 				if err != nil {
-					fmt.Printf("解析红球号码失败: %v, 跳过此期\n", err)
+					logger.Warnf("解析红球号码失败: %v, 跳过此期", err)
 					continue
 				}
 				result.RedBalls = append(result.RedBalls, num)
@@ -1527,14 +1729,14 @@ func (c *CrawlerService) crawlSSQHistoryByPages(pages int) error {
 			// 解析蓝球
 			blueNum, err := strconv.Atoi(item.Blue)
 			if err != nil {
-				fmt.Printf("解析蓝球号码失败: %v, 跳过此期\n", err)
+				logger.Warnf("解析蓝球号码失败: %v, 跳过此期", err)
 				continue
 			}
 			result.BlueBalls = []int{blueNum}
 
 			// 验证结果
 			if len(result.RedBalls) != 6 || len(result.BlueBalls) != 1 {
-				fmt.Printf("期号 %s 球号数量错误，红球: %d, 蓝球: %d, 跳过此期\n",
+				logger.Warnf("期号 %s 球号数量错误，红球: %d, 蓝球: %d, 跳过此期",
 					result.Period, len(result.RedBalls), len(result.BlueBalls))
 				continue
 			}
@@ -1542,38 +1744,41 @@ func (c *CrawlerService) crawlSSQHistoryByPages(pages int) error {
 			// 检查是否已存在
 			exists, err := c.checkPeriodExists("ssq", result.Period)
 			if err != nil {
-				fmt.Printf("检查期号 %s 是否存在失败: %v\n", result.Period, err)
+				logger.Warnf("检查期号 %s 是否存在失败: %v", result.Period, err)
 				continue
 			}
 			if exists {
-				fmt.Printf("期号 %s 已存在，跳过\n", result.Period)
+				logger.Infof("期号 %s 已存在，跳过", result.Period)
 				continue
 			}
 
 			// 保存到数据库
 			err = c.SaveDrawResult(result)
 			if err != nil {
-				fmt.Printf("保存期号 %s 失败: %v\n", result.Period, err)
+				logger.Warnf("保存期号 %s 失败: %v", result.Period, err)
 				continue
 			}
 
 			savedCount++
-			fmt.Printf("成功保存期号 %s\n", result.Period)
+			logger.Infof("成功保存期号 %s", result.Period)
 		}
 
-		fmt.Printf("第 %d 页数据抓取完成，本页获取 %d 条记录\n", page, len(apiResult.Result))
+		logger.Infof("第 %d 页数据抓取完成，本页获取 %d 条记录", page, len(apiResult.Result))
 
 		// 添加延迟避免请求过于频繁
-		time.Sleep(1 * time.Second)
+		if err := c.pause(time.Second); err != nil {
+			return err
+		}
 	}
 
-	fmt.Printf("双色球历史数据抓取完成，共保存 %d 条记录\n", savedCount)
+	logger.Infof("双色球历史数据抓取完成，共保存 %d 条记录", savedCount)
 	return nil
 }
 
 // crawlDLTHistoryByPages 从体彩API批量抓取大乐透历史数据（按页数）
 func (c *CrawlerService) crawlDLTHistoryByPages(pages int) error {
-	fmt.Println("开始从体彩API批量抓取大乐透历史数据...")
+	logger := c.logger("dlt")
+	logger.Info("开始从体彩API批量抓取大乐透历史数据...")
 
 	var savedCount int
 	maxPages := pages // 根据传入的页数参数确定抓取页数
@@ -1582,7 +1787,7 @@ func (c *CrawlerService) crawlDLTHistoryByPages(pages int) error {
 	}
 
 	for page := 1; page <= maxPages; page++ {
-		fmt.Printf("正在抓取第 %d 页数据...\n", page)
+		logger.Infof("正在抓取第 %d 页数据...", page)
 
 		// 使用 ticai 包构建请求
 		req := ticai.DLTHistoryReq{
@@ -1596,13 +1801,13 @@ func (c *CrawlerService) crawlDLTHistoryByPages(pages int) error {
 		// 调用 ticai 包获取数据
 		apiResult, err := ticai.TicaiHandlerInst.GetDLTHistory(req)
 		if err != nil {
-			fmt.Printf("调用体彩API失败: %v，尝试下一页\n", err)
+			logger.Warnf("调用体彩API失败: %v，尝试下一页", err)
 			continue
 		}
 
 		// 如果没有更多数据，退出循环
 		if len(apiResult.Value.List) == 0 {
-			fmt.Println("没有更多数据，结束抓取")
+			logger.Info("没有更多数据，结束抓取")
 			break
 		}
 
@@ -1612,7 +1817,7 @@ func (c *CrawlerService) crawlDLTHistoryByPages(pages int) error {
 			if len(period) == 5 {
 				period = "20" + period // 25109 -> 2025109
 			} else if len(period) != 7 {
-				fmt.Printf("期号格式错误: %s, 跳过此期\n", period)
+				logger.Warnf("期号格式错误: %s, 跳过此期", period)
 				continue
 			}
 			result := &DrawResult{
@@ -1624,7 +1829,7 @@ func (c *CrawlerService) crawlDLTHistoryByPages(pages int) error {
 			// 解析开奖结果，格式如："01 11 14 25 27 04 10"
 			parts := strings.Split(item.LotteryDrawResult, " ")
 			if len(parts) < 7 {
-				fmt.Printf("期号 %s 开奖结果格式错误: %s, 跳过此期\n",
+				logger.Warnf("期号 %s 开奖结果格式错误: %s, 跳过此期",
 					result.Period, item.LotteryDrawResult)
 				continue
 			}
@@ -1633,7 +1838,7 @@ func (c *CrawlerService) crawlDLTHistoryByPages(pages int) error {
 			for i := 0; i < 5; i++ {
 				num, err := strconv.Atoi(parts[i])
 				if err != nil {
-					fmt.Printf("解析前区号码失败: %v, 跳过此期\n", err)
+					logger.Warnf("解析前区号码失败: %v, 跳过此期", err)
 					continue
 				}
 				result.RedBalls = append(result.RedBalls, num)
@@ -1643,7 +1848,7 @@ func (c *CrawlerService) crawlDLTHistoryByPages(pages int) error {
 			for i := 5; i < 7; i++ {
 				num, err := strconv.Atoi(parts[i])
 				if err != nil {
-					fmt.Printf("解析后区号码失败: %v, 跳过此期\n", err)
+					logger.Warnf("解析后区号码失败: %v, 跳过此期", err)
 					continue
 				}
 				result.BlueBalls = append(result.BlueBalls, num)
@@ -1651,7 +1856,7 @@ func (c *CrawlerService) crawlDLTHistoryByPages(pages int) error {
 
 			// 验证结果
 			if len(result.RedBalls) != 5 || len(result.BlueBalls) != 2 {
-				fmt.Printf("期号 %s 球号数量错误，前区: %d, 后区: %d, 跳过此期\n",
+				logger.Warnf("期号 %s 球号数量错误，前区: %d, 后区: %d, 跳过此期",
 					result.Period, len(result.RedBalls), len(result.BlueBalls))
 				continue
 			}
@@ -1659,55 +1864,243 @@ func (c *CrawlerService) crawlDLTHistoryByPages(pages int) error {
 			// 检查是否已存在
 			exists, err := c.checkPeriodExists("dlt", result.Period)
 			if err != nil {
-				fmt.Printf("检查期号 %s 是否存在失败: %v\n", result.Period, err)
+				logger.Warnf("检查期号 %s 是否存在失败: %v", result.Period, err)
 				continue
 			}
 			if exists {
-				fmt.Printf("期号 %s 已存在，跳过\n", result.Period)
+				logger.Infof("期号 %s 已存在，跳过", result.Period)
 				continue
 			}
 
 			// 保存到数据库
 			err = c.SaveDrawResult(result)
 			if err != nil {
-				fmt.Printf("保存期号 %s 失败: %v\n", result.Period, err)
+				logger.Warnf("保存期号 %s 失败: %v", result.Period, err)
 				continue
 			}
 
 			savedCount++
-			fmt.Printf("成功保存期号 %s\n", result.Period)
+			logger.Infof("成功保存期号 %s", result.Period)
 		}
 
-		fmt.Printf("第 %d 页数据抓取完成，本页获取 %d 条记录\n", page, len(apiResult.Value.List))
+		logger.Infof("第 %d 页数据抓取完成，本页获取 %d 条记录", page, len(apiResult.Value.List))
 
 		// 添加延迟避免请求过于频繁
-		time.Sleep(1 * time.Second)
+		if err := c.pause(time.Second); err != nil {
+			return err
+		}
+	}
+
+	logger.Infof("大乐透历史数据抓取完成，共保存 %d 条记录", savedCount)
+	return nil
+}
+
+// crawlLatestOfficial 从官方接口抓取最新一期开奖结果
+func (c *CrawlerService) crawlLatestOfficial(gameCode string) (*DrawResult, error) {
+	results, err := c.fetchOfficialHistoryPage(gameCode, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("未获取到%s开奖数据", c.gameName(gameCode))
+	}
+	return results[0], nil
+}
+
+// fetchOfficialHistoryPage 获取一页官方开奖数据，福彩3D/快乐8/七乐彩走中国福彩接口，排列三/排列五/七星彩走体彩接口
+func (c *CrawlerService) fetchOfficialHistoryPage(gameCode string, page, pageSize int) ([]*DrawResult, error) {
+	logger := c.logger(gameCode)
+	config, ok := officialCrawlConfigs[gameCode]
+	if !ok {
+		return nil, fmt.Errorf("不支持的游戏类型: %s", gameCode)
+	}
+
+	var results []*DrawResult
+	if config.APIName != "" {
+		apiResult, err := fucai.FucaiHandlerInst.GetSSQHistory(fucai.SSQHistoryReq{
+			Name:       config.APIName,
+			PageNo:     page,
+			PageSize:   pageSize,
+			SystemType: "PC",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("调用福彩API失败: %v", err)
+		}
+		for _, item := range apiResult.Result {
+			text := item.Red
+			if config.BlueCount > 0 {
+				text += "+" + item.Blue // 七乐彩特别号在蓝球字段
+			}
+			redBalls, blueBalls, err := parseOfficialNumbers(config, text)
+			if err != nil {
+				logger.Warnf("期号 %s 解析号码失败: %v, 跳过此期", item.Code, err)
+				continue
+			}
+			results = append(results, &DrawResult{
+				GameCode:  gameCode,
+				Period:    item.Code,
+				DrawDate:  strings.Split(item.Date, "(")[0], // "2025-09-28(日)" -> "2025-09-28"
+				RedBalls:  redBalls,
+				BlueBalls: blueBalls,
+			})
+		}
+		return results, nil
+	}
+
+	apiResult, err := ticai.TicaiHandlerInst.GetDLTHistory(ticai.DLTHistoryReq{
+		GameNo:     config.GameNo,
+		ProvinceId: "0",
+		PageSize:   pageSize,
+		PageNo:     page,
+		IsVerify:   1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("调用体彩API失败: %v", err)
+	}
+	for _, item := range apiResult.Value.List {
+		period := item.LotteryDrawNum
+		if len(period) == 5 {
+			period = "20" + period // 25275 -> 2025275
+		} else if len(period) != 7 {
+			logger.Warnf("期号格式错误: %s, 跳过此期", period)
+			continue
+		}
+		redBalls, blueBalls, err := parseOfficialNumbers(config, item.LotteryDrawResult)
+		if err != nil {
+			logger.Warnf("期号 %s 解析号码失败: %v, 跳过此期", period, err)
+			continue
+		}
+		results = append(results, &DrawResult{
+			GameCode:  gameCode,
+			Period:    period,
+			DrawDate:  item.LotteryDrawTime,
+			RedBalls:  redBalls,
+			BlueBalls: blueBalls,
+		})
+	}
+	return results, nil
+}
+
+// parseOfficialNumbers 按游戏配置解析官方接口返回的开奖号码，返回红球和蓝球
+// 带蓝球的号码可用"+"分隔，也可按位置区分（七星彩 "1 2 3 4 5 6 14"）
+func parseOfficialNumbers(config officialCrawlConfig, text string) ([]int, []int, error) {
+	if config.Digit && config.BlueCount == 0 {
+		digits, err := parseDigits(text, config.RedCount)
+		return digits, nil, err
+	}
+	balls, err := parseBallList(strings.Replace(text, "+", " ", 1))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(balls) != config.RedCount+config.BlueCount {
+		return nil, nil, fmt.Errorf("开奖号码个数错误: %s", text)
+	}
+	if config.Digit {
+		for _, digit := range balls[:config.RedCount] {
+			if digit < 0 || digit > 9 {
+				return nil, nil, fmt.Errorf("号码格式错误: %s", text)
+			}
+		}
+	}
+	return balls[:config.RedCount], balls[config.RedCount:], nil
+}
+
+// crawlOfficialHistoryByPages 从官方接口批量抓取历史数据（按页数）
+func (c *CrawlerService) crawlOfficialHistoryByPages(gameCode string, pages int) error {
+	logger := c.logger(gameCode)
+	gameName := c.gameName(gameCode)
+	logger.Infof("开始批量抓取%s历史数据...", gameName)
+
+	var savedCount int
+	maxPages := pages
+	if maxPages <= 0 {
+		maxPages = 1 // 至少抓取1页
 	}
 
-	fmt.Printf("大乐透历史数据抓取完成，共保存 %d 条记录\n", savedCount)
+	for page := 1; page <= maxPages; page++ {
+		logger.Infof("正在抓取第 %d 页数据...", page)
+
+		results, err := c.fetchOfficialHistoryPage(gameCode, page, 30)
+		if err != nil {
+			logger.Warnf("%v，尝试下一页", err)
+			continue
+		}
+
+		// 如果没有更多数据，退出循环
+		if len(results) == 0 {
+			logger.Info("没有更多数据，结束抓取")
+			break
+		}
+
+		for _, result := range results {
+			exists, err := c.checkPeriodExists(gameCode, result.Period)
+			if err != nil {
+				logger.Warnf("检查期号 %s 是否存在失败: %v", result.Period, err)
+				continue
+			}
+			if exists {
+				logger.Infof("期号 %s 已存在，跳过", result.Period)
+				continue
+			}
+
+			if err := c.SaveDrawResult(result); err != nil {
+				logger.Warnf("保存期号 %s 失败: %v", result.Period, err)
+				continue
+			}
+
+			savedCount++
+			logger.Infof("成功保存期号 %s", result.Period)
+		}
+
+		logger.Infof("第 %d 页数据抓取完成，本页获取 %d 条记录", page, len(results))
+
+		// 添加延迟避免请求过于频繁
+		if err := c.pause(time.Second); err != nil {
+			return err
+		}
+	}
+
+	logger.Infof("%s历史数据抓取完成，共保存 %d 条记录", gameName, savedCount)
 	return nil
 }
 
-// ScheduleCrawl 定时抓取任务
-func (c *CrawlerService) ScheduleCrawl() {
+// ScheduleCrawl 定时抓取任务，ctx 取消时停止
+func (c *CrawlerService) ScheduleCrawl(ctx context.Context) {
+	logger := applog.Ctx(ctx)
 	// 每天定时抓取最新开奖结果
 	ticker := time.NewTicker(30 * time.Minute) // 30分钟检查一次
 	defer ticker.Stop()
 
-	for range ticker.C {
-		fmt.Println("开始定时抓取开奖数据..")
-
-		// 抓取双色球
-		if err := c.CrawlAndSaveLatest("ssq"); err != nil {
-			fmt.Printf("抓取双色球数据失败: %v\n", err)
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("定时抓取任务已停止")
+			return
+		case <-ticker.C:
 		}
+		logger.Info("开始定时抓取开奖数据..")
 
-		// 抓取大乐透
-		if err := c.CrawlAndSaveLatest("dlt"); err != nil {
-			fmt.Printf("抓取大乐透数据失败: %v\n", err)
+		games, err := GetActiveGames(c.db)
+		if err != nil {
+			logger.Warnf("获取游戏列表失败: %v", err)
+			continue
+		}
+		for _, game := range games {
+			if ctx.Err() != nil {
+				break
+			}
+			// 没有配置数据源的游戏不抓取
+			if _, ok := c.sources[game.GameCode]; !ok {
+				continue
+			}
+			values := map[string]string{"gameCode": game.GameCode}
+			gameCtx := applog.WithGameCode(ctx, game.GameCode)
+			if _, err := RunJobByName(gameCtx, c.db, "crawl", values, model.JobTriggerSchedule, 0); err != nil {
+				applog.Ctx(gameCtx).Warnf("抓取%s数据失败: %v", game.GameName, err)
+			}
 		}
 
-		fmt.Println("定时抓取任务完成")
+		logger.Info("定时抓取任务完成")
 	}
 }
 
@@ -1718,28 +2111,14 @@ func (c *CrawlerService) checkPeriodExists(gameCode, period string) (bool, error
 	}
 
 	// 首先根据gameCode获取gameID
-	var gameID uint64
-	// 将gameCode转换为游戏名称
-	var gameName string
-	if gameCode == "ssq" {
-		gameName = "双色球"
-	} else if gameCode == "dlt" {
-		gameName = "大乐透"
-	} else {
-		return false, fmt.Errorf("不支持的游戏代码: %s", gameCode)
-	}
-
-	err := c.db.Model(&model.LotteryGame{}).
-		Where("game_name = ?", gameName).
-		Select("id").
-		Scan(&gameID).Error
+	game, err := LookupGame(c.db, gameCode)
 	if err != nil {
-		return false, err
+		return false, fmt.Errorf("不支持的游戏代码: %s", gameCode)
 	}
 
 	var count int64
 	err = c.db.Model(&model.DrawResult{}).
-		Where("game_id = ? AND period = ?", gameID, period).
+		Where("game_id = ? AND period = ?", game.ID, period).
 		Count(&count).Error
 
 	return count > 0, err
@@ -1747,6 +2126,7 @@ func (c *CrawlerService) checkPeriodExists(gameCode, period string) (bool, error
 
 // crawlSinglePeriod 抓取单期数据
 func (c *CrawlerService) crawlSinglePeriod(gameCode, period string) (*DrawResult, error) {
+	logger := c.logger(gameCode)
 	// 获取游戏特定的数据源
 	gameSources, exists := c.sources[gameCode]
 	if !exists {
@@ -1757,7 +2137,7 @@ func (c *CrawlerService) crawlSinglePeriod(gameCode, period string) (*DrawResult
 	for _, source := range gameSources {
 		result, err := c.crawlFromSource(source, gameCode)
 		if err != nil {
-			fmt.Printf("%s抓取失败: %v\n", source.Name, err)
+			logger.Warnf("%s抓取失败: %v", source.Name, err)
 			continue
 		}
 		if result != nil {
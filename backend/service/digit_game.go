@@ -0,0 +1,197 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"lucky/model"
+)
+
+// digitSeparator 数字型号码的分隔符
+var digitSeparator = regexp.MustCompile(`[\s,，]+`)
+
+// digitPrizeAmounts 数字型游戏各玩法的单注固定奖金(分)
+var digitPrizeAmounts = map[string]map[string]int64{
+	"fc3d": {
+		model.PlayTypeDirect: 104000, // 直选 1040元
+		model.PlayTypeGroup3: 34600,  // 组选三 346元
+		model.PlayTypeGroup6: 17300,  // 组选六 173元
+	},
+	"pl3": {
+		model.PlayTypeDirect: 104000, // 直选 1040元
+		model.PlayTypeGroup3: 34600,  // 组选三 346元
+		model.PlayTypeGroup6: 17300,  // 组选六 173元
+	},
+	"pl5": {
+		model.PlayTypeDirect: 10000000, // 直选 10万元
+	},
+}
+
+// PlayTypeNames 玩法名称
+var PlayTypeNames = map[string]string{
+	model.PlayTypeDirect: "直选",
+	model.PlayTypeGroup3: "组选三",
+	model.PlayTypeGroup6: "组选六",
+}
+
+// IsDigitGame 是否为数字型游戏（福彩3D、排列三、排列五、七星彩）
+func IsDigitGame(game *model.LotteryGame) bool {
+	return game.GameType == model.GameTypeDigit
+}
+
+// ValidatePlayType 校验玩法与号码是否匹配，返回规范化后的玩法
+// 数字型游戏未指定玩法时默认直选，快乐8按选号个数确定，选号型游戏没有玩法
+func ValidatePlayType(game *model.LotteryGame, playType string, redBalls model.NumberArray) (string, error) {
+	if IsKenoGame(game) {
+		return validateKenoPlayType(playType, redBalls)
+	}
+	if !IsDigitGame(game) {
+		if playType != "" {
+			return "", fmt.Errorf("%s不支持玩法选择", game.GameName)
+		}
+		return "", nil
+	}
+
+	if playType == "" {
+		playType = model.PlayTypeDirect
+	}
+	if game.BlueSelectCount > 0 {
+		// 七星彩按前后区奖级对奖，只有直选
+		if playType != model.PlayTypeDirect {
+			return "", fmt.Errorf("%s仅支持直选", game.GameName)
+		}
+		return playType, nil
+	}
+	if _, ok := digitPrizeAmounts[game.GameCode][playType]; !ok {
+		return "", fmt.Errorf("%s不支持该玩法: %s", game.GameName, playType)
+	}
+
+	switch playType {
+	case model.PlayTypeGroup3:
+		if digitForm(redBalls) != model.PlayTypeGroup3 {
+			return "", errors.New("组选三号码需要有且仅有两位相同")
+		}
+	case model.PlayTypeGroup6:
+		if digitForm(redBalls) != model.PlayTypeGroup6 {
+			return "", errors.New("组选六号码需要三位各不相同")
+		}
+	}
+	return playType, nil
+}
+
+// validateDigits 校验数字型号码的位数和每位取值，允许重复
+func validateDigits(game *model.LotteryGame, digits, blueBalls model.NumberArray) error {
+	if len(digits) != game.RedSelectCount {
+		return fmt.Errorf("号码位数不正确，需要%d位", game.RedSelectCount)
+	}
+	if len(blueBalls) != game.BlueSelectCount {
+		return fmt.Errorf("蓝球数量不正确，需要%d个", game.BlueSelectCount)
+	}
+	for _, digit := range digits {
+		if digit < 0 || digit >= game.RedBallCount {
+			return fmt.Errorf("号码超出范围(0-%d)", game.RedBallCount-1)
+		}
+	}
+	for _, digit := range blueBalls {
+		if digit < 0 || digit >= game.BlueBallCount {
+			return fmt.Errorf("后区号码超出范围(0-%d)", game.BlueBallCount-1)
+		}
+	}
+	return nil
+}
+
+// normalizeDigits 组选号码不计顺序，统一按升序保存
+func normalizeDigits(playType string, digits model.NumberArray) model.NumberArray {
+	if playType != model.PlayTypeGroup3 && playType != model.PlayTypeGroup6 {
+		return digits
+	}
+	sorted := append(model.NumberArray{}, digits...)
+	sort.Ints(sorted)
+	return sorted
+}
+
+// digitForm 判断三位数字的形态：group3(组三), group6(组六), leopard(豹子)，非三位返回空
+func digitForm(digits model.NumberArray) string {
+	if len(digits) != 3 {
+		return ""
+	}
+	distinct := make(map[int]bool)
+	for _, digit := range digits {
+		distinct[digit] = true
+	}
+	switch len(distinct) {
+	case 1:
+		return "leopard"
+	case 2:
+		return model.PlayTypeGroup3
+	default:
+		return model.PlayTypeGroup6
+	}
+}
+
+// countPositionMatches 计算按位置相同的数字个数
+func countPositionMatches(userDigits, drawDigits model.NumberArray) int {
+	matches := 0
+	for i := 0; i < len(userDigits) && i < len(drawDigits); i++ {
+		if userDigits[i] == drawDigits[i] {
+			matches++
+		}
+	}
+	return matches
+}
+
+// determineDigitPrizeLevel 判断数字型号码是否中奖，中奖返回1
+// 直选需号码和顺序一致；组选需开奖号码形态与玩法一致且数字相同（不计顺序）
+func determineDigitPrizeLevel(playType string, userDigits, drawDigits model.NumberArray) int {
+	switch playType {
+	case model.PlayTypeDirect, "":
+		if len(userDigits) == len(drawDigits) && countPositionMatches(userDigits, drawDigits) == len(drawDigits) {
+			return 1
+		}
+	case model.PlayTypeGroup3, model.PlayTypeGroup6:
+		if digitForm(drawDigits) == playType && compareNumberArrays(userDigits, drawDigits) {
+			return 1
+		}
+	}
+	return 0
+}
+
+// EvaluateDigitNumber 核对数字型号码，返回按位命中数、奖级和单注奖金(分)
+func EvaluateDigitNumber(game *model.LotteryGame, userNumber *model.UserNumber, drawResult *model.DrawResult) (int, int, int64) {
+	playType := userNumber.PlayType
+	if playType == "" {
+		playType = model.PlayTypeDirect
+	}
+	positionMatches := countPositionMatches(userNumber.RedBalls, drawResult.RedBalls)
+	prizeLevel := determineDigitPrizeLevel(playType, userNumber.RedBalls, drawResult.RedBalls)
+	if prizeLevel == 0 {
+		return positionMatches, 0, 0
+	}
+	return positionMatches, prizeLevel, digitPrizeAmounts[game.GameCode][playType]
+}
+
+// parseDigits 解析数字型号码，兼容 "5,2,8"、"5 2 8" 和 "528" 三种格式
+func parseDigits(text string, count int) ([]int, error) {
+	text = strings.TrimSpace(text)
+	parts := digitSeparator.Split(text, -1)
+	if len(parts) == 1 && len(text) == count {
+		parts = strings.Split(text, "")
+	}
+	if len(parts) != count {
+		return nil, fmt.Errorf("号码位数错误: %s", text)
+	}
+
+	digits := make([]int, 0, count)
+	for _, part := range parts {
+		digit, err := strconv.Atoi(part)
+		if err != nil || digit < 0 || digit > 9 {
+			return nil, fmt.Errorf("号码格式错误: %s", text)
+		}
+		digits = append(digits, digit)
+	}
+	return digits, nil
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"lucky/model"
+)
+
+var testFC3DGame = &model.LotteryGame{
+	GameCode:       "fc3d",
+	GameName:       "福彩3D",
+	GameType:       model.GameTypeDigit,
+	RedBallCount:   10,
+	RedSelectCount: 3,
+}
+
+var testPL5Game = &model.LotteryGame{
+	GameCode:       "pl5",
+	GameName:       "排列五",
+	GameType:       model.GameTypeDigit,
+	RedBallCount:   10,
+	RedSelectCount: 5,
+}
+
+func TestValidateDigitNumbers(t *testing.T) {
+	if err := ValidateNumbers(testFC3DGame, model.NumberArray{0, 0, 9}, nil); err != nil {
+		t.Errorf("数字可重复且包含0: %v", err)
+	}
+	if err := ValidateNumbers(testFC3DGame, model.NumberArray{1, 2}, nil); err == nil {
+		t.Error("位数不足应返回错误")
+	}
+	if err := ValidateNumbers(testFC3DGame, model.NumberArray{1, 2, 10}, nil); err == nil {
+		t.Error("超过9应返回错误")
+	}
+	if err := ValidateNumbers(testPL5Game, model.NumberArray{1, 2, 3, 4, 5}, model.NumberArray{1}); err == nil {
+		t.Error("数字型游戏不应有蓝球")
+	}
+}
+
+func TestValidatePlayType(t *testing.T) {
+	cases := []struct {
+		name     string
+		game     *model.LotteryGame
+		playType string
+		digits   model.NumberArray
+		want     string
+		wantErr  bool
+	}{
+		{"默认直选", testFC3DGame, "", model.NumberArray{1, 2, 3}, model.PlayTypeDirect, false},
+		{"组选三", testFC3DGame, model.PlayTypeGroup3, model.NumberArray{1, 1, 3}, model.PlayTypeGroup3, false},
+		{"组选三需有对子", testFC3DGame, model.PlayTypeGroup3, model.NumberArray{1, 2, 3}, "", true},
+		{"组选三不含豹子", testFC3DGame, model.PlayTypeGroup3, model.NumberArray{7, 7, 7}, "", true},
+		{"组选六", testFC3DGame, model.PlayTypeGroup6, model.NumberArray{1, 2, 3}, model.PlayTypeGroup6, false},
+		{"组选六不能重复", testFC3DGame, model.PlayTypeGroup6, model.NumberArray{1, 1, 3}, "", true},
+		{"排列五仅直选", testPL5Game, model.PlayTypeGroup6, model.NumberArray{1, 2, 3, 4, 5}, "", true},
+		{"未知玩法", testFC3DGame, "sum", model.NumberArray{1, 2, 3}, "", true},
+		{"选号型游戏无玩法", testSSQGame, "", nil, "", false},
+		{"选号型游戏不支持玩法", testSSQGame, model.PlayTypeDirect, nil, "", true},
+	}
+	for _, tc := range cases {
+		got, err := ValidatePlayType(tc.game, tc.playType, tc.digits)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Errorf("%s: ValidatePlayType = %q, %v", tc.name, got, err)
+		}
+	}
+}
+
+func TestEvaluateDigitNumber(t *testing.T) {
+	draw := &model.DrawResult{RedBalls: model.NumberArray{5, 2, 5}}
+	cases := []struct {
+		name       string
+		game       *model.LotteryGame
+		playType   string
+		digits     model.NumberArray
+		drawDigits model.NumberArray
+		wantLevel  int
+		wantAmount int64
+	}{
+		{"直选中奖", testFC3DGame, model.PlayTypeDirect, model.NumberArray{5, 2, 5}, draw.RedBalls, 1, 104000},
+		{"直选顺序不同", testFC3DGame, model.PlayTypeDirect, model.NumberArray{2, 5, 5}, draw.RedBalls, 0, 0},
+		{"组选三不计顺序", testFC3DGame, model.PlayTypeGroup3, model.NumberArray{2, 5, 5}, draw.RedBalls, 1, 34600},
+		{"组选六遇组三开奖不中", testFC3DGame, model.PlayTypeGroup6, model.NumberArray{1, 2, 5}, draw.RedBalls, 0, 0},
+		{"组选六中奖", testFC3DGame, model.PlayTypeGroup6, model.NumberArray{1, 2, 3}, model.NumberArray{3, 1, 2}, 1, 17300},
+		{"排列五直选", testPL5Game, model.PlayTypeDirect, model.NumberArray{0, 1, 2, 3, 4}, model.NumberArray{0, 1, 2, 3, 4}, 1, 10000000},
+	}
+	for _, tc := range cases {
+		userNumber := &model.UserNumber{RedBalls: tc.digits, PlayType: tc.playType}
+		_, level, amount := EvaluateDigitNumber(tc.game, userNumber, &model.DrawResult{RedBalls: tc.drawDigits})
+		if level != tc.wantLevel || amount != tc.wantAmount {
+			t.Errorf("%s: level=%d amount=%d, want %d %d", tc.name, level, amount, tc.wantLevel, tc.wantAmount)
+		}
+	}
+
+	positionMatches, _, _, _ := EvaluateNumber(testFC3DGame, &model.UserNumber{RedBalls: model.NumberArray{5, 9, 5}, PlayType: model.PlayTypeDirect}, draw)
+	if positionMatches != 2 {
+		t.Errorf("按位命中数 = %d, want 2", positionMatches)
+	}
+}
+
+func TestParseDigits(t *testing.T) {
+	cases := []struct {
+		text    string
+		count   int
+		want    []int
+		wantErr bool
+	}{
+		{"5,2,8", 3, []int{5, 2, 8}, false},
+		{"5 2 8", 3, []int{5, 2, 8}, false},
+		{"528", 3, []int{5, 2, 8}, false},
+		{" 0 1 2 3 4 ", 5, []int{0, 1, 2, 3, 4}, false},
+		{"5 2", 3, nil, true},
+		{"5,12,8", 3, nil, true},
+	}
+	for _, tc := range cases {
+		got, err := parseDigits(tc.text, tc.count)
+		if (err != nil) != tc.wantErr || !equalDigits(got, tc.want) {
+			t.Errorf("parseDigits(%q) = %v, %v", tc.text, got, err)
+		}
+	}
+
+	red, blue, err := ParseNumberLine(testFC3DGame, "0 0 7")
+	if err != nil || !equalDigits(red, []int{0, 0, 7}) || len(blue) != 0 {
+		t.Errorf("ParseNumberLine 数字型解析错误: %v %v %v", red, blue, err)
+	}
+}
+
+func TestBuildDigitTrend(t *testing.T) {
+	day := time.Date(2025, 10, 1, 21, 15, 0, 0, time.Local)
+	// 按期号倒序
+	results := []model.DrawResult{
+		{Period: "2025263", DrawDate: day.AddDate(0, 0, 2), RedBalls: model.NumberArray{1, 2, 3}},
+		{Period: "2025262", DrawDate: day.AddDate(0, 0, 1), RedBalls: model.NumberArray{4, 4, 4}},
+		{Period: "2025261", DrawDate: day, RedBalls: model.NumberArray{1, 1, 9}},
+	}
+
+	trend := buildDigitTrend(testFC3DGame, results)
+
+	if trend.PeriodCount != 3 || len(trend.Positions) != 3 {
+		t.Fatalf("统计期数或位数错误: %+v", trend)
+	}
+	if trend.Draws[0].Sum != 6 || trend.Draws[0].Span != 2 || trend.Draws[2].Form != model.PlayTypeGroup3 {
+		t.Errorf("单期形态统计错误: %+v", trend.Draws)
+	}
+	if trend.FormCounts["leopard"] != 1 || trend.FormCounts[model.PlayTypeGroup6] != 1 {
+		t.Errorf("形态次数错误: %v", trend.FormCounts)
+	}
+	first := trend.Positions[0]
+	if first.Frequency[1] != 2 || first.Missing[1] != 0 || first.Missing[4] != 1 || first.MaxMissing[1] != 1 {
+		t.Errorf("百位走势错误: %+v", first)
+	}
+	if first.Missing[0] != 3 {
+		t.Errorf("未出现数字的遗漏应为统计期数, got %d", first.Missing[0])
+	}
+}
+
+func equalDigits(a model.NumberArray, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
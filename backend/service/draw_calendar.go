@@ -0,0 +1,152 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"lucky/model"
+
+	"gorm.io/gorm"
+)
+
+// maxProjectedDays 推算起始期号时最多向后推算的天数（约一年）
+const maxProjectedDays = 366
+
+// everyDay 每日开奖
+var everyDay = []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}
+
+// drawWeekdays 各游戏的每周开奖日
+var drawWeekdays = map[string][]time.Weekday{
+	"ssq":  {time.Tuesday, time.Thursday, time.Sunday},   // 双色球：二、四、日
+	"dlt":  {time.Monday, time.Wednesday, time.Saturday}, // 大乐透：一、三、六
+	"fc3d": everyDay,                                     // 福彩3D：每日
+	"pl3":  everyDay,                                     // 排列三：每日
+	"pl5":  everyDay,                                     // 排列五：每日
+	"kl8":  everyDay,                                     // 快乐8：每日
+	"qlc":  {time.Monday, time.Wednesday, time.Friday},   // 七乐彩：一、三、五
+	"qxc":  {time.Tuesday, time.Friday, time.Sunday},     // 七星彩：二、五、日
+}
+
+// ScheduledDraw 开奖日历中的一期
+type ScheduledDraw struct {
+	Period   string    `json:"period"`   // 期号
+	DrawDate time.Time `json:"drawDate"` // 开奖日期
+	Drawn    bool      `json:"drawn"`    // 是否已开奖
+}
+
+// GetNextDraw 根据最新一期开奖结果推算下一期
+func GetNextDraw(db *gorm.DB, game *model.LotteryGame) (*ScheduledDraw, error) {
+	latest, err := GetLatestDrawResult(db, game.GameCode)
+	if err != nil {
+		return nil, errors.New("暂无开奖数据，无法推算期号")
+	}
+	draws, err := projectDraws(game.GameCode, latest.Period, latest.DrawDate, "", 1)
+	if err != nil {
+		return nil, err
+	}
+	return &draws[0], nil
+}
+
+// GetCoveredDraws 获取从startPeriod开始的count期，已开奖的以实际结果为准，未开奖的按开奖日历推算
+func GetCoveredDraws(db *gorm.DB, game *model.LotteryGame, startPeriod string, count int) ([]ScheduledDraw, error) {
+	var drawResults []model.DrawResult
+	err := db.Where("game_id = ? AND period >= ?", game.ID, startPeriod).
+		Order("period ASC").
+		Limit(count).
+		Find(&drawResults).Error
+	if err != nil {
+		return nil, err
+	}
+
+	draws := make([]ScheduledDraw, 0, count)
+	for _, drawResult := range drawResults {
+		draws = append(draws, ScheduledDraw{Period: drawResult.Period, DrawDate: drawResult.DrawDate, Drawn: true})
+	}
+	if len(draws) == count {
+		return draws, nil
+	}
+
+	latest, err := GetLatestDrawResult(db, game.GameCode)
+	if err != nil {
+		return nil, errors.New("暂无开奖数据，无法推算期号")
+	}
+
+	projected, err := projectDraws(game.GameCode, latest.Period, latest.DrawDate, startPeriod, count-len(draws))
+	if err != nil {
+		return nil, err
+	}
+	return append(draws, projected...), nil
+}
+
+// projectDraws 按开奖日历推算latestPeriod之后、期号不小于startPeriod的count期
+// 跨年后期号从001重新开始；春节等休市期间以实际开奖为准
+func projectDraws(gameCode, latestPeriod string, latestDate time.Time, startPeriod string, count int) ([]ScheduledDraw, error) {
+	weekdays, ok := drawWeekdays[gameCode]
+	if !ok {
+		return nil, fmt.Errorf("暂不支持游戏 %s 的开奖日历", gameCode)
+	}
+	year, seq, err := parsePeriod(latestPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	draws := make([]ScheduledDraw, 0, count)
+	date := latestDate
+	for len(draws) < count {
+		date = nextDrawDate(date, weekdays)
+		if date.Year() != year {
+			year, seq = date.Year(), 0
+		}
+		seq++
+		period := formatPeriod(year, seq)
+		if period < startPeriod {
+			if date.Sub(latestDate) > maxProjectedDays*24*time.Hour {
+				return nil, fmt.Errorf("起始期号 %s 超出可推算范围", startPeriod)
+			}
+			continue
+		}
+		draws = append(draws, ScheduledDraw{Period: period, DrawDate: date})
+	}
+	return draws, nil
+}
+
+// nextDrawDate 返回date之后的第一个开奖日
+func nextDrawDate(date time.Time, weekdays []time.Weekday) time.Time {
+	for {
+		date = date.AddDate(0, 0, 1)
+		for _, weekday := range weekdays {
+			if date.Weekday() == weekday {
+				return date
+			}
+		}
+	}
+}
+
+// ValidatePeriod 校验期号格式（7位：年份+3位序号）
+func ValidatePeriod(period string) error {
+	_, _, err := parsePeriod(period)
+	return err
+}
+
+// parsePeriod 解析7位期号为年份和序号
+func parsePeriod(period string) (int, int, error) {
+	if len(period) != 7 {
+		return 0, 0, fmt.Errorf("期号格式错误: %s", period)
+	}
+	year, err := strconv.Atoi(period[:4])
+	if err != nil {
+		return 0, 0, fmt.Errorf("期号格式错误: %s", period)
+	}
+	seq, err := strconv.Atoi(period[4:])
+	if err != nil || seq <= 0 {
+		return 0, 0, fmt.Errorf("期号格式错误: %s", period)
+	}
+	return year, seq, nil
+}
+
+// formatPeriod 将年份和序号格式化为7位期号
+func formatPeriod(year, seq int) string {
+	return fmt.Sprintf("%d%03d", year, seq)
+}
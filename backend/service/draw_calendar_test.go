@@ -0,0 +1,63 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProjectDraws(t *testing.T) {
+	// 2025-12-28 周日开奖 2025150 期
+	latestDate := time.Date(2025, 12, 28, 21, 15, 0, 0, time.Local)
+	draws, err := projectDraws("ssq", "2025150", latestDate, "", 3)
+	if err != nil {
+		t.Fatalf("projectDraws error = %v", err)
+	}
+
+	want := []struct {
+		period string
+		date   string
+	}{
+		{"2025151", "2025-12-30"},
+		{"2026001", "2026-01-01"},
+		{"2026002", "2026-01-04"},
+	}
+	for i, w := range want {
+		if draws[i].Period != w.period || draws[i].DrawDate.Format("2006-01-02") != w.date {
+			t.Errorf("第%d期 = %s %s, want %s %s", i+1, draws[i].Period, draws[i].DrawDate.Format("2006-01-02"), w.period, w.date)
+		}
+	}
+}
+
+func TestProjectDrawsFromStartPeriod(t *testing.T) {
+	// 2025-10-18 周六开奖 2025119 期
+	latestDate := time.Date(2025, 10, 18, 21, 25, 0, 0, time.Local)
+	draws, err := projectDraws("dlt", "2025119", latestDate, "2025122", 2)
+	if err != nil {
+		t.Fatalf("projectDraws error = %v", err)
+	}
+	if draws[0].Period != "2025122" || draws[0].DrawDate.Weekday() != time.Saturday {
+		t.Errorf("起始期 = %s %s, want 2025122 Saturday", draws[0].Period, draws[0].DrawDate.Weekday())
+	}
+	if draws[1].Period != "2025123" || draws[1].DrawDate.Weekday() != time.Monday {
+		t.Errorf("第二期 = %s %s, want 2025123 Monday", draws[1].Period, draws[1].DrawDate.Weekday())
+	}
+
+	if _, err := projectDraws("dlt", "2025119", latestDate, "2027001", 1); err == nil {
+		t.Error("超出推算范围时应返回错误")
+	}
+	if _, err := projectDraws("unknown", "2025119", latestDate, "", 1); err == nil {
+		t.Error("不支持的游戏应返回错误")
+	}
+}
+
+func TestParseCalendarPeriod(t *testing.T) {
+	year, seq, err := parsePeriod("2025119")
+	if err != nil || year != 2025 || seq != 119 {
+		t.Errorf("parsePeriod = %d %d %v, want 2025 119", year, seq, err)
+	}
+	for _, period := range []string{"25119", "2025abc", "2025000"} {
+		if err := ValidatePeriod(period); err == nil {
+			t.Errorf("ValidatePeriod(%q) 应返回错误", period)
+		}
+	}
+}
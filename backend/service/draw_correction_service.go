@@ -0,0 +1,333 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	applog "lucky/common/log"
+	"lucky/model"
+
+	"gorm.io/gorm"
+)
+
+// ErrDrawResultConflict 抓取到的开奖号码与已保存的不一致
+var ErrDrawResultConflict = errors.New("与已保存的开奖号码不一致")
+
+// DrawCorrection 开奖结果更正的来源、操作人和原因
+type DrawCorrection struct {
+	Source     string // admin(管理员), crawler(数据源)
+	OperatorID int64
+	Reason     string
+}
+
+// DrawCorrectionResult 开奖结果更正及重新核对的结果
+type DrawCorrectionResult struct {
+	DrawResult  *model.DrawResult         `json:"drawResult"`
+	Revision    *model.DrawResultRevision `json:"revision"`    // 被替换的版本
+	Reevaluated int                       `json:"reevaluated"` // 重新核对的号码数
+	Changed     []OutcomeChange           `json:"changed"`     // 核对结果发生变化的号码
+	Notified    int                       `json:"notified"`    // 发送的通知数
+}
+
+// OutcomeChange 号码在更正前后的核对结果
+type OutcomeChange struct {
+	UserID         int64  `json:"userId"`
+	UserNumberID   int64  `json:"userNumberId"`
+	Numbers        string `json:"numbers"`
+	OldPrizeLevel  int    `json:"oldPrizeLevel"`
+	OldPrizeAmount int64  `json:"oldPrizeAmount"`
+	OldWinLevel    string `json:"oldWinLevel"`
+	NewPrizeLevel  int    `json:"newPrizeLevel"`
+	NewPrizeAmount int64  `json:"newPrizeAmount"`
+	NewWinLevel    string `json:"newWinLevel"`
+}
+
+// sameDrawNumbers 判断两期开奖号码是否一致
+func sameDrawNumbers(a, b *model.DrawResult) bool {
+	return FormatNumberLine(a.RedBalls, a.BlueBalls) == FormatNumberLine(b.RedBalls, b.BlueBalls)
+}
+
+// sameFloatingPrizes 判断两期的一、二等奖单注奖金是否一致，中奖记录按这两项计算浮动奖级的奖金
+func sameFloatingPrizes(a, b *model.DrawResult) bool {
+	return a.FirstAmount == b.FirstAmount && a.SecondAmount == b.SecondAmount
+}
+
+// ApplyDrawCorrection 更正开奖结果：保存被替换的版本、版本号加1，
+// 然后重新核对该期的中奖记录、追号计划和购彩记录，并通知中奖结果发生变化的用户
+func ApplyDrawCorrection(db *gorm.DB, current, corrected *model.DrawResult, correction DrawCorrection) (*DrawCorrectionResult, error) {
+	if strings.TrimSpace(correction.Reason) == "" {
+		return nil, errors.New("请填写更正原因")
+	}
+	game, err := GetGameByID(db, current.GameID)
+	if err != nil {
+		return nil, fmt.Errorf("游戏不存在")
+	}
+
+	revision := model.NewDrawResultRevision(current, correction.Source, correction.OperatorID, correction.Reason)
+	updated := *corrected
+	updated.ID = current.ID
+	updated.GameID = current.GameID
+	updated.Period = current.Period
+	updated.Version = current.Version + 1
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := model.NewDrawResultRevisionDAO(tx).Create(revision); err != nil {
+			return err
+		}
+		// 按版本号更新，避免并发更正互相覆盖
+		result := tx.Model(&model.DrawResult{}).
+			Where("id = ? AND version = ?", current.ID, current.Version).
+			Updates(map[string]interface{}{
+				"red_balls":     updated.RedBalls,
+				"blue_balls":    updated.BlueBalls,
+				"draw_date":     updated.DrawDate,
+				"sales_amount":  updated.SalesAmount,
+				"prize_pool":    updated.PrizePool,
+				"first_prize":   updated.FirstPrize,
+				"first_amount":  updated.FirstAmount,
+				"second_prize":  updated.SecondPrize,
+				"second_amount": updated.SecondAmount,
+				"version":       updated.Version,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("开奖结果已被其他操作更正，请刷新后重试")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	notifyDrawResultChanged(db, game, &updated)
+
+	correctionResult := &DrawCorrectionResult{DrawResult: &updated, Revision: revision}
+	if sameDrawNumbers(current, &updated) && sameFloatingPrizes(current, &updated) {
+		// 号码和一、二等奖单注奖金都未变化，只更正了开奖日期、销量等信息，无需重新核对
+		return correctionResult, nil
+	}
+
+	changes, reevaluated, err := reevaluateDraw(db, game, current, &updated)
+	correctionResult.Reevaluated = reevaluated
+	correctionResult.Changed = changes
+	if err != nil {
+		return correctionResult, fmt.Errorf("开奖结果已更正，但重新核对失败: %v", err)
+	}
+	correctionResult.Notified = notifyOutcomeChanges(db, game, &updated, changes)
+	return correctionResult, nil
+}
+
+// correctCrawledDrawResult 按数据源更正后的号码和开奖日期更正已保存的开奖结果，奖金、销量等信息保持不变
+func correctCrawledDrawResult(db *gorm.DB, result *DrawResult) (*DrawCorrectionResult, error) {
+	scraped, err := newDrawResultModel(db, result)
+	if err != nil {
+		return nil, err
+	}
+	var current model.DrawResult
+	if err := db.Where("game_id = ? AND period = ?", scraped.GameID, scraped.Period).First(&current).Error; err != nil {
+		return nil, err
+	}
+
+	corrected := current
+	corrected.RedBalls = scraped.RedBalls
+	corrected.BlueBalls = scraped.BlueBalls
+	corrected.DrawDate = scraped.DrawDate
+	return ApplyDrawCorrection(db, &current, &corrected, DrawCorrection{
+		Source: model.DrawChangeSourceCrawler,
+		Reason: fmt.Sprintf("数据源开奖号码由 %s 更正为 %s",
+			FormatNumberLine(current.RedBalls, current.BlueBalls), FormatNumberLine(scraped.RedBalls, scraped.BlueBalls)),
+	})
+}
+
+// reevaluateDraw 按更正后的开奖号码重新核对该期的全部中奖记录，
+// 并重新计算包含该期的追号计划和已结算的手动购彩记录，返回核对结果发生变化的号码
+func reevaluateDraw(db *gorm.DB, game *model.LotteryGame, previous, updated *model.DrawResult) ([]OutcomeChange, int, error) {
+	var userDraws []model.UserDraw
+	if err := db.Where("draw_result_id = ?", updated.ID).Find(&userDraws).Error; err != nil {
+		return nil, 0, err
+	}
+
+	changes := make([]OutcomeChange, 0)
+	numbers := make(map[int64]*model.UserNumber)
+	reevaluated := 0
+	var failedNumbers []int64
+	for i := range userDraws {
+		userDraw := &userDraws[i]
+		numberID := int64(userDraw.UserNumberID)
+		userNumber, checked := numbers[numberID]
+		if !checked {
+			var err error
+			userNumber, err = model.NewUserNumberDAO(db).GetByID(numberID)
+			if err != nil {
+				// 号码已删除的中奖记录不再核对
+				numbers[numberID] = nil
+				continue
+			}
+			numbers[numberID] = userNumber
+			reevaluated++
+		}
+		if userNumber == nil {
+			continue
+		}
+
+		oldRed, _, oldLevel, oldAmount := EvaluateNumber(game, userNumber, previous)
+		newRed, _, newLevel, newAmount := EvaluateNumber(game, userNumber, updated)
+		err := db.Model(userDraw).Updates(map[string]interface{}{
+			"prize_level":  newLevel,
+			"prize_amount": newAmount,
+			"is_winning":   newLevel > 0,
+		}).Error
+		if err != nil {
+			failedNumbers = append(failedNumbers, numberID)
+			continue
+		}
+		observeSavedEvaluations(game.GameCode, newLevel)
+		if !checked && (oldLevel != newLevel || oldAmount != newAmount) {
+			changes = append(changes, OutcomeChange{
+				UserID:         userNumber.UserID,
+				UserNumberID:   userNumber.ID,
+				Numbers:        FormatNumberLine(userNumber.RedBalls, userNumber.BlueBalls),
+				OldPrizeLevel:  oldLevel,
+				OldPrizeAmount: oldAmount,
+				OldWinLevel:    WinLevelName(game, userNumber.PlayType, oldLevel, oldRed),
+				NewPrizeLevel:  newLevel,
+				NewPrizeAmount: newAmount,
+				NewWinLevel:    WinLevelName(game, userNumber.PlayType, newLevel, newRed),
+			})
+		}
+	}
+
+	if err := reevaluatePlans(db, updated.ID); err != nil {
+		return changes, reevaluated, err
+	}
+	purchases, err := model.NewPurchaseDAO(db).GetSettledManualByPeriod(updated.GameID, updated.Period)
+	if err != nil {
+		return changes, reevaluated, err
+	}
+	var failedPurchases []int64
+	for _, purchase := range purchases {
+		if err := settlePurchase(db, purchase, game, updated); err != nil {
+			failedPurchases = append(failedPurchases, purchase.ID)
+		}
+	}
+
+	switch {
+	case len(failedNumbers) > 0:
+		return changes, reevaluated, fmt.Errorf("号码 %v 重新核对失败", failedNumbers)
+	case len(failedPurchases) > 0:
+		return changes, reevaluated, fmt.Errorf("购彩记录 %v 重新结算失败", failedPurchases)
+	}
+	return changes, reevaluated, nil
+}
+
+// reevaluatePlans 重新计算包含该期开奖的追号计划，中奖停追的计划按更正后的结果重新判断是否停止
+func reevaluatePlans(db *gorm.DB, drawResultID uint64) error {
+	plans, err := model.NewNumberPlanDAO(db).GetByDrawResultID(drawResultID)
+	if err != nil {
+		return err
+	}
+
+	var failedPlans []int64
+	for _, plan := range plans {
+		userNumber, err := model.NewUserNumberDAO(db).GetByIDWithGame(plan.UserNumberID)
+		if err == nil {
+			plan.Status = "active"
+			err = refreshNumberPlan(db, plan, userNumber)
+		}
+		if err != nil {
+			failedPlans = append(failedPlans, plan.ID)
+		}
+	}
+	if len(failedPlans) > 0 {
+		return fmt.Errorf("追号计划 %v 重新核对失败", failedPlans)
+	}
+	return nil
+}
+
+// notifyOutcomeChanges 通知中奖结果发生变化的用户，返回发送成功的通知数
+func notifyOutcomeChanges(db *gorm.DB, game *model.LotteryGame, drawResult *model.DrawResult, changes []OutcomeChange) int {
+	notified := 0
+	for _, change := range changes {
+		drawResultID := drawResult.ID
+		userNumberID := change.UserNumberID
+		notification := &model.Notification{
+			UserID:       change.UserID,
+			Type:         model.NotificationDrawCorrected,
+			Title:        fmt.Sprintf("%s第%s期开奖结果更正", game.GameName, drawResult.Period),
+			Content:      formatOutcomeChange(game, drawResult, change),
+			DrawResultID: &drawResultID,
+			UserNumberID: &userNumberID,
+		}
+		if err := model.NewNotificationDAO(db).Create(notification); err != nil {
+			dbLogger(db).WithField(applog.FieldUserID, change.UserID).Warnf("发送更正通知失败: %v", err)
+			continue
+		}
+		notified++
+	}
+	return notified
+}
+
+// formatOutcomeChange 生成更正通知内容
+func formatOutcomeChange(game *model.LotteryGame, drawResult *model.DrawResult, change OutcomeChange) string {
+	return fmt.Sprintf("%s第%s期开奖号码已更正为 %s，您的号码 %s 核对结果由「%s」变为「%s」",
+		game.GameName, drawResult.Period, FormatNumberLine(drawResult.RedBalls, drawResult.BlueBalls),
+		change.Numbers, describeOutcome(change.OldWinLevel, change.OldPrizeAmount), describeOutcome(change.NewWinLevel, change.NewPrizeAmount))
+}
+
+// describeOutcome 描述核对结果，浮动奖金不显示金额
+func describeOutcome(winLevel string, prizeAmount int64) string {
+	if winLevel == "" {
+		return "未中奖"
+	}
+	if prizeAmount <= 0 {
+		return winLevel
+	}
+	return fmt.Sprintf("%s %d元", winLevel, prizeAmount/100)
+}
+
+// GetDrawResultRevisions 获取某期开奖结果的当前版本和历史版本
+func GetDrawResultRevisions(db *gorm.DB, gameCode, period string) (*model.DrawResult, []*model.DrawResultRevision, error) {
+	game, err := LookupGame(db, gameCode)
+	if err != nil {
+		return nil, nil, fmt.Errorf("游戏不存在: %s", gameCode)
+	}
+
+	var drawResult model.DrawResult
+	if err := db.Where("game_id = ? AND period = ?", game.ID, period).First(&drawResult).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, fmt.Errorf("期号 %s 不存在", period)
+		}
+		return nil, nil, err
+	}
+
+	revisions, err := model.NewDrawResultRevisionDAO(db).GetByDrawResultID(drawResult.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &drawResult, revisions, nil
+}
+
+// GetNotifications 分页获取用户通知及未读数
+func GetNotifications(db *gorm.DB, userID uint64, unreadOnly bool, page, pageSize int) ([]*model.Notification, int64, int64, error) {
+	notificationDAO := model.NewNotificationDAO(db)
+	notifications, total, err := notificationDAO.GetByUserID(int64(userID), unreadOnly, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	unread, err := notificationDAO.CountUnread(int64(userID))
+	return notifications, total, unread, err
+}
+
+// MarkNotificationsRead 标记通知为已读，notificationID 为0时标记全部
+func MarkNotificationsRead(db *gorm.DB, userID uint64, notificationID int64) error {
+	updated, err := model.NewNotificationDAO(db).MarkRead(int64(userID), notificationID)
+	if err != nil {
+		return err
+	}
+	if notificationID > 0 && updated == 0 {
+		return errors.New("通知不存在或已读")
+	}
+	return nil
+}
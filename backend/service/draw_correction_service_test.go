@@ -0,0 +1,194 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"lucky/common/database/dbtest"
+	"lucky/model"
+
+	"gorm.io/gorm/clause"
+)
+
+func TestSameDrawNumbers(t *testing.T) {
+	saved := &model.DrawResult{RedBalls: model.NumberArray{1, 5, 12, 18, 25, 33}, BlueBalls: model.NumberArray{8}}
+	if !sameDrawNumbers(saved, &model.DrawResult{RedBalls: model.NumberArray{1, 5, 12, 18, 25, 33}, BlueBalls: model.NumberArray{8}}) {
+		t.Error("相同号码应判断为一致")
+	}
+	if sameDrawNumbers(saved, &model.DrawResult{RedBalls: model.NumberArray{1, 5, 12, 18, 25, 33}, BlueBalls: model.NumberArray{9}}) {
+		t.Error("蓝球不同应判断为不一致")
+	}
+	if sameDrawNumbers(&model.DrawResult{RedBalls: model.NumberArray{1, 2, 3}}, &model.DrawResult{RedBalls: model.NumberArray{3, 2, 1}}) {
+		t.Error("数字型游戏顺序不同应判断为不一致")
+	}
+
+	conflictErr := fmt.Errorf("期号 %s %w", "2025100", ErrDrawResultConflict)
+	if status, _ := crawlRunStatus(conflictErr); status != model.CrawlStatusFailed {
+		t.Errorf("号码不一致应记为抓取失败, got %s", status)
+	}
+	if errors.Is(conflictErr, ErrDrawResultExists) {
+		t.Error("号码不一致不应视为期号已存在")
+	}
+}
+
+func TestNewDrawResultRevision(t *testing.T) {
+	drawResult := &model.DrawResult{
+		ID:          12,
+		GameID:      1,
+		Period:      "2025100",
+		RedBalls:    model.NumberArray{1, 5, 12, 18, 25, 33},
+		BlueBalls:   model.NumberArray{8},
+		DrawDate:    time.Date(2025, 9, 2, 0, 0, 0, 0, time.Local),
+		FirstPrize:  3,
+		FirstAmount: 500000000,
+		Version:     2,
+	}
+
+	revision := model.NewDrawResultRevision(drawResult, model.DrawChangeSourceAdmin, 7, "官方公告更正")
+	if revision.DrawResultID != 12 || revision.Version != 2 || revision.Period != "2025100" {
+		t.Errorf("历史版本应记录被替换的版本: %+v", revision)
+	}
+	if FormatNumberLine(revision.RedBalls, revision.BlueBalls) != "01 05 12 18 25 33 + 08" || revision.FirstAmount != 500000000 {
+		t.Errorf("历史版本应保存原开奖数据: %+v", revision)
+	}
+	if revision.ChangeSource != model.DrawChangeSourceAdmin || revision.OperatorID != 7 || revision.Reason != "官方公告更正" {
+		t.Errorf("历史版本应记录更正人和原因: %+v", revision)
+	}
+}
+
+func TestFormatOutcomeChange(t *testing.T) {
+	drawResult := &model.DrawResult{Period: "2025100", RedBalls: model.NumberArray{1, 5, 12, 18, 25, 33}, BlueBalls: model.NumberArray{9}}
+	change := OutcomeChange{
+		Numbers:        "01 05 12 18 25 33 + 08",
+		OldPrizeLevel:  6,
+		OldPrizeAmount: 500,
+		OldWinLevel:    "六等奖",
+		NewPrizeLevel:  3,
+		NewPrizeAmount: 300000,
+		NewWinLevel:    "三等奖",
+	}
+
+	want := "双色球第2025100期开奖号码已更正为 01 05 12 18 25 33 + 09，您的号码 01 05 12 18 25 33 + 08 核对结果由「六等奖 5元」变为「三等奖 3000元」"
+	if got := formatOutcomeChange(&model.LotteryGame{GameName: "双色球"}, drawResult, change); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if got := describeOutcome("", 0); got != "未中奖" {
+		t.Errorf("未中奖 got %q", got)
+	}
+	if got := describeOutcome("一等奖", 0); got != "一等奖" {
+		t.Errorf("浮动奖金不显示金额, got %q", got)
+	}
+}
+
+// TestApplyDrawCorrectionPrizeAmount 只更正一等奖奖金时也要重新核对，中奖记录按新奖金计算
+func TestApplyDrawCorrectionPrizeAmount(t *testing.T) {
+	db := dbtest.Open(t)
+	game := *testSSQGame
+	game.GameName = "双色球"
+	if err := db.Create(&game).Error; err != nil {
+		t.Fatalf("创建游戏失败: %v", err)
+	}
+	current := &model.DrawResult{
+		GameID:      game.ID,
+		Period:      "2025100",
+		RedBalls:    model.NumberArray{1, 5, 12, 18, 25, 33},
+		BlueBalls:   model.NumberArray{8},
+		DrawDate:    time.Date(2025, 9, 2, 0, 0, 0, 0, time.Local),
+		FirstPrize:  3,
+		FirstAmount: 500000000,
+		Version:     1,
+	}
+	if err := db.Create(current).Error; err != nil {
+		t.Fatalf("创建开奖结果失败: %v", err)
+	}
+	userNumber := &model.UserNumber{UserID: 1, GameID: game.ID, RedBalls: current.RedBalls, BlueBalls: current.BlueBalls, Multiplier: 1, IsActive: true}
+	if err := db.Omit(clause.Associations).Create(userNumber).Error; err != nil {
+		t.Fatalf("创建号码失败: %v", err)
+	}
+	if err := saveUserDraw(db, userNumber.ID, current.ID, 1, current.FirstAmount); err != nil {
+		t.Fatalf("保存核对结果失败: %v", err)
+	}
+
+	corrected := *current
+	corrected.FirstAmount = 600000000
+	result, err := ApplyDrawCorrection(db, current, &corrected, DrawCorrection{Source: model.DrawChangeSourceAdmin, OperatorID: 1, Reason: "更正一等奖单注奖金"})
+	if err != nil {
+		t.Fatalf("ApplyDrawCorrection: %v", err)
+	}
+	if result.Reevaluated != 1 || len(result.Changed) != 1 {
+		t.Fatalf("奖金变化应重新核对并记录变化: reevaluated=%d changed=%+v", result.Reevaluated, result.Changed)
+	}
+	if change := result.Changed[0]; change.OldPrizeAmount != 500000000 || change.NewPrizeAmount != 600000000 {
+		t.Errorf("核对结果变化 = %+v", change)
+	}
+
+	var userDraw model.UserDraw
+	if err := db.Where("user_number_id = ?", userNumber.ID).First(&userDraw).Error; err != nil {
+		t.Fatalf("读取核对结果失败: %v", err)
+	}
+	if userDraw.PrizeLevel != 1 || userDraw.PrizeAmount != 600000000 {
+		t.Errorf("中奖记录应按更正后的奖金计算: level=%d amount=%d", userDraw.PrizeLevel, userDraw.PrizeAmount)
+	}
+
+	// 只更正销量时不重新核对
+	current = result.DrawResult
+	salesOnly := *current
+	salesOnly.SalesAmount = 380000000
+	result, err = ApplyDrawCorrection(db, current, &salesOnly, DrawCorrection{Source: model.DrawChangeSourceAdmin, OperatorID: 1, Reason: "更正销量"})
+	if err != nil || result.Reevaluated != 0 {
+		t.Errorf("号码和奖金未变化时不应重新核对: reevaluated=%d err=%v", result.Reevaluated, err)
+	}
+}
+
+func TestCorrectCrawledDrawResult(t *testing.T) {
+	db := dbtest.Open(t)
+	game := *testSSQGame
+	game.GameName = "双色球"
+	if err := db.Create(&game).Error; err != nil {
+		t.Fatalf("创建游戏失败: %v", err)
+	}
+	saved, err := saveDrawResult(db, &DrawResult{GameCode: "ssq", Period: "2025100", DrawDate: "2025-09-02", RedBalls: []int{1, 5, 12, 18, 25, 33}, BlueBalls: []int{8}})
+	if err != nil {
+		t.Fatalf("保存开奖结果失败: %v", err)
+	}
+	userNumber := &model.UserNumber{UserID: 1, GameID: game.ID, RedBalls: saved.RedBalls, BlueBalls: saved.BlueBalls, Multiplier: 1, IsActive: true}
+	if err := db.Omit(clause.Associations).Create(userNumber).Error; err != nil {
+		t.Fatalf("创建号码失败: %v", err)
+	}
+	if err := saveUserDraw(db, userNumber.ID, saved.ID, 1, 0); err != nil {
+		t.Fatalf("保存核对结果失败: %v", err)
+	}
+
+	// 数据源更正了蓝球，未能再次抓取确认时保留已保存的号码
+	changed := &DrawResult{GameCode: "ssq", Period: "2025100", DrawDate: "2025-09-02", RedBalls: []int{1, 5, 12, 18, 25, 33}, BlueBalls: []int{9}}
+	crawler := NewCrawlerServiceWithDB(db)
+	crawler.sources = map[string][]DrawDataSource{}
+	if err := crawler.SaveDrawResult(changed); !errors.Is(err, ErrDrawResultConflict) {
+		t.Fatalf("未确认的号码变化应返回 ErrDrawResultConflict, got %v", err)
+	}
+
+	result, err := correctCrawledDrawResult(db, changed)
+	if err != nil {
+		t.Fatalf("correctCrawledDrawResult: %v", err)
+	}
+	if result.DrawResult.Version != 2 || FormatNumberLine(result.DrawResult.RedBalls, result.DrawResult.BlueBalls) != "01 05 12 18 25 33 + 09" {
+		t.Errorf("开奖结果应更正为数据源号码: %+v", result.DrawResult)
+	}
+	if result.Revision.ChangeSource != model.DrawChangeSourceCrawler || result.Revision.OperatorID != 0 || result.Revision.Reason == "" {
+		t.Errorf("历史版本应记录为数据源更正: %+v", result.Revision)
+	}
+	if len(result.Changed) != 1 || result.Changed[0].OldPrizeLevel != 1 || result.Changed[0].NewPrizeLevel != 2 || result.Notified != 1 {
+		t.Errorf("应重新核对并通知用户: changed=%+v notified=%d", result.Changed, result.Notified)
+	}
+
+	var userDraw model.UserDraw
+	if err := db.Where("user_number_id = ?", userNumber.ID).First(&userDraw).Error; err != nil {
+		t.Fatalf("读取核对结果失败: %v", err)
+	}
+	if userDraw.PrizeLevel != 2 {
+		t.Errorf("中奖记录应按更正后的号码核对: level=%d", userDraw.PrizeLevel)
+	}
+}
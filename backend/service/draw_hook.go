@@ -0,0 +1,34 @@
+package service
+
+import (
+	"sync"
+
+	"lucky/model"
+
+	"gorm.io/gorm"
+)
+
+// DrawResultHook 开奖结果新增或更正后的回调
+type DrawResultHook func(db *gorm.DB, game *model.LotteryGame, drawResult *model.DrawResult)
+
+var (
+	drawResultHooksMu sync.RWMutex
+	drawResultHooks   []DrawResultHook
+)
+
+// OnDrawResultChanged 注册开奖结果新增或更正后的回调，抓取、手动录入和更正都会触发
+func OnDrawResultChanged(hook DrawResultHook) {
+	drawResultHooksMu.Lock()
+	defer drawResultHooksMu.Unlock()
+	drawResultHooks = append(drawResultHooks, hook)
+}
+
+// notifyDrawResultChanged 依次执行已注册的回调
+func notifyDrawResultChanged(db *gorm.DB, game *model.LotteryGame, drawResult *model.DrawResult) {
+	drawResultHooksMu.RLock()
+	hooks := drawResultHooks
+	drawResultHooksMu.RUnlock()
+	for _, hook := range hooks {
+		hook(db, game, drawResult)
+	}
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"errors"
+
+	"lucky/common/metrics"
+	"lucky/model"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	OnDrawResultChanged(func(db *gorm.DB, game *model.LotteryGame, drawResult *model.DrawResult) {
+		metrics.SetLastDraw(game.GameCode, drawResult.Period, drawResult.DrawDate)
+	})
+}
+
+// RecordLatestDrawMetrics 启动时记录各游戏已保存的最新一期开奖，之后由新开奖结果更新
+func RecordLatestDrawMetrics(db *gorm.DB) error {
+	games, err := GetActiveGames(db)
+	if err != nil {
+		return err
+	}
+	for _, game := range games {
+		latest, err := GetLatestDrawResult(db, game.GameCode)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		metrics.SetLastDraw(game.GameCode, latest.Period, latest.DrawDate)
+	}
+	return nil
+}
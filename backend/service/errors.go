@@ -0,0 +1,11 @@
+package service
+
+import "lucky/common/response"
+
+// 服务层错误对应的接口错误码，处理函数直接将这些错误交给 response.Fail
+func init() {
+	response.Register(ErrJobRunning, response.ErrJobRunning)
+	response.Register(ErrInvalidJob, response.ErrInvalidParams)
+	response.Register(ErrDrawResultExists, response.ErrConflict)
+	response.Register(ErrDrawResultConflict, response.ErrConflict)
+}
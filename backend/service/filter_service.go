@@ -0,0 +1,366 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"lucky/model"
+
+	"gorm.io/gorm"
+)
+
+// maxEnumerateCombinations 组合总数超过该值时改为随机抽样估算
+const maxEnumerateCombinations = 2000000
+
+// filterEstimateSamples 抽样模式下估算剩余组合数的抽样次数，与生成注数无关，保证估算精度
+const filterEstimateSamples = 200000
+
+// maxFilterSamples 抽样模式下的最大抽样次数，满足条件的组合很少时继续抽样直到选够号码
+const maxFilterSamples = 500000
+
+// NumberFilter 号码缩水条件（零值表示不限制）
+type NumberFilter struct {
+	SumMin          int   `json:"sumMin"`          // 红球和值下限
+	SumMax          int   `json:"sumMax"`          // 红球和值上限
+	OddCount        *int  `json:"oddCount"`        // 红球奇数个数（决定奇偶比）
+	SpanMin         int   `json:"spanMin"`         // 红球跨度下限
+	SpanMax         int   `json:"spanMax"`         // 红球跨度上限
+	MaxConsecutive  int   `json:"maxConsecutive"`  // 最多允许几个连号
+	ExcludeLastDraw bool  `json:"excludeLastDraw"` // 排除上期开奖号码
+	IncludePool     []int `json:"includePool"`     // 胆码池
+	IncludeCount    int   `json:"includeCount"`    // 至少包含胆码池中的个数
+}
+
+// FilterStat 单个过滤条件后剩余的组合数
+type FilterStat struct {
+	Name      string `json:"name"`      // 过滤条件名称
+	Remaining int64  `json:"remaining"` // 剩余组合数
+}
+
+// GeneratedNumber 生成的一注号码
+type GeneratedNumber struct {
+	RedBalls  model.NumberArray `json:"redBalls"`
+	BlueBalls model.NumberArray `json:"blueBalls"`
+}
+
+// FilterGenerateResult 缩水生成结果
+type FilterGenerateResult struct {
+	Total     int64             `json:"total"`     // 红球组合总数
+	Remaining int64             `json:"remaining"` // 通过全部过滤条件的组合数
+	Estimated bool              `json:"estimated"` // 是否为抽样估算值
+	Stats     []FilterStat      `json:"stats"`     // 逐个过滤条件的剩余组合数
+	Numbers   []GeneratedNumber `json:"numbers"`   // 生成的号码
+}
+
+// redFilter 单个红球过滤条件
+type redFilter struct {
+	name  string
+	check func(balls []int) bool
+}
+
+// GenerateFilteredNumbers 按缩水条件生成号码
+func GenerateFilteredNumbers(db *gorm.DB, game *model.LotteryGame, filter NumberFilter, count int) (*FilterGenerateResult, error) {
+	var excludedRed, excludedBlue []int
+	if filter.ExcludeLastDraw {
+		lastDraw, err := GetLatestDrawResult(db, game.GameCode)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("暂无开奖数据，无法排除上期号码")
+			}
+			return nil, err
+		}
+		excludedRed = lastDraw.RedBalls
+		excludedBlue = lastDraw.BlueBalls
+	}
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return generateFiltered(game, filter, excludedRed, excludedBlue, count, rng)
+}
+
+// generateFiltered 在红球组合空间中按条件过滤并抽取count注号码
+func generateFiltered(game *model.LotteryGame, filter NumberFilter, excludedRed, excludedBlue []int, count int, rng *rand.Rand) (*FilterGenerateResult, error) {
+	if count <= 0 {
+		return nil, errors.New("生成注数必须大于0")
+	}
+	if err := validateFilter(game, filter); err != nil {
+		return nil, err
+	}
+
+	filters := buildRedFilters(game, filter, excludedRed)
+	n, k := game.RedBallCount, game.RedSelectCount
+	total := combinationCount(n, k)
+
+	result := &FilterGenerateResult{Total: total}
+	passed := make([]int64, len(filters))
+	var picks [][]int
+
+	if total <= maxEnumerateCombinations {
+		// 组合空间较小，完整枚举并用蓄水池抽样保留count注
+		var seen int64
+		forEachCombination(n, k, func(balls []int) {
+			if !applyRedFilters(filters, balls, passed) {
+				return
+			}
+			seen++
+			if len(picks) < count {
+				picks = append(picks, append([]int(nil), balls...))
+			} else if j := rng.Int63n(seen); j < int64(count) {
+				picks[j] = append([]int(nil), balls...)
+			}
+		})
+		for i, f := range filters {
+			result.Stats = append(result.Stats, FilterStat{Name: f.name, Remaining: passed[i]})
+		}
+		result.Remaining = seen
+	} else {
+		// 组合空间过大，随机抽样并按通过率估算剩余组合数
+		// 估算固定使用 filterEstimateSamples 次抽样，选够号码后仍继续抽样；号码不足时继续抽到 maxFilterSamples
+		unique := make(map[string]bool)
+		samples := 0
+		var hits int64
+		for samples < filterEstimateSamples || (len(picks) < count && samples < maxFilterSamples) {
+			samples++
+			balls := randomBalls(rng, n, k, nil)
+			if !applyRedFilters(filters, balls, passed) {
+				continue
+			}
+			hits++
+			if len(picks) >= count {
+				continue
+			}
+			key := fmt.Sprint(balls)
+			if unique[key] {
+				continue
+			}
+			unique[key] = true
+			picks = append(picks, balls)
+		}
+		for i, f := range filters {
+			result.Stats = append(result.Stats, FilterStat{Name: f.name, Remaining: total * passed[i] / int64(samples)})
+		}
+		result.Remaining = total * hits / int64(samples)
+		result.Estimated = true
+	}
+
+	if len(picks) == 0 {
+		return result, errors.New("没有满足条件的号码组合，请放宽过滤条件")
+	}
+
+	for _, red := range picks {
+		result.Numbers = append(result.Numbers, GeneratedNumber{
+			RedBalls:  model.NumberArray(red),
+			BlueBalls: model.NumberArray(randomBlueBalls(rng, game, excludedBlue)),
+		})
+	}
+
+	return result, nil
+}
+
+// validateFilter 校验缩水条件是否合法
+func validateFilter(game *model.LotteryGame, filter NumberFilter) error {
+	if IsDigitGame(game) || IsKenoGame(game) {
+		return fmt.Errorf("%s不支持缩水生成", game.GameName)
+	}
+	if filter.SumMax > 0 && filter.SumMin > filter.SumMax {
+		return errors.New("和值下限不能大于上限")
+	}
+	if filter.SpanMax > 0 && filter.SpanMin > filter.SpanMax {
+		return errors.New("跨度下限不能大于上限")
+	}
+	if filter.OddCount != nil && (*filter.OddCount < 0 || *filter.OddCount > game.RedSelectCount) {
+		return fmt.Errorf("奇数个数必须在0-%d之间", game.RedSelectCount)
+	}
+	if filter.MaxConsecutive < 0 {
+		return errors.New("连号个数不能为负数")
+	}
+	for _, ball := range filter.IncludePool {
+		if ball < 1 || ball > game.RedBallCount {
+			return fmt.Errorf("胆码超出范围(1-%d)", game.RedBallCount)
+		}
+	}
+	if filter.IncludeCount > len(filter.IncludePool) {
+		return errors.New("胆码个数不能大于胆码池大小")
+	}
+	if filter.IncludeCount > game.RedSelectCount {
+		return fmt.Errorf("胆码个数不能大于%d", game.RedSelectCount)
+	}
+	return nil
+}
+
+// buildRedFilters 根据条件构建过滤链，顺序即统计顺序
+func buildRedFilters(game *model.LotteryGame, filter NumberFilter, excludedRed []int) []redFilter {
+	var filters []redFilter
+
+	if len(excludedRed) > 0 {
+		excluded := make(map[int]bool)
+		for _, ball := range excludedRed {
+			excluded[ball] = true
+		}
+		filters = append(filters, redFilter{name: "排除上期号码", check: func(balls []int) bool {
+			for _, ball := range balls {
+				if excluded[ball] {
+					return false
+				}
+			}
+			return true
+		}})
+	}
+
+	if filter.SumMin > 0 || filter.SumMax > 0 {
+		filters = append(filters, redFilter{name: "和值", check: func(balls []int) bool {
+			sum := 0
+			for _, ball := range balls {
+				sum += ball
+			}
+			return sum >= filter.SumMin && (filter.SumMax == 0 || sum <= filter.SumMax)
+		}})
+	}
+
+	if filter.OddCount != nil {
+		oddCount := *filter.OddCount
+		filters = append(filters, redFilter{name: "奇偶比", check: func(balls []int) bool {
+			odd := 0
+			for _, ball := range balls {
+				if ball%2 == 1 {
+					odd++
+				}
+			}
+			return odd == oddCount
+		}})
+	}
+
+	if filter.SpanMin > 0 || filter.SpanMax > 0 {
+		filters = append(filters, redFilter{name: "跨度", check: func(balls []int) bool {
+			span := balls[len(balls)-1] - balls[0]
+			return span >= filter.SpanMin && (filter.SpanMax == 0 || span <= filter.SpanMax)
+		}})
+	}
+
+	if filter.MaxConsecutive > 0 {
+		filters = append(filters, redFilter{name: "连号", check: func(balls []int) bool {
+			return maxConsecutiveRun(balls) <= filter.MaxConsecutive
+		}})
+	}
+
+	if filter.IncludeCount > 0 {
+		pool := make(map[int]bool)
+		for _, ball := range filter.IncludePool {
+			pool[ball] = true
+		}
+		filters = append(filters, redFilter{name: "胆码", check: func(balls []int) bool {
+			hit := 0
+			for _, ball := range balls {
+				if pool[ball] {
+					hit++
+				}
+			}
+			return hit >= filter.IncludeCount
+		}})
+	}
+
+	return filters
+}
+
+// applyRedFilters 依次执行过滤条件并累计各条件通过数
+func applyRedFilters(filters []redFilter, balls []int, passed []int64) bool {
+	for i, f := range filters {
+		if !f.check(balls) {
+			return false
+		}
+		passed[i]++
+	}
+	return true
+}
+
+// maxConsecutiveRun 计算升序号码中最长连号长度
+func maxConsecutiveRun(balls []int) int {
+	if len(balls) == 0 {
+		return 0
+	}
+	longest, run := 1, 1
+	for i := 1; i < len(balls); i++ {
+		if balls[i] == balls[i-1]+1 {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 1
+		}
+	}
+	return longest
+}
+
+// combinationCount 计算组合数C(n,k)
+func combinationCount(n, k int) int64 {
+	if k < 0 || k > n {
+		return 0
+	}
+	if k > n-k {
+		k = n - k
+	}
+	var result int64 = 1
+	for i := 1; i <= k; i++ {
+		result = result * int64(n-k+i) / int64(i)
+	}
+	return result
+}
+
+// forEachCombination 按字典序遍历1..n中取k个数的全部组合
+func forEachCombination(n, k int, fn func(balls []int)) {
+	if k <= 0 || k > n {
+		return
+	}
+	balls := make([]int, k)
+	for i := range balls {
+		balls[i] = i + 1
+	}
+	for {
+		fn(balls)
+		i := k - 1
+		for i >= 0 && balls[i] == n-k+i+1 {
+			i--
+		}
+		if i < 0 {
+			return
+		}
+		balls[i]++
+		for j := i + 1; j < k; j++ {
+			balls[j] = balls[j-1] + 1
+		}
+	}
+}
+
+// randomBalls 从1..n中随机选取k个不重复号码（升序），excluded中的号码不参与
+func randomBalls(rng *rand.Rand, n, k int, excluded []int) []int {
+	skip := make(map[int]bool)
+	for _, ball := range excluded {
+		skip[ball] = true
+	}
+	candidates := make([]int, 0, n)
+	for i := 1; i <= n; i++ {
+		if !skip[i] {
+			candidates = append(candidates, i)
+		}
+	}
+	rng.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	balls := append([]int(nil), candidates[:k]...)
+	sort.Ints(balls)
+	return balls
+}
+
+// randomBlueBalls 随机生成蓝球，排除后候选不足时忽略排除条件
+func randomBlueBalls(rng *rand.Rand, game *model.LotteryGame, excludedBlue []int) []int {
+	if game.BlueSelectCount <= 0 {
+		return []int{}
+	}
+	if game.BlueBallCount-len(excludedBlue) < game.BlueSelectCount {
+		excludedBlue = nil
+	}
+	return randomBalls(rng, game.BlueBallCount, game.BlueSelectCount, excludedBlue)
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"lucky/model"
+)
+
+var testSSQGame = &model.LotteryGame{
+	GameCode:        "ssq",
+	RedBallCount:    33,
+	BlueBallCount:   16,
+	RedSelectCount:  6,
+	BlueSelectCount: 1,
+}
+
+func TestCombinationCount(t *testing.T) {
+	cases := []struct {
+		n, k int
+		want int64
+	}{
+		{33, 6, 1107568},
+		{35, 5, 324632},
+		{10, 0, 1},
+		{5, 6, 0},
+	}
+	for _, tc := range cases {
+		if got := combinationCount(tc.n, tc.k); got != tc.want {
+			t.Errorf("combinationCount(%d,%d) = %d, want %d", tc.n, tc.k, got, tc.want)
+		}
+	}
+}
+
+func TestMaxConsecutiveRun(t *testing.T) {
+	if got := maxConsecutiveRun([]int{1, 2, 3, 7, 8, 20}); got != 3 {
+		t.Errorf("maxConsecutiveRun = %d, want 3", got)
+	}
+	if got := maxConsecutiveRun([]int{1, 3, 5}); got != 1 {
+		t.Errorf("maxConsecutiveRun = %d, want 1", got)
+	}
+}
+
+func TestGenerateFilteredSatisfiesFilter(t *testing.T) {
+	odd := 3
+	filter := NumberFilter{
+		SumMin:         90,
+		SumMax:         120,
+		OddCount:       &odd,
+		SpanMin:        20,
+		MaxConsecutive: 2,
+		IncludePool:    []int{1, 7, 15, 22},
+		IncludeCount:   1,
+	}
+	lastRed := []int{3, 9, 12, 18, 26, 31}
+	rng := rand.New(rand.NewSource(1))
+
+	result, err := generateFiltered(testSSQGame, filter, lastRed, []int{5}, 10, rng)
+	if err != nil {
+		t.Fatalf("generateFiltered error = %v", err)
+	}
+	if result.Estimated {
+		t.Error("SSQ组合空间应完整枚举")
+	}
+	if len(result.Stats) != 6 {
+		t.Fatalf("len(Stats) = %d, want 6", len(result.Stats))
+	}
+	for i := 1; i < len(result.Stats); i++ {
+		if result.Stats[i].Remaining > result.Stats[i-1].Remaining {
+			t.Errorf("过滤后组合数不应增加: %+v", result.Stats)
+		}
+	}
+	if result.Remaining != result.Stats[len(result.Stats)-1].Remaining {
+		t.Errorf("Remaining = %d, want %d", result.Remaining, result.Stats[len(result.Stats)-1].Remaining)
+	}
+	if len(result.Numbers) != 10 {
+		t.Fatalf("len(Numbers) = %d, want 10", len(result.Numbers))
+	}
+
+	for _, number := range result.Numbers {
+		if err := ValidateNumbers(testSSQGame, number.RedBalls, number.BlueBalls); err != nil {
+			t.Fatalf("生成号码不合法: %v", err)
+		}
+		if number.BlueBalls[0] == 5 {
+			t.Errorf("蓝球应排除上期号码: %v", number.BlueBalls)
+		}
+		sum, oddCount := 0, 0
+		for _, ball := range number.RedBalls {
+			sum += ball
+			if ball%2 == 1 {
+				oddCount++
+			}
+			for _, last := range lastRed {
+				if ball == last {
+					t.Errorf("红球应排除上期号码: %v", number.RedBalls)
+				}
+			}
+		}
+		if sum < 90 || sum > 120 || oddCount != 3 {
+			t.Errorf("号码不满足和值/奇偶条件: %v", number.RedBalls)
+		}
+		if number.RedBalls[5]-number.RedBalls[0] < 20 {
+			t.Errorf("号码不满足跨度条件: %v", number.RedBalls)
+		}
+		if maxConsecutiveRun(number.RedBalls) > 2 {
+			t.Errorf("号码不满足连号条件: %v", number.RedBalls)
+		}
+	}
+}
+
+// TestGenerateFilteredEstimate 七乐彩组合数超过枚举上限，按抽样估算剩余组合数，估算不受生成注数影响
+func TestGenerateFilteredEstimate(t *testing.T) {
+	if combinationCount(testQLCGame.RedBallCount, testQLCGame.RedSelectCount) <= maxEnumerateCombinations {
+		t.Fatal("七乐彩组合数应超过枚举上限")
+	}
+	// 30个号码中奇偶各15个，3奇4偶的组合数为 C(15,3)*C(15,4)
+	oddCount := 3
+	exact := combinationCount(15, 3) * combinationCount(15, 4)
+
+	for _, count := range []int{1, 5, 50} {
+		result, err := generateFiltered(testQLCGame, NumberFilter{OddCount: &oddCount}, nil, nil, count, rand.New(rand.NewSource(int64(count))))
+		if err != nil {
+			t.Fatalf("generateFiltered: %v", err)
+		}
+		if !result.Estimated || len(result.Numbers) != count {
+			t.Fatalf("应为抽样估算并生成 %d 注, got estimated=%v numbers=%d", count, result.Estimated, len(result.Numbers))
+		}
+		if diff := math.Abs(float64(result.Remaining-exact)) / float64(exact); diff > 0.03 {
+			t.Errorf("生成 %d 注时剩余组合数估算 %d 与实际 %d 相差 %.1f%%", count, result.Remaining, exact, diff*100)
+		}
+		if len(result.Stats) != 1 || result.Stats[0].Remaining != result.Remaining {
+			t.Errorf("过滤统计 = %+v", result.Stats)
+		}
+	}
+}
+
+func TestGenerateFilteredNoSurvivors(t *testing.T) {
+	filter := NumberFilter{SumMin: 500}
+	_, err := generateFiltered(testSSQGame, filter, nil, nil, 1, rand.New(rand.NewSource(1)))
+	if err == nil {
+		t.Fatal("期望无满足条件的组合时返回错误")
+	}
+}
+
+func TestValidateFilter(t *testing.T) {
+	filter := NumberFilter{IncludePool: []int{1, 2}, IncludeCount: 3}
+	if err := validateFilter(testSSQGame, filter); err == nil {
+		t.Error("胆码个数大于胆码池时应返回错误")
+	}
+	filter = NumberFilter{IncludePool: []int{40}, IncludeCount: 1}
+	if err := validateFilter(testSSQGame, filter); err == nil {
+		t.Error("胆码超出范围时应返回错误")
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"lucky/model"
+
+	"gorm.io/gorm"
+)
+
+// gameRegistryTTL 游戏注册表缓存有效期，过期后下次查询时重新加载
+const gameRegistryTTL = 5 * time.Minute
+
+// gameRegistry 按游戏代码缓存 lottery_games 中的游戏配置（含未启用的游戏）
+type gameRegistry struct {
+	mu       sync.RWMutex
+	games    map[string]model.LotteryGame
+	codes    []string // 按游戏ID排序的游戏代码
+	loadedAt time.Time
+}
+
+// registry 全局游戏注册表
+var registry = &gameRegistry{}
+
+// set 替换注册表中的全部游戏
+func (r *gameRegistry) set(games []model.LotteryGame) {
+	sort.Slice(games, func(i, j int) bool {
+		return games[i].ID < games[j].ID
+	})
+	byCode := make(map[string]model.LotteryGame, len(games))
+	codes := make([]string, 0, len(games))
+	for _, game := range games {
+		byCode[game.GameCode] = game
+		codes = append(codes, game.GameCode)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.games = byCode
+	r.codes = codes
+	r.loadedAt = time.Now()
+}
+
+// fresh 注册表是否已加载且未过期
+func (r *gameRegistry) fresh() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.games != nil && time.Since(r.loadedAt) < gameRegistryTTL
+}
+
+// ensureLoaded 注册表未加载或已过期时从数据库重新加载
+// 数据库不可用时沿用已缓存的数据
+func (r *gameRegistry) ensureLoaded(db *gorm.DB) error {
+	if r.fresh() {
+		return nil
+	}
+	if db == nil {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+		if r.games == nil {
+			return errors.New("游戏注册表未加载")
+		}
+		return nil
+	}
+	return LoadGameRegistry(db)
+}
+
+// LoadGameRegistry 从数据库加载全部游戏到注册表
+func LoadGameRegistry(db *gorm.DB) error {
+	var games []model.LotteryGame
+	if err := db.Find(&games).Error; err != nil {
+		return err
+	}
+	registry.set(games)
+	return nil
+}
+
+// InvalidateGameRegistry 使游戏注册表失效，下次查询时重新加载
+func InvalidateGameRegistry() {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.loadedAt = time.Time{}
+}
+
+// LookupGame 按游戏代码查询游戏（含未启用的游戏），不存在时返回 gorm.ErrRecordNotFound
+func LookupGame(db *gorm.DB, gameCode string) (*model.LotteryGame, error) {
+	if err := registry.ensureLoaded(db); err != nil {
+		return nil, err
+	}
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	game, ok := registry.games[gameCode]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &game, nil
+}
+
+// RegisteredGames 注册表中的全部游戏，按游戏ID排序
+func RegisteredGames(db *gorm.DB) ([]model.LotteryGame, error) {
+	if err := registry.ensureLoaded(db); err != nil {
+		return nil, err
+	}
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	games := make([]model.LotteryGame, 0, len(registry.codes))
+	for _, code := range registry.codes {
+		games = append(games, registry.games[code])
+	}
+	return games, nil
+}
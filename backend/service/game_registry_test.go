@@ -0,0 +1,51 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"lucky/model"
+
+	"gorm.io/gorm"
+)
+
+func TestGameRegistry(t *testing.T) {
+	registry.set([]model.LotteryGame{
+		{ID: 2, GameCode: "dlt", GameName: "大乐透", IsActive: true},
+		{ID: 1, GameCode: "ssq", GameName: "双色球", IsActive: true},
+		{ID: 3, GameCode: "pl5", GameName: "排列五", IsActive: false},
+	})
+	defer InvalidateGameRegistry()
+
+	game, err := LookupGame(nil, "ssq")
+	if err != nil || game.GameName != "双色球" {
+		t.Fatalf("LookupGame(ssq) = %+v, %v", game, err)
+	}
+	// 返回副本，修改不影响注册表
+	game.GameName = "改名"
+	if again, _ := LookupGame(nil, "ssq"); again.GameName != "双色球" {
+		t.Errorf("注册表被调用方修改: %s", again.GameName)
+	}
+
+	if _, err := LookupGame(nil, "unknown"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("未知游戏应返回 ErrRecordNotFound, got %v", err)
+	}
+	if _, err := GetGameByCode(nil, "pl5"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("未启用的游戏应返回 ErrRecordNotFound, got %v", err)
+	}
+
+	games, err := GetActiveGames(nil)
+	if err != nil || len(games) != 2 || games[0].GameCode != "ssq" || games[1].GameCode != "dlt" {
+		t.Errorf("启用游戏应按ID排序: %+v %v", games, err)
+	}
+}
+
+func TestGameRegistryNotLoaded(t *testing.T) {
+	registry.mu.Lock()
+	registry.games = nil
+	registry.mu.Unlock()
+
+	if _, err := LookupGame(nil, "ssq"); err == nil {
+		t.Error("注册表未加载且数据库不可用时应返回错误")
+	}
+}
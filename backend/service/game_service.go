@@ -8,16 +8,29 @@ import (
 
 // GetActiveGames 获取所有启用的游戏
 func GetActiveGames(db *gorm.DB) ([]model.LotteryGame, error) {
-	var games []model.LotteryGame
-	err := db.Where("is_active = ?", true).Find(&games).Error
-	return games, err
+	games, err := RegisteredGames(db)
+	if err != nil {
+		return nil, err
+	}
+	active := make([]model.LotteryGame, 0, len(games))
+	for _, game := range games {
+		if game.IsActive {
+			active = append(active, game)
+		}
+	}
+	return active, nil
 }
 
-// GetGameByCode 根据游戏代码获取游戏
+// GetGameByCode 根据游戏代码获取启用的游戏，不存在或未启用时返回 gorm.ErrRecordNotFound
 func GetGameByCode(db *gorm.DB, gameCode string) (*model.LotteryGame, error) {
-	var game model.LotteryGame
-	err := db.Where("game_code = ? AND is_active = ?", gameCode, true).First(&game).Error
-	return &game, err
+	game, err := LookupGame(db, gameCode)
+	if err != nil {
+		return &model.LotteryGame{}, err
+	}
+	if !game.IsActive {
+		return &model.LotteryGame{}, gorm.ErrRecordNotFound
+	}
+	return game, nil
 }
 
 // GetGameByID 根据ID获取游戏
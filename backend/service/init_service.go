@@ -16,41 +16,104 @@ func InitializeData(db *gorm.DB) error {
 	return nil
 }
 
-// initLotteryGames 初始化彩票游戏数据
+// initLotteryGames 初始化彩票游戏数据，按游戏代码补齐缺失的游戏
 func initLotteryGames(db *gorm.DB) error {
-	// 检查是否已存在游戏数据
-	var count int64
-	if err := db.Model(&model.LotteryGame{}).Count(&count).Error; err != nil {
-		return err
-	}
-
-	// 如果已有数据，则不需要初始化
-	if count > 0 {
-		return nil
+	games := []model.LotteryGame{
+		// 双色球
+		{
+			GameCode:        "ssq",
+			GameName:        "双色球",
+			GameType:        model.GameTypeBall,
+			RedBallCount:    33,
+			BlueBallCount:   16,
+			RedSelectCount:  6,
+			BlueSelectCount: 1,
+			IsActive:        true,
+		},
+		// 大乐透
+		{
+			GameCode:        "dlt",
+			GameName:        "大乐透",
+			GameType:        model.GameTypeBall,
+			RedBallCount:    35,
+			BlueBallCount:   12,
+			RedSelectCount:  5,
+			BlueSelectCount: 2,
+			IsActive:        true,
+		},
+		// 福彩3D：3位，每位0-9
+		{
+			GameCode:       "fc3d",
+			GameName:       "福彩3D",
+			GameType:       model.GameTypeDigit,
+			RedBallCount:   10,
+			RedSelectCount: 3,
+			IsActive:       true,
+		},
+		// 排列三：3位，每位0-9
+		{
+			GameCode:       "pl3",
+			GameName:       "排列三",
+			GameType:       model.GameTypeDigit,
+			RedBallCount:   10,
+			RedSelectCount: 3,
+			IsActive:       true,
+		},
+		// 排列五：5位，每位0-9
+		{
+			GameCode:       "pl5",
+			GameName:       "排列五",
+			GameType:       model.GameTypeDigit,
+			RedBallCount:   10,
+			RedSelectCount: 5,
+			IsActive:       true,
+		},
+		// 快乐8：每期从1-80开出20个号码，玩家选1-10个号码
+		{
+			GameCode:       "kl8",
+			GameName:       "快乐8",
+			GameType:       model.GameTypeKeno,
+			RedBallCount:   80,
+			RedSelectCount: 10,
+			IsActive:       true,
+		},
+		// 七乐彩：从1-30中选7个号码，另开出1个特别号
+		{
+			GameCode:         "qlc",
+			GameName:         "七乐彩",
+			GameType:         model.GameTypeBall,
+			RedBallCount:     30,
+			RedSelectCount:   7,
+			SpecialBallCount: 1,
+			IsActive:         true,
+		},
+		// 七星彩：前区6位每位0-9，后区1位0-14
+		{
+			GameCode:        "qxc",
+			GameName:        "七星彩",
+			GameType:        model.GameTypeDigit,
+			RedBallCount:    10,
+			BlueBallCount:   15,
+			RedSelectCount:  6,
+			BlueSelectCount: 1,
+			IsActive:        true,
+		},
 	}
 
-	// 初始化双色球
-	ssq := model.LotteryGame{
-		GameCode:        "ssq",
-		GameName:        "双色球",
-		RedBallCount:    33,
-		BlueBallCount:   16,
-		RedSelectCount:  6,
-		BlueSelectCount: 1,
-		IsActive:        true,
+	for i := range games {
+		// 检查是否已存在该游戏
+		var count int64
+		if err := db.Model(&model.LotteryGame{}).Where("game_code = ?", games[i].GameCode).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		if err := db.Create(&games[i]).Error; err != nil {
+			return err
+		}
 	}
-
-	// 初始化大乐透
-	dlt := model.LotteryGame{
-		GameCode:        "dlt",
-		GameName:        "大乐透",
-		RedBallCount:    35,
-		BlueBallCount:   12,
-		RedSelectCount:  5,
-		BlueSelectCount: 2,
-		IsActive:        true,
-	}
-
-	// 批量创建
-	return db.Create([]*model.LotteryGame{&ssq, &dlt}).Error
+	// 新增游戏后重新加载游戏注册表
+	InvalidateGameRegistry()
+	return nil
 }
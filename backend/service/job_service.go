@@ -0,0 +1,365 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"lucky/model"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrJobRunning 同一任务（同一并发控制键）正在执行
+	ErrJobRunning = errors.New("任务正在执行")
+	// ErrInvalidJob 任务名称或参数错误
+	ErrInvalidJob = errors.New("任务参数错误")
+)
+
+// maxJobOutputLength 任务执行记录中输出的最大长度
+const maxJobOutputLength = 4000
+
+// maxJobErrorLength 任务执行记录中失败原因的最大长度
+const maxJobErrorLength = 1000
+
+// jobStaleAfter 超过该时间仍为执行中的记录视为进程异常退出遗留，不再阻止新任务执行
+const jobStaleAfter = 2 * time.Hour
+
+// 任务参数类型
+const (
+	JobParamString = "string"
+	JobParamInt    = "int"
+)
+
+// JobParamSpec 任务参数定义
+type JobParamSpec struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"` // string, int
+	Required    bool   `json:"required"`
+	Default     string `json:"default,omitempty"`
+	Min         int    `json:"min,omitempty"` // 整数参数的取值范围，0表示不限制
+	Max         int    `json:"max,omitempty"`
+	Description string `json:"description"`
+}
+
+// JobParams 按参数定义校验后的任务参数
+type JobParams map[string]interface{}
+
+// String 读取字符串参数
+func (p JobParams) String(name string) string {
+	value, _ := p[name].(string)
+	return value
+}
+
+// Int 读取整数参数
+func (p JobParams) Int(name string) int {
+	value, _ := p[name].(int)
+	return value
+}
+
+// JobInvocation 一次任务调用的上下文
+type JobInvocation struct {
+	DB         *gorm.DB
+	Params     JobParams
+	Trigger    string
+	OperatorID int64
+}
+
+// Job 已注册的命名任务
+type Job struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Params      []JobParamSpec `json:"params"`
+	// LockParam 并发控制参数：同一任务该参数值相同时同时只执行一个，为空时整个任务同时只执行一个
+	LockParam string                                                        `json:"-"`
+	Run       func(ctx context.Context, inv *JobInvocation) (string, error) `json:"-"`
+}
+
+// Usage 任务命令格式，如 "backfill <gameCode> [pages]"
+func (j *Job) Usage() string {
+	parts := []string{j.Name}
+	for _, spec := range j.Params {
+		if spec.Required {
+			parts = append(parts, "<"+spec.Name+">")
+		} else {
+			parts = append(parts, "["+spec.Name+"]")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// jobRegistry 任务名称到任务的映射
+var jobRegistry = map[string]*Job{}
+
+// runningJobs 本进程内正在执行的并发控制键
+var (
+	runningJobsMu sync.Mutex
+	runningJobs   = map[string]bool{}
+)
+
+// RegisterJob 注册命名任务，名称重复时覆盖
+func RegisterJob(job *Job) {
+	jobRegistry[job.Name] = job
+}
+
+// LookupJob 根据名称查找任务
+func LookupJob(name string) (*Job, bool) {
+	job, ok := jobRegistry[strings.ToLower(name)]
+	return job, ok
+}
+
+// RegisteredJobs 全部已注册的任务，按名称排序
+func RegisteredJobs() []*Job {
+	jobs := make([]*Job, 0, len(jobRegistry))
+	for _, job := range jobRegistry {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].Name < jobs[j].Name
+	})
+	return jobs
+}
+
+// jobNames 已注册的任务名称
+func jobNames() string {
+	names := make([]string, 0, len(jobRegistry))
+	for _, job := range RegisteredJobs() {
+		names = append(names, job.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// ParseJobCommand 解析 "任务名 参数..." 格式的任务命令，参数按定义顺序依次对应
+// 兼容旧的 gocron 任务配置：命令只有游戏代码（如 "ssq"）时按抓取最新一期处理
+func ParseJobCommand(command string) (*Job, JobParams, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil, nil, fmt.Errorf("%w: 任务命令不能为空", ErrInvalidJob)
+	}
+	job, ok := LookupJob(fields[0])
+	args := fields[1:]
+	if !ok {
+		if len(fields) > 1 {
+			return nil, nil, fmt.Errorf("%w: 未知任务 %s，支持的任务: %s", ErrInvalidJob, fields[0], jobNames())
+		}
+		job, _ = LookupJob("crawl")
+		args = fields
+	}
+	if len(args) > len(job.Params) {
+		return nil, nil, fmt.Errorf("%w: 参数过多，用法: %s", ErrInvalidJob, job.Usage())
+	}
+
+	values := make(map[string]string, len(args))
+	for i, arg := range args {
+		values[job.Params[i].Name] = arg
+	}
+	params, err := job.ParseParams(values)
+	if err != nil {
+		return nil, nil, err
+	}
+	return job, params, nil
+}
+
+// ParseParams 按参数定义校验并转换参数，未提供的参数使用默认值
+func (j *Job) ParseParams(values map[string]string) (JobParams, error) {
+	params := make(JobParams, len(j.Params))
+	for _, spec := range j.Params {
+		raw := strings.TrimSpace(values[spec.Name])
+		if raw == "" {
+			raw = spec.Default
+		}
+		if raw == "" {
+			if spec.Required {
+				return nil, fmt.Errorf("%w: 缺少参数 %s，用法: %s", ErrInvalidJob, spec.Name, j.Usage())
+			}
+			continue
+		}
+
+		switch spec.Type {
+		case JobParamInt:
+			value, err := strconv.Atoi(raw)
+			if err != nil {
+				return nil, fmt.Errorf("%w: 参数 %s 必须是整数: %s", ErrInvalidJob, spec.Name, raw)
+			}
+			if (spec.Min != 0 && value < spec.Min) || (spec.Max != 0 && value > spec.Max) {
+				return nil, fmt.Errorf("%w: 参数 %s 需在%d-%d之间", ErrInvalidJob, spec.Name, spec.Min, spec.Max)
+			}
+			params[spec.Name] = value
+		default:
+			params[spec.Name] = raw
+		}
+	}
+	return params, nil
+}
+
+// lockKey 任务的并发控制键
+func (j *Job) lockKey(params JobParams) string {
+	if j.LockParam == "" {
+		return ""
+	}
+	return fmt.Sprint(params[j.LockParam])
+}
+
+// acquireJobLock 获取本进程内任务的并发控制键，正在执行时返回 ErrJobRunning
+// 其他进程的并发由执行记录的 running_key 唯一索引控制，见 RunJob
+func acquireJobLock(key string) (func(), error) {
+	runningJobsMu.Lock()
+	defer runningJobsMu.Unlock()
+	if runningJobs[key] {
+		return nil, fmt.Errorf("%s %w", strings.TrimSuffix(key, ":"), ErrJobRunning)
+	}
+
+	runningJobs[key] = true
+	return func() {
+		runningJobsMu.Lock()
+		delete(runningJobs, key)
+		runningJobsMu.Unlock()
+	}, nil
+}
+
+// RunJob 执行任务并保存执行记录，返回的记录中包含任务输出；数据库不可用时只执行不保存
+// ctx 超时或取消、或服务关闭时立即返回错误并记录失败，任务本身在后台执行完毕后才释放并发控制键
+func RunJob(ctx context.Context, db *gorm.DB, job *Job, params JobParams, trigger string, operatorID int64) (*model.JobRun, error) {
+	lockKey := job.lockKey(params)
+	runningKey := job.Name + ":" + lockKey
+	release, err := acquireJobLock(runningKey)
+	if err != nil {
+		return nil, err
+	}
+
+	paramsJSON, _ := json.Marshal(params)
+	run := &model.JobRun{
+		JobName:    job.Name,
+		LockKey:    lockKey,
+		RunningKey: &runningKey,
+		Params:     string(paramsJSON),
+		Trigger:    trigger,
+		OperatorID: operatorID,
+		Status:     model.JobStatusRunning,
+		StartedAt:  time.Now(),
+	}
+	if db != nil {
+		created, err := model.NewJobRunDAO(db).CreateRunning(run, time.Now().Add(-jobStaleAfter))
+		if err != nil || !created {
+			release()
+			if err != nil {
+				return nil, fmt.Errorf("创建任务执行记录失败: %v", err)
+			}
+			return nil, fmt.Errorf("%s %w", strings.TrimSuffix(runningKey, ":"), ErrJobRunning)
+		}
+		// 任务在后台执行完毕后释放执行记录占用的键，超时返回后其他进程仍不能重复执行
+		releaseLocal := release
+		release = func() {
+			if err := model.NewJobRunDAO(db).ReleaseRunning(run.ID); err != nil {
+				dbLogger(db).WithField("job", run.JobName).Warnf("释放任务并发控制键失败: %v", err)
+			}
+			releaseLocal()
+		}
+	}
+
+	type jobResult struct {
+		output string
+		err    error
+	}
+	// 服务关闭时取消进行中的任务
+	jobCtx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(BaseContext(), cancel)
+	done := make(chan jobResult, 1)
+	goBackground(func() {
+		defer cancel()
+		defer stop()
+		result := func() (result jobResult) {
+			defer func() {
+				if r := recover(); r != nil {
+					result = jobResult{err: fmt.Errorf("任务异常: %v", r)}
+				}
+			}()
+			output, err := job.Run(jobCtx, &JobInvocation{DB: db, Params: params, Trigger: trigger, OperatorID: operatorID})
+			return jobResult{output: output, err: err}
+		}()
+		// 先释放并发控制键再返回结果，调用方拿到结果后即可再次执行
+		release()
+		done <- result
+	})
+
+	var result jobResult
+	select {
+	case result = <-done:
+	case <-jobCtx.Done():
+		// 任务完成后 cancel 也会使 jobCtx 结束，此时结果已在 done 中，不能记为超时或取消
+		select {
+		case result = <-done:
+		default:
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				result.err = fmt.Errorf("任务 %s 执行超时", job.Name)
+			} else {
+				result.err = fmt.Errorf("任务 %s 已取消", job.Name)
+			}
+		}
+	}
+
+	finishJobRun(db, run, result.output, result.err)
+	return run, result.err
+}
+
+// finishJobRun 记录任务执行结果，保存失败只打印日志
+func finishJobRun(db *gorm.DB, run *model.JobRun, output string, jobErr error) {
+	now := time.Now()
+	run.FinishedAt = &now
+	run.DurationMs = now.Sub(run.StartedAt).Milliseconds()
+	run.Output = truncateRunes(output, maxJobOutputLength)
+	run.Status = model.JobStatusSuccess
+	if jobErr != nil {
+		run.Status = model.JobStatusFailed
+		run.Error = truncateRunes(jobErr.Error(), maxJobErrorLength)
+	}
+	if db == nil {
+		return
+	}
+	if err := model.NewJobRunDAO(db).Finish(run); err != nil {
+		dbLogger(db).WithField("job", run.JobName).Warnf("更新任务执行记录失败: %v", err)
+	}
+}
+
+// truncateRunes 按字符截断字符串
+func truncateRunes(text string, maxLength int) string {
+	runes := []rune(text)
+	if len(runes) <= maxLength {
+		return text
+	}
+	return string(runes[:maxLength])
+}
+
+// RunJobByName 按名称和参数执行任务
+func RunJobByName(ctx context.Context, db *gorm.DB, name string, values map[string]string, trigger string, operatorID int64) (*model.JobRun, error) {
+	job, ok := LookupJob(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: 未知任务 %s，支持的任务: %s", ErrInvalidJob, name, jobNames())
+	}
+	params, err := job.ParseParams(values)
+	if err != nil {
+		return nil, err
+	}
+	return RunJob(ctx, db, job, params, trigger, operatorID)
+}
+
+// RunJobCommand 解析并执行 "任务名 参数..." 格式的任务命令
+func RunJobCommand(ctx context.Context, db *gorm.DB, command, trigger string) (*model.JobRun, error) {
+	job, params, err := ParseJobCommand(command)
+	if err != nil {
+		return nil, err
+	}
+	return RunJob(ctx, db, job, params, trigger, 0)
+}
+
+// ListJobRuns 分页获取任务执行记录
+func ListJobRuns(db *gorm.DB, jobName, status string, page, pageSize int) ([]*model.JobRun, int64, error) {
+	return model.NewJobRunDAO(db).List(jobName, status, (page-1)*pageSize, pageSize)
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"lucky/common/database/dbtest"
+	"lucky/model"
+)
+
+func TestParseJobCommand(t *testing.T) {
+	cases := []struct {
+		command string
+		wantJob string
+		want    JobParams
+		wantErr bool
+	}{
+		{"crawl ssq", "crawl", JobParams{"gameCode": "ssq"}, false},
+		{"ssq", "crawl", JobParams{"gameCode": "ssq"}, false},
+		{"BACKFILL dlt", "backfill", JobParams{"gameCode": "dlt", "pages": 1}, false},
+		{"backfill dlt 5", "backfill", JobParams{"gameCode": "dlt", "pages": 5}, false},
+		{"stats kl8 50", "stats", JobParams{"gameCode": "kl8", "periodCount": 50}, false},
+		{"evaluate ssq 2024001", "evaluate", JobParams{"gameCode": "ssq", "period": "2024001"}, false},
+		{"echo", "echo", JobParams{}, false},
+		{"", "", nil, true},
+		{"crawl", "", nil, true},
+		{"backfill dlt abc", "", nil, true},
+		{"backfill dlt 0", "", nil, true},
+		{"backfill dlt 101", "", nil, true},
+		{"crawl ssq extra", "", nil, true},
+		{"unknown ssq", "", nil, true},
+	}
+
+	for _, tc := range cases {
+		job, params, err := ParseJobCommand(tc.command)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%q: 应返回错误", tc.command)
+			} else if !errors.Is(err, ErrInvalidJob) {
+				t.Errorf("%q: 错误应为 ErrInvalidJob, got %v", tc.command, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.command, err)
+			continue
+		}
+		if job.Name != tc.wantJob {
+			t.Errorf("%q: job = %s, want %s", tc.command, job.Name, tc.wantJob)
+		}
+		if len(params) != len(tc.want) {
+			t.Errorf("%q: params = %v, want %v", tc.command, params, tc.want)
+			continue
+		}
+		for name, value := range tc.want {
+			if params[name] != value {
+				t.Errorf("%q: params[%s] = %v, want %v", tc.command, name, params[name], value)
+			}
+		}
+	}
+}
+
+func TestJobUsage(t *testing.T) {
+	job, _ := LookupJob("backfill")
+	if got := job.Usage(); got != "backfill <gameCode> [pages]" {
+		t.Errorf("Usage() = %q", got)
+	}
+}
+
+func TestRunJobWithoutDB(t *testing.T) {
+	job, _ := LookupJob("echo")
+	run, err := RunJob(context.Background(), nil, job, JobParams{"text": "hello"}, model.JobTriggerCLI, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if run.Status != model.JobStatusSuccess || run.Output != "hello" || run.Trigger != model.JobTriggerCLI {
+		t.Errorf("run = %+v", run)
+	}
+	if run.FinishedAt == nil || run.Params != `{"text":"hello"}` {
+		t.Errorf("run = %+v", run)
+	}
+}
+
+func TestRunJobConcurrency(t *testing.T) {
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	job := &Job{
+		Name:      "test_block",
+		Params:    []JobParamSpec{{Name: "key", Type: JobParamString, Required: true}},
+		LockParam: "key",
+		Run: func(ctx context.Context, inv *JobInvocation) (string, error) {
+			close(started)
+			<-finish
+			return "done", nil
+		},
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := RunJob(context.Background(), nil, job, JobParams{"key": "a"}, model.JobTriggerCLI, 0)
+		done <- err
+	}()
+	<-started
+
+	// 同一并发控制键正在执行时拒绝
+	if _, err := RunJob(context.Background(), nil, job, JobParams{"key": "a"}, model.JobTriggerCLI, 0); !errors.Is(err, ErrJobRunning) {
+		t.Errorf("同一并发控制键应返回 ErrJobRunning, got %v", err)
+	}
+
+	// 不同并发控制键不受影响
+	other := *job
+	other.Run = func(ctx context.Context, inv *JobInvocation) (string, error) { return "other", nil }
+	if run, err := RunJob(context.Background(), nil, &other, JobParams{"key": "b"}, model.JobTriggerCLI, 0); err != nil || run.Output != "other" {
+		t.Errorf("不同并发控制键应可执行, run=%+v err=%v", run, err)
+	}
+
+	close(finish)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 执行完毕后释放并发控制键
+	other.Run = func(ctx context.Context, inv *JobInvocation) (string, error) { return "again", nil }
+	if _, err := RunJob(context.Background(), nil, &other, JobParams{"key": "a"}, model.JobTriggerCLI, 0); err != nil {
+		t.Errorf("执行完毕后应可再次执行, got %v", err)
+	}
+}
+
+func TestRunJobTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	job := &Job{
+		// 超时后任务仍在后台执行，使用唯一名称避免影响重复执行的测试
+		Name: fmt.Sprintf("test_slow_%d", time.Now().UnixNano()),
+		Run: func(ctx context.Context, inv *JobInvocation) (string, error) {
+			<-release
+			return "", nil
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	run, err := RunJob(ctx, nil, job, JobParams{}, model.JobTriggerGocron, 0)
+	if err == nil {
+		t.Fatal("超时应返回错误")
+	}
+	if run == nil || run.Status != model.JobStatusFailed || run.Error == "" {
+		t.Errorf("超时应记录失败, run=%+v", run)
+	}
+}
+
+func TestRunJobCanceledOnShutdown(t *testing.T) {
+	base, cancel := context.WithCancel(context.Background())
+	SetBaseContext(base)
+	defer SetBaseContext(context.Background())
+
+	started := make(chan struct{})
+	job := &Job{
+		Name: fmt.Sprintf("test_shutdown_%d", time.Now().UnixNano()),
+		Run: func(ctx context.Context, inv *JobInvocation) (string, error) {
+			close(started)
+			<-ctx.Done()
+			return "", ctx.Err()
+		},
+	}
+
+	go func() {
+		<-started
+		cancel()
+	}()
+	run, err := RunJob(context.Background(), nil, job, JobParams{}, model.JobTriggerGocron, 0)
+	if err == nil {
+		t.Fatal("服务关闭时任务应被取消")
+	}
+	if run == nil || run.Status != model.JobStatusFailed {
+		t.Errorf("取消应记录失败, run=%+v", run)
+	}
+
+	ctx, stop := context.WithTimeout(context.Background(), time.Second)
+	defer stop()
+	if err := WaitBackground(ctx); err != nil {
+		t.Errorf("任务取消后应能等到后台任务结束: %v", err)
+	}
+}
+
+func TestRunJobPanic(t *testing.T) {
+	job := &Job{
+		Name: "test_panic",
+		Run: func(ctx context.Context, inv *JobInvocation) (string, error) {
+			panic("boom")
+		},
+	}
+
+	run, err := RunJob(context.Background(), nil, job, JobParams{}, model.JobTriggerCLI, 0)
+	if err == nil || run.Status != model.JobStatusFailed {
+		t.Errorf("任务异常应记录失败, run=%+v err=%v", run, err)
+	}
+	if _, err := RunJob(context.Background(), nil, job, JobParams{}, model.JobTriggerCLI, 0); errors.Is(err, ErrJobRunning) {
+		t.Error("任务异常后应释放并发控制键")
+	}
+}
+
+func TestRunJobRecordsRun(t *testing.T) {
+	db := dbtest.Open(t)
+	job, _ := LookupJob("echo")
+	run, err := RunJob(context.Background(), db, job, JobParams{"text": "hello"}, model.JobTriggerAPI, 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	runs, total, err := ListJobRuns(db, "echo", model.JobStatusSuccess, 1, 10)
+	if err != nil {
+		t.Fatalf("ListJobRuns: %v", err)
+	}
+	if total != 1 || len(runs) != 1 || runs[0].ID != run.ID {
+		t.Fatalf("应记录1次执行, total=%d runs=%+v", total, runs)
+	}
+	if runs[0].Output != "hello" || runs[0].OperatorID != 7 || runs[0].FinishedAt == nil {
+		t.Errorf("执行记录 = %+v", runs[0])
+	}
+}
+
+// TestRunJobResultNotLostToCancel 任务完成后 cancel 使 jobCtx 同时结束，结果不能被记为取消
+func TestRunJobResultNotLostToCancel(t *testing.T) {
+	job := &Job{
+		Name: "test_fast",
+		Run: func(ctx context.Context, inv *JobInvocation) (string, error) {
+			return "ok", nil
+		},
+	}
+	for i := 0; i < 2000; i++ {
+		run, err := RunJob(context.Background(), nil, job, JobParams{}, model.JobTriggerCLI, 0)
+		if err != nil || run.Status != model.JobStatusSuccess || run.Output != "ok" {
+			t.Fatalf("第%d次执行应成功, run=%+v err=%v", i+1, run, err)
+		}
+	}
+}
+
+// TestRunJobLockAcrossProcesses 其他进程执行中的记录占用并发控制键时拒绝，遗留的过期记录不阻止执行
+func TestRunJobLockAcrossProcesses(t *testing.T) {
+	db := dbtest.Open(t)
+	job, _ := LookupJob("echo")
+	runningKey := "echo:hi"
+	other := &model.JobRun{JobName: "echo", LockKey: "hi", RunningKey: &runningKey, Trigger: model.JobTriggerCLI,
+		Status: model.JobStatusRunning, StartedAt: time.Now()}
+	if err := db.Create(other).Error; err != nil {
+		t.Fatalf("创建执行记录失败: %v", err)
+	}
+
+	if _, err := RunJob(context.Background(), db, job, JobParams{"text": "hi"}, model.JobTriggerAPI, 0); !errors.Is(err, ErrJobRunning) {
+		t.Fatalf("其他进程执行中应返回 ErrJobRunning, got %v", err)
+	}
+
+	// 进程异常退出遗留的记录超过 jobStaleAfter 后不再占用
+	db.Model(other).Update("started_at", time.Now().Add(-jobStaleAfter-time.Minute))
+	run, err := RunJob(context.Background(), db, job, JobParams{"text": "hi"}, model.JobTriggerAPI, 0)
+	if err != nil {
+		t.Fatalf("遗留记录不应阻止执行: %v", err)
+	}
+	var held int64
+	db.Model(&model.JobRun{}).Where("running_key IS NOT NULL").Count(&held)
+	if held != 0 {
+		t.Errorf("执行结束后应释放并发控制键, 仍有 %d 条记录占用", held)
+	}
+	if run.Status != model.JobStatusSuccess {
+		t.Errorf("run = %+v", run)
+	}
+}
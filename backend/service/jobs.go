@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"lucky/model"
+)
+
+// gameCodeParam 游戏代码参数
+var gameCodeParam = JobParamSpec{Name: "gameCode", Type: JobParamString, Required: true, Description: "游戏代码"}
+
+func init() {
+	RegisterJob(&Job{
+		Name:        "echo",
+		Description: "返回输入内容，用于 gocron 测试节点连接",
+		Params:      []JobParamSpec{{Name: "text", Type: JobParamString, Description: "返回的内容"}},
+		LockParam:   "text",
+		Run:         runEchoJob,
+	})
+	RegisterJob(&Job{
+		Name:        "crawl",
+		Description: "抓取并保存最新一期开奖数据",
+		Params:      []JobParamSpec{gameCodeParam},
+		LockParam:   "gameCode",
+		Run:         runCrawlJob,
+	})
+	RegisterJob(&Job{
+		Name:        "backfill",
+		Description: "按页回补历史开奖数据，每页30期",
+		Params: []JobParamSpec{
+			gameCodeParam,
+			{Name: "pages", Type: JobParamInt, Default: "1", Min: 1, Max: maxBackfillPages, Description: "回补页数"},
+		},
+		LockParam: "gameCode",
+		Run:       runBackfillJob,
+	})
+	RegisterJob(&Job{
+		Name:        "stats",
+		Description: "重新计算号码分布和遗漏统计（数字型游戏为按位走势），并清除开奖数据缓存",
+		Params: []JobParamSpec{
+			gameCodeParam,
+			{Name: "periodCount", Type: JobParamInt, Default: "30", Min: 1, Max: 500, Description: "统计期数"},
+		},
+		LockParam: "gameCode",
+		Run:       runStatsJob,
+	})
+	RegisterJob(&Job{
+		Name:        "evaluate",
+		Description: "按开奖结果核对追号计划和待开奖的购彩记录，默认最新一期",
+		Params: []JobParamSpec{
+			gameCodeParam,
+			{Name: "period", Type: JobParamString, Description: "期号，为空时核对最新一期"},
+		},
+		LockParam: "gameCode",
+		Run:       runEvaluateJob,
+	})
+}
+
+// jobGame 查询任务参数中的游戏
+func jobGame(inv *JobInvocation) (*model.LotteryGame, error) {
+	gameCode := inv.Params.String("gameCode")
+	game, err := GetGameByCode(inv.DB, gameCode)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 不支持的游戏代码 %s", ErrInvalidJob, gameCode)
+	}
+	return game, nil
+}
+
+// runEchoJob 返回输入内容
+func runEchoJob(ctx context.Context, inv *JobInvocation) (string, error) {
+	return inv.Params.String("text"), nil
+}
+
+// runCrawlJob 抓取并保存最新一期开奖数据，没有新数据不视为失败
+func runCrawlJob(ctx context.Context, inv *JobInvocation) (string, error) {
+	game, err := jobGame(inv)
+	if err != nil {
+		return "", err
+	}
+
+	crawler := NewCrawlerServiceWithDB(inv.DB).WithContext(ctx)
+	run, err := crawler.CrawlTracked(game.GameCode, model.CrawlModeLatest, inv.Trigger, 0, inv.OperatorID)
+	if errors.Is(err, ErrDrawResultExists) {
+		return fmt.Sprintf("%s没有新的开奖数据: %v", game.GameName, err), nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	output := fmt.Sprintf("%s抓取成功", game.GameName)
+	if latest, err := GetLatestDrawResult(inv.DB, game.GameCode); err == nil {
+		output = fmt.Sprintf("%s第%s期抓取成功: %s", game.GameName, latest.Period, FormatNumberLine(latest.RedBalls, latest.BlueBalls))
+	}
+	if run != nil {
+		output += fmt.Sprintf(" (抓取记录 #%d)", run.ID)
+	}
+	return output, nil
+}
+
+// runBackfillJob 按页回补历史开奖数据
+func runBackfillJob(ctx context.Context, inv *JobInvocation) (string, error) {
+	game, err := jobGame(inv)
+	if err != nil {
+		return "", err
+	}
+	pages := inv.Params.Int("pages")
+
+	drawResultDAO := model.NewDrawResultDAO(inv.DB)
+	before, _ := drawResultDAO.CountByGameID(game.ID)
+	crawler := NewCrawlerServiceWithDB(inv.DB).WithContext(ctx)
+	if _, err := crawler.CrawlTracked(game.GameCode, model.CrawlModeBackfill, inv.Trigger, pages, inv.OperatorID); err != nil {
+		return "", err
+	}
+	after, _ := drawResultDAO.CountByGameID(game.ID)
+	return fmt.Sprintf("%s回补%d页完成，新增%d期，共%d期", game.GameName, pages, after-before, after), nil
+}
+
+// runStatsJob 重新计算号码统计并清除开奖数据缓存，下次请求时按最新开奖结果生成
+func runStatsJob(ctx context.Context, inv *JobInvocation) (string, error) {
+	game, err := jobGame(inv)
+	if err != nil {
+		return "", err
+	}
+	periodCount := inv.Params.Int("periodCount")
+
+	var lines []string
+	if IsDigitGame(game) {
+		trend, err := GetDigitTrend(inv.DB, game.GameCode, periodCount)
+		if err != nil {
+			return "", fmt.Errorf("计算走势失败: %v", err)
+		}
+		lines = append(lines, fmt.Sprintf("%s近%d期按位走势已计算，实际统计%d期", game.GameName, periodCount, trend.PeriodCount))
+	} else {
+		distribution, err := GetNumberDistribution(inv.DB, game.GameCode, periodCount)
+		if err != nil {
+			return "", fmt.Errorf("计算号码分布失败: %v", err)
+		}
+		missing, err := GetNumberMissing(inv.DB, game.GameCode, periodCount)
+		if err != nil {
+			return "", fmt.Errorf("计算遗漏统计失败: %v", err)
+		}
+		lines = append(lines, fmt.Sprintf("%s近%d期号码分布已计算: 红球%d个，蓝球%d个", game.GameName, periodCount,
+			len(distribution["red"]), len(distribution["blue"])))
+		lines = append(lines, fmt.Sprintf("%s遗漏统计已计算，实际统计%d期", game.GameName, missing.PeriodCount))
+	}
+
+	if err := InvalidateDrawCaches(game.GameCode); err != nil {
+		lines = append(lines, fmt.Sprintf("清除开奖数据缓存失败: %v", err))
+	} else {
+		lines = append(lines, "开奖数据缓存已清除")
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// runEvaluateJob 按指定期（默认最新一期）开奖结果核对追号计划和待开奖的购彩记录
+func runEvaluateJob(ctx context.Context, inv *JobInvocation) (string, error) {
+	game, err := jobGame(inv)
+	if err != nil {
+		return "", err
+	}
+
+	var drawResult *model.DrawResult
+	if period := inv.Params.String("period"); period != "" {
+		drawResult, err = GetDrawResultByPeriod(inv.DB, game.GameCode, period)
+	} else {
+		drawResult, err = GetLatestDrawResult(inv.DB, game.GameCode)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s开奖结果不存在", game.GameName)
+	}
+
+	var failures []string
+	if err := EvaluateNumberPlans(inv.DB, drawResult); err != nil {
+		failures = append(failures, err.Error())
+	}
+	if err := EvaluatePurchases(inv.DB, drawResult); err != nil {
+		failures = append(failures, err.Error())
+	}
+	if len(failures) > 0 {
+		return "", fmt.Errorf("%s第%s期核对失败: %s", game.GameName, drawResult.Period, strings.Join(failures, "; "))
+	}
+	return fmt.Sprintf("%s第%s期追号计划和购彩记录核对完成", game.GameName, drawResult.Period), nil
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"lucky/model"
+)
+
+// kenoPlayPrefix 快乐8玩法前缀，pick1-pick10 对应选一至选十
+const kenoPlayPrefix = "pick"
+
+// kenoPrize 快乐8单个奖级
+type kenoPrize struct {
+	Hits   int   // 命中个数
+	Amount int64 // 单注奖金(分)
+}
+
+// kenoPrizeTables 快乐8各玩法的奖级，按选号个数区分，奖级从高到低排列
+var kenoPrizeTables = map[int][]kenoPrize{
+	10: {{10, 500000000}, {9, 800000}, {8, 80000}, {7, 8000}, {6, 500}, {5, 300}, {0, 200}}, // 选十，中十为封顶奖金500万元
+	9:  {{9, 30000000}, {8, 200000}, {7, 20000}, {6, 2000}, {5, 500}, {4, 300}, {0, 200}},   // 选九
+	8:  {{8, 5000000}, {7, 80000}, {6, 8800}, {5, 1000}, {4, 300}, {0, 200}},                // 选八
+	7:  {{7, 1000000}, {6, 28800}, {5, 2800}, {4, 400}, {0, 200}},                           // 选七
+	6:  {{6, 300000}, {5, 3000}, {4, 1000}, {3, 300}},                                       // 选六
+	5:  {{5, 100000}, {4, 2100}, {3, 300}},                                                  // 选五
+	4:  {{4, 10000}, {3, 500}, {2, 300}},                                                    // 选四
+	3:  {{3, 5300}, {2, 300}},                                                               // 选三
+	2:  {{2, 1900}},                                                                         // 选二
+	1:  {{1, 460}},                                                                          // 选一
+}
+
+// kenoPlayNames 快乐8玩法名称
+var kenoPlayNames = []string{"", "选一", "选二", "选三", "选四", "选五", "选六", "选七", "选八", "选九", "选十"}
+
+// IsKenoGame 是否为快乐8型游戏
+func IsKenoGame(game *model.LotteryGame) bool {
+	return game.GameType == model.GameTypeKeno
+}
+
+// kenoPlayType 选号个数对应的玩法
+func kenoPlayType(pickCount int) string {
+	return kenoPlayPrefix + strconv.Itoa(pickCount)
+}
+
+// kenoPickCount 解析玩法对应的选号个数，非快乐8玩法返回0
+func kenoPickCount(playType string) int {
+	if !strings.HasPrefix(playType, kenoPlayPrefix) {
+		return 0
+	}
+	pickCount, err := strconv.Atoi(strings.TrimPrefix(playType, kenoPlayPrefix))
+	if err != nil {
+		return 0
+	}
+	if _, ok := kenoPrizeTables[pickCount]; !ok {
+		return 0
+	}
+	return pickCount
+}
+
+// validateKenoPlayType 校验快乐8玩法与选号个数一致，未指定时按选号个数确定
+func validateKenoPlayType(playType string, redBalls model.NumberArray) (string, error) {
+	if playType == "" {
+		playType = kenoPlayType(len(redBalls))
+	}
+	pickCount := kenoPickCount(playType)
+	if pickCount == 0 {
+		return "", fmt.Errorf("快乐8不支持该玩法: %s", playType)
+	}
+	if pickCount != len(redBalls) {
+		return "", fmt.Errorf("%s需要选择%d个号码", kenoPlayNames[pickCount], pickCount)
+	}
+	return playType, nil
+}
+
+// validateKenoNumbers 校验快乐8号码：1至最多可选个数，不重复
+func validateKenoNumbers(game *model.LotteryGame, redBalls, blueBalls model.NumberArray) error {
+	if len(redBalls) < 1 || len(redBalls) > game.RedSelectCount {
+		return fmt.Errorf("号码数量不正确，需要1-%d个", game.RedSelectCount)
+	}
+	if len(blueBalls) != 0 {
+		return errors.New("快乐8没有蓝球")
+	}
+	used := make(map[int]bool)
+	for _, ball := range redBalls {
+		if ball < 1 || ball > game.RedBallCount {
+			return fmt.Errorf("号码超出范围(1-%d)", game.RedBallCount)
+		}
+		if used[ball] {
+			return errors.New("号码重复")
+		}
+		used[ball] = true
+	}
+	return nil
+}
+
+// determineKenoPrize 按玩法和命中个数判断奖级和单注奖金(分)，奖级从1开始，未中奖返回0
+func determineKenoPrize(pickCount, hits int) (int, int64) {
+	for i, prize := range kenoPrizeTables[pickCount] {
+		if prize.Hits == hits {
+			return i + 1, prize.Amount
+		}
+	}
+	return 0, 0
+}
+
+// EvaluateKenoNumber 核对快乐8号码，返回命中个数、奖级和单注奖金(分)
+func EvaluateKenoNumber(userNumber *model.UserNumber, drawResult *model.DrawResult) (int, int, int64) {
+	pickCount := kenoPickCount(userNumber.PlayType)
+	if pickCount == 0 {
+		pickCount = len(userNumber.RedBalls)
+	}
+	hits := countMatches(userNumber.RedBalls, drawResult.RedBalls)
+	prizeLevel, prizeAmount := determineKenoPrize(pickCount, hits)
+	return hits, prizeLevel, prizeAmount
+}
+
+// KenoWinLevelName 快乐8中奖等级名称，如 "选十中9"
+func KenoWinLevelName(playType string, hits int) string {
+	pickCount := kenoPickCount(playType)
+	if pickCount == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s中%d", kenoPlayNames[pickCount], hits)
+}
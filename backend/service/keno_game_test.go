@@ -0,0 +1,118 @@
+package service
+
+import (
+	"testing"
+
+	"lucky/model"
+)
+
+var testKL8Game = &model.LotteryGame{
+	GameCode:       "kl8",
+	GameName:       "快乐8",
+	GameType:       model.GameTypeKeno,
+	RedBallCount:   80,
+	RedSelectCount: 10,
+}
+
+// testKL8Draw 一期快乐8开奖号码
+var testKL8Draw = model.NumberArray{2, 7, 11, 15, 19, 23, 28, 31, 36, 40, 44, 49, 53, 58, 61, 66, 70, 73, 77, 80}
+
+func TestValidateKenoNumbers(t *testing.T) {
+	if err := ValidateNumbers(testKL8Game, model.NumberArray{1, 80}, nil); err != nil {
+		t.Errorf("选二号码应合法: %v", err)
+	}
+	if err := ValidateNumbers(testKL8Game, model.NumberArray{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, nil); err == nil {
+		t.Error("超过10个号码应返回错误")
+	}
+	if err := ValidateNumbers(testKL8Game, model.NumberArray{81}, nil); err == nil {
+		t.Error("超出1-80应返回错误")
+	}
+	if err := ValidateNumbers(testKL8Game, model.NumberArray{5, 5}, nil); err == nil {
+		t.Error("重复号码应返回错误")
+	}
+
+	playType, err := ValidatePlayType(testKL8Game, "", model.NumberArray{1, 2, 3})
+	if err != nil || playType != "pick3" {
+		t.Errorf("未指定玩法应按号码个数确定, got %q %v", playType, err)
+	}
+	if _, err := ValidatePlayType(testKL8Game, "pick5", model.NumberArray{1, 2, 3}); err == nil {
+		t.Error("玩法与号码个数不一致应返回错误")
+	}
+	if _, err := ValidatePlayType(testKL8Game, "pick11", model.NumberArray{1}); err == nil {
+		t.Error("不存在的玩法应返回错误")
+	}
+}
+
+func TestEvaluateKenoNumber(t *testing.T) {
+	draw := &model.DrawResult{RedBalls: testKL8Draw}
+	cases := []struct {
+		name       string
+		balls      model.NumberArray
+		wantHits   int
+		wantLevel  int
+		wantAmount int64
+	}{
+		{"选一中1", model.NumberArray{2}, 1, 1, 460},
+		{"选一未中", model.NumberArray{1}, 0, 0, 0},
+		{"选三中2", model.NumberArray{2, 7, 8}, 2, 2, 300},
+		{"选五中3", model.NumberArray{2, 7, 11, 1, 3}, 3, 3, 300},
+		{"选十全中", model.NumberArray{2, 7, 11, 15, 19, 23, 28, 31, 36, 40}, 10, 1, 500000000},
+		{"选十中0也中奖", model.NumberArray{1, 3, 4, 5, 6, 8, 9, 10, 12, 13}, 0, 7, 200},
+		{"选十中4未中奖", model.NumberArray{2, 7, 11, 15, 1, 3, 4, 5, 6, 8}, 4, 0, 0},
+	}
+	for _, tc := range cases {
+		userNumber := &model.UserNumber{RedBalls: tc.balls, PlayType: kenoPlayType(len(tc.balls))}
+		hits, level, amount := EvaluateKenoNumber(userNumber, draw)
+		if hits != tc.wantHits || level != tc.wantLevel || amount != tc.wantAmount {
+			t.Errorf("%s: hits=%d level=%d amount=%d, want %d %d %d", tc.name, hits, level, amount, tc.wantHits, tc.wantLevel, tc.wantAmount)
+		}
+	}
+
+	if name := KenoWinLevelName("pick10", 9); name != "选十中9" {
+		t.Errorf("KenoWinLevelName = %q, want 选十中9", name)
+	}
+}
+
+func TestCountNumberMissing(t *testing.T) {
+	// 按期号正序
+	draws := []model.NumberArray{{1, 2}, {2, 3}, {3, 4}}
+	stats := countNumberMissing(draws, 5)
+
+	if len(stats) != 5 || stats[0].Number != 1 {
+		t.Fatalf("号码范围错误: %+v", stats)
+	}
+	if stats[0].Frequency != 1 || stats[0].Missing != 2 || stats[0].MaxMissing != 2 {
+		t.Errorf("号码1统计错误: %+v", stats[0])
+	}
+	if stats[2].Frequency != 2 || stats[2].Missing != 0 || stats[2].MaxMissing != 1 {
+		t.Errorf("号码3统计错误: %+v", stats[2])
+	}
+	if stats[4].Frequency != 0 || stats[4].Missing != 3 {
+		t.Errorf("未开出号码的遗漏应为统计期数: %+v", stats[4])
+	}
+}
+
+func TestParseOfficialNumbers(t *testing.T) {
+	balls, _, err := parseOfficialNumbers(officialCrawlConfigs["kl8"], "02,07,11,15,19,23,28,31,36,40,44,49,53,58,61,66,70,73,77,80")
+	if err != nil || len(balls) != 20 || balls[19] != 80 {
+		t.Errorf("快乐8开奖号码解析错误: %v %v", balls, err)
+	}
+	if _, _, err := parseOfficialNumbers(officialCrawlConfigs["kl8"], "01,02,03"); err == nil {
+		t.Error("快乐8开奖号码不足20个应返回错误")
+	}
+	digits, _, err := parseOfficialNumbers(officialCrawlConfigs["pl5"], "1 2 3 4 5")
+	if err != nil || len(digits) != 5 {
+		t.Errorf("排列五开奖号码解析错误: %v %v", digits, err)
+	}
+	red, special, err := parseOfficialNumbers(officialCrawlConfigs["qlc"], "03,08,12,17,21,25,29+14")
+	if err != nil || len(red) != 7 || len(special) != 1 || special[0] != 14 {
+		t.Errorf("七乐彩开奖号码解析错误: %v %v %v", red, special, err)
+	}
+	front, back, err := parseOfficialNumbers(officialCrawlConfigs["qxc"], "1 0 9 3 3 7 12")
+	if err != nil || len(front) != 6 || len(back) != 1 || back[0] != 12 {
+		t.Errorf("七星彩开奖号码解析错误: %v %v %v", front, back, err)
+	}
+	if _, _, err := parseOfficialNumbers(officialCrawlConfigs["qxc"], "1 0 19 3 3 7 12"); err == nil {
+		t.Error("七星彩前区超过9应返回错误")
+	}
+}
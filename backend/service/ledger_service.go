@@ -0,0 +1,385 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"lucky/model"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// additionalBetPrice 大乐透追加投注每注加价(分)
+	additionalBetPrice int64 = 100
+	// maxMultiplier 单张彩票最大倍数
+	maxMultiplier = 99
+)
+
+// PurchaseInput 录入购彩记录参数
+type PurchaseInput struct {
+	Period       string // 期号
+	UserNumberID *int64 // 购买的用户号码ID，与GroupID二选一
+	GroupID      *int64 // 购买的号码分组ID
+	BetCount     int    // 注数，为0时按号码或分组自动计算
+	Multiplier   int    // 倍数，为0时按号码设置，分组按1倍
+	IsAdditional *bool  // 是否追加（仅大乐透），为空时按号码设置
+	Cost         int64  // 投注金额(分)，为0时按注数、倍数和追加自动计算
+	Note         string // 备注
+}
+
+// LedgerTotals 投入与回报汇总
+type LedgerTotals struct {
+	PurchaseCount int     `json:"purchaseCount"` // 购彩次数
+	PendingCount  int     `json:"pendingCount"`  // 待开奖次数
+	WinningCount  int     `json:"winningCount"`  // 中奖次数
+	BetCount      int     `json:"betCount"`      // 总注数
+	TotalCost     int64   `json:"totalCost"`     // 总投入(分)
+	TotalPrize    int64   `json:"totalPrize"`    // 总奖金(分)
+	Profit        int64   `json:"profit"`        // 盈亏(分)
+	ROI           float64 `json:"roi"`           // 投资回报率，(奖金-投入)/投入
+}
+
+// GameLedger 单个游戏的汇总
+type GameLedger struct {
+	GameCode string `json:"gameCode"`
+	GameName string `json:"gameName"`
+	LedgerTotals
+}
+
+// MonthLedger 单月的汇总
+type MonthLedger struct {
+	Month string `json:"month"` // 月份，如2025-10
+	LedgerTotals
+}
+
+// BiggestWin 单笔最大中奖
+type BiggestWin struct {
+	PurchaseID  int64  `json:"purchaseId"`
+	GameCode    string `json:"gameCode"`
+	GameName    string `json:"gameName"`
+	Period      string `json:"period"`
+	PrizeLevel  int    `json:"prizeLevel"`
+	PrizeAmount int64  `json:"prizeAmount"` // 奖金(分)
+}
+
+// LedgerSummary 用户购彩账本汇总
+type LedgerSummary struct {
+	Lifetime   LedgerTotals  `json:"lifetime"`   // 累计
+	Games      []GameLedger  `json:"games"`      // 按游戏
+	Months     []MonthLedger `json:"months"`     // 按月份
+	BiggestWin *BiggestWin   `json:"biggestWin"` // 最大单笔中奖
+}
+
+// CreatePurchase 录入购彩记录，对应期号已开奖时立即结算
+func CreatePurchase(db *gorm.DB, userID uint64, game *model.LotteryGame, input PurchaseInput) (*model.Purchase, error) {
+	if err := ValidatePeriod(input.Period); err != nil {
+		return nil, err
+	}
+	if (input.UserNumberID == nil) == (input.GroupID == nil) {
+		return nil, errors.New("需要指定号码或分组中的一个")
+	}
+	purchase := &model.Purchase{
+		UserID:       int64(userID),
+		GameID:       game.ID,
+		Period:       input.Period,
+		UserNumberID: input.UserNumberID,
+		GroupID:      input.GroupID,
+		BetCount:     input.BetCount,
+		Multiplier:   1,
+		Cost:         input.Cost,
+		Source:       "manual",
+		Status:       "pending",
+		Note:         input.Note,
+	}
+
+	numbers, err := getPurchaseNumbers(db, purchase)
+	if err != nil {
+		return nil, err
+	}
+	for _, number := range numbers {
+		if number.GameID != game.ID {
+			return nil, errors.New("号码与购彩游戏不一致")
+		}
+	}
+
+	// 单注号码默认沿用号码的倍数和追加设置
+	if purchase.UserNumberID != nil {
+		if numbers[0].Multiplier > 0 {
+			purchase.Multiplier = numbers[0].Multiplier
+		}
+		purchase.IsAdditional = numbers[0].IsAdditional
+	}
+	if input.Multiplier != 0 {
+		purchase.Multiplier = input.Multiplier
+	}
+	if input.IsAdditional != nil {
+		purchase.IsAdditional = *input.IsAdditional
+	}
+	if err := ValidateBetOptions(game, purchase.Multiplier, purchase.IsAdditional); err != nil {
+		return nil, err
+	}
+	if purchase.BetCount <= 0 {
+		purchase.BetCount = len(numbers)
+	}
+	if purchase.BetCount <= 0 {
+		return nil, errors.New("注数必须大于0")
+	}
+	if purchase.Cost <= 0 {
+		purchase.Cost = calculateBetCost(purchase.BetCount, purchase.Multiplier, purchase.IsAdditional)
+	}
+
+	var drawResult model.DrawResult
+	err = db.Where("game_id = ? AND period = ?", game.ID, input.Period).First(&drawResult).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	drawn := err == nil
+	if drawn {
+		purchase.DrawDate = drawResult.DrawDate
+	} else if draws, err := GetCoveredDraws(db, game, input.Period, 1); err == nil {
+		purchase.DrawDate = draws[0].DrawDate
+	} else {
+		purchase.DrawDate = time.Now()
+	}
+
+	if err := model.NewPurchaseDAO(db).Create(purchase); err != nil {
+		return nil, err
+	}
+	if drawn {
+		if err := settlePurchase(db, purchase, game, &drawResult); err != nil {
+			return nil, err
+		}
+	}
+
+	purchase.Game = *game
+	return purchase, nil
+}
+
+// GetPurchases 分页获取用户购彩记录
+func GetPurchases(db *gorm.DB, userID uint64, page, pageSize int) ([]*model.Purchase, int64, error) {
+	purchaseDAO := model.NewPurchaseDAO(db)
+	total, err := purchaseDAO.CountByUserID(int64(userID))
+	if err != nil {
+		return nil, 0, err
+	}
+	purchases, err := purchaseDAO.GetByUserID(int64(userID), (page-1)*pageSize, pageSize)
+	return purchases, total, err
+}
+
+// DeletePurchase 删除手动录入的购彩记录
+func DeletePurchase(db *gorm.DB, userID uint64, purchaseID int64) error {
+	purchaseDAO := model.NewPurchaseDAO(db)
+	purchase, err := purchaseDAO.GetByID(purchaseID)
+	if err != nil || purchase.UserID != int64(userID) {
+		return fmt.Errorf("购彩记录不存在或不属于该用户")
+	}
+	if purchase.Source != "manual" {
+		return errors.New("追号生成的记录请通过追号计划管理")
+	}
+	return purchaseDAO.Delete(purchaseID)
+}
+
+// GetLedgerSummary 获取用户购彩账本汇总
+func GetLedgerSummary(db *gorm.DB, userID uint64) (*LedgerSummary, error) {
+	purchases, err := model.NewPurchaseDAO(db).GetAllByUserID(int64(userID))
+	if err != nil {
+		return nil, err
+	}
+	return summarizeLedger(purchases), nil
+}
+
+// EvaluatePurchases 新一期开奖后结算该期待开奖的购彩记录
+func EvaluatePurchases(db *gorm.DB, drawResult *model.DrawResult) error {
+	purchases, err := model.NewPurchaseDAO(db).GetPendingByPeriod(drawResult.GameID, drawResult.Period)
+	if err != nil || len(purchases) == 0 {
+		return err
+	}
+
+	game, err := GetGameByID(db, drawResult.GameID)
+	if err != nil {
+		return fmt.Errorf("游戏不存在")
+	}
+
+	// 单笔失败不影响其他记录的结算
+	var failedPurchases []int64
+	for _, purchase := range purchases {
+		if err := settlePurchase(db, purchase, game, drawResult); err != nil {
+			failedPurchases = append(failedPurchases, purchase.ID)
+		}
+	}
+	if len(failedPurchases) > 0 {
+		return fmt.Errorf("购彩记录 %v 结算失败", failedPurchases)
+	}
+	return nil
+}
+
+// settlePurchase 按开奖结果结算购彩记录，并保存各注号码的核对结果
+func settlePurchase(db *gorm.DB, purchase *model.Purchase, game *model.LotteryGame, drawResult *model.DrawResult) error {
+	numbers, err := getPurchaseNumbers(db, purchase)
+	if err != nil {
+		return err
+	}
+
+	levels := make([]int, len(numbers))
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var prizeAmount int64
+		prizeLevel := 0
+		for i := range numbers {
+			_, _, level, amount := EvaluateNumber(game, &numbers[i], drawResult)
+			if err := saveUserDraw(tx, numbers[i].ID, drawResult.ID, level, amount); err != nil {
+				return err
+			}
+			levels[i] = level
+			prizeAmount += ApplyBetOptions(game.GameCode, level, amount, purchase.Multiplier, purchase.IsAdditional)
+			prizeLevel = betterPrizeLevel(prizeLevel, level)
+		}
+
+		purchase.PrizeLevel = prizeLevel
+		purchase.PrizeAmount = prizeAmount
+		purchase.Status = "settled"
+		return model.NewPurchaseDAO(tx).Update(purchase)
+	})
+	if err != nil {
+		return err
+	}
+	observeSavedEvaluations(game.GameCode, levels...)
+	return nil
+}
+
+// getPurchaseNumbers 获取购彩记录对应的号码
+func getPurchaseNumbers(db *gorm.DB, purchase *model.Purchase) ([]model.UserNumber, error) {
+	var numbers []model.UserNumber
+	if purchase.UserNumberID != nil {
+		err := db.Where("id = ? AND user_id = ?", *purchase.UserNumberID, purchase.UserID).Find(&numbers).Error
+		if err != nil {
+			return nil, err
+		}
+		if len(numbers) == 0 {
+			return nil, fmt.Errorf("号码不存在或不属于该用户")
+		}
+		return numbers, nil
+	}
+
+	if _, err := getUserNumberGroup(db, uint64(purchase.UserID), *purchase.GroupID); err != nil {
+		return nil, err
+	}
+	err := db.Where("user_id = ? AND group_id = ?", purchase.UserID, *purchase.GroupID).Find(&numbers).Error
+	return numbers, err
+}
+
+// newPlanPurchase 生成追号计划某期的购彩记录
+func newPlanPurchase(plan *model.NumberPlan, record *model.NumberPlanPeriod, drawDate time.Time) model.Purchase {
+	return model.Purchase{
+		UserID:       plan.UserID,
+		GameID:       plan.GameID,
+		Period:       record.Period,
+		DrawDate:     drawDate,
+		UserNumberID: &plan.UserNumberID,
+		PlanID:       &plan.ID,
+		BetCount:     1,
+		Multiplier:   plan.Multiplier,
+		IsAdditional: plan.IsAdditional,
+		Cost:         calculateBetCost(1, plan.Multiplier, plan.IsAdditional),
+		Source:       "plan",
+		Status:       "settled",
+		PrizeLevel:   record.PrizeLevel,
+		PrizeAmount:  record.PrizeAmount,
+	}
+}
+
+// calculateBetCost 计算投注金额(分)：每注2元，追加每注加1元，再乘以倍数
+func calculateBetCost(betCount, multiplier int, isAdditional bool) int64 {
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	price := defaultBetPrice
+	if isAdditional {
+		price += additionalBetPrice
+	}
+	return int64(betCount) * int64(multiplier) * price
+}
+
+// summarizeLedger 按累计、游戏和月份汇总购彩记录
+func summarizeLedger(purchases []*model.Purchase) *LedgerSummary {
+	summary := &LedgerSummary{Games: []GameLedger{}, Months: []MonthLedger{}}
+	games := make(map[uint64]*GameLedger)
+	months := make(map[string]*MonthLedger)
+
+	for _, purchase := range purchases {
+		addToLedger(&summary.Lifetime, purchase)
+
+		game, ok := games[purchase.GameID]
+		if !ok {
+			game = &GameLedger{GameCode: purchase.Game.GameCode, GameName: purchase.Game.GameName}
+			games[purchase.GameID] = game
+		}
+		addToLedger(&game.LedgerTotals, purchase)
+
+		monthKey := purchaseMonth(purchase)
+		month, ok := months[monthKey]
+		if !ok {
+			month = &MonthLedger{Month: monthKey}
+			months[monthKey] = month
+		}
+		addToLedger(&month.LedgerTotals, purchase)
+
+		if purchase.PrizeAmount > 0 && (summary.BiggestWin == nil || purchase.PrizeAmount > summary.BiggestWin.PrizeAmount) {
+			summary.BiggestWin = &BiggestWin{
+				PurchaseID:  purchase.ID,
+				GameCode:    purchase.Game.GameCode,
+				GameName:    purchase.Game.GameName,
+				Period:      purchase.Period,
+				PrizeLevel:  purchase.PrizeLevel,
+				PrizeAmount: purchase.PrizeAmount,
+			}
+		}
+	}
+
+	finishLedger(&summary.Lifetime)
+	for _, game := range games {
+		finishLedger(&game.LedgerTotals)
+		summary.Games = append(summary.Games, *game)
+	}
+	for _, month := range months {
+		finishLedger(&month.LedgerTotals)
+		summary.Months = append(summary.Months, *month)
+	}
+	sort.Slice(summary.Games, func(i, j int) bool { return summary.Games[i].GameCode < summary.Games[j].GameCode })
+	sort.Slice(summary.Months, func(i, j int) bool { return summary.Months[i].Month > summary.Months[j].Month })
+
+	return summary
+}
+
+// addToLedger 将单笔购彩记录计入汇总
+func addToLedger(totals *LedgerTotals, purchase *model.Purchase) {
+	totals.PurchaseCount++
+	totals.BetCount += purchase.BetCount * purchase.Multiplier
+	totals.TotalCost += purchase.Cost
+	totals.TotalPrize += purchase.PrizeAmount
+	if purchase.Status == "pending" {
+		totals.PendingCount++
+	}
+	if purchase.PrizeLevel > 0 {
+		totals.WinningCount++
+	}
+}
+
+// finishLedger 计算盈亏和投资回报率（保留4位小数）
+func finishLedger(totals *LedgerTotals) {
+	totals.Profit = totals.TotalPrize - totals.TotalCost
+	if totals.TotalCost > 0 {
+		totals.ROI = math.Round(float64(totals.Profit)/float64(totals.TotalCost)*10000) / 10000
+	}
+}
+
+// purchaseMonth 购彩记录所属月份，按开奖日期确定
+func purchaseMonth(purchase *model.Purchase) string {
+	if purchase.DrawDate.IsZero() {
+		return purchase.CreatedAt.Format("2006-01")
+	}
+	return purchase.DrawDate.Format("2006-01")
+}
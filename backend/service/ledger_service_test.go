@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"lucky/model"
+)
+
+func TestCalculateBetCost(t *testing.T) {
+	cases := []struct {
+		betCount, multiplier int
+		isAdditional         bool
+		want                 int64
+	}{
+		{1, 1, false, 200},
+		{5, 2, false, 2000},
+		{1, 1, true, 300},
+		{3, 10, true, 9000},
+	}
+	for _, tc := range cases {
+		if got := calculateBetCost(tc.betCount, tc.multiplier, tc.isAdditional); got != tc.want {
+			t.Errorf("calculateBetCost(%d, %d, %v) = %d, want %d", tc.betCount, tc.multiplier, tc.isAdditional, got, tc.want)
+		}
+	}
+}
+
+func TestSummarizeLedger(t *testing.T) {
+	ssq := model.LotteryGame{ID: 1, GameCode: "ssq", GameName: "双色球"}
+	dlt := model.LotteryGame{ID: 2, GameCode: "dlt", GameName: "大乐透"}
+	september := time.Date(2025, 9, 28, 21, 15, 0, 0, time.Local)
+	october := time.Date(2025, 10, 14, 21, 15, 0, 0, time.Local)
+
+	purchases := []*model.Purchase{
+		{ID: 1, GameID: 1, Game: ssq, Period: "2025112", DrawDate: september, BetCount: 5, Multiplier: 1, Cost: 1000, Status: "settled", PrizeLevel: 6, PrizeAmount: 500},
+		{ID: 2, GameID: 1, Game: ssq, Period: "2025118", DrawDate: october, BetCount: 1, Multiplier: 2, Cost: 400, Status: "settled", PrizeLevel: 4, PrizeAmount: 40000},
+		{ID: 3, GameID: 2, Game: dlt, Period: "2025119", DrawDate: october, BetCount: 2, Multiplier: 1, Cost: 600, Status: "pending"},
+	}
+
+	summary := summarizeLedger(purchases)
+
+	lifetime := summary.Lifetime
+	if lifetime.PurchaseCount != 3 || lifetime.PendingCount != 1 || lifetime.WinningCount != 2 || lifetime.BetCount != 9 {
+		t.Errorf("累计次数统计错误: %+v", lifetime)
+	}
+	if lifetime.TotalCost != 2000 || lifetime.TotalPrize != 40500 || lifetime.Profit != 38500 {
+		t.Errorf("累计金额统计错误: %+v", lifetime)
+	}
+	if lifetime.ROI != 19.25 {
+		t.Errorf("ROI = %v, want 19.25", lifetime.ROI)
+	}
+
+	if len(summary.Games) != 2 || summary.Games[0].GameCode != "dlt" || summary.Games[1].TotalCost != 1400 {
+		t.Errorf("按游戏统计错误: %+v", summary.Games)
+	}
+	if len(summary.Months) != 2 || summary.Months[0].Month != "2025-10" || summary.Months[0].PurchaseCount != 2 {
+		t.Errorf("按月份统计错误: %+v", summary.Months)
+	}
+	if summary.BiggestWin == nil || summary.BiggestWin.PurchaseID != 2 {
+		t.Errorf("最大中奖统计错误: %+v", summary.BiggestWin)
+	}
+
+	empty := summarizeLedger(nil)
+	if empty.Lifetime.ROI != 0 || empty.BiggestWin != nil {
+		t.Errorf("空账本汇总错误: %+v", empty)
+	}
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+
+	applog "lucky/common/log"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// dbLogger 带有请求上下文字段的日志，处理函数通过 mysql.DB.WithContext 传入请求 context
+func dbLogger(db *gorm.DB) *logrus.Entry {
+	return applog.Ctx(dbContext(db))
+}
+
+// dbContext 数据库连接上的请求 context，未设置时为 context.Background()
+func dbContext(db *gorm.DB) context.Context {
+	if db == nil || db.Statement == nil || db.Statement.Context == nil {
+		return context.Background()
+	}
+	return db.Statement.Context
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"lucky/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NumberGroupInfo 号码分组及组内号码数
+type NumberGroupInfo struct {
+	model.NumberGroup
+	NumberCount int64 `json:"number_count"` // 组内号码数
+}
+
+// GroupPeriodWinning 分组在单期的中奖情况
+type GroupPeriodWinning struct {
+	Period         string `json:"period"`         // 期号
+	DrawDate       string `json:"drawDate"`       // 开奖日期
+	WinningCount   int    `json:"winningCount"`   // 中奖注数
+	BestPrizeLevel int    `json:"bestPrizeLevel"` // 最高奖级，0表示未中奖
+}
+
+// GroupWinningSummary 分组中奖汇总
+type GroupWinningSummary struct {
+	Group              *model.NumberGroup   `json:"group"`
+	NumberCount        int                  `json:"numberCount"`        // 组内号码数
+	PeriodCount        int                  `json:"periodCount"`        // 核对期数
+	WinningCount       int                  `json:"winningCount"`       // 中奖注次
+	WinningNumberCount int                  `json:"winningNumberCount"` // 至少中奖一次的号码数
+	BestPrizeLevel     int                  `json:"bestPrizeLevel"`     // 最高奖级，0表示未中奖
+	LevelCounts        map[int]int          `json:"levelCounts"`        // 各奖级中奖注次
+	Periods            []GroupPeriodWinning `json:"periods"`            // 逐期中奖情况
+}
+
+// CreateNumberGroup 创建号码分组
+func CreateNumberGroup(db *gorm.DB, userID uint64, gameID uint64, name, note string) (*model.NumberGroup, error) {
+	if name == "" {
+		return nil, errors.New("分组名称不能为空")
+	}
+	group := &model.NumberGroup{
+		UserID: int64(userID),
+		GameID: gameID,
+		Name:   name,
+		Note:   note,
+		Source: "manual",
+	}
+	if err := model.NewNumberGroupDAO(db).Create(group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// GetNumberGroups 获取用户的号码分组列表
+func GetNumberGroups(db *gorm.DB, userID uint64) ([]NumberGroupInfo, error) {
+	groups, err := model.NewNumberGroupDAO(db).GetByUserID(int64(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	var counts []struct {
+		GroupID int64
+		Total   int64
+	}
+	err = db.Model(&model.UserNumber{}).
+		Select("group_id, COUNT(*) AS total").
+		Where("user_id = ? AND group_id IS NOT NULL", userID).
+		Group("group_id").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	countMap := make(map[int64]int64)
+	for _, item := range counts {
+		countMap[item.GroupID] = item.Total
+	}
+
+	infos := make([]NumberGroupInfo, 0, len(groups))
+	for _, group := range groups {
+		infos = append(infos, NumberGroupInfo{NumberGroup: *group, NumberCount: countMap[group.ID]})
+	}
+	return infos, nil
+}
+
+// UpdateNumberGroup 更新号码分组名称和备注
+func UpdateNumberGroup(db *gorm.DB, userID uint64, groupID int64, name, note *string) error {
+	group, err := getUserNumberGroup(db, userID, groupID)
+	if err != nil {
+		return err
+	}
+	if name != nil {
+		if *name == "" {
+			return errors.New("分组名称不能为空")
+		}
+		group.Name = *name
+	}
+	if note != nil {
+		group.Note = *note
+	}
+	return model.NewNumberGroupDAO(db).Update(group)
+}
+
+// DeleteNumberGroup 删除号码分组，组内号码保留并移出分组
+func DeleteNumberGroup(db *gorm.DB, userID uint64, groupID int64) error {
+	if _, err := getUserNumberGroup(db, userID, groupID); err != nil {
+		return err
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		return model.NewNumberGroupDAO(tx).Delete(groupID)
+	})
+}
+
+// CreateNumberTag 创建号码标签
+func CreateNumberTag(db *gorm.DB, userID uint64, name, color string) (*model.NumberTag, error) {
+	if name == "" {
+		return nil, errors.New("标签名称不能为空")
+	}
+
+	var count int64
+	if err := db.Model(&model.NumberTag{}).Where("user_id = ? AND name = ?", userID, name).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("标签 %s 已存在", name)
+	}
+
+	tag := &model.NumberTag{UserID: int64(userID), Name: name, Color: color}
+	if err := model.NewNumberTagDAO(db).Create(tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// GetNumberTags 获取用户的号码标签列表
+func GetNumberTags(db *gorm.DB, userID uint64) ([]*model.NumberTag, error) {
+	return model.NewNumberTagDAO(db).GetByUserID(int64(userID))
+}
+
+// DeleteNumberTag 删除号码标签
+func DeleteNumberTag(db *gorm.DB, userID uint64, tagID int64) error {
+	tagDAO := model.NewNumberTagDAO(db)
+	tag, err := tagDAO.GetByID(tagID)
+	if err != nil || tag.UserID != int64(userID) {
+		return fmt.Errorf("标签不存在或不属于该用户")
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		return model.NewNumberTagDAO(tx).Delete(tagID)
+	})
+}
+
+// BulkMoveNumbers 批量移动号码到分组，groupID为nil时移出分组
+// 与分组游戏不一致的号码会被跳过，返回实际移动的数量
+func BulkMoveNumbers(db *gorm.DB, userID uint64, numberIDs []int64, groupID *int64) (int64, error) {
+	if len(numberIDs) == 0 {
+		return 0, errors.New("号码ID不能为空")
+	}
+
+	query := db.Model(&model.UserNumber{}).Where("user_id = ? AND id IN ?", userID, numberIDs)
+	if groupID != nil {
+		group, err := getUserNumberGroup(db, userID, *groupID)
+		if err != nil {
+			return 0, err
+		}
+		query = query.Where("game_id = ?", group.GameID)
+	}
+
+	result := query.Update("group_id", groupID)
+	return result.RowsAffected, result.Error
+}
+
+// BulkTagNumbers 批量为号码添加或移除标签
+func BulkTagNumbers(db *gorm.DB, userID uint64, numberIDs, tagIDs []int64, remove bool) error {
+	if len(numberIDs) == 0 || len(tagIDs) == 0 {
+		return errors.New("号码ID和标签ID不能为空")
+	}
+
+	var ownedTags int64
+	if err := db.Model(&model.NumberTag{}).Where("user_id = ? AND id IN ?", userID, tagIDs).Count(&ownedTags).Error; err != nil {
+		return err
+	}
+	if ownedTags != int64(len(tagIDs)) {
+		return fmt.Errorf("标签不存在或不属于该用户")
+	}
+
+	var ownedNumbers []int64
+	if err := db.Model(&model.UserNumber{}).Where("user_id = ? AND id IN ?", userID, numberIDs).Pluck("id", &ownedNumbers).Error; err != nil {
+		return err
+	}
+	if len(ownedNumbers) == 0 {
+		return fmt.Errorf("号码不存在或不属于该用户")
+	}
+
+	if remove {
+		return db.Where("user_number_id IN ? AND tag_id IN ?", ownedNumbers, tagIDs).Delete(&model.UserNumberTag{}).Error
+	}
+
+	links := make([]model.UserNumberTag, 0, len(ownedNumbers)*len(tagIDs))
+	for _, numberID := range ownedNumbers {
+		for _, tagID := range tagIDs {
+			links = append(links, model.UserNumberTag{UserNumberID: numberID, TagID: tagID})
+		}
+	}
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error
+}
+
+// BulkSetNumbersActive 批量启用或停用号码，返回实际更新的数量
+func BulkSetNumbersActive(db *gorm.DB, userID uint64, numberIDs []int64, isActive bool) (int64, error) {
+	if len(numberIDs) == 0 {
+		return 0, errors.New("号码ID不能为空")
+	}
+	result := db.Model(&model.UserNumber{}).
+		Where("user_id = ? AND id IN ?", userID, numberIDs).
+		Update("is_active", isActive)
+	return result.RowsAffected, result.Error
+}
+
+// GetGroupWinningSummary 汇总分组内号码在近N期的中奖情况
+func GetGroupWinningSummary(db *gorm.DB, userID uint64, groupID int64, periodCount int) (*GroupWinningSummary, error) {
+	group, err := getUserNumberGroup(db, userID, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	game, err := GetGameByID(db, group.GameID)
+	if err != nil {
+		return nil, fmt.Errorf("游戏不存在")
+	}
+
+	var numbers []model.UserNumber
+	if err := db.Where("user_id = ? AND group_id = ?", userID, groupID).Find(&numbers).Error; err != nil {
+		return nil, err
+	}
+
+	drawResults, err := GetLatestDrawResults(db, game.GameCode, periodCount)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &GroupWinningSummary{
+		Group:       group,
+		NumberCount: len(numbers),
+		PeriodCount: len(drawResults),
+		LevelCounts: make(map[int]int),
+		Periods:     []GroupPeriodWinning{},
+	}
+
+	winningNumbers := make(map[int64]bool)
+	for _, drawResult := range drawResults {
+		periodWinning := GroupPeriodWinning{
+			Period:   drawResult.Period,
+			DrawDate: drawResult.DrawDate.Format("2006-01-02"),
+		}
+		for i := range numbers {
+			number := &numbers[i]
+			_, _, level, _ := EvaluateNumber(game, number, &drawResult)
+			if level == 0 {
+				continue
+			}
+			periodWinning.WinningCount++
+			periodWinning.BestPrizeLevel = betterPrizeLevel(periodWinning.BestPrizeLevel, level)
+			summary.LevelCounts[level]++
+			winningNumbers[number.ID] = true
+		}
+		summary.WinningCount += periodWinning.WinningCount
+		summary.BestPrizeLevel = betterPrizeLevel(summary.BestPrizeLevel, periodWinning.BestPrizeLevel)
+		summary.Periods = append(summary.Periods, periodWinning)
+	}
+	summary.WinningNumberCount = len(winningNumbers)
+
+	return summary, nil
+}
+
+// getUserNumberGroup 获取属于指定用户的号码分组
+func getUserNumberGroup(db *gorm.DB, userID uint64, groupID int64) (*model.NumberGroup, error) {
+	group, err := model.NewNumberGroupDAO(db).GetByID(groupID)
+	if err != nil || group.UserID != int64(userID) {
+		return nil, fmt.Errorf("分组不存在或不属于该用户")
+	}
+	return group, nil
+}
+
+// betterPrizeLevel 返回两个奖级中较高的一个（数字越小奖级越高，0表示未中奖）
+func betterPrizeLevel(a, b int) int {
+	if a == 0 {
+		return b
+	}
+	if b == 0 || a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"lucky/common/database/dbtest"
+	"lucky/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// createTestGames 在测试库中创建双色球和大乐透
+func createTestGames(t *testing.T, db *gorm.DB) (*model.LotteryGame, *model.LotteryGame) {
+	t.Helper()
+	ssq, dlt := *testSSQGame, *testDLTGame
+	ssq.GameName = "双色球"
+	for _, game := range []*model.LotteryGame{&ssq, &dlt} {
+		game.GameType = model.GameTypeBall
+		game.IsActive = true
+		if err := db.Create(game).Error; err != nil {
+			t.Fatalf("创建游戏失败: %v", err)
+		}
+	}
+	return &ssq, &dlt
+}
+
+// createTestNumber 在测试库中创建用户号码
+func createTestNumber(t *testing.T, db *gorm.DB, userID int64, game *model.LotteryGame, red, blue model.NumberArray) *model.UserNumber {
+	t.Helper()
+	number := &model.UserNumber{UserID: userID, GameID: game.ID, RedBalls: red, BlueBalls: blue, Multiplier: 1, IsActive: true}
+	if err := db.Omit(clause.Associations).Create(number).Error; err != nil {
+		t.Fatalf("创建号码失败: %v", err)
+	}
+	return number
+}
+
+// numberGroupID 读取号码当前的分组
+func numberGroupID(t *testing.T, db *gorm.DB, numberID int64) *int64 {
+	t.Helper()
+	var number model.UserNumber
+	if err := db.First(&number, numberID).Error; err != nil {
+		t.Fatalf("读取号码失败: %v", err)
+	}
+	return number.GroupID
+}
+
+func TestBulkMoveNumbers(t *testing.T) {
+	db := dbtest.Open(t)
+	ssq, dlt := createTestGames(t, db)
+	mine1 := createTestNumber(t, db, 1, ssq, model.NumberArray{1, 2, 3, 4, 5, 6}, model.NumberArray{1})
+	mine2 := createTestNumber(t, db, 1, ssq, model.NumberArray{7, 8, 9, 10, 11, 12}, model.NumberArray{2})
+	mineDLT := createTestNumber(t, db, 1, dlt, model.NumberArray{1, 2, 3, 4, 5}, model.NumberArray{1, 2})
+	others := createTestNumber(t, db, 2, ssq, model.NumberArray{1, 2, 3, 4, 5, 6}, model.NumberArray{3})
+
+	group, err := CreateNumberGroup(db, 1, ssq.ID, "常买", "")
+	if err != nil {
+		t.Fatalf("CreateNumberGroup: %v", err)
+	}
+
+	// 其他游戏的号码和其他用户的号码都被跳过
+	moved, err := BulkMoveNumbers(db, 1, []int64{mine1.ID, mine2.ID, mineDLT.ID, others.ID}, &group.ID)
+	if err != nil || moved != 2 {
+		t.Fatalf("应只移动本人的2注双色球号码, moved=%d err=%v", moved, err)
+	}
+	if groupID := numberGroupID(t, db, mine1.ID); groupID == nil || *groupID != group.ID {
+		t.Errorf("号码应移入分组, got %v", groupID)
+	}
+	if groupID := numberGroupID(t, db, mineDLT.ID); groupID != nil {
+		t.Errorf("大乐透号码不应移入双色球分组, got %v", *groupID)
+	}
+	if groupID := numberGroupID(t, db, others.ID); groupID != nil {
+		t.Errorf("其他用户的号码不应被移动, got %v", *groupID)
+	}
+
+	// 不能把号码移入其他用户的分组，也不能移动其他用户的号码
+	if _, err := BulkMoveNumbers(db, 2, []int64{others.ID}, &group.ID); err == nil {
+		t.Error("移入其他用户的分组应返回错误")
+	}
+	othersGroup, err := CreateNumberGroup(db, 2, ssq.ID, "他人分组", "")
+	if err != nil {
+		t.Fatalf("CreateNumberGroup: %v", err)
+	}
+	if moved, err := BulkMoveNumbers(db, 2, []int64{mine1.ID}, &othersGroup.ID); err != nil || moved != 0 {
+		t.Errorf("不应移动其他用户的号码, moved=%d err=%v", moved, err)
+	}
+	if groupID := numberGroupID(t, db, mine1.ID); groupID == nil || *groupID != group.ID {
+		t.Errorf("号码分组不应被其他用户修改, got %v", groupID)
+	}
+
+	// 分组为空时移出分组
+	if moved, err := BulkMoveNumbers(db, 1, []int64{mine2.ID}, nil); err != nil || moved != 1 {
+		t.Fatalf("移出分组 moved=%d err=%v", moved, err)
+	}
+	if groupID := numberGroupID(t, db, mine2.ID); groupID != nil {
+		t.Errorf("号码应移出分组, got %v", *groupID)
+	}
+}
+
+func TestDeleteNumberGroup(t *testing.T) {
+	db := dbtest.Open(t)
+	ssq, _ := createTestGames(t, db)
+	number := createTestNumber(t, db, 1, ssq, model.NumberArray{1, 2, 3, 4, 5, 6}, model.NumberArray{1})
+	group, err := CreateNumberGroup(db, 1, ssq.ID, "常买", "")
+	if err != nil {
+		t.Fatalf("CreateNumberGroup: %v", err)
+	}
+	if _, err := BulkMoveNumbers(db, 1, []int64{number.ID}, &group.ID); err != nil {
+		t.Fatalf("BulkMoveNumbers: %v", err)
+	}
+
+	if err := DeleteNumberGroup(db, 2, group.ID); err == nil {
+		t.Error("删除其他用户的分组应返回错误")
+	}
+	if err := DeleteNumberGroup(db, 1, group.ID); err != nil {
+		t.Fatalf("DeleteNumberGroup: %v", err)
+	}
+	if groupID := numberGroupID(t, db, number.ID); groupID != nil {
+		t.Errorf("删除分组后号码应保留并移出分组, got %v", *groupID)
+	}
+	if _, err := model.NewNumberGroupDAO(db).GetByID(group.ID); err == nil {
+		t.Error("分组应已删除")
+	}
+}
+
+func TestBulkTagNumbers(t *testing.T) {
+	db := dbtest.Open(t)
+	ssq, _ := createTestGames(t, db)
+	first := createTestNumber(t, db, 1, ssq, model.NumberArray{1, 2, 3, 4, 5, 6}, model.NumberArray{1})
+	second := createTestNumber(t, db, 1, ssq, model.NumberArray{7, 8, 9, 10, 11, 12}, model.NumberArray{2})
+	createTestNumber(t, db, 1, ssq, model.NumberArray{13, 14, 15, 16, 17, 18}, model.NumberArray{3})
+
+	tag, err := CreateNumberTag(db, 1, "生日", "#ff0000")
+	if err != nil {
+		t.Fatalf("CreateNumberTag: %v", err)
+	}
+	if _, err := CreateNumberTag(db, 1, "生日", ""); err == nil {
+		t.Error("重复的标签名应返回错误")
+	}
+	othersTag, err := CreateNumberTag(db, 2, "生日", "")
+	if err != nil {
+		t.Fatalf("其他用户可以使用相同的标签名: %v", err)
+	}
+
+	// 重复添加同一标签时忽略已存在的关联
+	ids := []int64{first.ID, second.ID}
+	for i := 0; i < 2; i++ {
+		if err := BulkTagNumbers(db, 1, ids, []int64{tag.ID}, false); err != nil {
+			t.Fatalf("BulkTagNumbers: %v", err)
+		}
+	}
+	var links int64
+	db.Model(&model.UserNumberTag{}).Where("tag_id = ?", tag.ID).Count(&links)
+	if links != 2 {
+		t.Errorf("重复添加标签应去重, got %d 条关联", links)
+	}
+	if err := BulkTagNumbers(db, 1, ids, []int64{othersTag.ID}, false); err == nil {
+		t.Error("使用其他用户的标签应返回错误")
+	}
+
+	// 按标签筛选号码
+	numbers, total, err := GetUserNumbers(db, 1, UserNumberFilter{TagID: &tag.ID}, 1, 10)
+	if err != nil || total != 2 || len(numbers) != 2 {
+		t.Errorf("按标签筛选应返回2注, total=%d len=%d err=%v", total, len(numbers), err)
+	}
+
+	if err := BulkTagNumbers(db, 1, []int64{first.ID}, []int64{tag.ID}, true); err != nil {
+		t.Fatalf("移除标签: %v", err)
+	}
+	if _, total, _ := GetUserNumbers(db, 1, UserNumberFilter{TagID: &tag.ID}, 1, 10); total != 1 {
+		t.Errorf("移除标签后应剩1注, got %d", total)
+	}
+
+	if err := DeleteNumberTag(db, 1, tag.ID); err != nil {
+		t.Fatalf("DeleteNumberTag: %v", err)
+	}
+	db.Model(&model.UserNumberTag{}).Where("tag_id = ?", tag.ID).Count(&links)
+	if links != 0 {
+		t.Errorf("删除标签应删除关联, got %d", links)
+	}
+}
+
+func TestGetGroupWinningSummary(t *testing.T) {
+	db := dbtest.Open(t)
+	ssq, _ := createTestGames(t, db)
+	winner := createTestNumber(t, db, 1, ssq, model.NumberArray{1, 5, 12, 18, 25, 33}, model.NumberArray{8})
+	loser := createTestNumber(t, db, 1, ssq, model.NumberArray{2, 3, 4, 6, 7, 9}, model.NumberArray{16})
+	createTestNumber(t, db, 1, ssq, model.NumberArray{1, 5, 12, 18, 25, 33}, model.NumberArray{8}) // 不在分组内
+
+	group, err := CreateNumberGroup(db, 1, ssq.ID, "常买", "")
+	if err != nil {
+		t.Fatalf("CreateNumberGroup: %v", err)
+	}
+	if _, err := BulkMoveNumbers(db, 1, []int64{winner.ID, loser.ID}, &group.ID); err != nil {
+		t.Fatalf("BulkMoveNumbers: %v", err)
+	}
+
+	draws := []*model.DrawResult{
+		// 一等奖
+		{GameID: ssq.ID, Period: "2025100", DrawDate: time.Date(2025, 9, 2, 0, 0, 0, 0, time.Local),
+			RedBalls: model.NumberArray{1, 5, 12, 18, 25, 33}, BlueBalls: model.NumberArray{8}},
+		// 只中蓝球，六等奖
+		{GameID: ssq.ID, Period: "2025101", DrawDate: time.Date(2025, 9, 4, 0, 0, 0, 0, time.Local),
+			RedBalls: model.NumberArray{10, 11, 13, 14, 15, 17}, BlueBalls: model.NumberArray{8}},
+		// 未中奖
+		{GameID: ssq.ID, Period: "2025102", DrawDate: time.Date(2025, 9, 7, 0, 0, 0, 0, time.Local),
+			RedBalls: model.NumberArray{10, 11, 13, 14, 15, 17}, BlueBalls: model.NumberArray{1}},
+	}
+	for _, draw := range draws {
+		if err := db.Create(draw).Error; err != nil {
+			t.Fatalf("创建开奖结果失败: %v", err)
+		}
+	}
+
+	if _, err := GetGroupWinningSummary(db, 2, group.ID, 10); err == nil {
+		t.Error("查询其他用户的分组应返回错误")
+	}
+	summary, err := GetGroupWinningSummary(db, 1, group.ID, 10)
+	if err != nil {
+		t.Fatalf("GetGroupWinningSummary: %v", err)
+	}
+	if summary.NumberCount != 2 || summary.PeriodCount != 3 {
+		t.Errorf("号码数=%d 期数=%d, want 2 3", summary.NumberCount, summary.PeriodCount)
+	}
+	if summary.WinningCount != 2 || summary.WinningNumberCount != 1 || summary.BestPrizeLevel != 1 {
+		t.Errorf("中奖注次=%d 中奖号码数=%d 最高奖级=%d, want 2 1 1", summary.WinningCount, summary.WinningNumberCount, summary.BestPrizeLevel)
+	}
+	if len(summary.LevelCounts) != 2 || summary.LevelCounts[1] != 1 || summary.LevelCounts[6] != 1 {
+		t.Errorf("各奖级中奖注次 = %v", summary.LevelCounts)
+	}
+	// 逐期按开奖时间倒序
+	if len(summary.Periods) != 3 || summary.Periods[0].Period != "2025102" || summary.Periods[0].WinningCount != 0 ||
+		summary.Periods[1].BestPrizeLevel != 6 || summary.Periods[2].BestPrizeLevel != 1 {
+		t.Errorf("逐期中奖情况 = %+v", summary.Periods)
+	}
+}
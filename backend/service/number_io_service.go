@@ -0,0 +1,476 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"lucky/model"
+
+	"gorm.io/gorm"
+)
+
+// maxImportLines 单次导入的最大号码数
+const maxImportLines = 500
+
+// numberSeparator 号码之间的分隔符：空白、英文/中文逗号
+var numberSeparator = regexp.MustCompile(`[\s,，]+`)
+
+// ImportLineError 导入失败的行
+type ImportLineError struct {
+	Line    int    `json:"line"`    // 行号（JSON为数组下标+1）
+	Content string `json:"content"` // 原始内容
+	Error   string `json:"error"`   // 失败原因
+}
+
+// ImportResult 号码导入结果
+type ImportResult struct {
+	Total      int                `json:"total"`      // 有效行数
+	Imported   int                `json:"imported"`   // 成功导入数
+	Duplicated int                `json:"duplicated"` // 重复跳过数
+	Errors     []ImportLineError  `json:"errors"`     // 失败的行
+	Numbers    []model.UserNumber `json:"numbers"`    // 导入的号码
+}
+
+// ExportNumber 导出的单注号码
+type ExportNumber struct {
+	GameCode     string            `json:"gameCode"`
+	RedBalls     model.NumberArray `json:"redBalls"`
+	BlueBalls    model.NumberArray `json:"blueBalls"`
+	PlayType     string            `json:"playType"`     // 玩法，选号型游戏为空
+	Multiplier   int               `json:"multiplier"`   // 倍数，导入时为0按1倍
+	IsAdditional bool              `json:"isAdditional"` // 是否追加（大乐透）
+	Nickname     string            `json:"nickname"`
+	Note         string            `json:"note"`
+	Source       string            `json:"source"`
+	IsActive     bool              `json:"isActive"`
+	CreatedAt    time.Time         `json:"createdAt"`
+}
+
+// importEntry 解析后的待导入号码
+type importEntry struct {
+	line         int
+	content      string
+	redBalls     model.NumberArray
+	blueBalls    model.NumberArray
+	playType     string
+	multiplier   int
+	isAdditional bool
+	nickname     string
+	note         string
+	err          error
+}
+
+// ImportUserNumbers 批量导入用户号码，逐行校验并跳过重复号码
+func ImportUserNumbers(db *gorm.DB, userID uint64, game *model.LotteryGame, format, content string, groupID *int64) (*ImportResult, error) {
+	entries, err := parseImportContent(game, format, content)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > maxImportLines {
+		return nil, fmt.Errorf("单次最多导入%d注号码", maxImportLines)
+	}
+
+	if groupID != nil {
+		group, err := getUserNumberGroup(db, userID, *groupID)
+		if err != nil {
+			return nil, err
+		}
+		if group.GameID != game.ID {
+			return nil, errors.New("分组与导入号码的游戏不一致")
+		}
+	}
+
+	var existingNumbers []model.UserNumber
+	if err := db.Where("user_id = ? AND game_id = ?", int64(userID), game.ID).Find(&existingNumbers).Error; err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{Total: len(entries), Errors: []ImportLineError{}}
+	var toCreate []model.UserNumber
+	for _, entry := range entries {
+		if entry.err != nil {
+			result.Errors = append(result.Errors, ImportLineError{Line: entry.line, Content: entry.content, Error: entry.err.Error()})
+			continue
+		}
+		if err := ValidateNumbers(game, entry.redBalls, entry.blueBalls); err != nil {
+			result.Errors = append(result.Errors, ImportLineError{Line: entry.line, Content: entry.content, Error: err.Error()})
+			continue
+		}
+		playType, err := ValidatePlayType(game, entry.playType, entry.redBalls)
+		if err != nil {
+			result.Errors = append(result.Errors, ImportLineError{Line: entry.line, Content: entry.content, Error: err.Error()})
+			continue
+		}
+		multiplier := entry.multiplier
+		if multiplier == 0 {
+			multiplier = 1
+		}
+		if err := ValidateBetOptions(game, multiplier, entry.isAdditional); err != nil {
+			result.Errors = append(result.Errors, ImportLineError{Line: entry.line, Content: entry.content, Error: err.Error()})
+			continue
+		}
+		if containsNumber(game, existingNumbers, entry.redBalls, entry.blueBalls, playType) || containsNumber(game, toCreate, entry.redBalls, entry.blueBalls, playType) {
+			result.Duplicated++
+			continue
+		}
+		toCreate = append(toCreate, model.UserNumber{
+			UserID:       int64(userID),
+			GameID:       game.ID,
+			RedBalls:     entry.redBalls,
+			BlueBalls:    entry.blueBalls,
+			PlayType:     playType,
+			Multiplier:   multiplier,
+			IsAdditional: entry.isAdditional,
+			GroupID:      groupID,
+			Nickname:     entry.nickname,
+			Note:         entry.note,
+			Source:       "import",
+			IsActive:     true,
+		})
+	}
+
+	if len(toCreate) > 0 {
+		if err := db.Create(&toCreate).Error; err != nil {
+			return nil, err
+		}
+	}
+	result.Imported = len(toCreate)
+	result.Numbers = toCreate
+
+	return result, nil
+}
+
+// ExportUserNumbers 导出用户号码，返回文件内容和Content-Type
+func ExportUserNumbers(db *gorm.DB, userID uint64, gameCode, format string) ([]byte, string, error) {
+	query := db.Preload("Game").Where("user_numbers.user_id = ?", userID)
+	if gameCode != "" {
+		query = query.Joins("JOIN lottery_games ON user_numbers.game_id = lottery_games.id").
+			Where("lottery_games.game_code = ?", gameCode)
+	}
+
+	var numbers []model.UserNumber
+	if err := query.Order("user_numbers.game_id ASC, user_numbers.created_at ASC").Find(&numbers).Error; err != nil {
+		return nil, "", err
+	}
+
+	exports := make([]ExportNumber, 0, len(numbers))
+	for _, number := range numbers {
+		exports = append(exports, ExportNumber{
+			GameCode:     number.Game.GameCode,
+			RedBalls:     number.RedBalls,
+			BlueBalls:    number.BlueBalls,
+			PlayType:     number.PlayType,
+			Multiplier:   number.Multiplier,
+			IsAdditional: number.IsAdditional,
+			Nickname:     number.Nickname,
+			Note:         number.Note,
+			Source:       number.Source,
+			IsActive:     number.IsActive,
+			CreatedAt:    number.CreatedAt,
+		})
+	}
+
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(exports, "", "  ")
+		return data, "application/json; charset=utf-8", err
+	case "csv":
+		data, err := exportNumbersCSV(exports)
+		return data, "text/csv; charset=utf-8", err
+	case "txt", "":
+		return exportNumbersText(exports), "text/plain; charset=utf-8", nil
+	default:
+		return nil, "", fmt.Errorf("不支持的导出格式: %s", format)
+	}
+}
+
+// ParseNumberLine 解析单行号码文本
+// 支持 "01 05 16 20 21 32 + 07"、"01,11,14,25,27+04,10" 以及不带"+"按位置区分前后区的 "05 07 08 15 33 06 10"
+// 数字型游戏支持 "5 2 8"、"5,2,8" 和 "528"，七星彩如 "1 2 3 4 5 6 + 14"，快乐8为1-10个号码
+func ParseNumberLine(game *model.LotteryGame, line string) (model.NumberArray, model.NumberArray, error) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil, nil, errors.New("号码为空")
+	}
+
+	// 七星彩带后区，与选号型一样以"+"分隔或按位置区分前后区
+	if IsDigitGame(game) && game.BlueSelectCount == 0 {
+		digits, err := parseDigits(line, game.RedSelectCount)
+		if err != nil {
+			return nil, nil, err
+		}
+		return digits, model.NumberArray{}, nil
+	}
+	// 快乐8选号个数不固定，整行均为号码
+	if IsKenoGame(game) {
+		balls, err := parseBallList(line)
+		if err != nil {
+			return nil, nil, err
+		}
+		return balls, model.NumberArray{}, nil
+	}
+
+	if strings.Contains(line, "+") {
+		parts := strings.Split(line, "+")
+		if len(parts) != 2 {
+			return nil, nil, errors.New("号码格式错误，只能包含一个\"+\"")
+		}
+		redBalls, err := parseBallList(parts[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		blueBalls, err := parseBallList(parts[1])
+		if err != nil {
+			return nil, nil, err
+		}
+		return redBalls, blueBalls, nil
+	}
+
+	balls, err := parseBallList(line)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(balls) != game.RedSelectCount+game.BlueSelectCount {
+		return nil, nil, fmt.Errorf("号码个数不正确，需要%d个红球和%d个蓝球", game.RedSelectCount, game.BlueSelectCount)
+	}
+	return balls[:game.RedSelectCount], balls[game.RedSelectCount:], nil
+}
+
+// FormatNumberLine 将号码格式化为 "01 05 16 20 21 32 + 07"
+func FormatNumberLine(redBalls, blueBalls model.NumberArray) string {
+	if len(blueBalls) == 0 {
+		return formatBallList(redBalls)
+	}
+	return formatBallList(redBalls) + " + " + formatBallList(blueBalls)
+}
+
+// parseImportContent 按格式解析导入内容
+func parseImportContent(game *model.LotteryGame, format, content string) ([]importEntry, error) {
+	switch format {
+	case "txt", "":
+		return parseImportText(game, content), nil
+	case "csv":
+		return parseImportCSV(game, content)
+	case "json":
+		return parseImportJSON(game, content)
+	default:
+		return nil, fmt.Errorf("不支持的导入格式: %s", format)
+	}
+}
+
+// parseImportText 解析文本格式，每行一注，#开头为注释
+func parseImportText(game *model.LotteryGame, content string) []importEntry {
+	var entries []importEntry
+	for i, raw := range strings.Split(content, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entry := importEntry{line: i + 1, content: line}
+		entry.redBalls, entry.blueBalls, entry.err = ParseNumberLine(game, line)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// parseImportCSV 解析CSV格式，需包含red_balls、blue_balls列，可选game_code、play_type、multiplier、is_additional、nickname、note列
+func parseImportCSV(game *model.LotteryGame, content string) ([]importEntry, error) {
+	reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(content, "\ufeff")))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("CSV解析失败: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("CSV内容为空")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.TrimSpace(strings.ToLower(name))] = i
+	}
+	redIdx, hasRed := columns["red_balls"]
+	blueIdx, hasBlue := columns["blue_balls"]
+	if !hasRed || !hasBlue {
+		return nil, errors.New("CSV表头必须包含red_balls和blue_balls列")
+	}
+
+	field := func(record []string, name string) string {
+		if idx, ok := columns[name]; ok && idx < len(record) {
+			return strings.TrimSpace(record[idx])
+		}
+		return ""
+	}
+
+	var entries []importEntry
+	for i, record := range records[1:] {
+		entry := importEntry{
+			line:     i + 2,
+			content:  strings.Join(record, ","),
+			playType: field(record, "play_type"),
+			nickname: field(record, "nickname"),
+			note:     field(record, "note"),
+		}
+		if gameCode := field(record, "game_code"); gameCode != "" && gameCode != game.GameCode {
+			entry.err = fmt.Errorf("游戏代码 %s 与导入游戏 %s 不一致", gameCode, game.GameCode)
+		} else if redIdx >= len(record) || blueIdx >= len(record) {
+			entry.err = errors.New("缺少号码列")
+		} else {
+			entry.redBalls, entry.err = parseBallList(record[redIdx])
+			if entry.err == nil {
+				// 数字型和快乐8没有蓝球，导出的蓝球列为空
+				entry.blueBalls = model.NumberArray{}
+				if blue := strings.TrimSpace(record[blueIdx]); blue != "" {
+					entry.blueBalls, entry.err = parseBallList(blue)
+				}
+			}
+			if entry.err == nil {
+				entry.multiplier, entry.isAdditional, entry.err = parseBetOptions(field(record, "multiplier"), field(record, "is_additional"))
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// parseImportJSON 解析JSON数组格式，元素结构同导出格式
+func parseImportJSON(game *model.LotteryGame, content string) ([]importEntry, error) {
+	var items []ExportNumber
+	if err := json.Unmarshal([]byte(content), &items); err != nil {
+		return nil, fmt.Errorf("JSON解析失败: %v", err)
+	}
+
+	entries := make([]importEntry, 0, len(items))
+	for i, item := range items {
+		entry := importEntry{
+			line:         i + 1,
+			content:      FormatNumberLine(item.RedBalls, item.BlueBalls),
+			redBalls:     item.RedBalls,
+			blueBalls:    item.BlueBalls,
+			playType:     item.PlayType,
+			multiplier:   item.Multiplier,
+			isAdditional: item.IsAdditional,
+			nickname:     item.Nickname,
+			note:         item.Note,
+		}
+		if item.GameCode != "" && item.GameCode != game.GameCode {
+			entry.err = fmt.Errorf("游戏代码 %s 与导入游戏 %s 不一致", item.GameCode, game.GameCode)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// exportNumbersText 导出为文本格式，按游戏分段
+func exportNumbersText(exports []ExportNumber) []byte {
+	var buf bytes.Buffer
+	lastGame := ""
+	for _, number := range exports {
+		if number.GameCode != lastGame {
+			if lastGame != "" {
+				buf.WriteString("\n")
+			}
+			buf.WriteString("# " + number.GameCode + "\n")
+			lastGame = number.GameCode
+		}
+		buf.WriteString(FormatNumberLine(number.RedBalls, number.BlueBalls) + "\n")
+	}
+	return buf.Bytes()
+}
+
+// exportNumbersCSV 导出为CSV格式（带BOM便于Excel识别中文）
+func exportNumbersCSV(exports []ExportNumber) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\ufeff")
+	writer := csv.NewWriter(&buf)
+	header := []string{"game_code", "red_balls", "blue_balls", "play_type", "multiplier", "is_additional", "nickname", "note", "source", "is_active", "created_at"}
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+	for _, number := range exports {
+		record := []string{
+			number.GameCode,
+			formatBallList(number.RedBalls),
+			formatBallList(number.BlueBalls),
+			number.PlayType,
+			strconv.Itoa(number.Multiplier),
+			strconv.FormatBool(number.IsAdditional),
+			number.Nickname,
+			number.Note,
+			number.Source,
+			strconv.FormatBool(number.IsActive),
+			number.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// parseBetOptions 解析CSV中的倍数和追加列，为空时倍数按1倍、不追加
+func parseBetOptions(multiplierText, additionalText string) (int, bool, error) {
+	multiplier := 1
+	if multiplierText != "" {
+		value, err := strconv.Atoi(multiplierText)
+		if err != nil {
+			return 0, false, fmt.Errorf("无法识别的倍数: %s", multiplierText)
+		}
+		multiplier = value
+	}
+	isAdditional := false
+	if additionalText != "" {
+		value, err := strconv.ParseBool(additionalText)
+		if err != nil {
+			return 0, false, fmt.Errorf("无法识别的追加设置: %s", additionalText)
+		}
+		isAdditional = value
+	}
+	return multiplier, isAdditional, nil
+}
+
+// parseBallList 解析以空白或逗号分隔的号码列表
+func parseBallList(text string) (model.NumberArray, error) {
+	balls := model.NumberArray{}
+	for _, field := range numberSeparator.Split(strings.TrimSpace(text), -1) {
+		if field == "" {
+			continue
+		}
+		num, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("无法识别的号码: %s", field)
+		}
+		balls = append(balls, num)
+	}
+	if len(balls) == 0 {
+		return nil, errors.New("号码为空")
+	}
+	return balls, nil
+}
+
+// formatBallList 将号码格式化为两位数并以空格分隔
+func formatBallList(balls model.NumberArray) string {
+	parts := make([]string, 0, len(balls))
+	for _, ball := range balls {
+		parts = append(parts, fmt.Sprintf("%02d", ball))
+	}
+	return strings.Join(parts, " ")
+}
+
+// containsNumber 判断号码列表中是否已有相同号码
+func containsNumber(game *model.LotteryGame, numbers []model.UserNumber, redBalls, blueBalls model.NumberArray, playType string) bool {
+	for i := range numbers {
+		if sameNumber(game, &numbers[i], redBalls, blueBalls, playType) {
+			return true
+		}
+	}
+	return false
+}
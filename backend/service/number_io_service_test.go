@@ -0,0 +1,175 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"lucky/common/database/dbtest"
+	"lucky/model"
+)
+
+var testDLTGame = &model.LotteryGame{
+	GameCode:        "dlt",
+	GameName:        "大乐透",
+	RedBallCount:    35,
+	BlueBallCount:   12,
+	RedSelectCount:  5,
+	BlueSelectCount: 2,
+}
+
+func TestParseNumberLine(t *testing.T) {
+	cases := []struct {
+		game *model.LotteryGame
+		line string
+		red  model.NumberArray
+		blue model.NumberArray
+	}{
+		{testSSQGame, "01 05 16 20 21 32 + 07", model.NumberArray{1, 5, 16, 20, 21, 32}, model.NumberArray{7}},
+		{testDLTGame, "01,11,14,25,27+04,10", model.NumberArray{1, 11, 14, 25, 27}, model.NumberArray{4, 10}},
+		{testDLTGame, "05 07 08 15 33 06 10", model.NumberArray{5, 7, 8, 15, 33}, model.NumberArray{6, 10}},
+		{testSSQGame, "03，09，12，18，27，30 + 11", model.NumberArray{3, 9, 12, 18, 27, 30}, model.NumberArray{11}},
+	}
+	for _, tc := range cases {
+		red, blue, err := ParseNumberLine(tc.game, tc.line)
+		if err != nil {
+			t.Fatalf("ParseNumberLine(%q) error = %v", tc.line, err)
+		}
+		if !compareNumberArrays(red, tc.red) || !compareNumberArrays(blue, tc.blue) {
+			t.Errorf("ParseNumberLine(%q) = %v + %v, want %v + %v", tc.line, red, blue, tc.red, tc.blue)
+		}
+	}
+
+	for _, line := range []string{"01 02 03 + 04 + 05", "01 02 0a 04 05 06 + 07", "01 02 03 04 05 06", ""} {
+		if _, _, err := ParseNumberLine(testSSQGame, line); err == nil {
+			t.Errorf("ParseNumberLine(%q) 应返回错误", line)
+		}
+	}
+}
+
+func TestParseImportText(t *testing.T) {
+	content := "# ssq\n01 05 16 20 21 32 + 07\n\nabc\n02 06 17 21 22 33 + 08\n"
+	entries := parseImportText(testSSQGame, content)
+	if len(entries) != 3 {
+		t.Fatalf("len(entries) = %d, want 3", len(entries))
+	}
+	if entries[0].line != 2 || entries[0].err != nil {
+		t.Errorf("第一注解析错误: %+v", entries[0])
+	}
+	if entries[1].line != 4 || entries[1].err == nil {
+		t.Errorf("非法行应记录错误: %+v", entries[1])
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	exports := []ExportNumber{
+		{GameCode: "ssq", RedBalls: model.NumberArray{1, 5, 16, 20, 21, 32}, BlueBalls: model.NumberArray{7}, Nickname: "生日"},
+		{GameCode: "ssq", RedBalls: model.NumberArray{2, 6, 17, 21, 22, 33}, BlueBalls: model.NumberArray{8}},
+	}
+
+	textEntries := parseImportText(testSSQGame, string(exportNumbersText(exports)))
+	csvData, err := exportNumbersCSV(exports)
+	if err != nil {
+		t.Fatalf("exportNumbersCSV error = %v", err)
+	}
+	csvEntries, err := parseImportCSV(testSSQGame, string(csvData))
+	if err != nil {
+		t.Fatalf("parseImportCSV error = %v", err)
+	}
+
+	for name, entries := range map[string][]importEntry{"txt": textEntries, "csv": csvEntries} {
+		if len(entries) != len(exports) {
+			t.Fatalf("%s: len(entries) = %d, want %d", name, len(entries), len(exports))
+		}
+		for i, entry := range entries {
+			if entry.err != nil {
+				t.Fatalf("%s: 第%d行解析错误: %v", name, entry.line, entry.err)
+			}
+			if !compareNumberArrays(entry.redBalls, exports[i].RedBalls) || !compareNumberArrays(entry.blueBalls, exports[i].BlueBalls) {
+				t.Errorf("%s: 第%d注号码不一致", name, i+1)
+			}
+		}
+	}
+	if csvEntries[0].nickname != "生日" {
+		t.Errorf("CSV昵称 = %q, want 生日", csvEntries[0].nickname)
+	}
+
+	entries, err := parseImportCSV(testDLTGame, string(csvData))
+	if err != nil {
+		t.Fatalf("parseImportCSV error = %v", err)
+	}
+	if entries[0].err == nil || !strings.Contains(entries[0].err.Error(), "不一致") {
+		t.Errorf("游戏不一致时应记录错误: %+v", entries[0])
+	}
+}
+
+// TestImportExportBetOptions 导出后再导入保留玩法、倍数和追加
+func TestImportExportBetOptions(t *testing.T) {
+	db := dbtest.Open(t)
+	fc3d, dlt := *testFC3DGame, *testDLTGame
+	dlt.GameType = model.GameTypeBall
+	for _, game := range []*model.LotteryGame{&fc3d, &dlt} {
+		game.IsActive = true
+		if err := db.Create(game).Error; err != nil {
+			t.Fatalf("创建游戏失败: %v", err)
+		}
+	}
+	originals := []*model.UserNumber{
+		{GameID: fc3d.ID, RedBalls: model.NumberArray{1, 1, 2}, PlayType: model.PlayTypeGroup3, Multiplier: 5},
+		{GameID: fc3d.ID, RedBalls: model.NumberArray{1, 2, 3}, PlayType: model.PlayTypeGroup6, Multiplier: 1},
+		{GameID: dlt.ID, RedBalls: model.NumberArray{1, 5, 16, 20, 21}, BlueBalls: model.NumberArray{3, 7}, Multiplier: 2, IsAdditional: true},
+	}
+	for _, number := range originals {
+		number.UserID = 1
+		if number.BlueBalls == nil {
+			number.BlueBalls = model.NumberArray{}
+		}
+		if err := db.Omit("Game").Create(number).Error; err != nil {
+			t.Fatalf("创建号码失败: %v", err)
+		}
+	}
+
+	for i, format := range []string{"json", "csv"} {
+		userID := uint64(10 + i)
+		for _, game := range []*model.LotteryGame{&fc3d, &dlt} {
+			data, _, err := ExportUserNumbers(db, 1, game.GameCode, format)
+			if err != nil {
+				t.Fatalf("%s: ExportUserNumbers: %v", format, err)
+			}
+			result, err := ImportUserNumbers(db, userID, game, format, string(data), nil)
+			if err != nil || len(result.Errors) > 0 {
+				t.Fatalf("%s: ImportUserNumbers: %v %+v", format, err, result)
+			}
+		}
+
+		var imported []model.UserNumber
+		db.Where("user_id = ?", userID).Order("id ASC").Find(&imported)
+		if len(imported) != len(originals) {
+			t.Fatalf("%s: 导入 %d 注, want %d", format, len(imported), len(originals))
+		}
+		for j, number := range imported {
+			want := originals[j]
+			if number.PlayType != want.PlayType || number.Multiplier != want.Multiplier || number.IsAdditional != want.IsAdditional {
+				t.Errorf("%s: 第%d注 玩法=%q 倍数=%d 追加=%v, want %q %d %v", format, j+1,
+					number.PlayType, number.Multiplier, number.IsAdditional, want.PlayType, want.Multiplier, want.IsAdditional)
+			}
+		}
+	}
+
+	// 玩法与号码不符、不支持的玩法和非法倍数记为失败，不以默认玩法保存
+	content := `[
+		{"redBalls": [1, 2, 3], "playType": "group3"},
+		{"redBalls": [4, 5, 6], "playType": "pick5"},
+		{"redBalls": [7, 8, 9], "multiplier": 1000},
+		{"redBalls": [7, 8, 9], "playType": "group6"}
+	]`
+	result, err := ImportUserNumbers(db, 20, &fc3d, "json", content, nil)
+	if err != nil {
+		t.Fatalf("ImportUserNumbers: %v", err)
+	}
+	if result.Imported != 1 || len(result.Errors) != 3 {
+		t.Fatalf("应导入1注、失败3注, got imported=%d errors=%+v", result.Imported, result.Errors)
+	}
+	if result.Numbers[0].PlayType != model.PlayTypeGroup6 || result.Numbers[0].Multiplier != 1 {
+		t.Errorf("未指定倍数时按1倍: %+v", result.Numbers[0])
+	}
+}
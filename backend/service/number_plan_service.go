@@ -0,0 +1,310 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"lucky/model"
+
+	"gorm.io/gorm"
+)
+
+// maxPlanPeriods 追号计划最多期数
+const maxPlanPeriods = 150
+
+// NumberPlanInfo 追号计划及进度
+type NumberPlanInfo struct {
+	model.NumberPlan
+	RemainingPeriods int   `json:"remaining_periods"` // 剩余期数
+	TotalCost        int64 `json:"total_cost"`        // 计划总金额(分)
+	SpentCost        int64 `json:"spent_cost"`        // 已开奖期数金额(分)
+	Profit           int64 `json:"profit"`            // 累计盈亏(分)
+}
+
+// PlanPeriodResult 追号计划单期情况
+type PlanPeriodResult struct {
+	ScheduledDraw
+	RedMatches  int   `json:"redMatches"`  // 红球匹配数
+	BlueMatches int   `json:"blueMatches"` // 蓝球匹配数
+	PrizeLevel  int   `json:"prizeLevel"`  // 奖级，0表示未中奖
+	PrizeAmount int64 `json:"prizeAmount"` // 奖金(分)
+}
+
+// NumberPlanDetail 追号计划详情
+type NumberPlanDetail struct {
+	Plan    NumberPlanInfo     `json:"plan"`
+	Periods []PlanPeriodResult `json:"periods"` // 计划覆盖的各期
+}
+
+// CreateNumberPlan 创建追号计划，startPeriod为空时从下一期开始
+// multiplier为0、isAdditional为nil时沿用号码的倍数和追加设置
+func CreateNumberPlan(db *gorm.DB, userID uint64, numberID int64, startPeriod string, totalPeriods int, stopAfterWin bool, multiplier int, isAdditional *bool) (*NumberPlanInfo, error) {
+	if totalPeriods <= 0 || totalPeriods > maxPlanPeriods {
+		return nil, fmt.Errorf("追号期数需在1-%d之间", maxPlanPeriods)
+	}
+
+	userNumber, err := model.NewUserNumberDAO(db).GetByIDWithGame(numberID)
+	if err != nil || userNumber.UserID != int64(userID) {
+		return nil, fmt.Errorf("号码不存在或不属于该用户")
+	}
+
+	if startPeriod == "" {
+		next, err := GetNextDraw(db, &userNumber.Game)
+		if err != nil {
+			return nil, err
+		}
+		startPeriod = next.Period
+	} else if err := ValidatePeriod(startPeriod); err != nil {
+		return nil, err
+	}
+
+	if multiplier == 0 {
+		multiplier = userNumber.Multiplier
+	}
+	if multiplier == 0 {
+		multiplier = 1
+	}
+	additional := userNumber.IsAdditional
+	if isAdditional != nil {
+		additional = *isAdditional
+	}
+	if err := ValidateBetOptions(&userNumber.Game, multiplier, additional); err != nil {
+		return nil, err
+	}
+
+	plan := &model.NumberPlan{
+		UserID:       int64(userID),
+		UserNumberID: userNumber.ID,
+		GameID:       userNumber.GameID,
+		StartPeriod:  startPeriod,
+		TotalPeriods: totalPeriods,
+		StopAfterWin: stopAfterWin,
+		Multiplier:   multiplier,
+		IsAdditional: additional,
+		Status:       "active",
+	}
+	if err := model.NewNumberPlanDAO(db).Create(plan); err != nil {
+		return nil, err
+	}
+
+	// 起始期号已开奖时立即核对
+	if err := refreshNumberPlan(db, plan, userNumber); err != nil {
+		return nil, err
+	}
+	plan.UserNumber = *userNumber
+
+	info := newNumberPlanInfo(plan)
+	return &info, nil
+}
+
+// GetNumberPlans 获取用户的追号计划列表
+func GetNumberPlans(db *gorm.DB, userID uint64, status string) ([]NumberPlanInfo, error) {
+	plans, err := model.NewNumberPlanDAO(db).GetByUserID(int64(userID), status)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]NumberPlanInfo, 0, len(plans))
+	for _, plan := range plans {
+		infos = append(infos, newNumberPlanInfo(plan))
+	}
+	return infos, nil
+}
+
+// GetNumberPlanDetail 获取追号计划详情，包含已开奖期的结果和未开奖期的预计开奖日期
+func GetNumberPlanDetail(db *gorm.DB, userID uint64, planID int64) (*NumberPlanDetail, error) {
+	plan, err := getUserNumberPlan(db, userID, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	planDAO := model.NewNumberPlanDAO(db)
+	records, err := planDAO.GetPeriods(plan.ID)
+	if err != nil {
+		return nil, err
+	}
+	recordMap := make(map[string]*model.NumberPlanPeriod)
+	for _, record := range records {
+		recordMap[record.Period] = record
+	}
+
+	detail := &NumberPlanDetail{Plan: newNumberPlanInfo(plan), Periods: []PlanPeriodResult{}}
+
+	// 已停止的计划只展示实际购买的期数
+	count := plan.TotalPeriods
+	if plan.Status == "stopped" || plan.Status == "cancelled" {
+		count = plan.DrawnPeriods
+	}
+	if count == 0 {
+		return detail, nil
+	}
+
+	draws, err := GetCoveredDraws(db, &plan.UserNumber.Game, plan.StartPeriod, count)
+	if err != nil {
+		return nil, err
+	}
+	for _, draw := range draws {
+		result := PlanPeriodResult{ScheduledDraw: draw}
+		if record, ok := recordMap[draw.Period]; ok {
+			result.RedMatches = record.RedMatches
+			result.BlueMatches = record.BlueMatches
+			result.PrizeLevel = record.PrizeLevel
+			result.PrizeAmount = record.PrizeAmount
+		}
+		detail.Periods = append(detail.Periods, result)
+	}
+	return detail, nil
+}
+
+// CancelNumberPlan 取消进行中的追号计划
+func CancelNumberPlan(db *gorm.DB, userID uint64, planID int64) error {
+	plan, err := getUserNumberPlan(db, userID, planID)
+	if err != nil {
+		return err
+	}
+	if plan.Status != "active" {
+		return errors.New("只能取消进行中的追号计划")
+	}
+	plan.Status = "cancelled"
+	return model.NewNumberPlanDAO(db).Update(plan)
+}
+
+// EvaluateNumberPlans 新一期开奖后核对该游戏所有进行中的追号计划
+func EvaluateNumberPlans(db *gorm.DB, drawResult *model.DrawResult) error {
+	plans, err := model.NewNumberPlanDAO(db).GetActiveByGameID(drawResult.GameID, drawResult.Period)
+	if err != nil {
+		return err
+	}
+
+	// 单个计划失败不影响其他计划的核对
+	var failedPlans []int64
+	for _, plan := range plans {
+		userNumber, err := model.NewUserNumberDAO(db).GetByIDWithGame(plan.UserNumberID)
+		if err == nil {
+			err = refreshNumberPlan(db, plan, userNumber)
+		}
+		if err != nil {
+			failedPlans = append(failedPlans, plan.ID)
+		}
+	}
+	if len(failedPlans) > 0 {
+		return fmt.Errorf("追号计划 %v 核对失败", failedPlans)
+	}
+	return nil
+}
+
+// refreshNumberPlan 按已开奖结果重新计算追号计划的逐期结果和进度
+func refreshNumberPlan(db *gorm.DB, plan *model.NumberPlan, userNumber *model.UserNumber) error {
+	var drawResults []model.DrawResult
+	err := db.Where("game_id = ? AND period >= ?", plan.GameID, plan.StartPeriod).
+		Order("period ASC").
+		Limit(plan.TotalPeriods).
+		Find(&drawResults).Error
+	if err != nil {
+		return err
+	}
+
+	plan.DrawnPeriods = 0
+	plan.WinningPeriods = 0
+	plan.TotalPrize = 0
+	plan.LastPeriod = ""
+
+	records := make([]model.NumberPlanPeriod, 0, len(drawResults))
+	purchases := make([]model.Purchase, 0, len(drawResults))
+	userDraws := make([]model.UserDraw, 0, len(drawResults))
+	stopped := false
+	for i := range drawResults {
+		drawResult := &drawResults[i]
+		redMatches, blueMatches, prizeLevel, prizeAmount := EvaluateNumber(&userNumber.Game, userNumber, drawResult)
+		betPrize := ApplyBetOptions(userNumber.Game.GameCode, prizeLevel, prizeAmount, plan.Multiplier, plan.IsAdditional)
+
+		record := model.NumberPlanPeriod{
+			PlanID:       plan.ID,
+			Period:       drawResult.Period,
+			DrawResultID: drawResult.ID,
+			RedMatches:   redMatches,
+			BlueMatches:  blueMatches,
+			PrizeLevel:   prizeLevel,
+			PrizeAmount:  betPrize,
+		}
+		records = append(records, record)
+		userDraws = append(userDraws, model.UserDraw{DrawResultID: uint(drawResult.ID), PrizeLevel: prizeLevel, PrizeAmount: prizeAmount})
+		purchases = append(purchases, newPlanPurchase(plan, &record, drawResult.DrawDate))
+
+		plan.DrawnPeriods++
+		plan.LastPeriod = drawResult.Period
+		if prizeLevel > 0 {
+			plan.WinningPeriods++
+			plan.TotalPrize += betPrize
+			if plan.StopAfterWin {
+				stopped = true
+				break
+			}
+		}
+	}
+
+	switch {
+	case stopped:
+		plan.Status = "stopped"
+	case plan.DrawnPeriods >= plan.TotalPeriods:
+		plan.Status = "finished"
+	}
+
+	// 逐期结果、核对记录和购彩账本一并更新
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("plan_id = ?", plan.ID).Delete(&model.NumberPlanPeriod{}).Error; err != nil {
+			return err
+		}
+		if err := model.NewPurchaseDAO(tx).DeleteByPlanID(plan.ID); err != nil {
+			return err
+		}
+		if len(records) > 0 {
+			if err := tx.Create(&records).Error; err != nil {
+				return err
+			}
+			if err := tx.Omit("Game").Create(&purchases).Error; err != nil {
+				return err
+			}
+		}
+		for _, userDraw := range userDraws {
+			if err := saveUserDraw(tx, plan.UserNumberID, uint64(userDraw.DrawResultID), userDraw.PrizeLevel, userDraw.PrizeAmount); err != nil {
+				return err
+			}
+		}
+		return model.NewNumberPlanDAO(tx).Update(plan)
+	})
+	if err != nil {
+		return err
+	}
+	for _, userDraw := range userDraws {
+		observeSavedEvaluations(userNumber.Game.GameCode, userDraw.PrizeLevel)
+	}
+	return nil
+}
+
+// getUserNumberPlan 获取属于指定用户的追号计划
+func getUserNumberPlan(db *gorm.DB, userID uint64, planID int64) (*model.NumberPlan, error) {
+	plan, err := model.NewNumberPlanDAO(db).GetByID(planID)
+	if err != nil || plan.UserID != int64(userID) {
+		return nil, fmt.Errorf("追号计划不存在或不属于该用户")
+	}
+	return plan, nil
+}
+
+// newNumberPlanInfo 计算追号计划的进度和金额
+func newNumberPlanInfo(plan *model.NumberPlan) NumberPlanInfo {
+	info := NumberPlanInfo{
+		NumberPlan: *plan,
+		TotalCost:  calculateBetCost(plan.TotalPeriods, plan.Multiplier, plan.IsAdditional),
+		SpentCost:  calculateBetCost(plan.DrawnPeriods, plan.Multiplier, plan.IsAdditional),
+	}
+	switch plan.Status {
+	case "active":
+		info.RemainingPeriods = plan.TotalPeriods - plan.DrawnPeriods
+	case "stopped", "cancelled":
+		// 停追或取消后剩余期数不再购买
+		info.TotalCost = info.SpentCost
+	}
+	info.Profit = plan.TotalPrize - info.SpentCost
+	return info
+}
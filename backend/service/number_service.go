@@ -4,13 +4,24 @@ import (
 	"errors"
 	"fmt"
 
+	applog "lucky/common/log"
 	"lucky/model"
 
+	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 // ValidateNumbers 验证号码
 func ValidateNumbers(game *model.LotteryGame, redBalls, blueBalls model.NumberArray) error {
+	// 数字型游戏按位校验，允许重复
+	if IsDigitGame(game) {
+		return validateDigits(game, redBalls, blueBalls)
+	}
+	// 快乐8选号个数随玩法变化
+	if IsKenoGame(game) {
+		return validateKenoNumbers(game, redBalls, blueBalls)
+	}
+
 	// 验证红球数量
 	if len(redBalls) != game.RedSelectCount {
 		return fmt.Errorf("红球数量不正确，需要%d个", game.RedSelectCount)
@@ -75,32 +86,53 @@ func compareNumberArrays(arr1, arr2 model.NumberArray) bool {
 	return true
 }
 
-// SaveUserNumber 保存用户号码
-func SaveUserNumber(db *gorm.DB, userID uint64, gameID uint64, redBalls, blueBalls model.NumberArray, nickname, source string) (*model.UserNumber, error) {
+// sameNumber 判断是否为同一注号码，数字型直选按顺序比较，其余忽略顺序
+func sameNumber(game *model.LotteryGame, number *model.UserNumber, redBalls, blueBalls model.NumberArray, playType string) bool {
+	if number.PlayType != playType {
+		return false
+	}
+	if IsDigitGame(game) && playType == model.PlayTypeDirect {
+		return len(number.RedBalls) == len(redBalls) && countPositionMatches(number.RedBalls, redBalls) == len(redBalls) &&
+			len(number.BlueBalls) == len(blueBalls) && countPositionMatches(number.BlueBalls, blueBalls) == len(blueBalls)
+	}
+	return compareNumberArrays(number.RedBalls, redBalls) && compareNumberArrays(number.BlueBalls, blueBalls)
+}
+
+// SaveUserNumber 保存用户号码，playType 为数字型游戏的玩法
+// 已存在相同号码时返回已有记录，created 为 false
+func SaveUserNumber(db *gorm.DB, userID uint64, gameID uint64, redBalls, blueBalls model.NumberArray, playType, nickname, source string) (*model.UserNumber, bool, error) {
 	// 获取游戏信息
 	var game model.LotteryGame
 	if err := db.First(&game, gameID).Error; err != nil {
-		return nil, fmt.Errorf("游戏不存在")
+		return nil, false, fmt.Errorf("游戏不存在")
 	}
 
 	// 验证号码
 	if err := ValidateNumbers(&game, redBalls, blueBalls); err != nil {
-		return nil, err
+		return nil, false, err
+	}
+	playType, err := ValidatePlayType(&game, playType, redBalls)
+	if err != nil {
+		return nil, false, err
+	}
+	redBalls = normalizeDigits(playType, redBalls)
+	if blueBalls == nil {
+		blueBalls = model.NumberArray{}
 	}
 
 	// 检查是否已存在相同的号码
 	var existingNumbers []model.UserNumber
-	err := db.Where("user_id = ? AND game_id = ?", int64(userID), uint64(gameID)).Find(&existingNumbers).Error
+	err = db.Where("user_id = ? AND game_id = ?", int64(userID), uint64(gameID)).Find(&existingNumbers).Error
 
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	// 手动比较号码数组
 	for _, existing := range existingNumbers {
-		if compareNumberArrays(existing.RedBalls, redBalls) && compareNumberArrays(existing.BlueBalls, blueBalls) {
+		if sameNumber(&game, &existing, redBalls, blueBalls, playType) {
 			// 找到相同的号码，返回现有记录
-			return &existing, nil
+			return &existing, false, nil
 		}
 	}
 
@@ -110,16 +142,22 @@ func SaveUserNumber(db *gorm.DB, userID uint64, gameID uint64, redBalls, blueBal
 		GameID:    uint64(gameID),
 		RedBalls:  redBalls,
 		BlueBalls: blueBalls,
+		PlayType:  playType,
 		Nickname:  nickname,
 		Source:    source,
 		IsActive:  true,
 	}
 
 	if err := db.Create(&userNumber).Error; err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	return &userNumber, nil
+	dbLogger(db).WithFields(logrus.Fields{
+		applog.FieldGameCode: game.GameCode,
+		"number_id":          userNumber.ID,
+		"source":             source,
+	}).Info("保存用户号码")
+	return &userNumber, true, nil
 }
 
 // SaveUserNumbers 保存用户号码（批量）
@@ -148,7 +186,7 @@ func SaveUserNumbers(db *gorm.DB, userID uint64, gameID uint64, redBalls, blueBa
 }
 
 // UpdateUserNumber 更新用户号码
-func UpdateUserNumber(db *gorm.DB, userID uint64, numberID uint64, nickname string, isActive *bool) error {
+func UpdateUserNumber(db *gorm.DB, userID uint64, numberID uint64, nickname string, note *string, isActive *bool) error {
 	// 查找用户号码
 	var userNumber model.UserNumber
 	if err := db.Where("id = ? AND user_id = ?", numberID, userID).First(&userNumber).Error; err != nil {
@@ -162,6 +200,10 @@ func UpdateUserNumber(db *gorm.DB, userID uint64, numberID uint64, nickname stri
 		updates["nickname"] = nickname
 	}
 
+	if note != nil {
+		updates["note"] = *note
+	}
+
 	if isActive != nil {
 		updates["is_active"] = *isActive
 	}
@@ -172,20 +214,80 @@ func UpdateUserNumber(db *gorm.DB, userID uint64, numberID uint64, nickname stri
 	}
 
 	// 执行更新
-	return db.Model(&userNumber).Updates(updates).Error
+	if err := db.Model(&userNumber).Updates(updates).Error; err != nil {
+		return err
+	}
+	dbLogger(db).WithField("number_id", numberID).Infof("更新用户号码: %v", updates)
+	return nil
+}
+
+// SetNumberBetOptions 设置用户号码的倍数和追加，参数为nil时不修改
+func SetNumberBetOptions(db *gorm.DB, userID uint64, numberID uint64, multiplier *int, isAdditional *bool) error {
+	var userNumber model.UserNumber
+	if err := db.Preload("Game").Where("id = ? AND user_id = ?", numberID, userID).First(&userNumber).Error; err != nil {
+		return fmt.Errorf("号码不存在或不属于该用户")
+	}
+
+	if userNumber.Multiplier < 1 {
+		userNumber.Multiplier = 1
+	}
+	if multiplier != nil {
+		userNumber.Multiplier = *multiplier
+	}
+	if isAdditional != nil {
+		userNumber.IsAdditional = *isAdditional
+	}
+	if err := ValidateBetOptions(&userNumber.Game, userNumber.Multiplier, userNumber.IsAdditional); err != nil {
+		return err
+	}
+
+	return db.Model(&model.UserNumber{}).Where("id = ?", numberID).Updates(map[string]interface{}{
+		"multiplier":    userNumber.Multiplier,
+		"is_additional": userNumber.IsAdditional,
+	}).Error
+}
+
+// UserNumberFilter 用户号码列表过滤条件
+type UserNumberFilter struct {
+	GameCode string // 游戏代码
+	GroupID  *int64 // 分组ID，0表示未分组
+	TagID    *int64 // 标签ID
+	Source   string // 来源
+	IsActive *bool  // 是否启用
 }
 
 // GetUserNumbers 获取用户号码列表
-func GetUserNumbers(db *gorm.DB, userID uint64, gameCode string, page, pageSize int) ([]model.UserNumber, int64, error) {
+func GetUserNumbers(db *gorm.DB, userID uint64, filter UserNumberFilter, page, pageSize int) ([]model.UserNumber, int64, error) {
 	var numbers []model.UserNumber
 	var total int64
 
-	query := db.Model(&model.UserNumber{}).Where("user_id = ?", userID)
+	query := db.Model(&model.UserNumber{}).Where("user_numbers.user_id = ?", userID)
 
-	if gameCode != "" {
+	if filter.GameCode != "" {
 		// 通过gameCode条件查询
 		query = query.Joins("JOIN lottery_games ON user_numbers.game_id = lottery_games.id").
-			Where("lottery_games.game_code = ?", gameCode)
+			Where("lottery_games.game_code = ?", filter.GameCode)
+	}
+
+	if filter.GroupID != nil {
+		if *filter.GroupID == 0 {
+			query = query.Where("user_numbers.group_id IS NULL")
+		} else {
+			query = query.Where("user_numbers.group_id = ?", *filter.GroupID)
+		}
+	}
+
+	if filter.TagID != nil {
+		query = query.Joins("JOIN user_number_tags ON user_number_tags.user_number_id = user_numbers.id").
+			Where("user_number_tags.tag_id = ?", *filter.TagID)
+	}
+
+	if filter.Source != "" {
+		query = query.Where("user_numbers.source = ?", filter.Source)
+	}
+
+	if filter.IsActive != nil {
+		query = query.Where("user_numbers.is_active = ?", *filter.IsActive)
 	}
 
 	// 获取总数
@@ -196,20 +298,34 @@ func GetUserNumbers(db *gorm.DB, userID uint64, gameCode string, page, pageSize
 
 	// 分页查询
 	offset := (page - 1) * pageSize
-	err = query.Preload("Game").Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&numbers).Error
+	err = query.Preload("Game").Preload("Tags").Order("user_numbers.created_at DESC").Offset(offset).Limit(pageSize).Find(&numbers).Error
 
 	return numbers, total, err
 }
 
 // DeleteUserNumber 删除用户号码
 func DeleteUserNumber(db *gorm.DB, userID uint64, numberID uint64) error {
-	result := db.Where("id = ? AND user_id = ?", numberID, userID).Delete(&model.UserNumber{})
-	if result.Error != nil {
-		return result.Error
+	var count int64
+	if err := db.Model(&model.UserNumber{}).Where("id = ? AND user_id = ?", numberID, userID).Count(&count).Error; err != nil {
+		return err
 	}
-	if result.RowsAffected == 0 {
+	if count == 0 {
 		return fmt.Errorf("号码不存在或不属于该用户")
 	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := model.NewNumberPlanDAO(tx).DeleteByUserNumberID(int64(numberID)); err != nil {
+			return err
+		}
+		if err := tx.Where("user_number_id = ?", numberID).Delete(&model.UserNumberTag{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.UserNumber{}, numberID).Error
+	})
+	if err != nil {
+		return err
+	}
+	dbLogger(db).WithField("number_id", numberID).Info("删除用户号码")
 	return nil
 }
 
@@ -227,22 +343,19 @@ func CheckWinningNumbers(db *gorm.DB, userNumberID uint, drawResultID uint) (*mo
 		return nil, fmt.Errorf("开奖结果不存在")
 	}
 
-	// 计算中奖情况
-	redMatches := countMatches(userNumber.RedBalls, drawResult.RedBalls)
-	blueMatches := countMatches(userNumber.BlueBalls, drawResult.BlueBalls)
-
-	// 判断奖级（取消关联后，按 GameID 查询游戏信息获取名称）
+	// 计算中奖情况（取消关联后，按 GameID 查询游戏信息）
 	var gameInfo model.LotteryGame
 	if err := db.First(&gameInfo, userNumber.GameID).Error; err != nil {
 		return nil, fmt.Errorf("游戏不存在")
 	}
-	prizeLevel := determinePrizeLevel(gameInfo.GameName, redMatches, blueMatches)
+	_, _, prizeLevel, prizeAmount := EvaluateNumber(&gameInfo, &userNumber, &drawResult)
 
 	// 创建中奖记录
 	userDraw := &model.UserDraw{
 		UserNumberID: userNumberID,
 		DrawResultID: drawResultID,
 		PrizeLevel:   prizeLevel,
+		PrizeAmount:  prizeAmount,
 		IsWinning:    prizeLevel > 0,
 		IsActive:     true,
 	}
@@ -250,7 +363,14 @@ func CheckWinningNumbers(db *gorm.DB, userNumberID uint, drawResultID uint) (*mo
 	if err := db.Create(userDraw).Error; err != nil {
 		return nil, err
 	}
+	observeSavedEvaluations(gameInfo.GameCode, prizeLevel)
 
+	dbLogger(db).WithFields(logrus.Fields{
+		applog.FieldGameCode: gameInfo.GameCode,
+		"number_id":          userNumberID,
+		"period":             drawResult.Period,
+		"prize_level":        prizeLevel,
+	}).Info("核对中奖号码")
 	return userDraw, nil
 }
 
@@ -268,16 +388,21 @@ func countMatches(userBalls, drawBalls model.NumberArray) int {
 	return matches
 }
 
+// prizeLevelRules 各游戏按红蓝球匹配数判断奖级的规则，按游戏代码区分
+var prizeLevelRules = map[string]func(redMatches, blueMatches int) int{
+	"ssq": determineSSQPrizeLevel,
+	"dlt": determineDLTPrizeLevel,
+	"qlc": determineQLCPrizeLevel,
+	"qxc": determineQXCPrizeLevel,
+}
+
 // determinePrizeLevel 判断奖级
-func determinePrizeLevel(gameName string, redMatches, blueMatches int) int {
-	switch gameName {
-	case "双色球":
-		return determineSSQPrizeLevel(redMatches, blueMatches)
-	case "大乐透":
-		return determineDLTPrizeLevel(redMatches, blueMatches)
-	default:
+func determinePrizeLevel(gameCode string, redMatches, blueMatches int) int {
+	rule, ok := prizeLevelRules[gameCode]
+	if !ok {
 		return 0
 	}
+	return rule(redMatches, blueMatches)
 }
 
 // determineSSQPrizeLevel 判断双色球奖级
@@ -320,6 +445,44 @@ func determineDLTPrizeLevel(redMatches, blueMatches int) int {
 	return 0 // 未中奖
 }
 
+// determineQLCPrizeLevel 判断七乐彩奖级，specialMatches 为所选号码命中特别号的个数
+func determineQLCPrizeLevel(redMatches, specialMatches int) int {
+	if redMatches == 7 {
+		return 1 // 一等奖
+	} else if redMatches == 6 && specialMatches == 1 {
+		return 2 // 二等奖
+	} else if redMatches == 6 {
+		return 3 // 三等奖
+	} else if redMatches == 5 && specialMatches == 1 {
+		return 4 // 四等奖
+	} else if redMatches == 5 {
+		return 5 // 五等奖
+	} else if redMatches == 4 && specialMatches == 1 {
+		return 6 // 六等奖
+	} else if redMatches == 4 {
+		return 7 // 七等奖
+	}
+	return 0 // 未中奖
+}
+
+// determineQXCPrizeLevel 判断七星彩奖级，前区为前6位按位命中数，后区为第7位是否命中
+func determineQXCPrizeLevel(frontMatches, backMatches int) int {
+	if frontMatches == 6 && backMatches == 1 {
+		return 1 // 一等奖
+	} else if frontMatches == 6 {
+		return 2 // 二等奖
+	} else if frontMatches == 5 && backMatches == 1 {
+		return 3 // 三等奖
+	} else if frontMatches == 5 || (frontMatches == 4 && backMatches == 1) {
+		return 4 // 四等奖
+	} else if frontMatches == 4 || (frontMatches == 3 && backMatches == 1) {
+		return 5 // 五等奖
+	} else if frontMatches == 3 || backMatches == 1 {
+		return 6 // 六等奖
+	}
+	return 0 // 未中奖
+}
+
 // GetUserDraws 获取用户中奖记录
 func GetUserDraws(db *gorm.DB, userID uint64, page, pageSize int) ([]model.UserDraw, int64, error) {
 	var draws []model.UserDraw
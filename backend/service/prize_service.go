@@ -0,0 +1,165 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"lucky/common/metrics"
+	"lucky/model"
+
+	"gorm.io/gorm"
+)
+
+// additionalPrizePercent 大乐透追加投注一、二等奖的追加比例(%)
+const additionalPrizePercent = 80
+
+// fixedPrizeAmounts 各游戏固定奖级的单注奖金(分)，按游戏代码区分，一二等奖为浮动奖金
+// 七乐彩三等奖同为浮动奖金，开奖结果未记录其金额，按0计
+var fixedPrizeAmounts = map[string]map[int]int64{
+	"ssq": { // 双色球
+		3: 300000, // 三等奖 3000元
+		4: 20000,  // 四等奖 200元
+		5: 1000,   // 五等奖 10元
+		6: 500,    // 六等奖 5元
+	},
+	"dlt": { // 大乐透
+		3: 1000000, // 三等奖 1万元
+		4: 300000,  // 四等奖 3000元
+		5: 30000,   // 五等奖 300元
+		6: 10000,   // 六等奖 100元
+		7: 1500,    // 七等奖 15元
+		8: 500,     // 八等奖 5元
+	},
+	"qlc": { // 七乐彩
+		4: 20000, // 四等奖 200元
+		5: 5000,  // 五等奖 50元
+		6: 1000,  // 六等奖 10元
+		7: 500,   // 七等奖 5元
+	},
+	"qxc": { // 七星彩
+		3: 300000, // 三等奖 3000元
+		4: 50000,  // 四等奖 500元
+		5: 3000,   // 五等奖 30元
+		6: 500,    // 六等奖 5元
+	},
+}
+
+// prizeLevelNames 奖级名称
+var prizeLevelNames = []string{"", "一等奖", "二等奖", "三等奖", "四等奖", "五等奖", "六等奖", "七等奖", "八等奖"}
+
+// determinePrizeAmount 计算单注奖金(分)，浮动奖级取开奖结果中公布的单注奖金
+func determinePrizeAmount(gameCode string, prizeLevel int, drawResult *model.DrawResult) int64 {
+	switch prizeLevel {
+	case 0:
+		return 0
+	case 1:
+		return drawResult.FirstAmount
+	case 2:
+		return drawResult.SecondAmount
+	default:
+		return fixedPrizeAmounts[gameCode][prizeLevel]
+	}
+}
+
+// EvaluateNumber 核对单注号码在某期的中奖情况，返回红蓝球匹配数、奖级和单注奖金(分)
+// 数字型游戏的红球匹配数为按位命中数，快乐8按玩法奖级表对奖
+// 只计算不保存，核对结果保存后由调用方记录中奖核对指标
+func EvaluateNumber(game *model.LotteryGame, userNumber *model.UserNumber, drawResult *model.DrawResult) (int, int, int, int64) {
+	if IsDigitGame(game) && game.BlueSelectCount == 0 {
+		positionMatches, prizeLevel, prizeAmount := EvaluateDigitNumber(game, userNumber, drawResult)
+		return positionMatches, 0, prizeLevel, prizeAmount
+	}
+	if IsKenoGame(game) {
+		hits, prizeLevel, prizeAmount := EvaluateKenoNumber(userNumber, drawResult)
+		return hits, 0, prizeLevel, prizeAmount
+	}
+	redMatches, blueMatches := countNumberMatches(game, userNumber, drawResult)
+	prizeLevel := determinePrizeLevel(game.GameCode, redMatches, blueMatches)
+	return redMatches, blueMatches, prizeLevel, determinePrizeAmount(game.GameCode, prizeLevel, drawResult)
+}
+
+// countNumberMatches 计算红蓝球匹配数
+// 七星彩前后区均按位对奖；七乐彩的蓝球匹配数为所选号码命中特别号的个数
+func countNumberMatches(game *model.LotteryGame, userNumber *model.UserNumber, drawResult *model.DrawResult) (int, int) {
+	switch {
+	case IsDigitGame(game):
+		return countPositionMatches(userNumber.RedBalls, drawResult.RedBalls), countPositionMatches(userNumber.BlueBalls, drawResult.BlueBalls)
+	case game.SpecialBallCount > 0:
+		return countMatches(userNumber.RedBalls, drawResult.RedBalls), countMatches(userNumber.RedBalls, drawResult.BlueBalls)
+	default:
+		return countMatches(userNumber.RedBalls, drawResult.RedBalls), countMatches(userNumber.BlueBalls, drawResult.BlueBalls)
+	}
+}
+
+// WinLevelName 中奖等级名称：数字型为玩法名称，快乐8如"选十中9"，其余为"一等奖"等
+func WinLevelName(game *model.LotteryGame, playType string, prizeLevel, redMatches int) string {
+	switch {
+	case prizeLevel <= 0 || prizeLevel >= len(prizeLevelNames):
+		return ""
+	case IsDigitGame(game) && game.BlueSelectCount == 0:
+		if playType == "" {
+			playType = model.PlayTypeDirect
+		}
+		return PlayTypeNames[playType]
+	case IsKenoGame(game):
+		return KenoWinLevelName(playType, redMatches)
+	default:
+		return prizeLevelNames[prizeLevel]
+	}
+}
+
+// observeSavedEvaluations 按保存的核对结果记录中奖核对指标，只读的中奖查询和汇总不计入
+func observeSavedEvaluations(gameCode string, prizeLevels ...int) {
+	for _, prizeLevel := range prizeLevels {
+		metrics.ObserveWinningEvaluation(gameCode, prizeLevel > 0)
+	}
+}
+
+// saveUserDraw 保存号码在某期的核对结果，已存在时更新奖级和奖金
+func saveUserDraw(db *gorm.DB, userNumberID int64, drawResultID uint64, prizeLevel int, prizeAmount int64) error {
+	var userDraw model.UserDraw
+	err := db.Where("user_number_id = ? AND draw_result_id = ?", userNumberID, drawResultID).First(&userDraw).Error
+	if err == nil {
+		return db.Model(&userDraw).Updates(map[string]interface{}{
+			"prize_level":  prizeLevel,
+			"prize_amount": prizeAmount,
+			"is_winning":   prizeLevel > 0,
+		}).Error
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	userDraw = model.UserDraw{
+		UserNumberID: uint(userNumberID),
+		DrawResultID: uint(drawResultID),
+		PrizeLevel:   prizeLevel,
+		PrizeAmount:  prizeAmount,
+		IsWinning:    prizeLevel > 0,
+		IsActive:     true,
+	}
+	return db.Create(&userDraw).Error
+}
+
+// ValidateBetOptions 校验倍数和追加设置
+func ValidateBetOptions(game *model.LotteryGame, multiplier int, isAdditional bool) error {
+	if multiplier < 1 || multiplier > maxMultiplier {
+		return fmt.Errorf("倍数需在1-%d之间", maxMultiplier)
+	}
+	if isAdditional && game.GameCode != "dlt" {
+		return errors.New("仅大乐透支持追加投注")
+	}
+	return nil
+}
+
+// ApplyBetOptions 按追加和倍数计算实际奖金(分)
+// 大乐透追加投注的一、二等奖额外获得基本奖金的80%，其余奖级不变
+func ApplyBetOptions(gameCode string, prizeLevel int, amount int64, multiplier int, isAdditional bool) int64 {
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	if isAdditional && gameCode == "dlt" && (prizeLevel == 1 || prizeLevel == 2) {
+		amount += amount * additionalPrizePercent / 100
+	}
+	return amount * int64(multiplier)
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"lucky/common/database/dbtest"
+	"lucky/common/metrics"
+	"lucky/model"
+)
+
+var testQLCGame = &model.LotteryGame{
+	GameCode:         "qlc",
+	GameName:         "七乐彩",
+	GameType:         model.GameTypeBall,
+	RedBallCount:     30,
+	RedSelectCount:   7,
+	SpecialBallCount: 1,
+}
+
+var testQXCGame = &model.LotteryGame{
+	GameCode:        "qxc",
+	GameName:        "七星彩",
+	GameType:        model.GameTypeDigit,
+	RedBallCount:    10,
+	BlueBallCount:   15,
+	RedSelectCount:  6,
+	BlueSelectCount: 1,
+}
+
+func TestApplyBetOptions(t *testing.T) {
+	cases := []struct {
+		name         string
+		gameCode     string
+		prizeLevel   int
+		amount       int64
+		multiplier   int
+		isAdditional bool
+		want         int64
+	}{
+		{"单注单倍", "dlt", 3, 1000000, 1, false, 1000000},
+		{"倍投", "ssq", 6, 500, 5, false, 2500},
+		{"追加一等奖", "dlt", 1, 1000000000, 1, true, 1800000000},
+		{"追加二等奖倍投", "dlt", 2, 10000000, 2, true, 36000000},
+		{"追加不影响固定奖级", "dlt", 5, 30000, 3, true, 90000},
+		{"非大乐透忽略追加", "ssq", 1, 500000000, 1, true, 500000000},
+		{"倍数为0按1倍", "ssq", 5, 1000, 0, false, 1000},
+		{"未中奖", "dlt", 0, 0, 10, true, 0},
+	}
+	for _, tc := range cases {
+		if got := ApplyBetOptions(tc.gameCode, tc.prizeLevel, tc.amount, tc.multiplier, tc.isAdditional); got != tc.want {
+			t.Errorf("%s: ApplyBetOptions = %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestValidateBetOptions(t *testing.T) {
+	if err := ValidateBetOptions(testDLTGame, 99, true); err != nil {
+		t.Errorf("大乐透99倍追加应合法: %v", err)
+	}
+	if err := ValidateBetOptions(testSSQGame, 1, true); err == nil {
+		t.Error("双色球追加应返回错误")
+	}
+	if err := ValidateBetOptions(testSSQGame, 100, false); err == nil {
+		t.Error("超过99倍应返回错误")
+	}
+	if err := ValidateBetOptions(testSSQGame, 0, false); err == nil {
+		t.Error("0倍应返回错误")
+	}
+}
+
+func TestDeterminePrizeAmount(t *testing.T) {
+	drawResult := &model.DrawResult{FirstAmount: 600000000, SecondAmount: 15000000}
+	if got := determinePrizeAmount("ssq", 1, drawResult); got != 600000000 {
+		t.Errorf("一等奖取开奖公布金额, got %d", got)
+	}
+	if got := determinePrizeAmount("dlt", 2, drawResult); got != 15000000 {
+		t.Errorf("二等奖取开奖公布金额, got %d", got)
+	}
+	if got := determinePrizeAmount("dlt", 7, drawResult); got != 1500 {
+		t.Errorf("大乐透七等奖 = %d, want 1500", got)
+	}
+	if got := determinePrizeAmount("ssq", 0, drawResult); got != 0 {
+		t.Errorf("未中奖 = %d, want 0", got)
+	}
+}
+
+func TestEvaluateQLCNumber(t *testing.T) {
+	draw := &model.DrawResult{
+		RedBalls:     model.NumberArray{3, 8, 12, 17, 21, 25, 29},
+		BlueBalls:    model.NumberArray{14},
+		FirstAmount:  100000000,
+		SecondAmount: 2000000,
+	}
+	cases := []struct {
+		name        string
+		balls       model.NumberArray
+		wantSpecial int
+		wantLevel   int
+		wantAmount  int64
+	}{
+		{"全中", model.NumberArray{3, 8, 12, 17, 21, 25, 29}, 0, 1, 100000000},
+		{"中6加特别号", model.NumberArray{3, 8, 12, 17, 21, 25, 14}, 1, 2, 2000000},
+		{"中5加特别号", model.NumberArray{3, 8, 12, 17, 21, 1, 14}, 1, 4, 20000},
+		{"中4", model.NumberArray{3, 8, 12, 17, 1, 2, 4}, 0, 7, 500},
+		{"中3加特别号未中奖", model.NumberArray{3, 8, 12, 1, 2, 4, 14}, 1, 0, 0},
+	}
+	for _, tc := range cases {
+		_, special, level, amount := EvaluateNumber(testQLCGame, &model.UserNumber{RedBalls: tc.balls}, draw)
+		if special != tc.wantSpecial || level != tc.wantLevel || amount != tc.wantAmount {
+			t.Errorf("%s: special=%d level=%d amount=%d, want %d %d %d", tc.name, special, level, amount, tc.wantSpecial, tc.wantLevel, tc.wantAmount)
+		}
+	}
+
+	if err := ValidateNumbers(testQLCGame, model.NumberArray{1, 2, 3, 4, 5, 6, 30}, nil); err != nil {
+		t.Errorf("七乐彩7个号码应合法: %v", err)
+	}
+	if err := ValidateNumbers(testQLCGame, model.NumberArray{1, 2, 3, 4, 5, 6, 7}, model.NumberArray{8}); err == nil {
+		t.Error("七乐彩特别号不可选择")
+	}
+}
+
+func TestEvaluateQXCNumber(t *testing.T) {
+	draw := &model.DrawResult{RedBalls: model.NumberArray{1, 0, 9, 3, 3, 7}, BlueBalls: model.NumberArray{12}}
+	cases := []struct {
+		name       string
+		front      model.NumberArray
+		back       int
+		wantLevel  int
+		wantAmount int64
+	}{
+		{"前区5位加后区", model.NumberArray{1, 0, 9, 3, 3, 8}, 12, 3, 300000},
+		{"前区4位加后区", model.NumberArray{1, 0, 9, 3, 0, 0}, 12, 4, 50000},
+		{"按位对奖", model.NumberArray{0, 1, 9, 3, 3, 7}, 0, 5, 3000},
+		{"仅中后区", model.NumberArray{0, 0, 0, 0, 0, 0}, 12, 6, 500},
+		{"前区2位未中奖", model.NumberArray{1, 0, 0, 0, 0, 0}, 0, 0, 0},
+	}
+	for _, tc := range cases {
+		userNumber := &model.UserNumber{RedBalls: tc.front, BlueBalls: model.NumberArray{tc.back}, PlayType: model.PlayTypeDirect}
+		_, _, level, amount := EvaluateNumber(testQXCGame, userNumber, draw)
+		if level != tc.wantLevel || amount != tc.wantAmount {
+			t.Errorf("%s: level=%d amount=%d, want %d %d", tc.name, level, amount, tc.wantLevel, tc.wantAmount)
+		}
+	}
+	if name := WinLevelName(testQXCGame, model.PlayTypeDirect, 3, 5); name != "三等奖" {
+		t.Errorf("WinLevelName = %q, want 三等奖", name)
+	}
+
+	if err := ValidateNumbers(testQXCGame, model.NumberArray{0, 0, 9, 9, 1, 1}, model.NumberArray{14}); err != nil {
+		t.Errorf("七星彩号码应合法: %v", err)
+	}
+	if err := ValidateNumbers(testQXCGame, model.NumberArray{0, 0, 9, 9, 1, 1}, model.NumberArray{15}); err == nil {
+		t.Error("后区超过14应返回错误")
+	}
+	if _, err := ValidatePlayType(testQXCGame, model.PlayTypeGroup6, model.NumberArray{0, 1, 2, 3, 4, 5}); err == nil {
+		t.Error("七星彩仅支持直选")
+	}
+	red, blue, err := ParseNumberLine(testQXCGame, "1 0 9 3 3 7 + 12")
+	if err != nil || len(red) != 6 || len(blue) != 1 || blue[0] != 12 {
+		t.Errorf("七星彩号码解析错误: %v %v %v", red, blue, err)
+	}
+}
+
+func TestCountMatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		userBalls []int
+		drawBalls []int
+		expected  int
+	}{
+		{
+			name:      "No matches",
+			userBalls: []int{1, 2, 3, 4, 5},
+			drawBalls: []int{6, 7, 8, 9, 10},
+			expected:  0,
+		},
+		{
+			name:      "Partial matches",
+			userBalls: []int{1, 2, 3, 4, 5},
+			drawBalls: []int{1, 2, 8, 9, 10},
+			expected:  2,
+		},
+		{
+			name:      "All matches",
+			userBalls: []int{1, 2, 3, 4, 5},
+			drawBalls: []int{1, 2, 3, 4, 5},
+			expected:  5,
+		},
+		{
+			name:      "Duplicate in user balls",
+			userBalls: []int{1, 1, 2, 3, 4},
+			drawBalls: []int{1, 5, 6, 7, 8},
+			expected:  2, // 两个1都匹配同一个开奖号码1
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := countMatches(tt.userBalls, tt.drawBalls); result != tt.expected {
+				t.Errorf("countMatches() = %d, want %d", result, tt.expected)
+			}
+		})
+	}
+}
+
+// winningEvaluationCount 读取某游戏的中奖核对指标，按中奖与否汇总
+func winningEvaluationCount(t *testing.T, gameCode string) float64 {
+	t.Helper()
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatalf("读取监控指标失败: %v", err)
+	}
+	var count float64
+	for _, family := range families {
+		if family.GetName() != "lucky_winning_evaluations_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "game" && label.GetValue() == gameCode {
+					count += metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return count
+}
+
+// TestWinningEvaluationMetric 只在保存核对结果时记录中奖核对指标，只读的核对不计入
+func TestWinningEvaluationMetric(t *testing.T) {
+	db := dbtest.Open(t)
+	ssq, _ := createTestGames(t, db)
+	number := createTestNumber(t, db, 1, ssq, model.NumberArray{1, 5, 12, 18, 25, 33}, model.NumberArray{8})
+	draw := &model.DrawResult{GameID: ssq.ID, Period: "2025100", DrawDate: time.Date(2025, 9, 2, 0, 0, 0, 0, time.Local),
+		RedBalls: model.NumberArray{1, 5, 12, 18, 25, 33}, BlueBalls: model.NumberArray{8}}
+	if err := db.Create(draw).Error; err != nil {
+		t.Fatalf("创建开奖结果失败: %v", err)
+	}
+
+	before := winningEvaluationCount(t, ssq.GameCode)
+	EvaluateNumber(ssq, number, draw)
+	if got := winningEvaluationCount(t, ssq.GameCode); got != before {
+		t.Errorf("只读核对不应计入指标: %v -> %v", before, got)
+	}
+
+	if _, err := CheckWinningNumbers(db, uint(number.ID), uint(draw.ID)); err != nil {
+		t.Fatalf("CheckWinningNumbers: %v", err)
+	}
+	if got := winningEvaluationCount(t, ssq.GameCode); got != before+1 {
+		t.Errorf("保存核对结果应计入指标: %v -> %v", before, got)
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"lucky/common/cache"
+	"lucky/model"
+
+	"gorm.io/gorm"
+)
+
+// drawCacheTTL 开奖数据相关缓存的过期时间，新开奖结果保存或更正时按游戏立即失效
+const drawCacheTTL = time.Hour
+
+// DrawCacheTag 游戏开奖数据缓存的标签，开奖结果列表、号码分布、遗漏数据和走势缓存均带有该标签
+func DrawCacheTag(gameCode string) string {
+	return "draws:" + gameCode
+}
+
+// InvalidateDrawCaches 清除游戏的开奖数据缓存
+func InvalidateDrawCaches(gameCode string) error {
+	return cache.InvalidateTags(DrawCacheTag(gameCode))
+}
+
+func init() {
+	OnDrawResultChanged(func(db *gorm.DB, game *model.LotteryGame, drawResult *model.DrawResult) {
+		if err := InvalidateDrawCaches(game.GameCode); err != nil {
+			dbLogger(db).Warnf("清除%s开奖数据缓存失败: %v", game.GameName, err)
+		}
+	})
+}
+
+// DrawResultPage 开奖结果分页数据
+type DrawResultPage struct {
+	List  []model.DrawResult `json:"list"`
+	Total int64              `json:"total"`
+}
+
+// CachedDrawResults 带缓存的开奖结果列表
+func CachedDrawResults(db *gorm.DB, gameCode string, page, pageSize int) (*DrawResultPage, error) {
+	key := fmt.Sprintf("draw_results:%s:%d:%d", gameCode, page, pageSize)
+	return cache.GetOrLoad(dbContext(db), key, drawCacheTTL, []string{DrawCacheTag(gameCode)}, func() (*DrawResultPage, error) {
+		results, total, err := GetDrawResults(db, gameCode, page, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		return &DrawResultPage{List: results, Total: total}, nil
+	})
+}
+
+// CachedNumberDistribution 带缓存的号码分布
+func CachedNumberDistribution(db *gorm.DB, gameCode string, periodCount int) (map[string][]NumberFrequency, error) {
+	key := fmt.Sprintf("distribution:%s:%d", gameCode, periodCount)
+	return cache.GetOrLoad(dbContext(db), key, drawCacheTTL, []string{DrawCacheTag(gameCode)}, func() (map[string][]NumberFrequency, error) {
+		return GetNumberDistribution(db, gameCode, periodCount)
+	})
+}
+
+// CachedNumberMissing 带缓存的号码遗漏统计
+func CachedNumberMissing(db *gorm.DB, gameCode string, periodCount int) (*NumberMissingStats, error) {
+	key := fmt.Sprintf("number_missing:%s:%d", gameCode, periodCount)
+	return cache.GetOrLoad(dbContext(db), key, drawCacheTTL, []string{DrawCacheTag(gameCode)}, func() (*NumberMissingStats, error) {
+		return GetNumberMissing(db, gameCode, periodCount)
+	})
+}
+
+// CachedDigitTrend 带缓存的数字型游戏按位走势
+func CachedDigitTrend(db *gorm.DB, gameCode string, periodCount int) (*DigitTrend, error) {
+	key := fmt.Sprintf("digit_trend:%s:%d", gameCode, periodCount)
+	return cache.GetOrLoad(dbContext(db), key, drawCacheTTL, []string{DrawCacheTag(gameCode)}, func() (*DigitTrend, error) {
+		return GetDigitTrend(db, gameCode, periodCount)
+	})
+}
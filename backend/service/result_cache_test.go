@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+
+	"lucky/common/cache"
+	"lucky/common/database/dbtest"
+	"lucky/model"
+)
+
+func TestDrawCachesInvalidatedOnNewDraw(t *testing.T) {
+	previous := cache.Default
+	cache.Default = cache.NewMemory(100)
+	defer func() { cache.Default = previous }()
+
+	db := dbtest.Open(t)
+	game := *testSSQGame
+	game.GameName = "双色球"
+	if err := db.Create(&game).Error; err != nil {
+		t.Fatalf("创建游戏失败: %v", err)
+	}
+	save := func(period string, red model.NumberArray) {
+		t.Helper()
+		if _, err := saveDrawResult(db, &DrawResult{GameCode: "ssq", Period: period, DrawDate: "2025-09-02", RedBalls: red, BlueBalls: []int{8}}); err != nil {
+			t.Fatalf("保存开奖结果失败: %v", err)
+		}
+	}
+
+	save("2025100", model.NumberArray{1, 2, 3, 4, 5, 6})
+	page, err := CachedDrawResults(db, "ssq", 1, 20)
+	if err != nil || page.Total != 1 {
+		t.Fatalf("CachedDrawResults = %+v, %v", page, err)
+	}
+	distribution, err := CachedNumberDistribution(db, "ssq", 30)
+	if err != nil {
+		t.Fatalf("CachedNumberDistribution: %v", err)
+	}
+	if frequency(distribution["red"], 1) != 1 {
+		t.Fatalf("红球1应出现1次: %v", distribution["red"])
+	}
+
+	// 绕过保存流程直接写库时缓存不变
+	db.Create(&model.DrawResult{GameID: game.ID, Period: "2025101", RedBalls: model.NumberArray{1, 7, 8, 9, 10, 11}, BlueBalls: model.NumberArray{8}})
+	if page, _ := CachedDrawResults(db, "ssq", 1, 20); page.Total != 1 {
+		t.Errorf("未触发失效时应返回缓存, total=%d", page.Total)
+	}
+
+	// 保存新一期后立即失效
+	save("2025102", model.NumberArray{1, 12, 13, 14, 15, 16})
+	if page, _ := CachedDrawResults(db, "ssq", 1, 20); page.Total != 3 {
+		t.Errorf("保存新一期后开奖结果缓存应失效, total=%d", page.Total)
+	}
+	distribution, _ = CachedNumberDistribution(db, "ssq", 30)
+	if frequency(distribution["red"], 1) != 3 {
+		t.Errorf("保存新一期后号码分布缓存应失效: %v", distribution["red"])
+	}
+}
+
+// frequency 号码在分布中的出现次数
+func frequency(list []NumberFrequency, number int) int {
+	for _, item := range list {
+		if item.Number == number {
+			return item.Frequency
+		}
+	}
+	return 0
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"sort"
 
 	"lucky/model"
@@ -135,19 +136,23 @@ func GetNumberDistribution(db *gorm.DB, gameCode string, periodCount int) (map[s
 		return blueDistribution[i].Number < blueDistribution[j].Number
 	})
 
-	// 5. 补全缺失的号码（频率为0）
-	var redMax, blueMax int
-	if gameCode == "ssq" {
-		redMax = 33
-		blueMax = 16
-	} else if gameCode == "dlt" {
-		redMax = 35
-		blueMax = 12
+	// 5. 补全缺失的号码（频率为0），号码范围取自游戏配置
+	// 数字型游戏统计0-9（七星彩后区0-14），七乐彩特别号从红球号码池中开出
+	game, err := GetGameByCode(db, gameCode)
+	if err != nil {
+		return nil, err
+	}
+	redMin, redMax, blueMin, blueMax := 1, game.RedBallCount, 1, game.BlueBallCount
+	if IsDigitGame(game) {
+		redMin, redMax = 0, game.RedBallCount-1
+		blueMin, blueMax = 0, game.BlueBallCount-1
+	} else if game.SpecialBallCount > 0 {
+		blueMax = game.RedBallCount
 	}
 
 	// 补全红球
 	completeDistribution := []NumberFrequency{}
-	for i := 1; i <= redMax; i++ {
+	for i := redMin; i <= redMax; i++ {
 		found := false
 		for _, item := range redDistribution {
 			if item.Number == i {
@@ -167,7 +172,7 @@ func GetNumberDistribution(db *gorm.DB, gameCode string, periodCount int) (map[s
 
 	// 补全蓝球
 	completeDistribution = []NumberFrequency{}
-	for i := 1; i <= blueMax; i++ {
+	for i := blueMin; i <= blueMax; i++ {
 		found := false
 		for _, item := range blueDistribution {
 			if item.Number == i {
@@ -190,3 +195,193 @@ func GetNumberDistribution(db *gorm.DB, gameCode string, periodCount int) (map[s
 		"blue": blueDistribution,
 	}, nil
 }
+
+// NumberMissing 单个号码的出现次数与遗漏
+type NumberMissing struct {
+	Number     int `json:"number"`
+	Frequency  int `json:"frequency"`  // 出现次数
+	Missing    int `json:"missing"`    // 当前遗漏期数
+	MaxMissing int `json:"maxMissing"` // 最大遗漏期数
+}
+
+// NumberMissingStats 近N期号码遗漏统计
+type NumberMissingStats struct {
+	GameCode    string          `json:"gameCode"`
+	PeriodCount int             `json:"periodCount"` // 实际统计期数
+	RedBalls    []NumberMissing `json:"redBalls"`
+	BlueBalls   []NumberMissing `json:"blueBalls"`
+}
+
+// GetNumberMissing 根据已保存的开奖结果统计近N期各号码的出现次数和遗漏
+func GetNumberMissing(db *gorm.DB, gameCode string, periodCount int) (*NumberMissingStats, error) {
+	game, err := GetGameByCode(db, gameCode)
+	if err != nil {
+		return nil, err
+	}
+	if IsDigitGame(game) {
+		return nil, errors.New("数字型游戏请使用按位走势")
+	}
+
+	results, err := GetLatestDrawResults(db, gameCode, periodCount)
+	if err != nil {
+		return nil, err
+	}
+
+	// 按期号正序统计
+	redDraws := make([]model.NumberArray, 0, len(results))
+	blueDraws := make([]model.NumberArray, 0, len(results))
+	for i := len(results) - 1; i >= 0; i-- {
+		redDraws = append(redDraws, results[i].RedBalls)
+		blueDraws = append(blueDraws, results[i].BlueBalls)
+	}
+
+	blueMax := game.BlueBallCount
+	if game.SpecialBallCount > 0 {
+		blueMax = game.RedBallCount // 七乐彩特别号从红球号码池中开出
+	}
+
+	return &NumberMissingStats{
+		GameCode:    gameCode,
+		PeriodCount: len(results),
+		RedBalls:    countNumberMissing(redDraws, game.RedBallCount),
+		BlueBalls:   countNumberMissing(blueDraws, blueMax),
+	}, nil
+}
+
+// countNumberMissing 统计1-maxNumber各号码的出现次数和遗漏，draws按期号正序
+func countNumberMissing(draws []model.NumberArray, maxNumber int) []NumberMissing {
+	stats := make([]NumberMissing, maxNumber)
+	for i := range stats {
+		stats[i].Number = i + 1
+	}
+	for _, balls := range draws {
+		drawn := make(map[int]bool, len(balls))
+		for _, ball := range balls {
+			drawn[ball] = true
+		}
+		for i := range stats {
+			stat := &stats[i]
+			if drawn[stat.Number] {
+				stat.Frequency++
+				stat.Missing = 0
+				continue
+			}
+			stat.Missing++
+			if stat.Missing > stat.MaxMissing {
+				stat.MaxMissing = stat.Missing
+			}
+		}
+	}
+	return stats
+}
+
+// DigitDrawTrend 数字型游戏单期开奖形态
+type DigitDrawTrend struct {
+	Period   string            `json:"period"`   // 期号
+	DrawDate string            `json:"drawDate"` // 开奖日期
+	Digits   model.NumberArray `json:"digits"`   // 开奖号码
+	Sum      int               `json:"sum"`      // 和值
+	Span     int               `json:"span"`     // 跨度
+	Form     string            `json:"form"`     // 形态：group3(组三), group6(组六), leopard(豹子)，排列五为空
+}
+
+// DigitPositionTrend 数字型游戏单个位置的走势统计
+type DigitPositionTrend struct {
+	Position   int   `json:"position"`   // 位置，从1开始
+	Frequency  []int `json:"frequency"`  // 0-9各数字出现次数
+	Missing    []int `json:"missing"`    // 0-9各数字当前遗漏期数
+	MaxMissing []int `json:"maxMissing"` // 0-9各数字最大遗漏期数
+}
+
+// DigitTrend 数字型游戏走势
+type DigitTrend struct {
+	GameCode    string               `json:"gameCode"`
+	PeriodCount int                  `json:"periodCount"` // 实际统计期数
+	Draws       []DigitDrawTrend     `json:"draws"`       // 按期号倒序
+	Positions   []DigitPositionTrend `json:"positions"`   // 按位统计
+	FormCounts  map[string]int       `json:"formCounts"`  // 各形态出现次数
+}
+
+// GetDigitTrend 获取数字型游戏近N期的按位走势
+func GetDigitTrend(db *gorm.DB, gameCode string, periodCount int) (*DigitTrend, error) {
+	game, err := GetGameByCode(db, gameCode)
+	if err != nil {
+		return nil, err
+	}
+	if !IsDigitGame(game) {
+		return nil, errors.New("仅福彩3D、排列三、排列五支持按位走势")
+	}
+
+	results, err := GetLatestDrawResults(db, gameCode, periodCount)
+	if err != nil {
+		return nil, err
+	}
+	return buildDigitTrend(game, results), nil
+}
+
+// buildDigitTrend 根据开奖结果（按期号倒序）统计按位走势
+func buildDigitTrend(game *model.LotteryGame, results []model.DrawResult) *DigitTrend {
+	trend := &DigitTrend{
+		GameCode:    game.GameCode,
+		PeriodCount: len(results),
+		Draws:       make([]DigitDrawTrend, 0, len(results)),
+		Positions:   make([]DigitPositionTrend, game.RedSelectCount),
+		FormCounts:  make(map[string]int),
+	}
+	for i := range trend.Positions {
+		trend.Positions[i] = DigitPositionTrend{
+			Position:   i + 1,
+			Frequency:  make([]int, game.RedBallCount),
+			Missing:    make([]int, game.RedBallCount),
+			MaxMissing: make([]int, game.RedBallCount),
+		}
+	}
+
+	for _, result := range results {
+		digits := result.RedBalls
+		draw := DigitDrawTrend{
+			Period:   result.Period,
+			DrawDate: result.DrawDate.Format("2006-01-02"),
+			Digits:   digits,
+			Form:     digitForm(digits),
+		}
+		if len(digits) > 0 {
+			minDigit, maxDigit := digits[0], digits[0]
+			for _, digit := range digits {
+				draw.Sum += digit
+				if digit < minDigit {
+					minDigit = digit
+				}
+				if digit > maxDigit {
+					maxDigit = digit
+				}
+			}
+			draw.Span = maxDigit - minDigit
+		}
+		if draw.Form != "" {
+			trend.FormCounts[draw.Form]++
+		}
+		trend.Draws = append(trend.Draws, draw)
+	}
+
+	// 从最早一期开始累计遗漏
+	for i := len(results) - 1; i >= 0; i-- {
+		digits := results[i].RedBalls
+		for pos := range trend.Positions {
+			position := &trend.Positions[pos]
+			for digit := range position.Missing {
+				if pos < len(digits) && digits[pos] == digit {
+					position.Frequency[digit]++
+					position.Missing[digit] = 0
+					continue
+				}
+				position.Missing[digit]++
+				if position.Missing[digit] > position.MaxMissing[digit] {
+					position.MaxMissing[digit] = position.Missing[digit]
+				}
+			}
+		}
+	}
+
+	return trend
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+	"strings"
+
+	"lucky/model"
+
+	"gorm.io/gorm"
+)
+
+// maxWheelCandidates 旋转矩阵支持的最大候选号码数
+const maxWheelCandidates = 16
+
+// defaultBetPrice 单注价格(分)
+const defaultBetPrice int64 = 200
+
+// WheelResult 旋转矩阵生成结果
+type WheelResult struct {
+	Candidates     []int             `json:"candidates"`     // 候选红球
+	MatchCount     int               `json:"matchCount"`     // 候选号码中开出的个数
+	GuaranteeCount int               `json:"guaranteeCount"` // 保证至少一注命中的个数
+	Source         string            `json:"source"`         // 矩阵来源：table(内置矩阵), greedy(贪心构造)
+	Numbers        []GeneratedNumber `json:"numbers"`        // 生成的号码
+	BetCount       int               `json:"betCount"`       // 注数
+	Cost           int64             `json:"cost"`           // 金额(分)
+}
+
+// GenerateWheel 根据候选红球生成旋转矩阵
+// 保证：候选号码中开出matchCount个时，至少有一注命中guaranteeCount个红球
+func GenerateWheel(game *model.LotteryGame, candidates, blueBalls []int, matchCount, guaranteeCount int) (*WheelResult, error) {
+	k := game.RedSelectCount
+	if err := validateWheel(game, candidates, matchCount, guaranteeCount); err != nil {
+		return nil, err
+	}
+
+	sorted := append([]int(nil), candidates...)
+	sort.Ints(sorted)
+	v := len(sorted)
+
+	source := "table"
+	blocks := lookupWheelTable(v, k, matchCount, guaranteeCount)
+	if blocks == nil {
+		source = "greedy"
+		blocks = greedyCoveringDesign(v, k, matchCount, guaranteeCount)
+	}
+
+	result := &WheelResult{
+		Candidates:     sorted,
+		MatchCount:     matchCount,
+		GuaranteeCount: guaranteeCount,
+		Source:         source,
+	}
+	for _, block := range blocks {
+		red := make(model.NumberArray, 0, k)
+		for i := 0; i < v; i++ {
+			if block&(1<<uint(i)) != 0 {
+				red = append(red, sorted[i])
+			}
+		}
+		blue := model.NumberArray(append([]int(nil), blueBalls...))
+		if err := ValidateNumbers(game, red, blue); err != nil {
+			return nil, err
+		}
+		result.Numbers = append(result.Numbers, GeneratedNumber{RedBalls: red, BlueBalls: blue})
+	}
+	result.BetCount = len(result.Numbers)
+	result.Cost = int64(result.BetCount) * defaultBetPrice
+
+	return result, nil
+}
+
+// WheelGroupResult 旋转矩阵保存为分组的结果
+type WheelGroupResult struct {
+	Group      *model.NumberGroup `json:"group"`      // 新建的分组，没有新保存的号码时为空
+	Saved      int                `json:"saved"`      // 新保存到分组的号码数
+	Duplicated int                `json:"duplicated"` // 已存在而跳过的号码数，原号码保留在原分组
+}
+
+// SaveWheelAsGroup 将旋转矩阵的号码保存为一个号码分组
+// 已存在的相同号码不重复保存，也不移入新分组；分组在保存第一注新号码时创建，全部号码都已存在时不创建分组
+func SaveWheelAsGroup(db *gorm.DB, userID uint64, game *model.LotteryGame, wheel *WheelResult, groupName string) (*WheelGroupResult, error) {
+	if groupName == "" {
+		groupName = fmt.Sprintf("%s旋转矩阵(%d选中%d保%d)", game.GameName, len(wheel.Candidates), wheel.MatchCount, wheel.GuaranteeCount)
+	}
+
+	result := &WheelGroupResult{}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		userNumberDAO := model.NewUserNumberDAO(tx)
+		for _, number := range wheel.Numbers {
+			userNumber, created, err := SaveUserNumber(tx, userID, game.ID, number.RedBalls, number.BlueBalls, "", "", "wheel")
+			if err != nil {
+				return err
+			}
+			if !created {
+				result.Duplicated++
+				continue
+			}
+			if result.Group == nil {
+				group := &model.NumberGroup{UserID: int64(userID), GameID: game.ID, Name: groupName, Source: "wheel"}
+				if err := model.NewNumberGroupDAO(tx).Create(group); err != nil {
+					return err
+				}
+				result.Group = group
+			}
+			if err := userNumberDAO.UpdateGroup(userNumber.ID, &result.Group.ID); err != nil {
+				return err
+			}
+			result.Saved++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// validateWheel 校验旋转矩阵参数
+func validateWheel(game *model.LotteryGame, candidates []int, matchCount, guaranteeCount int) error {
+	if IsDigitGame(game) || IsKenoGame(game) {
+		return fmt.Errorf("%s不支持旋转矩阵", game.GameName)
+	}
+	k := game.RedSelectCount
+	v := len(candidates)
+	if v < k {
+		return fmt.Errorf("候选号码不能少于%d个", k)
+	}
+	if v > maxWheelCandidates {
+		return fmt.Errorf("候选号码不能超过%d个", maxWheelCandidates)
+	}
+	used := make(map[int]bool)
+	for _, ball := range candidates {
+		if ball < 1 || ball > game.RedBallCount {
+			return fmt.Errorf("红球号码超出范围(1-%d)", game.RedBallCount)
+		}
+		if used[ball] {
+			return errors.New("红球号码重复")
+		}
+		used[ball] = true
+	}
+	if guaranteeCount < 1 || guaranteeCount > matchCount {
+		return errors.New("保证命中数必须在1到开出个数之间")
+	}
+	if matchCount > k || matchCount > v {
+		return fmt.Errorf("开出个数不能大于%d", min(k, v))
+	}
+	return nil
+}
+
+// lookupWheelTable 查找内置矩阵，返回以候选号码下标为位的注码掩码
+func lookupWheelTable(v, k, m, t int) []uint32 {
+	design, ok := knownWheels[fmt.Sprintf("%d-%d-%d-%d", v, k, m, t)]
+	if !ok {
+		return nil
+	}
+	blocks := make([]uint32, 0, len(design))
+	for _, ticket := range strings.Fields(design) {
+		var block uint32
+		for _, ch := range ticket {
+			block |= 1 << uint(strings.IndexRune(wheelDigits, ch))
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// wheelCovers 检查注码集合是否满足旋转矩阵保证条件
+func wheelCovers(blocks []uint32, v, m, t int) bool {
+	covered := true
+	forEachMaskCombination(allBits(v), m, func(draw uint32) {
+		if !covered {
+			return
+		}
+		for _, block := range blocks {
+			if bits.OnesCount32(block&draw) >= t {
+				return
+			}
+		}
+		covered = false
+	})
+	return covered
+}
+
+// greedyCoveringDesign 贪心构造覆盖设计：每次选取覆盖最多未覆盖开奖组合的注码
+func greedyCoveringDesign(v, k, m, t int) []uint32 {
+	all := allBits(v)
+
+	uncovered := make(map[uint32]bool)
+	forEachMaskCombination(all, m, func(draw uint32) {
+		uncovered[draw] = true
+	})
+
+	// gain 计算一注号码能新覆盖的开奖组合数
+	gain := func(block uint32) int {
+		count := 0
+		forEachCoveredDraw(block, all, m, t, func(draw uint32) {
+			if uncovered[draw] {
+				count++
+			}
+		})
+		return count
+	}
+
+	candidates := &wheelHeap{}
+	forEachMaskCombination(all, k, func(block uint32) {
+		*candidates = append(*candidates, wheelCandidate{block: block, gain: gain(block)})
+	})
+	heap.Init(candidates)
+
+	// 覆盖数只会减少，因此可以惰性更新堆顶
+	var blocks []uint32
+	for len(uncovered) > 0 && candidates.Len() > 0 {
+		top := heap.Pop(candidates).(wheelCandidate)
+		current := gain(top.block)
+		if current == 0 {
+			continue
+		}
+		if candidates.Len() > 0 && current < (*candidates)[0].gain {
+			top.gain = current
+			heap.Push(candidates, top)
+			continue
+		}
+		blocks = append(blocks, top.block)
+		forEachCoveredDraw(top.block, all, m, t, func(draw uint32) {
+			delete(uncovered, draw)
+		})
+	}
+
+	return blocks
+}
+
+// forEachCoveredDraw 遍历与注码交集不少于t个的全部m个号码组合
+func forEachCoveredDraw(block, all uint32, m, t int, fn func(draw uint32)) {
+	outside := all &^ block
+	for j := t; j <= m && j <= bits.OnesCount32(block); j++ {
+		forEachMaskCombination(block, j, func(inner uint32) {
+			forEachMaskCombination(outside, m-j, func(outer uint32) {
+				fn(inner | outer)
+			})
+		})
+	}
+}
+
+// forEachMaskCombination 遍历mask中选取size个位的全部子集
+func forEachMaskCombination(mask uint32, size int, fn func(sub uint32)) {
+	if size == 0 {
+		fn(0)
+		return
+	}
+	var positions []int
+	for i := 0; i < 32; i++ {
+		if mask&(1<<uint(i)) != 0 {
+			positions = append(positions, i)
+		}
+	}
+	forEachCombination(len(positions), size, func(idx []int) {
+		var sub uint32
+		for _, p := range idx {
+			sub |= 1 << uint(positions[p-1])
+		}
+		fn(sub)
+	})
+}
+
+// allBits 返回低v位全为1的掩码
+func allBits(v int) uint32 {
+	return uint32(1)<<uint(v) - 1
+}
+
+// wheelCandidate 贪心候选注码
+type wheelCandidate struct {
+	block uint32
+	gain  int
+}
+
+// wheelHeap 按覆盖数降序的候选堆
+type wheelHeap []wheelCandidate
+
+func (h wheelHeap) Len() int { return len(h) }
+func (h wheelHeap) Less(i, j int) bool {
+	if h[i].gain != h[j].gain {
+		return h[i].gain > h[j].gain
+	}
+	return h[i].block < h[j].block
+}
+func (h wheelHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *wheelHeap) Push(x interface{}) { *h = append(*h, x.(wheelCandidate)) }
+func (h *wheelHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
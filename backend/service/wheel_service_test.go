@@ -0,0 +1,138 @@
+package service
+
+import (
+	"fmt"
+	"math/bits"
+	"testing"
+
+	"lucky/common/database/dbtest"
+	"lucky/model"
+)
+
+func TestKnownWheelsCover(t *testing.T) {
+	for key := range knownWheels {
+		var v, k, m, g int
+		if _, err := fmt.Sscanf(key, "%d-%d-%d-%d", &v, &k, &m, &g); err != nil {
+			t.Fatalf("矩阵键格式错误: %s", key)
+		}
+		blocks := lookupWheelTable(v, k, m, g)
+		for _, block := range blocks {
+			if bits.OnesCount32(block) != k || block&^allBits(v) != 0 {
+				t.Fatalf("矩阵 %s 含非法注码 %b", key, block)
+			}
+		}
+		if !wheelCovers(blocks, v, m, g) {
+			t.Errorf("矩阵 %s 不满足保证条件", key)
+		}
+	}
+}
+
+func TestGreedyCoveringDesign(t *testing.T) {
+	cases := []struct{ v, k, m, g int }{
+		{10, 6, 5, 4},
+		{13, 6, 5, 4},
+		{12, 5, 4, 3},
+	}
+	for _, tc := range cases {
+		blocks := greedyCoveringDesign(tc.v, tc.k, tc.m, tc.g)
+		if len(blocks) == 0 || !wheelCovers(blocks, tc.v, tc.m, tc.g) {
+			t.Errorf("贪心矩阵 %+v 不满足保证条件", tc)
+		}
+	}
+}
+
+func TestGenerateWheel(t *testing.T) {
+	candidates := []int{3, 8, 11, 15, 19, 22, 26, 28, 30, 33}
+	result, err := GenerateWheel(testSSQGame, candidates, []int{7}, 5, 4)
+	if err != nil {
+		t.Fatalf("GenerateWheel error = %v", err)
+	}
+	if result.Source != "table" {
+		t.Errorf("Source = %s, want table", result.Source)
+	}
+	if result.BetCount != len(result.Numbers) || result.Cost != int64(result.BetCount)*defaultBetPrice {
+		t.Errorf("注数或金额计算错误: %+v", result)
+	}
+	for _, number := range result.Numbers {
+		if err := ValidateNumbers(testSSQGame, number.RedBalls, number.BlueBalls); err != nil {
+			t.Fatalf("生成号码不合法: %v", err)
+		}
+	}
+
+	result, err = GenerateWheel(testSSQGame, append(candidates, 1, 2, 4), []int{7}, 5, 4)
+	if err != nil {
+		t.Fatalf("GenerateWheel error = %v", err)
+	}
+	if result.Source != "greedy" {
+		t.Errorf("Source = %s, want greedy", result.Source)
+	}
+}
+
+func TestGenerateWheelValidation(t *testing.T) {
+	if _, err := GenerateWheel(testSSQGame, []int{1, 2, 3, 4, 5}, []int{7}, 5, 4); err == nil {
+		t.Error("候选号码不足时应返回错误")
+	}
+	if _, err := GenerateWheel(testSSQGame, []int{1, 2, 3, 4, 5, 6, 7}, []int{7}, 4, 5); err == nil {
+		t.Error("保证数大于开出数时应返回错误")
+	}
+	if _, err := GenerateWheel(testSSQGame, []int{1, 2, 3, 4, 5, 6, 7}, []int{20}, 5, 4); err == nil {
+		t.Error("蓝球超出范围时应返回错误")
+	}
+}
+
+func TestSaveWheelAsGroup(t *testing.T) {
+	db := dbtest.Open(t)
+	ssq, _ := createTestGames(t, db)
+	wheel, err := GenerateWheel(ssq, []int{1, 2, 3, 4, 5, 6, 7, 8}, []int{7}, 6, 5)
+	if err != nil {
+		t.Fatalf("GenerateWheel: %v", err)
+	}
+
+	// 第一注已保存在其他分组中
+	first := wheel.Numbers[0]
+	existing := createTestNumber(t, db, 1, ssq, first.RedBalls, first.BlueBalls)
+	oldGroup, err := CreateNumberGroup(db, 1, ssq.ID, "常买", "")
+	if err != nil {
+		t.Fatalf("CreateNumberGroup: %v", err)
+	}
+	if _, err := BulkMoveNumbers(db, 1, []int64{existing.ID}, &oldGroup.ID); err != nil {
+		t.Fatalf("BulkMoveNumbers: %v", err)
+	}
+
+	result, err := SaveWheelAsGroup(db, 1, ssq, wheel, "")
+	if err != nil {
+		t.Fatalf("SaveWheelAsGroup: %v", err)
+	}
+	if result.Saved != wheel.BetCount-1 || result.Duplicated != 1 {
+		t.Errorf("saved=%d duplicated=%d, want %d 1", result.Saved, result.Duplicated, wheel.BetCount-1)
+	}
+	if result.Group.Name != "双色球旋转矩阵(8选中6保5)" || result.Group.Source != "wheel" {
+		t.Errorf("分组 = %+v", result.Group)
+	}
+	if groupID := numberGroupID(t, db, existing.ID); groupID == nil || *groupID != oldGroup.ID {
+		t.Errorf("已存在的号码应留在原分组, got %v", groupID)
+	}
+	var inGroup int64
+	db.Model(&model.UserNumber{}).Where("group_id = ?", result.Group.ID).Count(&inGroup)
+	if int(inGroup) != result.Saved {
+		t.Errorf("新分组内号码数 = %d, want %d", inGroup, result.Saved)
+	}
+
+	// 再次保存时全部为重复号码，不创建空分组，原分组不变
+	again, err := SaveWheelAsGroup(db, 1, ssq, wheel, "再来一次")
+	if err != nil {
+		t.Fatalf("SaveWheelAsGroup: %v", err)
+	}
+	if again.Saved != 0 || again.Duplicated != wheel.BetCount {
+		t.Errorf("重复保存 saved=%d duplicated=%d, want 0 %d", again.Saved, again.Duplicated, wheel.BetCount)
+	}
+	var emptyGroups int64
+	db.Model(&model.NumberGroup{}).Where("name = ?", "再来一次").Count(&emptyGroups)
+	if again.Group != nil || emptyGroups != 0 {
+		t.Errorf("没有新号码时不应创建分组: group=%+v count=%d", again.Group, emptyGroups)
+	}
+	db.Model(&model.UserNumber{}).Where("group_id = ?", result.Group.ID).Count(&inGroup)
+	if int(inGroup) != result.Saved {
+		t.Errorf("重复保存不应移动号码, 原分组内剩 %d 注", inGroup)
+	}
+}
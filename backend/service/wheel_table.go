@@ -0,0 +1,53 @@
+package service
+
+// wheelDigits 内置矩阵中表示候选号码下标的字符
+const wheelDigits = "0123456789abcdefg"
+
+// knownWheels 内置旋转矩阵，键为"候选数-每注红球数-开出数-保证数"，
+// 每个字段是一注号码，字符为候选号码（升序）的下标
+var knownWheels = map[string]string{
+	"6-5-5-4":  "01245",                                                                                                                                                                                                                                                       // 1注
+	"6-5-4-3":  "01245",                                                                                                                                                                                                                                                       // 1注
+	"6-5-4-4":  "01245 01234 01345 01235 02345",                                                                                                                                                                                                                               // 5注
+	"6-5-3-3":  "01245 01234 01345 01235",                                                                                                                                                                                                                                     // 4注
+	"7-5-5-4":  "23456 01234 01256",                                                                                                                                                                                                                                           // 3注
+	"7-5-4-3":  "23456 01234 01246",                                                                                                                                                                                                                                           // 3注
+	"7-5-4-4":  "23456 01234 01256 01456 01356 02345 12345 02346 12346",                                                                                                                                                                                                       // 9注
+	"7-5-3-3":  "23456 01234 01256 01456 01356",                                                                                                                                                                                                                               // 5注
+	"8-5-5-4":  "01247 01356 23567 34567 23456",                                                                                                                                                                                                                               // 5注
+	"8-5-4-3":  "01247 01356 23567",                                                                                                                                                                                                                                           // 3注
+	"8-5-4-4":  "12347 01356 01245 14567 01267 03467 23567 02456 03457 12346 01237 01257 02345 01346 24567 13567 13456 12356 01247 04567 01236",                                                                                                                               // 21注
+	"8-5-3-3":  "01247 23567 03457 12346 02456 14567 01367 01235",                                                                                                                                                                                                             // 8注
+	"9-5-5-4":  "02467 12378 12458 13467 03457 01256 05678 01348 23568",                                                                                                                                                                                                       // 9注
+	"9-5-4-3":  "15678 01234 23458 05678 34678",                                                                                                                                                                                                                               // 5注
+	"9-5-4-4":  "12678 24567 02458 01456 02346 01238 13567 03457 34678 12347 01257 14578 05678 02367 01378 01467 12345 01368 23578 12468 01358 02478 01348 34568 12568 02356 12346 02468 01246 23468",                                                                         // 30注
+	"9-5-3-3":  "01247 34578 01368 12568 02345 01567 13456 02468 12378 02578 14678 02367",                                                                                                                                                                                     // 12注
+	"10-5-5-4": "01467 02368 12579 13458 46789 03459 02578 12369 02456 23479 01378 15689 12489 35679 01235 12679 34589",                                                                                                                                                       // 17注
+	"10-5-4-3": "26789 01458 02356 13479 02456 01579 01348 23789",                                                                                                                                                                                                             // 8注
+	"10-5-3-3": "02357 12469 13568 01789 34679 02348 04569 25678 12457 01239 34589 13478 01467 02368 12579 24689 01258",                                                                                                                                                       // 17注
+	"11-5-5-4": "1479a 01456 02347 3468a 0359a 2567a 13578 24589 06789 0128a 12369 1235a 02368 01349 34567 0167a 0458a 02579 3789a 2469a 12478 15689",                                                                                                                         // 22注
+	"11-5-4-3": "2578a 03459 16789 0124a 2367a 13568 2689a 03478 0457a 1279a 02567",                                                                                                                                                                                           // 11注
+	"11-5-3-3": "01257 3468a 04789 1379a 12689 23459 0569a 2467a 01346 1458a 35678 0238a 01678 2789a 0149a 45679 12347 02458 0357a 1256a 02369 13589",                                                                                                                         // 22注
+	"12-5-5-4": "2789b 1249a 0467a 02458 359ab 1236b 13478 018ab 03689 01579 4568b 23567 0234b 1568a 0269a 1679b 257ab 2378a 0345a 4789a 14569 0357b 346ab 01267 12358 0149b 23479 2468b 1457b 1379a 0259b 5789a 0156a 0678b 12589 01234 3589b 089ab 14568 2568a",             // 40注
+	"12-5-4-3": "2789b 1358a 0467a 03459 0126b 23468 1457b 369ab 1259a 05678 01237 048ab 01469 1357b",                                                                                                                                                                         // 14注
+	"12-5-3-3": "13689 2457a 01458 01279 013ab 589ab 2678b 2349b 03467 0268a 12356 0569b 146ab 03578 1579b 3679a 2348a 14789 128ab 0249a 24569 0245b 078ab 1567a 12347 02389 3468b 3457b 0359a 24568 0169a",                                                                   // 31注
+	"7-6-6-5":  "123456",                                                                                                                                                                                                                                                      // 1注
+	"7-6-5-4":  "123456",                                                                                                                                                                                                                                                      // 1注
+	"7-6-5-5":  "123456 012345 013456 023456 012456 012346",                                                                                                                                                                                                                   // 6注
+	"7-6-4-4":  "123456 012345 013456 023456 012456",                                                                                                                                                                                                                          // 5注
+	"8-6-6-5":  "012456 012347 123567 023467",                                                                                                                                                                                                                                 // 4注
+	"8-6-5-4":  "012456 012347 123567",                                                                                                                                                                                                                                        // 3注
+	"8-6-5-5":  "012456 012347 123567 034567 123467 024567 023456 123457 012357 014567 013456 012367",                                                                                                                                                                         // 12注
+	"8-6-4-4":  "012456 012347 034567 123467 023567 013567 123457",                                                                                                                                                                                                            // 7注
+	"9-6-6-5":  "014678 023578 123456 234678 013568 013457 012567 012458",                                                                                                                                                                                                     // 8注
+	"9-6-5-4":  "123567 023458 014678",                                                                                                                                                                                                                                        // 3注
+	"9-6-5-5":  "135678 012358 124578 024567 123456 012468 234678 034568 013478 012367 023578 013457 015678 023458 125678 013467 012347 145678 023568 024678 234567 123468 012456 123578 013678 034578 013567 013458 012578 023467 123467 234568",                             // 32注
+	"9-6-4-4":  "123567 023458 014678 013457 123468 025678 124578 013568 023467 012378 012456 345678",                                                                                                                                                                         // 12注
+	"10-6-6-5": "123458 024689 023567 145679 013789 014578 012369 235789 134678 034569 012479 012568 234678 156789",                                                                                                                                                           // 14注
+	"10-6-5-4": "124567 012389 345789 034568 012679 012378 124569",                                                                                                                                                                                                            // 7注
+	"10-6-4-4": "124679 013579 024578 023689 134568 012459 015689 013478 345679 235789 046789 012367 023456 125678 123489 025679 034589 123457 136789 012468",                                                                                                                 // 20注
+	"11-6-6-5": "34568a 12357a 02678a 125689 035679 012346 02459a 234789 01389a 14679a 014578 01456a 02358a 134589 12478a 013678 56789a 012579 03479a 024689 12369a 234567",                                                                                                   // 22注
+	"11-6-5-4": "01245a 56789a 023678 12369a 034569 123578 03478a 014678 024789 24569a 014789",                                                                                                                                                                                // 11注
+	"11-6-4-4": "023589 15679a 12489a 04568a 123467 01378a 013469 02679a 345789 012457 23568a 012678 02345a 36789a 134568 12359a 01589a 023567 245689 04579a 23478a 014789 01246a 023479 12578a 34679a 123789 13457a 012569 045678 03689a 13689a 02348a 235679 012345 124569", // 36注
+	"12-6-5-4": "04567b 1234ab 023689 15789a 14689b 01267a 23578b 3679ab 013478 01259b 24568a 0489ab 01356a 234579",                                                                                                                                                           // 14注
+}